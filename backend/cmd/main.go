@@ -1,11 +1,24 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/gin-gonic/gin"
-	"github.com/gin-contrib/cors"
 	"github.com/liviaruegger/MAC0350/backend/config"
 	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/events"
 	"github.com/liviaruegger/MAC0350/backend/internal/handler"
+	"github.com/liviaruegger/MAC0350/backend/internal/jobs"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
 	"github.com/liviaruegger/MAC0350/backend/internal/repository"
 
 	// Swagger imports
@@ -20,47 +33,228 @@ import (
 // @host            localhost:8080
 // @BasePath        /
 
-func SetupRouter() *gin.Engine {
-	db := config.SetupDatabase()
+// SetupRouter wires the handlers of the API, registering the handlers of the
+// background jobs the services enqueue on runner; The events of the users are
+// published to and streamed from broker
+func SetupRouter(cfg config.Config, db *sql.DB, runner *jobs.Runner, broker *events.Broker) *gin.Engine {
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := app.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
 
 	userRepo := repository.NewUserRepository(db)
-	userService := app.NewUserService(userRepo)
+	userService := app.NewUserService(userRepo, auditRepo)
 	userHandler := handler.NewUserHandler(userService)
 
+	activityRepo := repository.NewActivityRepository(db)
 	intervalRepo := repository.NewIntervalRepository(db)
-	intervalService := app.NewIntervalService(intervalRepo)
+	intervalService := app.NewIntervalService(intervalRepo, activityRepo, auditRepo)
 	intervalHandler := handler.NewIntervalHandler(intervalService)
 
-	activityRepo := repository.NewActivityRepository(db)
-	activityService := app.NewActivityService(activityRepo, intervalRepo)
+	cssRepo := repository.NewCSSRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+
+	activityService := app.NewActivityService(activityRepo, intervalRepo, cssRepo, userRepo, auditRepo, webhookRepo, runner, broker)
 	activityHandler := handler.NewActivityHandler(activityService)
 
-	router := gin.Default()
-	router.Use(cors.Default())
+	trainingLoadService := app.NewTrainingLoadService(userRepo, activityRepo)
+	trainingLoadHandler := handler.NewTrainingLoadHandler(trainingLoadService)
+
+	cssService := app.NewCSSService(cssRepo, activityRepo, intervalRepo, auditRepo)
+	cssHandler := handler.NewCSSHandler(cssService)
+
+	heartRateService := app.NewHeartRateService(userRepo, activityRepo, intervalRepo)
+	heartRateHandler := handler.NewHeartRateHandler(heartRateService)
+
+	strokeService := app.NewStrokeService(activityRepo, intervalRepo)
+	strokeHandler := handler.NewStrokeHandler(strokeService)
+
+	retentionService := app.NewRetentionService(repository.NewRetentionRepository(db), userRepo, activityRepo, auditRepo, cfg.DeletedRetention)
+	retentionHandler := handler.NewRetentionHandler(retentionService, userService, activityService)
+	if cfg.PurgeInterval > 0 {
+		runner.Every(app.PurgeJobType, cfg.PurgeInterval, retentionService.PurgeDeletedJob)
+	}
+
+	exportService := app.NewExportService(repository.NewExportRepository(db), userRepo, activityRepo, intervalRepo, cssRepo, auditRepo, runner, cfg.ExportTTL)
+	exportHandler := handler.NewExportHandler(exportService)
+	runner.Handle(app.ExportJobType, exportService.GenerateExport)
+
+	webhookService := app.NewWebhookService(webhookRepo, userRepo, cfg.WebhookAllowPrivate)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	runner.Handle(app.WebhookJobType, webhookService.DeliverWebhook)
+
+	eventService := app.NewEventService(broker, userRepo)
+	eventHandler := handler.NewEventHandler(eventService)
+
+	graphQLHandler := handler.NewGraphQLHandler(userService, activityService)
+
+	jobService := app.NewJobService(repository.NewJobRepository(db))
+	jobHandler := handler.NewJobHandler(jobService)
+
+	healthRepo := repository.NewHealthRepository(db)
+	healthService := app.NewHealthService(healthRepo, config.SchemaVersion, cfg.ReadinessTimeout)
+	healthHandler := handler.NewHealthHandler(healthService)
+	metrics := handler.NewMetrics(healthService.DatabaseStats)
+
+	if err := handler.RegisterValidators(); err != nil {
+		fatal("validator registration failed", err)
+	}
+
+	router := gin.New()
+	// The client IP, used by the rate limits and access log, is only taken from
+	// X-Forwarded-For when the request comes from one of the configured proxies
+	trustedProxies := make([]string, len(cfg.TrustedProxies))
+	for i, proxy := range cfg.TrustedProxies {
+		trustedProxies[i] = proxy.String()
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		fatal("trusted proxies configuration failed", err)
+	}
+	router.Use(handler.RequestID())
+	router.Use(handler.AccessLog())
+	router.Use(handler.CORS(handler.CORSPolicy{
+		Origins:     cfg.CORSOrigins,
+		Methods:     cfg.CORSMethods,
+		Headers:     cfg.CORSHeaders,
+		Credentials: cfg.CORSCredentials,
+		MaxAge:      cfg.CORSMaxAge,
+	}))
+	router.Use(metrics.Middleware())
+	router.Use(handler.ErrorHandler())
+	router.Use(handler.Recovery())
+	router.Use(handler.MaxBodySize(cfg.MaxBodySize))
+	router.Use(handler.Authenticate(cfg.AuthUserHeader, cfg.TrustedProxies, cfg.AdminUserIDs))
+
+	// The routes are bound by the request timeout, registered on their groups so
+	// the streaming routes are not
+	timeout := handler.Timeout(cfg.RequestTimeout)
+	routes := router.Group("", timeout)
+
+	// Health and metrics routes
+	routes.GET("/healthz", healthHandler.Healthz)
+	routes.GET("/readyz", healthHandler.Readyz)
+	routes.GET("/metrics", metrics.ServeMetrics)
 
 	// Swagger route
-	router.GET("/swagger/*any", ginswagger.WrapHandler(swaggerfiles.Handler))
+	routes.GET("/swagger/*any", ginswagger.WrapHandler(swaggerfiles.Handler))
 
-	// User routes
-	router.POST("/users", userHandler.CreateUser)
-	router.GET("/users", userHandler.GetAllUsers)
-	router.GET("/users/email/:email", userHandler.GetUserByEmail)
-	router.GET("/users/:id", userHandler.GetUserByID)
-	router.PUT("/users/:id", userHandler.UpdateUser)
-	router.DELETE("/users/:id", userHandler.DeleteUser)
+	// Reads and writes are rate limited separately, each client sharing its budget
+	// across the routes of a group, whatever the version or alias it calls
+	readLimit := handler.NewRateLimiter(handler.RateLimit{Rate: cfg.RateLimit, Burst: cfg.RateLimitBurst}).Middleware()
+	writeLimit := handler.NewRateLimiter(handler.RateLimit{Rate: cfg.WriteRateLimit, Burst: cfg.WriteRateLimitBurst}).Middleware()
 
-	// Activity routes
-	router.POST("/activities", activityHandler.CreateActivity)
-	router.GET("/activities", activityHandler.GetAllActivities)
-	router.GET("/users/:id/activities", activityHandler.GetActivitiesByUser)
+	v1 := v1Handlers{
+		user:         userHandler,
+		activity:     activityHandler,
+		interval:     intervalHandler,
+		trainingLoad: trainingLoadHandler,
+		css:          cssHandler,
+		heartRate:    heartRateHandler,
+		stroke:       strokeHandler,
+		retention:    retentionHandler,
+		audit:        auditHandler,
+		export:       exportHandler,
+		job:          jobHandler,
+		webhook:      webhookHandler,
+		event:        eventHandler,
+		graphql:      graphQLHandler,
+	}
+	api := router.Group("/api/v1")
+	registerV1Routes(api.Group("", readLimit, timeout), api.Group("", writeLimit, timeout), api.Group("", readLimit, handler.Streaming()), v1)
 
-	// Interval routes
-	router.POST("/intervals", intervalHandler.CreateInterval)
+	// Unversioned aliases of the routes older than /api/v1, kept until the frontend and scripts migrate
+	legacy := router.Group("", handler.Deprecated(legacyRoutesDeprecation, cfg.LegacyRoutesSunset, "/api/v1"))
+	registerLegacyRoutes(legacy.Group("", readLimit, timeout), legacy.Group("", writeLimit, timeout), v1)
 
 	return router
 }
 
 func main() {
-	router := SetupRouter()
-	router.Run(":8080")
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+
+	slog.SetDefault(logging.NewLogger(os.Stderr, cfg.LogLevel))
+	if cfg.LogLevel > slog.LevelDebug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	domain.DefaultDurationFormat = cfg.DurationFormat
+
+	// A termination signal stops the server, or the connection attempts if it is not up yet
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := config.SetupDatabase(ctx, cfg.Database)
+	if err != nil {
+		fatal("database setup failed", err)
+	}
+
+	runner := jobs.NewRunner(repository.NewJobRepository(db), jobs.Config{
+		Concurrency:  cfg.JobConcurrency,
+		PollInterval: cfg.JobPollInterval,
+		Timeout:      cfg.JobTimeout,
+		MaxAttempts:  cfg.JobMaxAttempts,
+	})
+
+	// Events are relayed between the instances, each delivering them to its own subscribers
+	broker := events.NewBroker(repository.NewEventRepository(db))
+
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           SetupRouter(cfg, db, runner, broker),
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	// The jobs in flight at shutdown complete while the requests drain
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		runner.Run(ctx)
+	}()
+
+	// The event streams never go idle, so they are closed for the requests to drain
+	server.RegisterOnShutdown(broker.Close)
+	go func() {
+		if err := events.Listen(ctx, cfg.Database.DSN(), broker); err != nil {
+			slog.Error("listening for events failed, events are only streamed by the instance publishing them", "error", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("listening", "addr", cfg.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("server failed", err)
+		}
+	}()
+
+	// Wait for a termination signal, then stop accepting connections and drain the in-flight requests
+	<-ctx.Done()
+	stop()
+
+	slog.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("server shutdown failed", "error", err)
+	}
+	select {
+	case <-jobsDone:
+	case <-shutdownCtx.Done():
+		slog.Warn("background jobs still running at shutdown, they are run again once their lease expires")
+	}
+	if err := db.Close(); err != nil {
+		slog.Error("database close failed", "error", err)
+	}
+}
+
+// fatal logs an error that prevents the server from running and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/handler"
+)
+
+// legacyRoutesDeprecation is when the unversioned routes were deprecated in favor of /api/v1
+var legacyRoutesDeprecation = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// v1Handlers are the handlers serving version 1 of the API; A breaking change gets
+// new handlers, registered by a registerV2Routes under /api/v2, while v1 keeps these
+type v1Handlers struct {
+	user         *handler.UserHandler
+	activity     *handler.ActivityHandler
+	interval     *handler.IntervalHandler
+	trainingLoad *handler.TrainingLoadHandler
+	css          *handler.CSSHandler
+	heartRate    *handler.HeartRateHandler
+	stroke       *handler.StrokeHandler
+	retention    *handler.RetentionHandler
+	audit        *handler.AuditHandler
+	export       *handler.ExportHandler
+	job          *handler.JobHandler
+	webhook      *handler.WebhookHandler
+	event        *handler.EventHandler
+	graphql      *handler.GraphQLHandler
+}
+
+// registerV1Routes registers the routes of version 1 of the API, reading routes on
+// reads, writing routes on writes and streaming routes on streams, so each group
+// can have its own middlewares
+func registerV1Routes(reads, writes, streams gin.IRoutes, h v1Handlers) {
+	// User routes
+	writes.POST("/users", h.user.CreateUser)
+	reads.GET("/users", h.user.GetAllUsers)
+	reads.GET("/users/email/:email", h.user.GetUserByEmail)
+	reads.GET("/users/:id", h.user.GetUserByID)
+	writes.PUT("/users/:id", h.user.UpdateUser)
+	writes.DELETE("/users/:id", h.user.DeleteUser)
+	writes.POST("/users/:id/restore", h.retention.RestoreUser)
+	reads.GET("/users/:id/audit", h.audit.GetUserAudit)
+
+	// Data export routes
+	writes.POST("/users/:id/export", h.export.RequestExport)
+	reads.GET("/exports/:id", h.export.GetExport)
+	reads.GET("/exports/:id/download", h.export.DownloadExport)
+
+	// Background job routes
+	reads.GET("/jobs/:id", h.job.GetJob)
+
+	// Webhook routes
+	writes.POST("/users/:id/webhooks", h.webhook.CreateWebhook)
+	reads.GET("/users/:id/webhooks", h.webhook.GetWebhooks)
+	writes.DELETE("/webhooks/:id", h.webhook.DeleteWebhook)
+	reads.GET("/webhooks/:id/deliveries", h.webhook.GetDeliveries)
+
+	// Event stream routes
+	streams.GET("/users/:id/events", h.event.StreamEvents)
+
+	// Activity routes
+	writes.POST("/activities", h.activity.CreateActivity)
+	reads.GET("/activities", h.activity.GetAllActivities)
+	reads.GET("/activities/:id", h.activity.GetActivityByID)
+	writes.PUT("/activities/:id", h.activity.UpdateActivity)
+	writes.DELETE("/activities/:id", h.activity.DeleteActivity)
+	writes.POST("/activities/:id/restore", h.retention.RestoreActivity)
+	reads.GET("/users/:id/activities", h.activity.GetActivitiesByUser)
+
+	// Training load routes
+	reads.GET("/users/:id/training-load", h.trainingLoad.GetTrainingLoad)
+
+	// CSS and pace zone routes
+	writes.POST("/users/:id/css", h.css.CreateCSSTest)
+	reads.GET("/users/:id/css", h.css.GetCSSHistory)
+	reads.GET("/users/:id/pace-zones", h.css.GetPaceZones)
+
+	// Heart-rate zone routes
+	reads.GET("/users/:id/heart-rate-zones", h.heartRate.GetHeartRateZones)
+	reads.GET("/users/:id/time-in-zone", h.heartRate.GetTimeInZone)
+
+	// Stroke efficiency routes
+	reads.GET("/users/:id/stroke-efficiency", h.stroke.GetStrokeEfficiency)
+
+	// Interval routes
+	writes.POST("/intervals", h.interval.CreateInterval)
+
+	// GraphQL routes; Queries only read, so they share the budget of the reads
+	reads.POST("/graphql", h.graphql.Query)
+	reads.GET("/graphql/schema", h.graphql.GetSchema)
+}
+
+// registerLegacyRoutes registers the unversioned aliases of the v1 routes that existed
+// before /api/v1 was introduced; The list is frozen, routes added since are only served under /api/v1
+func registerLegacyRoutes(reads, writes gin.IRoutes, h v1Handlers) {
+	// User routes
+	writes.POST("/users", h.user.CreateUser)
+	reads.GET("/users", h.user.GetAllUsers)
+	reads.GET("/users/email/:email", h.user.GetUserByEmail)
+	reads.GET("/users/:id", h.user.GetUserByID)
+	writes.PUT("/users/:id", h.user.UpdateUser)
+	writes.DELETE("/users/:id", h.user.DeleteUser)
+
+	// Activity routes
+	writes.POST("/activities", h.activity.CreateActivity)
+	reads.GET("/activities", h.activity.GetAllActivities)
+	reads.GET("/activities/:id", h.activity.GetActivityByID)
+	reads.GET("/users/:id/activities", h.activity.GetActivitiesByUser)
+
+	// Training load routes
+	reads.GET("/users/:id/training-load", h.trainingLoad.GetTrainingLoad)
+
+	// CSS and pace zone routes
+	writes.POST("/users/:id/css", h.css.CreateCSSTest)
+	reads.GET("/users/:id/css", h.css.GetCSSHistory)
+	reads.GET("/users/:id/pace-zones", h.css.GetPaceZones)
+
+	// Heart-rate zone routes
+	reads.GET("/users/:id/heart-rate-zones", h.heartRate.GetHeartRateZones)
+	reads.GET("/users/:id/time-in-zone", h.heartRate.GetTimeInZone)
+
+	// Stroke efficiency routes
+	reads.GET("/users/:id/stroke-efficiency", h.stroke.GetStrokeEfficiency)
+
+	// Interval routes
+	writes.POST("/intervals", h.interval.CreateInterval)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/handler"
+)
+
+func TestRegisterLegacyRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1")
+	registerV1Routes(api, api, api, v1Handlers{})
+	legacy := router.Group("", handler.Deprecated(legacyRoutesDeprecation, time.Now(), "/api/v1"))
+	registerLegacyRoutes(legacy, legacy, v1Handlers{})
+
+	versioned := map[string]bool{}
+	var aliases []string
+	for _, route := range router.Routes() {
+		if path, ok := strings.CutPrefix(route.Path, "/api/v1"); ok {
+			versioned[route.Method+" "+path] = true
+		} else {
+			aliases = append(aliases, route.Method+" "+route.Path)
+		}
+	}
+	// Only the 18 routes older than /api/v1 have an alias
+	if len(aliases) != 18 {
+		t.Errorf("expected 18 aliases, got %d: %v", len(aliases), aliases)
+	}
+	for _, alias := range aliases {
+		if !versioned[alias] {
+			t.Errorf("alias %s has no v1 route", alias)
+		}
+		if strings.Contains(alias, "graphql") || strings.Contains(alias, "webhooks") || strings.Contains(alias, "events") {
+			t.Errorf("route %s was added after /api/v1 and must not have an alias", alias)
+		}
+	}
+}
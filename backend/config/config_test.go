@@ -0,0 +1,286 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// env returns a lookupEnv reading from vars
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+// database holds the settings required to load any configuration
+var database = map[string]string{"DB_HOST": "localhost", "DB_USER": "swim", "DB_NAME": "swim"}
+
+func withDatabase(vars map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range database {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+	return merged
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil, env(database))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Addr != ":8080" {
+		t.Errorf("Addr = %q, want \":8080\"", cfg.Addr)
+	}
+	if cfg.RequestTimeout != 10*time.Second || cfg.WriteTimeout != 30*time.Second {
+		t.Errorf("RequestTimeout, WriteTimeout = %v, %v, want 10s, 30s", cfg.RequestTimeout, cfg.WriteTimeout)
+	}
+	if cfg.DurationFormat != domain.DurationFormatGo {
+		t.Errorf("DurationFormat = %q, want %q", cfg.DurationFormat, domain.DurationFormatGo)
+	}
+	if len(cfg.CORSOrigins) != 2 || cfg.CORSOrigins[0] != "http://localhost:5173" {
+		t.Errorf("CORSOrigins = %v, want the Vite dev server", cfg.CORSOrigins)
+	}
+	if cfg.CORSCredentials || cfg.CORSMaxAge != 12*time.Hour || len(cfg.CORSMethods) != 6 {
+		t.Errorf("CORSCredentials, CORSMaxAge, CORSMethods = %v, %v, %v", cfg.CORSCredentials, cfg.CORSMaxAge, cfg.CORSMethods)
+	}
+	if cfg.RateLimit != 20 || cfg.WriteRateLimit != 5 || cfg.MaxBodySize != 1<<20 {
+		t.Errorf("RateLimit, WriteRateLimit, MaxBodySize = %v, %v, %d", cfg.RateLimit, cfg.WriteRateLimit, cfg.MaxBodySize)
+	}
+	if cfg.WebhookAllowPrivate {
+		t.Error("WebhookAllowPrivate = true, want false")
+	}
+	if cfg.DeletedRetention != 30*24*time.Hour || cfg.PurgeInterval != time.Hour || cfg.ExportTTL != 24*time.Hour {
+		t.Errorf("DeletedRetention, PurgeInterval, ExportTTL = %v, %v, %v, want 720h, 1h, 24h", cfg.DeletedRetention, cfg.PurgeInterval, cfg.ExportTTL)
+	}
+	if cfg.JobConcurrency != 4 || cfg.JobPollInterval != time.Second || cfg.JobTimeout != 10*time.Minute || cfg.JobMaxAttempts != 5 {
+		t.Errorf("JobConcurrency, JobPollInterval, JobTimeout, JobMaxAttempts = %d, %v, %v, %d, want 4, 1s, 10m, 5",
+			cfg.JobConcurrency, cfg.JobPollInterval, cfg.JobTimeout, cfg.JobMaxAttempts)
+	}
+	if cfg.AuthUserHeader != "" || len(cfg.AdminUserIDs) != 0 {
+		t.Errorf("AuthUserHeader, AdminUserIDs = %q, %v, want anonymous requests", cfg.AuthUserHeader, cfg.AdminUserIDs)
+	}
+	if len(cfg.TrustedProxies) != 0 {
+		t.Errorf("TrustedProxies = %v, want none", cfg.TrustedProxies)
+	}
+	if cfg.LogLevel != slog.LevelInfo {
+		t.Errorf("LogLevel = %v, want INFO", cfg.LogLevel)
+	}
+	want := "host=localhost port=5432 user=swim password='' dbname=swim sslmode=disable"
+	if got := cfg.Database.DSN(); got != want {
+		t.Errorf("DSN() = %q, want %q", got, want)
+	}
+}
+
+func TestDatabaseConfig_DSN(t *testing.T) {
+	cfg := DatabaseConfig{Host: "db", Port: "5432", User: "swim", Password: `it's a \secret`, Name: "swim", SSLMode: "require"}
+	want := `host=db port=5432 user=swim password='it\'s a \\secret' dbname=swim sslmode=require`
+	if got := cfg.DSN(); got != want {
+		t.Errorf("DSN() = %s, want %s", got, want)
+	}
+
+	cfg.URL = "postgres://swim@db/swim?sslmode=verify-full"
+	if got := cfg.DSN(); got != cfg.URL {
+		t.Errorf("DSN() = %s, want the URL", got)
+	}
+}
+
+func TestLoad_Database(t *testing.T) {
+	t.Run("pool", func(t *testing.T) {
+		cfg, err := Load([]string{"-db-max-open-conns", "10", "-db-max-idle-conns", "5", "-db-conn-max-lifetime", "5m"}, env(database))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.Database.MaxOpenConns != 10 || cfg.Database.MaxIdleConns != 5 || cfg.Database.ConnMaxLifetime != 5*time.Minute {
+			t.Errorf("unexpected pool settings %+v", cfg.Database)
+		}
+		if cfg.Database.ConnectTimeout != 30*time.Second {
+			t.Errorf("ConnectTimeout = %v, want 30s", cfg.Database.ConnectTimeout)
+		}
+	})
+
+	t.Run("URL replaces the connection settings", func(t *testing.T) {
+		cfg, err := Load(nil, env(map[string]string{"DATABASE_URL": "postgres://swim@db/swim"}))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.Database.DSN() != "postgres://swim@db/swim" {
+			t.Errorf("DSN() = %s", cfg.Database.DSN())
+		}
+	})
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	vars := withDatabase(map[string]string{
+		"PORT":             "9090",
+		"REQUEST_TIMEOUT":  "5s",
+		"DURATION_FORMAT":  "swim",
+		"CORS_ORIGINS":     "http://localhost:3000, https://swim.example.com",
+		"LOG_LEVEL":        "debug",
+		"AUTH_USER_HEADER": "X-User-ID",
+		"ADMIN_USER_IDS":   "6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1",
+		"TRUSTED_PROXIES":  "10.0.0.0/8, 192.168.1.10",
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		cfg, err := Load(nil, env(vars))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.Addr != ":9090" {
+			t.Errorf("Addr = %q, want \":9090\"", cfg.Addr)
+		}
+		if cfg.RequestTimeout != 5*time.Second {
+			t.Errorf("RequestTimeout = %v, want 5s", cfg.RequestTimeout)
+		}
+		if cfg.DurationFormat != domain.DurationFormatSwim {
+			t.Errorf("DurationFormat = %q, want swim", cfg.DurationFormat)
+		}
+		if len(cfg.CORSOrigins) != 2 || cfg.CORSOrigins[1] != "https://swim.example.com" {
+			t.Errorf("CORSOrigins = %v", cfg.CORSOrigins)
+		}
+		if cfg.LogLevel != slog.LevelDebug {
+			t.Errorf("LogLevel = %v, want DEBUG", cfg.LogLevel)
+		}
+		if cfg.AuthUserHeader != "X-User-ID" || len(cfg.AdminUserIDs) != 1 || cfg.AdminUserIDs[0].String() != "6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1" {
+			t.Errorf("AuthUserHeader, AdminUserIDs = %q, %v", cfg.AuthUserHeader, cfg.AdminUserIDs)
+		}
+		if len(cfg.TrustedProxies) != 2 || cfg.TrustedProxies[1].String() != "192.168.1.10/32" {
+			t.Errorf("TrustedProxies = %v", cfg.TrustedProxies)
+		}
+	})
+
+	t.Run("flags take precedence", func(t *testing.T) {
+		cfg, err := Load([]string{"-addr", "127.0.0.1:7070", "-request-timeout", "0"}, env(vars))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.Addr != "127.0.0.1:7070" {
+			t.Errorf("Addr = %q, want \"127.0.0.1:7070\"", cfg.Addr)
+		}
+		if cfg.RequestTimeout != 0 {
+			t.Errorf("RequestTimeout = %v, want 0", cfg.RequestTimeout)
+		}
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		if _, err := Load([]string{"-unknown"}, env(vars)); err == nil {
+			t.Error("expected an error for an unknown flag")
+		}
+	})
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want []string
+	}{
+		{
+			"every invalid setting is reported",
+			withDatabase(map[string]string{"READ_TIMEOUT": "soon", "DURATION_FORMAT": "iso", "LOG_LEVEL": "loud"}),
+			[]string{"READ_TIMEOUT", "DURATION_FORMAT", "LOG_LEVEL"},
+		},
+		{
+			"negative timeout",
+			withDatabase(map[string]string{"IDLE_TIMEOUT": "-1s"}),
+			[]string{"IDLE_TIMEOUT"},
+		},
+		{
+			"write timeout not longer than the request timeout",
+			withDatabase(map[string]string{"WRITE_TIMEOUT": "10s", "REQUEST_TIMEOUT": "10s"}),
+			[]string{"WRITE_TIMEOUT"},
+		},
+		{
+			"invalid address",
+			withDatabase(map[string]string{"ADDR": "localhost"}),
+			[]string{"ADDR"},
+		},
+		{
+			"empty CORS origins",
+			withDatabase(map[string]string{"CORS_ORIGINS": " , "}),
+			[]string{"CORS_ORIGINS"},
+		},
+		{
+			"invalid CORS origins",
+			withDatabase(map[string]string{"CORS_ORIGINS": "localhost:5173,https://*.*.example.com"}),
+			[]string{"CORS_ORIGINS"},
+		},
+		{
+			"credentials with any origin",
+			withDatabase(map[string]string{"CORS_ORIGINS": "*", "CORS_CREDENTIALS": "true"}),
+			[]string{"CORS_CREDENTIALS"},
+		},
+		{
+			"invalid limits",
+			withDatabase(map[string]string{"RATE_LIMIT": "NaN", "MAX_BODY_SIZE": "1MB", "WRITE_RATE_LIMIT_BURST": "0"}),
+			[]string{"RATE_LIMIT", "MAX_BODY_SIZE", "WRITE_RATE_LIMIT_BURST"},
+		},
+		{
+			"no retention of deleted records and exports",
+			withDatabase(map[string]string{"DELETED_RETENTION": "0s", "EXPORT_TTL": "0s"}),
+			[]string{"DELETED_RETENTION", "EXPORT_TTL"},
+		},
+		{
+			"no background job runs",
+			withDatabase(map[string]string{"JOB_CONCURRENCY": "0", "JOB_POLL_INTERVAL": "0s", "JOB_TIMEOUT": "0s", "JOB_MAX_ATTEMPTS": "0"}),
+			[]string{"JOB_CONCURRENCY", "JOB_POLL_INTERVAL", "JOB_TIMEOUT", "JOB_MAX_ATTEMPTS"},
+		},
+		{
+			"invalid trusted proxies",
+			withDatabase(map[string]string{"TRUSTED_PROXIES": "10.0.0.0/33,proxy.local"}),
+			[]string{"TRUSTED_PROXIES"},
+		},
+		{
+			"authentication without trusted proxies",
+			withDatabase(map[string]string{"AUTH_USER_HEADER": "X-User-ID"}),
+			[]string{"AUTH_USER_HEADER"},
+		},
+		{
+			"invalid admins",
+			withDatabase(map[string]string{"AUTH_USER_HEADER": "X-User-ID", "TRUSTED_PROXIES": "10.0.0.1", "ADMIN_USER_IDS": "admin"}),
+			[]string{"ADMIN_USER_IDS"},
+		},
+		{
+			"admins without authentication",
+			withDatabase(map[string]string{"ADMIN_USER_IDS": "6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1"}),
+			[]string{"ADMIN_USER_IDS"},
+		},
+		{
+			"invalid pool settings",
+			withDatabase(map[string]string{"DB_MAX_OPEN_CONNS": "-1", "DB_CONN_MAX_LIFETIME": "forever"}),
+			[]string{"DB_MAX_OPEN_CONNS", "DB_CONN_MAX_LIFETIME"},
+		},
+		{
+			"more idle than open connections",
+			withDatabase(map[string]string{"DB_MAX_OPEN_CONNS": "5", "DB_MAX_IDLE_CONNS": "10"}),
+			[]string{"DB_MAX_IDLE_CONNS"},
+		},
+		{
+			"missing database settings",
+			map[string]string{},
+			[]string{"DB_HOST", "DB_USER", "DB_NAME"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(nil, env(tt.vars))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, setting := range tt.want {
+				if !strings.Contains(err.Error(), setting+":") {
+					t.Errorf("error %q does not mention %s", err, setting)
+				}
+			}
+		})
+	}
+}
@@ -1,48 +1,129 @@
 package config
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"strings"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 )
 
-func SetupDatabase() *sql.DB {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-	)
+// Delays between the attempts to connect to the database, doubled after each failure
+const (
+	initialRetryDelay = 250 * time.Millisecond
+	maxRetryDelay     = 5 * time.Second
+)
 
-	db, err := sql.Open("postgres", dsn)
+// SetupDatabase connects to the database, retrying for up to cfg.ConnectTimeout
+// while it is unreachable, e.g., still starting, and brings its schema up to date
+func SetupDatabase(ctx context.Context, cfg DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DSN())
 	if err != nil {
-		log.Fatal("Database connection error:", err)
+		return nil, fmt.Errorf("connecting to the database: %w", err)
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := waitForDatabase(ctx, db.PingContext, cfg.ConnectTimeout, initialRetryDelay); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("pinging the database: %w", err)
+	}
+
+	if err := setupSchema(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// waitForDatabase calls ping until it succeeds, backing off exponentially from delay
+// between attempts, and gives up with the last error once maxWait has elapsed
+func waitForDatabase(ctx context.Context, ping func(context.Context) error, maxWait, delay time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+
+		slog.WarnContext(ctx, "database unavailable", "attempt", attempt, "retry_in", delay.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxRetryDelay)
 	}
+}
+
+// schemaLockKey is the key of the advisory lock held while the schema is set up, so that
+// instances starting together do not apply the migrations concurrently
+const schemaLockKey = 7_350_001
+
+// schemaConn runs the statements setting up the schema; It is a single connection, as
+// session-level advisory locks only apply to the connection that takes them
+type schemaConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
 
-	if err := db.Ping(); err != nil {
-		log.Fatal("Database ping error:", err)
+// setupSchema creates the tables, applies the migrations and syncs the enum constraints,
+// holding the schema lock; Waiting for the lock is bounded by ctx
+func setupSchema(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to the database: %w", err)
 	}
+	defer conn.Close()
 
-	createTables(db)
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, schemaLockKey); err != nil {
+		return fmt.Errorf("acquiring the schema lock: %w", err)
+	}
+	defer func() {
+		// The lock is also released when the connection is closed, so a failure is only logged
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, schemaLockKey); err != nil {
+			slog.WarnContext(ctx, "releasing the schema lock failed", "error", err)
+		}
+	}()
 
-	return db
+	if err := createTables(ctx, conn); err != nil {
+		return err
+	}
+	if err := migrateTables(ctx, conn); err != nil {
+		return err
+	}
+	return syncEnumConstraints(ctx, conn)
 }
 
-func createTables(db *sql.DB) {
+func createTables(ctx context.Context, conn schemaConn) error {
 	userTable := `
 	CREATE TABLE IF NOT EXISTS users (
 		id UUID PRIMARY KEY,
 		name TEXT NOT NULL,
-		email TEXT UNIQUE NOT NULL,
+		email TEXT NOT NULL,
 		city TEXT NOT NULL,
 		phone TEXT NOT NULL,
 		age INTEGER NOT NULL,
 		height INTEGER NOT NULL,
-		weight DOUBLE PRECISION NOT NULL
+		weight DOUBLE PRECISION NOT NULL,
+		max_heart_rate INTEGER NOT NULL DEFAULT 0,
+		resting_heart_rate INTEGER NOT NULL DEFAULT 0,
+		lactate_threshold_heart_rate INTEGER NOT NULL DEFAULT 0,
+		display_unit TEXT NOT NULL DEFAULT 'meters',
+		version INTEGER NOT NULL DEFAULT 1,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		anonymized_at TIMESTAMPTZ
 	);`
 
 	activitiesTable := `
@@ -51,46 +132,319 @@ func createTables(db *sql.DB) {
 		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
 		date TEXT NOT NULL,
 		start TIMESTAMP NOT NULL,
-		duration BIGINT NOT NULL,
+		duration_ms BIGINT NOT NULL,
 		distance FLOAT NOT NULL,
 		laps INTEGER NOT NULL,
 		pool_size FLOAT NOT NULL,
-		location_type TEXT NOT NULL CHECK (location_type IN ('pool', 'open_water')),
+		location_type TEXT NOT NULL,
 		location_name TEXT,
-		feeling TEXT CHECK (feeling IN ('excellent', 'good', 'regular', 'tired', 'bad')),
+		feeling TEXT,
 		heart_rate_avg INTEGER,
 		heart_rate_max INTEGER,
-		notes TEXT DEFAULT ''
+		rpe INTEGER NOT NULL DEFAULT 0 CHECK (rpe BETWEEN 0 AND 10),
+		notes TEXT DEFAULT '',
+		pool_unit TEXT NOT NULL DEFAULT 'meters',
+		version INTEGER NOT NULL DEFAULT 1,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ
 	);`
 
 	intervalsTable := `
 	CREATE TABLE IF NOT EXISTS intervals (
 		id UUID PRIMARY KEY,
 		activity_id UUID NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
-		duration BIGINT NOT NULL,
+		duration_ms BIGINT NOT NULL,
 		distance FLOAT NOT NULL,
-		type TEXT NOT NULL CHECK (
-			type IN (
-				'swim', 'rest', 'drill', 'kick', 'pull',
-				'warmup', 'main_set', 'cooldown'
-			)
-		),
-		stroke TEXT NOT NULL CHECK (
-			stroke IN (
-				'freestyle', 'backstroke', 'breaststroke',
-				'butterfly', 'medley', 'unknown'
-			)
-		),
-		notes TEXT DEFAULT ''
+		type TEXT NOT NULL,
+		stroke TEXT NOT NULL,
+		notes TEXT DEFAULT '',
+		heart_rate_avg INTEGER NOT NULL DEFAULT 0,
+		stroke_count INTEGER NOT NULL DEFAULT 0 CHECK (stroke_count >= 0),
+		length_stroke_counts INTEGER[],
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ
+	);`
+
+	cssTestsTable := `
+	CREATE TABLE IF NOT EXISTS css_tests (
+		id UUID PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		date TEXT NOT NULL,
+		time_400_ms BIGINT NOT NULL,
+		time_200_ms BIGINT NOT NULL,
+		interval_400_id UUID REFERENCES intervals(id) ON DELETE SET NULL,
+		interval_200_id UUID REFERENCES intervals(id) ON DELETE SET NULL,
+		css_pace DOUBLE PRECISION NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);`
+
+	// Audit events outlive the records they describe, so they have no foreign keys
+	auditEventsTable := `
+	CREATE TABLE IF NOT EXISTS audit_events (
+		id UUID PRIMARY KEY,
+		occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		actor_id UUID,
+		user_id UUID NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_id UUID NOT NULL,
+		action TEXT NOT NULL,
+		changes JSONB NOT NULL DEFAULT '{}'
+	);`
+
+	dataExportsTable := `
+	CREATE TABLE IF NOT EXISTS data_exports (
+		id UUID PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		status TEXT NOT NULL,
+		archive BYTEA,
+		size BIGINT NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		completed_at TIMESTAMPTZ,
+		expires_at TIMESTAMPTZ NOT NULL,
+		job_id UUID
+	);`
+
+	// A running job is leased to its worker until locked_until, after which it is
+	// run again, e.g., when its worker crashed
+	jobsTable := `
+	CREATE TABLE IF NOT EXISTS jobs (
+		id UUID PRIMARY KEY,
+		type TEXT NOT NULL,
+		payload JSONB NOT NULL DEFAULT '{}',
+		user_id UUID,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		max_attempts INTEGER NOT NULL CHECK (max_attempts > 0),
+		run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		locked_until TIMESTAMPTZ,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		completed_at TIMESTAMPTZ
+	);`
+
+	webhooksTable := `
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id UUID PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		url TEXT NOT NULL,
+		events TEXT[] NOT NULL,
+		secret TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`
+
+	webhookDeliveriesTable := `
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id UUID PRIMARY KEY,
+		webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+		event_id UUID NOT NULL,
+		event TEXT NOT NULL,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		duration_ms BIGINT NOT NULL DEFAULT 0,
+		delivered_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 	);`
 
-	if _, err := db.Exec(userTable); err != nil {
-		log.Fatal("Error creating users table:", err)
+	if _, err := conn.ExecContext(ctx, userTable); err != nil {
+		return fmt.Errorf("creating users table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, activitiesTable); err != nil {
+		return fmt.Errorf("creating activities table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, intervalsTable); err != nil {
+		return fmt.Errorf("creating intervals table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, cssTestsTable); err != nil {
+		return fmt.Errorf("creating css_tests table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, auditEventsTable); err != nil {
+		return fmt.Errorf("creating audit_events table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, dataExportsTable); err != nil {
+		return fmt.Errorf("creating data_exports table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, jobsTable); err != nil {
+		return fmt.Errorf("creating jobs table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, webhooksTable); err != nil {
+		return fmt.Errorf("creating webhooks table: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, webhookDeliveriesTable); err != nil {
+		return fmt.Errorf("creating webhook_deliveries table: %w", err)
+	}
+
+	return nil
+}
+
+// migrations add columns introduced after the initial schema to existing databases;
+// New migrations must be appended, as their count is the schema version
+var migrations = []string{
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS max_heart_rate INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS resting_heart_rate INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE activities ADD COLUMN IF NOT EXISTS rpe INTEGER NOT NULL DEFAULT 0 CHECK (rpe BETWEEN 0 AND 10)`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS lactate_threshold_heart_rate INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE intervals ADD COLUMN IF NOT EXISTS heart_rate_avg INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE intervals ADD COLUMN IF NOT EXISTS stroke_count INTEGER NOT NULL DEFAULT 0 CHECK (stroke_count >= 0)`,
+	`ALTER TABLE intervals ADD COLUMN IF NOT EXISTS length_stroke_counts INTEGER[]`,
+	`ALTER TABLE activities ADD COLUMN IF NOT EXISTS pool_unit TEXT NOT NULL DEFAULT 'meters'`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS display_unit TEXT NOT NULL DEFAULT 'meters'`,
+	secondsToMilliseconds("activities", "duration"),
+	secondsToMilliseconds("intervals", "duration"),
+	secondsToMilliseconds("css_tests", "time_400"),
+	secondsToMilliseconds("css_tests", "time_200"),
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE activities ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1`,
+	auditColumns("users"),
+	auditColumns("activities"),
+	auditColumns("intervals"),
+	// Emails only have to be unique among the users that are not deleted
+	`ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email) WHERE deleted_at IS NULL`,
+	`CREATE INDEX IF NOT EXISTS audit_events_user_id ON audit_events (user_id, occurred_at DESC)`,
+	// Deleted users are anonymized past the retention period instead of purged
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ`,
+	// Exports are generated by background jobs
+	`ALTER TABLE data_exports ADD COLUMN IF NOT EXISTS job_id UUID`,
+	// Background jobs are claimed in order of run_at among the queued and expired running jobs
+	`CREATE INDEX IF NOT EXISTS jobs_claimable ON jobs (run_at) WHERE status IN ('queued', 'running')`,
+	`CREATE INDEX IF NOT EXISTS webhooks_user_id ON webhooks (user_id)`,
+	`CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, delivered_at DESC)`,
+	// CSS tests swum in a yards pool have 400yd and 200yd trials
+	`ALTER TABLE css_tests ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'meters'`,
+	// The recent activities of a user are read most recent first
+	`CREATE INDEX IF NOT EXISTS activities_user_id_date ON activities (user_id, date DESC, start DESC) WHERE deleted_at IS NULL`,
+}
+
+// SchemaVersion is the version of the schema this build expects, checked by the readiness probe
+var SchemaVersion = len(migrations)
+
+// migrateTables applies the migrations and records the resulting schema version;
+// The version never decreases, so an older build sharing the database still sees
+// a schema at least as recent as the one it expects
+func migrateTables(ctx context.Context, conn schemaConn) error {
+	for i, migration := range migrations {
+		if _, err := conn.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("applying migration %d: %w", i+1, err)
+		}
+	}
+
+	schemaVersion := []string{
+		`CREATE TABLE IF NOT EXISTS schema_version (
+			id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+			version INTEGER NOT NULL
+		)`,
+		fmt.Sprintf(`INSERT INTO schema_version (version) VALUES (%d)
+			ON CONFLICT (id) DO UPDATE SET version = GREATEST(schema_version.version, EXCLUDED.version)`, SchemaVersion),
+	}
+	for _, statement := range schemaVersion {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("recording schema version: %w", err)
+		}
+	}
+	return nil
+}
+
+// secondsToMilliseconds returns a migration replacing a column of whole seconds,
+// if it still exists, by a "<column>_ms" column holding the same durations in milliseconds
+func secondsToMilliseconds(table, column string) string {
+	return fmt.Sprintf(`
+	DO $$
+	BEGIN
+		IF EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_name = %[3]s AND column_name = %[4]s
+		) THEN
+			ALTER TABLE %[1]s RENAME COLUMN %[2]s TO %[2]s_ms;
+			UPDATE %[1]s SET %[2]s_ms = %[2]s_ms * 1000;
+		END IF;
+	END $$`, table, column, pq.QuoteLiteral(table), pq.QuoteLiteral(column))
+}
+
+// auditColumns returns a migration adding the creation, last update and soft
+// delete timestamps to a table; Existing rows are considered created by the migration
+func auditColumns(table string) string {
+	return fmt.Sprintf(`
+	ALTER TABLE %s
+		ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`, pq.QuoteIdentifier(table))
+}
+
+// enumColumns lists the columns restricted to the values of a domain enum
+var enumColumns = []struct {
+	table, column string
+	enum          interface{ Values() []string }
+}{
+	{"users", "display_unit", domain.DistanceUnit("")},
+	{"activities", "location_type", domain.LocationType("")},
+	{"activities", "feeling", domain.FeelingType("")},
+	{"activities", "pool_unit", domain.DistanceUnit("")},
+	{"css_tests", "unit", domain.DistanceUnit("")},
+	{"intervals", "type", domain.IntervalType("")},
+	{"intervals", "stroke", domain.StrokeType("")},
+	{"audit_events", "entity_type", domain.AuditEntityType("")},
+	{"audit_events", "action", domain.AuditAction("")},
+	{"data_exports", "status", domain.ExportStatus("")},
+	{"jobs", "status", domain.JobStatus("")},
+	{"webhook_deliveries", "event", domain.WebhookEvent("")},
+}
+
+// syncEnumConstraints recreates the CHECK constraint of each enum column whose values
+// differ from those defined in the domain, so adding a value only requires changing the domain
+func syncEnumConstraints(ctx context.Context, conn schemaConn) error {
+	for _, c := range enumColumns {
+		values := c.enum.Values()
+
+		var current string
+		err := conn.QueryRowContext(ctx,
+			`SELECT pg_get_constraintdef(oid) FROM pg_constraint WHERE conrelid = $1::regclass AND conname = $2`,
+			c.table, enumConstraintName(c.table, c.column),
+		).Scan(&current)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("reading the constraint of %s.%s: %w", c.table, c.column, err)
+		}
+		if current == enumConstraintDefinition(c.column, values) {
+			continue
+		}
+
+		if _, err := conn.ExecContext(ctx, enumConstraint(c.table, c.column, values)); err != nil {
+			return fmt.Errorf("syncing the constraint of %s.%s: %w", c.table, c.column, err)
+		}
 	}
-	if _, err := db.Exec(activitiesTable); err != nil {
-		log.Fatal("Error creating activities table:", err)
+	return nil
+}
+
+// enumConstraintName returns the name of the CHECK constraint of an enum column, the
+// default Postgres name for column constraints, e.g., "intervals_stroke_check"
+func enumConstraintName(table, column string) string {
+	return table + "_" + column + "_check"
+}
+
+// enumConstraintDefinition returns the definition of the CHECK constraint of an enum
+// column the way pg_get_constraintdef reports it, e.g.,
+// "CHECK ((stroke = ANY (ARRAY['freestyle'::text, 'backstroke'::text])))"
+func enumConstraintDefinition(column string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = pq.QuoteLiteral(value) + "::text"
 	}
-	if _, err := db.Exec(intervalsTable); err != nil {
-		log.Fatal("Error creating intervals table:", err)
+
+	return fmt.Sprintf("CHECK ((%s = ANY (ARRAY[%s])))", column, strings.Join(quoted, ", "))
+}
+
+// enumConstraint returns the statement replacing the CHECK constraint of a column
+func enumConstraint(table, column string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = pq.QuoteLiteral(value)
 	}
+
+	name := pq.QuoteIdentifier(enumConstraintName(table, column))
+	return fmt.Sprintf(
+		"ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s, ADD CONSTRAINT %s CHECK (%s IN (%s))",
+		pq.QuoteIdentifier(table), name, name, pq.QuoteIdentifier(column), strings.Join(quoted, ", "),
+	)
 }
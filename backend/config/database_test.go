@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+func TestEnumConstraint(t *testing.T) {
+	got := enumConstraint("activities", "pool_unit", domain.DistanceUnit("").Values())
+	expected := `ALTER TABLE "activities" DROP CONSTRAINT IF EXISTS "activities_pool_unit_check", ` +
+		`ADD CONSTRAINT "activities_pool_unit_check" CHECK ("pool_unit" IN ('meters', 'yards'))`
+
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestEnumConstraintDefinition(t *testing.T) {
+	got := enumConstraintDefinition("pool_unit", domain.DistanceUnit("").Values())
+	expected := `CHECK ((pool_unit = ANY (ARRAY['meters'::text, 'yards'::text])))`
+
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSyncEnumConstraints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Only the constraints that are missing or have other values are recreated
+	for i, c := range enumColumns {
+		query := mock.ExpectQuery(`SELECT pg_get_constraintdef\(oid\) FROM pg_constraint`).
+			WithArgs(c.table, enumConstraintName(c.table, c.column))
+		switch i {
+		case 0:
+			query.WillReturnError(sql.ErrNoRows)
+			mock.ExpectExec(`ALTER TABLE "` + c.table + `"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		case 1:
+			query.WillReturnRows(sqlmock.NewRows([]string{"pg_get_constraintdef"}).
+				AddRow(enumConstraintDefinition(c.column, c.enum.Values()[:1])))
+			mock.ExpectExec(`ALTER TABLE "` + c.table + `"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		default:
+			query.WillReturnRows(sqlmock.NewRows([]string{"pg_get_constraintdef"}).
+				AddRow(enumConstraintDefinition(c.column, c.enum.Values())))
+		}
+	}
+
+	if err := syncEnumConstraints(context.Background(), db); err != nil {
+		t.Fatalf("syncEnumConstraints() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitForDatabase(t *testing.T) {
+	unreachable := errors.New("connection refused")
+
+	t.Run("retries until reachable", func(t *testing.T) {
+		attempts := 0
+		ping := func(context.Context) error {
+			if attempts++; attempts < 3 {
+				return unreachable
+			}
+			return nil
+		}
+
+		if err := waitForDatabase(context.Background(), ping, time.Second, time.Millisecond); err != nil {
+			t.Fatalf("waitForDatabase() error = %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("gives up after the maximum wait", func(t *testing.T) {
+		start := time.Now()
+		ping := func(context.Context) error { return unreachable }
+
+		err := waitForDatabase(context.Background(), ping, 50*time.Millisecond, time.Millisecond)
+		if !errors.Is(err, unreachable) {
+			t.Errorf("expected the last ping error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected to give up after about 50ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("stops when canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := waitForDatabase(ctx, func(context.Context) error { return unreachable }, time.Minute, time.Minute)
+		if !errors.Is(err, unreachable) {
+			t.Errorf("expected the last ping error, got %v", err)
+		}
+	})
+}
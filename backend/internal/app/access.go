@@ -0,0 +1,22 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// authorizeUser fails unless the request is made by the user owning the resource
+// or by an admin; resource names what is accessed in messages, e.g., "audit log"
+func authorizeUser(ctx context.Context, userID uuid.UUID, resource string) error {
+	actor := auth.FromContext(ctx)
+	if !actor.Authenticated() {
+		return domain.NewUnauthorizedError("authentication required to access the " + resource)
+	}
+	if !actor.CanAccess(userID) {
+		return domain.NewForbiddenError("only the user and admins may access the user's " + resource)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 	"github.com/liviaruegger/MAC0350/backend/internal/entity"
 	"github.com/liviaruegger/MAC0350/backend/internal/mapper"
@@ -10,62 +12,247 @@ import (
 )
 
 type ActivityService interface {
-	CreateActivity(activity domain.Activity) error
-	GetAllActivities() ([]domain.Activity, error)
-	GetActivitiesByUser(userID uuid.UUID) ([]entity.Activity, error)
-	GetActivityByID(activityID uuid.UUID) (domain.Activity, error)
-	UpdateActivity(activity domain.Activity) error
-	DeleteActivity(activityID uuid.UUID) error
+	CreateActivity(ctx context.Context, activity domain.Activity) error
+	GetAllActivities(ctx context.Context) ([]domain.Activity, error)
+	GetActivitiesByUser(ctx context.Context, userID uuid.UUID) ([]entity.Activity, error)
+	GetActivityByID(ctx context.Context, activityID uuid.UUID) (entity.Activity, error)
+	GetActivitiesByIDs(ctx context.Context, activityIDs []uuid.UUID) ([]entity.Activity, error)
+	GetRecentActivitiesByUser(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Activity, error)
+	UpdateActivity(ctx context.Context, activity domain.Activity) error
+	DeleteActivity(ctx context.Context, activityID uuid.UUID) error
 }
 
 type activityService struct {
 	repo         repository.ActivityRepository
 	intervalRepo repository.IntervalRepository
+	cssRepo      repository.CSSRepository
+	userRepo     repository.UserRepository
+	audit        auditLog
+	webhooks     webhookEvents
+	stream       userEvents
 }
 
-// NewActivityService creates a new ActivityService
-func NewActivityService(r repository.ActivityRepository, intervalRepo repository.IntervalRepository) *activityService {
+// NewActivityService creates a new ActivityService; changes to activities are recorded in the audit log,
+// published to the webhooks of their user, delivered by jobs enqueued on queue, and to the event stream
+func NewActivityService(r repository.ActivityRepository, intervalRepo repository.IntervalRepository, cssRepo repository.CSSRepository, userRepo repository.UserRepository,
+	audit repository.AuditRepository, webhooks repository.WebhookRepository, queue JobQueue, stream EventStream) *activityService {
 	return &activityService{
 		repo:         r,
 		intervalRepo: intervalRepo,
+		cssRepo:      cssRepo,
+		userRepo:     userRepo,
+		audit:        auditLog{repo: audit},
+		webhooks:     webhookEvents{repo: webhooks, queue: queue},
+		stream:       userEvents{stream: stream},
+	}
+}
+
+func (s *activityService) CreateActivity(ctx context.Context, activity domain.Activity) error {
+	err := s.audit.change(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateActivity(ctx, activity); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, activity.UserID, domain.AuditEntityActivity, activity.ID, domain.AuditActionCreate, nil, activity)
+	})
+	if err != nil {
+		return err
 	}
+
+	s.webhooks.publish(ctx, activity.UserID, domain.WebhookActivityCreated, activity)
+	s.stream.publish(ctx, activity.UserID, domain.WebhookActivityCreated, activity)
+	s.publishRecords(ctx, activity)
+	return nil
 }
 
-func (s *activityService) CreateActivity(activity domain.Activity) error {
-	return s.repo.CreateActivity(activity)
+// publishRecords publishes the personal records achieved by a new activity, if
+// any webhook of its user subscribes to them
+func (s *activityService) publishRecords(ctx context.Context, activity domain.Activity) {
+	subscribers := s.webhooks.subscribers(ctx, activity.UserID, domain.WebhookRecordAchieved)
+	if len(subscribers) == 0 {
+		return
+	}
+
+	activities, err := s.repo.GetActivitiesByUser(ctx, activity.UserID)
+	if err != nil {
+		s.webhooks.fail(ctx, domain.WebhookRecordAchieved, err)
+		return
+	}
+	if record, ok := domain.LongestDistanceRecord(activity, activities); ok {
+		s.webhooks.deliver(ctx, subscribers, domain.WebhookRecordAchieved, record)
+	}
 }
 
-func (s *activityService) GetAllActivities() ([]domain.Activity, error) {
-	return s.repo.GetAllActivities()
+func (s *activityService) GetAllActivities(ctx context.Context) ([]domain.Activity, error) {
+	return s.repo.GetAllActivities(ctx)
 }
 
 // GetActivitiesByUser retrieves all activities and intervals for a specific user
-func (s *activityService) GetActivitiesByUser(userID uuid.UUID) ([]entity.Activity, error) {
-	activitiesDomain, err := s.repo.GetActivitiesByUser(userID)
+func (s *activityService) GetActivitiesByUser(ctx context.Context, userID uuid.UUID) ([]entity.Activity, error) {
+	activitiesDomain, err := s.repo.GetActivitiesByUser(ctx, userID)
 	if err != nil {
 		return []entity.Activity{}, err
 	}
+	if len(activitiesDomain) == 0 {
+		return []entity.Activity{}, nil
+	}
+
+	return s.mapActivities(ctx, userID, activitiesDomain)
+}
+
+// GetActivityByID retrieves a single activity with its intervals
+func (s *activityService) GetActivityByID(ctx context.Context, activityID uuid.UUID) (entity.Activity, error) {
+	activity, err := s.repo.GetActivityByID(ctx, activityID)
+	if err != nil {
+		return entity.Activity{}, err
+	}
 
-	activitiesEntity := make([]entity.Activity, len(activitiesDomain))
-	for i, activity := range activitiesDomain {
-		intervals, err := s.intervalRepo.GetIntervalsByActivity(activity.ID)
+	activities, err := s.mapActivities(ctx, activity.UserID, []domain.Activity{activity})
+	if err != nil {
+		return entity.Activity{}, err
+	}
+
+	return activities[0], nil
+}
+
+// GetActivitiesByIDs retrieves several activities with their intervals, loading the intervals
+// of all of them at once, e.g., for the activities of a GraphQL query; Unknown activities are left out
+func (s *activityService) GetActivitiesByIDs(ctx context.Context, activityIDs []uuid.UUID) ([]entity.Activity, error) {
+	activities, err := s.repo.GetActivitiesByIDs(ctx, activityIDs)
+	if err != nil {
+		return []entity.Activity{}, err
+	}
+
+	// Each user's activities are mapped along with the user's CSS tests and preferences
+	byUser := make(map[uuid.UUID][]domain.Activity)
+	var userIDs []uuid.UUID
+	for _, activity := range activities {
+		if _, ok := byUser[activity.UserID]; !ok {
+			userIDs = append(userIDs, activity.UserID)
+		}
+		byUser[activity.UserID] = append(byUser[activity.UserID], activity)
+	}
+
+	mapped := make([]entity.Activity, 0, len(activities))
+	for _, userID := range userIDs {
+		userActivities, err := s.mapActivities(ctx, userID, byUser[userID])
 		if err != nil {
 			return []entity.Activity{}, err
 		}
-		activitiesEntity[i] = mapper.MapActivityToEntity(activity, intervals)
+		mapped = append(mapped, userActivities...)
 	}
 
-	return activitiesEntity, nil
+	return mapped, nil
 }
 
-func (s *activityService) GetActivityByID(activityID uuid.UUID) (domain.Activity, error) {
-	return s.repo.GetActivityByID(activityID)
+// GetRecentActivitiesByUser retrieves the most recent activities of a user, up to limit, without
+// their intervals nor the metrics derived from them, which GetActivitiesByIDs loads when needed
+func (s *activityService) GetRecentActivitiesByUser(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Activity, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return []entity.Activity{}, err
+	}
+
+	activities, err := s.repo.GetRecentActivitiesByUser(ctx, userID, limit)
+	if err != nil {
+		return []entity.Activity{}, err
+	}
+
+	mapped := make([]entity.Activity, len(activities))
+	for i, activity := range activities {
+		mapped[i] = mapper.MapActivityToEntity(activity, nil, nil, user.DisplayUnit)
+	}
+	return mapped, nil
+}
+
+// mapActivities loads the intervals of the given activities of a user, in a single query, and maps them to entities,
+// annotated with the pace zones of the CSS test in effect on each activity date and with the
+// time spent in each of the user's heart-rate zones; distances and paces are displayed in the
+// user's preferred unit
+func (s *activityService) mapActivities(ctx context.Context, userID uuid.UUID, activities []domain.Activity) ([]entity.Activity, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return []entity.Activity{}, err
+	}
+
+	cssTests, err := s.cssRepo.GetCSSTestsByUser(ctx, userID)
+	if err != nil {
+		return []entity.Activity{}, err
+	}
+
+	activityIDs := make([]uuid.UUID, len(activities))
+	for i, activity := range activities {
+		activityIDs[i] = activity.ID
+	}
+	intervalsByActivity, err := s.intervalRepo.GetIntervalsByActivities(ctx, activityIDs)
+	if err != nil {
+		return []entity.Activity{}, err
+	}
+
+	heartRateZones := user.HeartRateZones()
+	activitiesEntity := make([]entity.Activity, len(activities))
+	for i, activity := range activities {
+		intervals := intervalsByActivity[activity.ID]
+
+		var paceZones []domain.PaceZone
+		if test, ok := domain.CSSTestAt(cssTests, activity.Day().Format(domain.DateLayout)); ok {
+			paceZones = test.Zones()
+		}
+		activitiesEntity[i] = mapper.MapActivityToEntity(activity, intervals, paceZones, user.DisplayUnit)
+
+		if times := domain.TimeInZones(activity, intervals, heartRateZones); domain.TotalZoneTime(times) > 0 {
+			activitiesEntity[i].TimeInZone = mapper.MapZoneTimesToEntity(times)
+		}
+	}
+
+	return activitiesEntity, nil
 }
 
-func (s *activityService) UpdateActivity(activity domain.Activity) error {
-	return s.repo.UpdateActivity(activity)
+func (s *activityService) UpdateActivity(ctx context.Context, activity domain.Activity) error {
+	var after domain.Activity
+	err := s.audit.change(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetActivityByID(ctx, activity.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.UpdateActivity(ctx, activity); err != nil {
+			return err
+		}
+
+		after, err = s.repo.GetActivityByID(ctx, activity.ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.record(ctx, after.UserID, domain.AuditEntityActivity, activity.ID, domain.AuditActionUpdate, before, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.webhooks.publish(ctx, after.UserID, domain.WebhookActivityUpdated, after)
+	s.stream.publish(ctx, after.UserID, domain.WebhookActivityUpdated, after)
+	return nil
 }
 
-func (s *activityService) DeleteActivity(activityID uuid.UUID) error {
-	return s.repo.DeleteActivity(activityID)
+// DeleteActivity deletes an activity along with its intervals; the deletion is
+// recorded once, for the activity
+func (s *activityService) DeleteActivity(ctx context.Context, activityID uuid.UUID) error {
+	var before domain.Activity
+	err := s.audit.change(ctx, func(ctx context.Context) error {
+		var err error
+		before, err = s.repo.GetActivityByID(ctx, activityID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteActivity(ctx, activityID); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, before.UserID, domain.AuditEntityActivity, activityID, domain.AuditActionDelete, before, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.webhooks.publish(ctx, before.UserID, domain.WebhookActivityDeleted, before)
+	s.stream.publish(ctx, before.UserID, domain.WebhookActivityDeleted, before)
+	return nil
 }
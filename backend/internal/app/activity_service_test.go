@@ -1,12 +1,15 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/liviaruegger/MAC0350/backend/internal/events"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -16,32 +19,42 @@ type MockActivityRepository struct {
 	mock.Mock
 }
 
-func (m *MockActivityRepository) CreateActivity(activity domain.Activity) error {
+func (m *MockActivityRepository) CreateActivity(ctx context.Context, activity domain.Activity) error {
 	args := m.Called(activity)
 	return args.Error(0)
 }
 
-func (m *MockActivityRepository) GetAllActivities() ([]domain.Activity, error) {
+func (m *MockActivityRepository) GetAllActivities(ctx context.Context) ([]domain.Activity, error) {
 	args := m.Called()
 	return args.Get(0).([]domain.Activity), args.Error(1)
 }
 
-func (m *MockActivityRepository) GetActivitiesByUser(userID uuid.UUID) ([]domain.Activity, error) {
+func (m *MockActivityRepository) GetActivitiesByUser(ctx context.Context, userID uuid.UUID) ([]domain.Activity, error) {
 	args := m.Called(userID)
 	return args.Get(0).([]domain.Activity), args.Error(1)
 }
 
-func (m *MockActivityRepository) GetActivityByID(activityID uuid.UUID) (domain.Activity, error) {
+func (m *MockActivityRepository) GetRecentActivitiesByUser(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Activity, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]domain.Activity), args.Error(1)
+}
+
+func (m *MockActivityRepository) GetActivityByID(ctx context.Context, activityID uuid.UUID) (domain.Activity, error) {
 	args := m.Called(activityID)
 	return args.Get(0).(domain.Activity), args.Error(1)
 }
 
-func (m *MockActivityRepository) UpdateActivity(activity domain.Activity) error {
+func (m *MockActivityRepository) GetActivitiesByIDs(ctx context.Context, activityIDs []uuid.UUID) ([]domain.Activity, error) {
+	args := m.Called(activityIDs)
+	return args.Get(0).([]domain.Activity), args.Error(1)
+}
+
+func (m *MockActivityRepository) UpdateActivity(ctx context.Context, activity domain.Activity) error {
 	args := m.Called(activity)
 	return args.Error(0)
 }
 
-func (m *MockActivityRepository) DeleteActivity(activityID uuid.UUID) error {
+func (m *MockActivityRepository) DeleteActivity(ctx context.Context, activityID uuid.UUID) error {
 	args := m.Called(activityID)
 	return args.Error(0)
 }
@@ -50,25 +63,38 @@ type MockIntervalRepository struct {
 	mock.Mock
 }
 
-func (m *MockIntervalRepository) CreateInterval(interval domain.Interval) error {
+func (m *MockIntervalRepository) CreateInterval(ctx context.Context, interval domain.Interval) error {
 	args := m.Called(interval)
 	return args.Error(0)
 }
 
-func (m *MockIntervalRepository) GetIntervalsByActivity(activityID uuid.UUID) ([]domain.Interval, error) {
+func (m *MockIntervalRepository) GetIntervalsByActivity(ctx context.Context, activityID uuid.UUID) ([]domain.Interval, error) {
 	args := m.Called(activityID)
 	return args.Get(0).([]domain.Interval), args.Error(1)
 }
 
+func (m *MockIntervalRepository) GetIntervalsByActivities(ctx context.Context, activityIDs []uuid.UUID) (map[uuid.UUID][]domain.Interval, error) {
+	args := m.Called(activityIDs)
+	if raw := args.Get(0); raw != nil {
+		return raw.(map[uuid.UUID][]domain.Interval), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIntervalRepository) GetIntervalByID(ctx context.Context, intervalID uuid.UUID) (domain.Interval, error) {
+	args := m.Called(intervalID)
+	return args.Get(0).(domain.Interval), args.Error(1)
+}
+
 func TestCreateActivity(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activity := domain.Activity{
 		ID:           uuid.New(),
 		UserID:       uuid.New(),
 		Start:        time.Now(),
-		Duration:     domain.DurationString("1h30m"),
+		Duration:     domain.MustParseDuration("1h30m"),
 		Distance:     4000,
 		Laps:         160,
 		PoolSize:     25,
@@ -78,7 +104,7 @@ func TestCreateActivity(t *testing.T) {
 
 	mockRepo.On("CreateActivity", activity).Return(nil)
 
-	err := service.CreateActivity(activity)
+	err := service.CreateActivity(context.Background(), activity)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -86,12 +112,12 @@ func TestCreateActivity(t *testing.T) {
 func TestCreateActivity_Error(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activity := domain.Activity{
 		ID:           uuid.New(),
 		UserID:       uuid.New(),
 		Start:        time.Now(),
-		Duration:     domain.DurationString("1h30m"),
+		Duration:     domain.MustParseDuration("1h30m"),
 		Distance:     4000,
 		Laps:         160,
 		PoolSize:     25,
@@ -101,7 +127,7 @@ func TestCreateActivity_Error(t *testing.T) {
 
 	mockRepo.On("CreateActivity", activity).Return(errors.New("db error"))
 
-	err := service.CreateActivity(activity)
+	err := service.CreateActivity(context.Background(), activity)
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -109,13 +135,13 @@ func TestCreateActivity_Error(t *testing.T) {
 func TestGetAllActivities(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activities := []domain.Activity{
 		{
 			ID:           uuid.New(),
 			UserID:       uuid.New(),
 			Start:        time.Now(),
-			Duration:     domain.DurationString("1h30m"),
+			Duration:     domain.MustParseDuration("1h30m"),
 			Distance:     4000,
 			Laps:         160,
 			PoolSize:     25,
@@ -126,7 +152,7 @@ func TestGetAllActivities(t *testing.T) {
 			ID:           uuid.New(),
 			UserID:       uuid.New(),
 			Start:        time.Now().Add(-time.Hour),
-			Duration:     domain.DurationString("45m"),
+			Duration:     domain.MustParseDuration("45m"),
 			Distance:     2000,
 			Laps:         80,
 			PoolSize:     25,
@@ -137,7 +163,7 @@ func TestGetAllActivities(t *testing.T) {
 
 	mockRepo.On("GetAllActivities").Return(activities, nil)
 
-	result, err := service.GetAllActivities()
+	result, err := service.GetAllActivities(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, activities, result)
 	mockRepo.AssertExpectations(t)
@@ -146,11 +172,11 @@ func TestGetAllActivities(t *testing.T) {
 func TestGetAllActivities_Error(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 
 	mockRepo.On("GetAllActivities").Return([]domain.Activity{}, errors.New("db error"))
 
-	result, err := service.GetAllActivities()
+	result, err := service.GetAllActivities(context.Background())
 	assert.Error(t, err)
 	assert.Empty(t, result)
 	mockRepo.AssertExpectations(t)
@@ -159,20 +185,25 @@ func TestGetAllActivities_Error(t *testing.T) {
 func TestGetActivitiesByUser(t *testing.T) {
 	mockActivityRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
+	mockCSSRepo := new(MockCSSRepository)
 
 	service := &activityService{
 		repo:         mockActivityRepo,
 		intervalRepo: mockIntervalRepo,
+		cssRepo:      mockCSSRepo,
 	}
 
 	userID := uuid.New()
+	service.userRepo = &mockUserRepo{users: map[uuid.UUID]domain.User{
+		userID: {ID: userID, Age: 30},
+	}}
 	activityID := uuid.New()
 	activities := []domain.Activity{
 		{
 			ID:           activityID,
 			UserID:       userID,
 			Start:        time.Now(),
-			Duration:     domain.DurationString("1h"),
+			Duration:     domain.MustParseDuration("1h"),
 			Distance:     1000,
 			Laps:         40,
 			PoolSize:     25,
@@ -182,33 +213,61 @@ func TestGetActivitiesByUser(t *testing.T) {
 	}
 	intervals := []domain.Interval{
 		{
-			ID:         uuid.New(),
-			ActivityID: activityID,
-			Duration:   domain.DurationString("30m"),
-			Distance:   500,
-			Type:       domain.IntervalType("swim"),
-			Stroke:     domain.StrokeType("freestyle"),
-			Notes:      "Test interval",
+			ID:           uuid.New(),
+			ActivityID:   activityID,
+			Duration:     domain.MustParseDuration("30m"),
+			Distance:     500,
+			Type:         domain.IntervalType("swim"),
+			Stroke:       domain.StrokeType("freestyle"),
+			Notes:        "Test interval",
+			HeartRateAvg: 120,
 		},
 	}
 
 	t.Run("success", func(t *testing.T) {
 		mockActivityRepo.On("GetActivitiesByUser", userID).Return(activities, nil)
-		mockIntervalRepo.On("GetIntervalsByActivity", activityID).Return(intervals, nil)
+		mockCSSRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{}, nil)
+		mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{activityID}).Return(map[uuid.UUID][]domain.Interval{activityID: intervals}, nil)
 
-		result, err := service.GetActivitiesByUser(userID)
+		result, err := service.GetActivitiesByUser(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
+		assert.Zero(t, result[0].Intervals[0].PaceZone)
+		assert.Equal(t, []entity.TimeInZone{
+			{Zone: 1, Name: "recovery", Duration: domain.MustParseDuration("0s")},
+			{Zone: 2, Name: "aerobic", Duration: domain.MustParseDuration("30m0s")},
+			{Zone: 3, Name: "tempo", Duration: domain.MustParseDuration("0s")},
+			{Zone: 4, Name: "threshold", Duration: domain.MustParseDuration("0s")},
+			{Zone: 5, Name: "anaerobic", Duration: domain.MustParseDuration("0s")},
+		}, result[0].TimeInZone)
 		mockActivityRepo.AssertExpectations(t)
 		mockIntervalRepo.AssertExpectations(t)
 	})
 
+	t.Run("with CSS pace zones", func(t *testing.T) {
+		mockActivityRepo.ExpectedCalls = nil
+		mockIntervalRepo.ExpectedCalls = nil
+		mockCSSRepo.ExpectedCalls = nil
+
+		mockActivityRepo.On("GetActivitiesByUser", userID).Return(activities, nil)
+		mockCSSRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{
+			{UserID: userID, Time400: domain.MustParseDuration("6m0s"), Time200: domain.MustParseDuration("2m0s")},
+		}, nil)
+		mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{activityID}).Return(map[uuid.UUID][]domain.Interval{activityID: intervals}, nil)
+
+		result, err := service.GetActivitiesByUser(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result[0].Intervals[0].PaceZone)
+		assert.Equal(t, "recovery", result[0].Intervals[0].PaceZoneName)
+		mockCSSRepo.AssertExpectations(t)
+	})
+
 	t.Run("activity repo error", func(t *testing.T) {
 		mockActivityRepo.ExpectedCalls = nil
 		mockIntervalRepo.ExpectedCalls = nil
 		mockActivityRepo.On("GetActivitiesByUser", userID).Return([]domain.Activity{}, errors.New("repo error"))
 
-		result, err := service.GetActivitiesByUser(userID)
+		result, err := service.GetActivitiesByUser(context.Background(), userID)
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		mockActivityRepo.AssertExpectations(t)
@@ -217,11 +276,13 @@ func TestGetActivitiesByUser(t *testing.T) {
 	t.Run("interval repo error", func(t *testing.T) {
 		mockActivityRepo.ExpectedCalls = nil
 		mockIntervalRepo.ExpectedCalls = nil
+		mockCSSRepo.ExpectedCalls = nil
 
 		mockActivityRepo.On("GetActivitiesByUser", userID).Return(activities, nil)
-		mockIntervalRepo.On("GetIntervalsByActivity", activityID).Return([]domain.Interval{}, errors.New("interval error"))
+		mockCSSRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{}, nil)
+		mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{activityID}).Return(nil, errors.New("interval error"))
 
-		result, err := service.GetActivitiesByUser(userID)
+		result, err := service.GetActivitiesByUser(context.Background(), userID)
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		mockActivityRepo.AssertExpectations(t)
@@ -232,13 +293,17 @@ func TestGetActivitiesByUser(t *testing.T) {
 func TestGetActivityByID(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	mockCSSRepo := new(MockCSSRepository)
+	userID := uuid.New()
+	service := NewActivityService(mockRepo, mockIntervalRepo, mockCSSRepo, &mockUserRepo{users: map[uuid.UUID]domain.User{
+		userID: {ID: userID},
+	}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activityID := uuid.New()
 	activity := domain.Activity{
-		ID:           uuid.New(),
-		UserID:       uuid.New(),
+		ID:           activityID,
+		UserID:       userID,
 		Start:        time.Now(),
-		Duration:     domain.DurationString("1h30m"),
+		Duration:     domain.MustParseDuration("1h30m"),
 		Distance:     4000,
 		Laps:         160,
 		PoolSize:     25,
@@ -247,36 +312,42 @@ func TestGetActivityByID(t *testing.T) {
 	}
 
 	mockRepo.On("GetActivityByID", activityID).Return(activity, nil)
+	mockCSSRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{}, nil)
+	mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{activityID}).Return(map[uuid.UUID][]domain.Interval{}, nil)
 
-	result, err := service.GetActivityByID(activityID)
+	result, err := service.GetActivityByID(context.Background(), activityID)
 	assert.NoError(t, err)
-	assert.Equal(t, activity, result)
+	assert.Equal(t, activityID, result.ID)
+	assert.Equal(t, 4000.0, result.Distance)
+	assert.Empty(t, result.TimeInZone)
 	mockRepo.AssertExpectations(t)
+	mockIntervalRepo.AssertExpectations(t)
 }
 
 func TestGetActivityByID_Error(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activityID := uuid.New()
 
 	mockRepo.On("GetActivityByID", activityID).Return(domain.Activity{}, errors.New("not found"))
 
-	result, err := service.GetActivityByID(activityID)
+	result, err := service.GetActivityByID(context.Background(), activityID)
 	assert.Error(t, err)
-	assert.Equal(t, domain.Activity{}, result)
+	assert.Equal(t, entity.Activity{}, result)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUpdateActivity(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	audit := &mockAuditRepo{}
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, audit, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activity := domain.Activity{
 		ID:           uuid.New(),
 		UserID:       uuid.New(),
 		Start:        time.Now(),
-		Duration:     domain.DurationString("1h30m"),
+		Duration:     domain.MustParseDuration("1h30m"),
 		Distance:     4000,
 		Laps:         160,
 		PoolSize:     25,
@@ -284,22 +355,29 @@ func TestUpdateActivity(t *testing.T) {
 		Notes:        "Test activity",
 	}
 
+	before := activity
+	before.Notes = "Old notes"
+	mockRepo.On("GetActivityByID", activity.ID).Return(before, nil).Once()
 	mockRepo.On("UpdateActivity", activity).Return(nil)
+	mockRepo.On("GetActivityByID", activity.ID).Return(activity, nil).Once()
 
-	err := service.UpdateActivity(activity)
+	err := service.UpdateActivity(context.Background(), activity)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+	assert.Len(t, audit.events, 1)
+	assert.Equal(t, activity.UserID, audit.events[0].UserID)
+	assert.Equal(t, map[string]domain.FieldChange{"notes": {Before: "Old notes", After: "Test activity"}}, audit.events[0].Changes)
 }
 
 func TestUpdateActivity_Error(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activity := domain.Activity{
 		ID:           uuid.New(),
 		UserID:       uuid.New(),
 		Start:        time.Now(),
-		Duration:     domain.DurationString("1h30m"),
+		Duration:     domain.MustParseDuration("1h30m"),
 		Distance:     4000,
 		Laps:         160,
 		PoolSize:     25,
@@ -307,9 +385,10 @@ func TestUpdateActivity_Error(t *testing.T) {
 		Notes:        "Test activity",
 	}
 
+	mockRepo.On("GetActivityByID", activity.ID).Return(activity, nil)
 	mockRepo.On("UpdateActivity", activity).Return(errors.New("update error"))
 
-	err := service.UpdateActivity(activity)
+	err := service.UpdateActivity(context.Background(), activity)
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -317,12 +396,13 @@ func TestUpdateActivity_Error(t *testing.T) {
 func TestDeleteActivity(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activityID := uuid.New()
 
+	mockRepo.On("GetActivityByID", activityID).Return(domain.Activity{ID: activityID, UserID: uuid.New()}, nil)
 	mockRepo.On("DeleteActivity", activityID).Return(nil)
 
-	err := service.DeleteActivity(activityID)
+	err := service.DeleteActivity(context.Background(), activityID)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -330,12 +410,91 @@ func TestDeleteActivity(t *testing.T) {
 func TestDeleteActivity_Error(t *testing.T) {
 	mockRepo := new(MockActivityRepository)
 	mockIntervalRepo := new(MockIntervalRepository)
-	service := NewActivityService(mockRepo, mockIntervalRepo)
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
 	activityID := uuid.New()
 
+	mockRepo.On("GetActivityByID", activityID).Return(domain.Activity{ID: activityID, UserID: uuid.New()}, nil)
 	mockRepo.On("DeleteActivity", activityID).Return(errors.New("delete error"))
 
-	err := service.DeleteActivity(activityID)
+	err := service.DeleteActivity(context.Background(), activityID)
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetActivitiesByIDs(t *testing.T) {
+	mockRepo := new(MockActivityRepository)
+	mockIntervalRepo := new(MockIntervalRepository)
+	mockCSSRepo := new(MockCSSRepository)
+	swimmer, coach := uuid.New(), uuid.New()
+	service := NewActivityService(mockRepo, mockIntervalRepo, mockCSSRepo, &mockUserRepo{users: map[uuid.UUID]domain.User{
+		swimmer: {ID: swimmer},
+		coach:   {ID: coach, DisplayUnit: domain.DistanceUnitYards},
+	}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
+
+	first := domain.Activity{ID: uuid.New(), UserID: swimmer, Distance: 1000}
+	second := domain.Activity{ID: uuid.New(), UserID: coach, Distance: 1000}
+	third := domain.Activity{ID: uuid.New(), UserID: swimmer, Distance: 2000}
+	ids := []uuid.UUID{first.ID, second.ID, third.ID, uuid.New()}
+	interval := domain.Interval{ID: uuid.New(), ActivityID: third.ID, Duration: domain.MustParseDuration("1m30s"), Distance: 100}
+
+	mockRepo.On("GetActivitiesByIDs", ids).Return([]domain.Activity{first, second, third}, nil)
+	mockCSSRepo.On("GetCSSTestsByUser", swimmer).Return([]domain.CSSTest{}, nil)
+	mockCSSRepo.On("GetCSSTestsByUser", coach).Return([]domain.CSSTest{}, nil)
+	// The intervals are loaded once per user, whatever the number of activities
+	mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{first.ID, third.ID}).
+		Return(map[uuid.UUID][]domain.Interval{third.ID: {interval}}, nil).Once()
+	mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{second.ID}).
+		Return(map[uuid.UUID][]domain.Interval{}, nil).Once()
+
+	result, err := service.GetActivitiesByIDs(context.Background(), ids)
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	byID := make(map[uuid.UUID]entity.Activity)
+	for _, activity := range result {
+		byID[activity.ID] = activity
+	}
+	assert.Empty(t, byID[first.ID].Intervals)
+	assert.Len(t, byID[third.ID].Intervals, 1)
+	assert.Equal(t, domain.DistanceUnitYards, byID[second.ID].DistanceUnit)
+	mockIntervalRepo.AssertExpectations(t)
+}
+
+func TestGetRecentActivitiesByUser(t *testing.T) {
+	mockRepo := new(MockActivityRepository)
+	mockIntervalRepo := new(MockIntervalRepository)
+	userID := uuid.New()
+	service := NewActivityService(mockRepo, mockIntervalRepo, new(MockCSSRepository), &mockUserRepo{users: map[uuid.UUID]domain.User{
+		userID: {ID: userID},
+	}}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, events.NewBroker(nil))
+
+	// The backfilled activity, swum first, was logged last; The repository orders by date
+	day := time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)
+	latest := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2026-10-03", Start: day.AddDate(0, 0, 2), CreatedAt: day.AddDate(0, 0, 2)}
+	middle := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2026-10-02", Start: day.AddDate(0, 0, 1), CreatedAt: day.AddDate(0, 0, 1)}
+	backfilled := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2026-09-01", Start: day.AddDate(0, -1, 0), CreatedAt: day.AddDate(0, 0, 3)}
+	mockRepo.On("GetRecentActivitiesByUser", userID, 2).Return([]domain.Activity{latest, middle}, nil)
+	mockRepo.On("GetRecentActivitiesByUser", userID, 10).Return([]domain.Activity{latest, middle, backfilled}, nil)
+
+	t.Run("most recent first", func(t *testing.T) {
+		result, err := service.GetRecentActivitiesByUser(context.Background(), userID, 2)
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, latest.ID, result[0].ID)
+		assert.Equal(t, middle.ID, result[1].ID)
+		assert.Empty(t, result[0].Intervals)
+		mockIntervalRepo.AssertNotCalled(t, "GetIntervalsByActivities", mock.Anything)
+		mockRepo.AssertNotCalled(t, "GetActivitiesByUser", mock.Anything)
+	})
+
+	t.Run("backfilled activity last", func(t *testing.T) {
+		result, err := service.GetRecentActivitiesByUser(context.Background(), userID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, result, 3)
+		assert.Equal(t, backfilled.ID, result[2].ID)
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		_, err := service.GetRecentActivitiesByUser(context.Background(), uuid.New(), 10)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+type AuditService interface {
+	GetUserAudit(ctx context.Context, userID uuid.UUID) ([]domain.AuditEvent, error)
+}
+
+// auditService serves the audit log of each user to the user and to admins
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+// NewAuditService creates a new AuditService
+func NewAuditService(r repository.AuditRepository) *auditService {
+	return &auditService{repo: r}
+}
+
+// GetUserAudit returns the changes made to the records of a user, most recent first;
+// Only the user and admins may read them
+func (s *auditService) GetUserAudit(ctx context.Context, userID uuid.UUID) ([]domain.AuditEvent, error) {
+	if err := authorizeUser(ctx, userID, "audit log"); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.GetEventsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		return []domain.AuditEvent{}, nil
+	}
+	return events, nil
+}
+
+// auditLog records the changes made through the services, on behalf of the actor
+// of the request; A change and its event are written in one transaction, so a
+// change is never left unrecorded
+type auditLog struct {
+	repo repository.AuditRepository
+}
+
+// change runs fn, which makes a change and records it, in a transaction
+func (l auditLog) change(ctx context.Context, fn func(ctx context.Context) error) error {
+	return l.repo.WithinTx(ctx, fn)
+}
+
+// record appends an event for a change to the record entityID, owned by userID;
+// before is nil for a created record and after for a deleted one
+func (l auditLog) record(ctx context.Context, userID uuid.UUID, entityType domain.AuditEntityType, entityID uuid.UUID, action domain.AuditAction, before, after any) error {
+	changes, err := domain.Diff(before, after)
+	if err != nil {
+		return err
+	}
+	return l.repo.RecordEvent(ctx, domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    auth.FromContext(ctx).ID(),
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    changes,
+	})
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAuditRepo keeps the recorded events in memory
+type mockAuditRepo struct {
+	events []domain.AuditEvent
+	err    error
+}
+
+// WithinTx runs fn and, if it fails, drops the events it recorded
+func (m *mockAuditRepo) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	recorded := len(m.events)
+	if err := fn(ctx); err != nil {
+		m.events = m.events[:recorded]
+		return err
+	}
+	return nil
+}
+
+func (m *mockAuditRepo) RecordEvent(ctx context.Context, event domain.AuditEvent) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockAuditRepo) GetEventsByUser(ctx context.Context, userID uuid.UUID) ([]domain.AuditEvent, error) {
+	var events []domain.AuditEvent
+	for _, event := range m.events {
+		if event.UserID == userID {
+			events = append(events, event)
+		}
+	}
+	return events, m.err
+}
+
+func TestGetUserAudit(t *testing.T) {
+	owner := uuid.New()
+	audit := &mockAuditRepo{events: []domain.AuditEvent{
+		{ID: uuid.New(), UserID: owner, EntityType: domain.AuditEntityUser, EntityID: owner, Action: domain.AuditActionCreate},
+		{ID: uuid.New(), UserID: uuid.New(), EntityType: domain.AuditEntityUser, Action: domain.AuditActionCreate},
+	}}
+	service := NewAuditService(audit)
+
+	tests := []struct {
+		name    string
+		actor   auth.Actor
+		events  int
+		wantErr error
+	}{
+		{"owner", auth.Actor{UserID: owner}, 1, nil},
+		{"admin", auth.Actor{UserID: uuid.New(), Admin: true}, 1, nil},
+		{"other user", auth.Actor{UserID: uuid.New()}, 0, domain.ErrForbidden},
+		{"anonymous", auth.Actor{}, 0, domain.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := service.GetUserAudit(auth.WithActor(context.Background(), tt.actor), owner)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, events, tt.events)
+		})
+	}
+
+	t.Run("no events", func(t *testing.T) {
+		events, err := service.GetUserAudit(auth.WithActor(context.Background(), auth.Actor{Admin: true, UserID: uuid.New()}), uuid.New())
+		assert.NoError(t, err)
+		assert.NotNil(t, events)
+		assert.Empty(t, events)
+	})
+}
+
+func TestUserService_RecordsAuditEvents(t *testing.T) {
+	audit := &mockAuditRepo{}
+	service := NewUserService(&mockUserRepo{users: make(map[uuid.UUID]domain.User)}, audit)
+	actor := auth.Actor{UserID: uuid.New()}
+	ctx := auth.WithActor(context.Background(), actor)
+	user := domain.User{ID: uuid.New(), Name: "Ana", Email: "ana@example.com"}
+
+	assert.NoError(t, service.CreateUser(ctx, user))
+	user.City = "Santos"
+	assert.NoError(t, service.UpdateUser(ctx, user))
+	assert.NoError(t, service.DeleteUser(ctx, user.ID))
+
+	assert.Len(t, audit.events, 3)
+	for i, action := range []domain.AuditAction{domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete} {
+		event := audit.events[i]
+		assert.Equal(t, action, event.Action)
+		assert.Equal(t, user.ID, event.UserID)
+		assert.Equal(t, user.ID, event.EntityID)
+		assert.Equal(t, domain.AuditEntityUser, event.EntityType)
+		assert.Equal(t, uuid.NullUUID{UUID: actor.UserID, Valid: true}, event.ActorID)
+	}
+	assert.Equal(t, domain.FieldChange{After: "ana@example.com"}, audit.events[0].Changes["email"])
+	assert.Equal(t, map[string]domain.FieldChange{"city": {Before: "", After: "Santos"}}, audit.events[1].Changes)
+	assert.Equal(t, domain.FieldChange{Before: "Santos"}, audit.events[2].Changes["city"])
+}
+
+func TestUserService_AuditFailureFailsChange(t *testing.T) {
+	repo := &mockUserRepo{users: make(map[uuid.UUID]domain.User)}
+	service := NewUserService(repo, &mockAuditRepo{err: errors.New("connection refused")})
+	user := domain.User{ID: uuid.New(), Name: "Ana"}
+
+	assert.EqualError(t, service.CreateUser(context.Background(), user), "connection refused")
+}
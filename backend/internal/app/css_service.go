@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/liviaruegger/MAC0350/backend/internal/mapper"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+var (
+	// ErrCSSTrialsNotFound is returned when no 400m and 200m trials are found among the user's intervals
+	ErrCSSTrialsNotFound = domain.NewNotFoundError("no 400m and 200m trials found")
+	// ErrCSSTrialNotOwned is returned when a trial interval belongs to another user's activity
+	ErrCSSTrialNotOwned = domain.NewValidationError(domain.FieldError{Field: "interval_id", Code: domain.CodeNotFound, Message: "must belong to one of the user's activities"})
+	// ErrNoCSSTest is returned when the user has not taken any CSS test yet
+	ErrNoCSSTest = domain.NewNotFoundError("no CSS test recorded")
+)
+
+type CSSService interface {
+	CreateCSSTest(ctx context.Context, userID, interval400ID, interval200ID uuid.UUID) (entity.CSSTest, error)
+	GetCSSHistory(ctx context.Context, userID uuid.UUID) ([]entity.CSSTest, error)
+	GetPaceZones(ctx context.Context, userID uuid.UUID) ([]entity.PaceZone, error)
+}
+
+// cssService provides Critical Swim Speed tests and pace zones
+type cssService struct {
+	repo         repository.CSSRepository
+	activityRepo repository.ActivityRepository
+	intervalRepo repository.IntervalRepository
+	audit        auditLog
+}
+
+// NewCSSService creates a new CSSService; recorded tests are added to the audit log
+func NewCSSService(r repository.CSSRepository, activityRepo repository.ActivityRepository, intervalRepo repository.IntervalRepository, audit repository.AuditRepository) *cssService {
+	return &cssService{
+		repo:         r,
+		activityRepo: activityRepo,
+		intervalRepo: intervalRepo,
+		audit:        auditLog{repo: audit},
+	}
+}
+
+// CreateCSSTest records a CSS test from the given 400m and 200m trial intervals;
+// If both IDs are uuid.Nil, the trials are detected in the user's most recent activity that has them
+func (s *cssService) CreateCSSTest(ctx context.Context, userID, interval400ID, interval200ID uuid.UUID) (entity.CSSTest, error) {
+	var test domain.CSSTest
+	var err error
+	if interval400ID == uuid.Nil && interval200ID == uuid.Nil {
+		test, err = s.detectCSSTest(ctx, userID)
+	} else {
+		test, err = s.loadCSSTest(ctx, userID, interval400ID, interval200ID)
+	}
+	if err != nil {
+		return entity.CSSTest{}, err
+	}
+
+	test.ID = uuid.New()
+	test.UserID = userID
+	if err := test.Validate(); err != nil {
+		return entity.CSSTest{}, err
+	}
+
+	err = s.audit.change(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateCSSTest(ctx, test); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, userID, domain.AuditEntityCSSTest, test.ID, domain.AuditActionCreate, nil, test)
+	})
+	if err != nil {
+		return entity.CSSTest{}, err
+	}
+
+	return mapper.MapCSSTestToEntity(test), nil
+}
+
+// detectCSSTest looks for the trials in the user's activities, from the most recent to the oldest
+func (s *cssService) detectCSSTest(ctx context.Context, userID uuid.UUID) (domain.CSSTest, error) {
+	activities, err := s.activityRepo.GetActivitiesByUser(ctx, userID)
+	if err != nil {
+		return domain.CSSTest{}, err
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Day().After(activities[j].Day())
+	})
+
+	// The intervals of all the activities are loaded in a single query
+	activityIDs := make([]uuid.UUID, len(activities))
+	for i, activity := range activities {
+		activityIDs[i] = activity.ID
+	}
+	intervalsByActivity, err := s.intervalRepo.GetIntervalsByActivities(ctx, activityIDs)
+	if err != nil {
+		return domain.CSSTest{}, err
+	}
+
+	for _, activity := range activities {
+		if trial400, trial200, ok := domain.DetectCSSTrials(intervalsByActivity[activity.ID], activity.PoolUnit); ok {
+			logging.FromContext(ctx).DebugContext(ctx, "CSS trials detected",
+				"activity_id", activity.ID,
+				"interval_400_id", trial400.ID,
+				"interval_200_id", trial200.ID,
+			)
+			return newCSSTest(activity, trial400, trial200), nil
+		}
+	}
+
+	return domain.CSSTest{}, ErrCSSTrialsNotFound
+}
+
+// loadCSSTest builds a test from the given intervals, checking they belong to the user
+func (s *cssService) loadCSSTest(ctx context.Context, userID, interval400ID, interval200ID uuid.UUID) (domain.CSSTest, error) {
+	trial400, activity, err := s.loadTrial(ctx, userID, interval400ID)
+	if err != nil {
+		return domain.CSSTest{}, err
+	}
+
+	trial200, _, err := s.loadTrial(ctx, userID, interval200ID)
+	if err != nil {
+		return domain.CSSTest{}, err
+	}
+
+	return newCSSTest(activity, trial400, trial200), nil
+}
+
+func (s *cssService) loadTrial(ctx context.Context, userID, intervalID uuid.UUID) (domain.Interval, domain.Activity, error) {
+	interval, err := s.intervalRepo.GetIntervalByID(ctx, intervalID)
+	if err != nil {
+		return domain.Interval{}, domain.Activity{}, err
+	}
+
+	activity, err := s.activityRepo.GetActivityByID(ctx, interval.ActivityID)
+	if err != nil {
+		return domain.Interval{}, domain.Activity{}, err
+	}
+	if activity.UserID != userID {
+		return domain.Interval{}, domain.Activity{}, ErrCSSTrialNotOwned
+	}
+
+	return interval, activity, nil
+}
+
+func newCSSTest(activity domain.Activity, trial400, trial200 domain.Interval) domain.CSSTest {
+	return domain.CSSTest{
+		Date:          activity.Day().Format(domain.DateLayout),
+		Time400:       trial400.Duration,
+		Time200:       trial200.Duration,
+		Interval400ID: uuid.NullUUID{UUID: trial400.ID, Valid: true},
+		Interval200ID: uuid.NullUUID{UUID: trial200.ID, Valid: true},
+		Unit:          activity.PoolUnit.OrDefault(),
+	}
+}
+
+// GetCSSHistory returns all CSS tests of a user, most recent first
+func (s *cssService) GetCSSHistory(ctx context.Context, userID uuid.UUID) ([]entity.CSSTest, error) {
+	tests, err := s.repo.GetCSSTestsByUser(ctx, userID)
+	if err != nil {
+		return []entity.CSSTest{}, err
+	}
+
+	history := make([]entity.CSSTest, len(tests))
+	for i, test := range tests {
+		history[i] = mapper.MapCSSTestToEntity(test)
+	}
+
+	return history, nil
+}
+
+// GetPaceZones returns the pace zones derived from the user's most recent CSS test
+func (s *cssService) GetPaceZones(ctx context.Context, userID uuid.UUID) ([]entity.PaceZone, error) {
+	tests, err := s.repo.GetCSSTestsByUser(ctx, userID)
+	if err != nil {
+		return []entity.PaceZone{}, err
+	}
+	if len(tests) == 0 {
+		return []entity.PaceZone{}, ErrNoCSSTest
+	}
+
+	return mapper.MapPaceZonesToEntity(tests[0].Zones()), nil
+}
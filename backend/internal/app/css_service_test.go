@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCSSRepository is a mock implementation of CSSRepository
+type MockCSSRepository struct {
+	mock.Mock
+}
+
+func (m *MockCSSRepository) CreateCSSTest(ctx context.Context, test domain.CSSTest) error {
+	args := m.Called(test)
+	return args.Error(0)
+}
+
+func (m *MockCSSRepository) GetCSSTestsByUser(ctx context.Context, userID uuid.UUID) ([]domain.CSSTest, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.CSSTest), args.Error(1)
+}
+
+func TestCreateCSSTest(t *testing.T) {
+	userID := uuid.New()
+	older := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2023-09-01"}
+	recent := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2023-10-01"}
+	trial400 := domain.Interval{ID: uuid.New(), ActivityID: recent.ID, Distance: 400, Duration: domain.MustParseDuration("6m0s"), Type: domain.IntervalMainSet}
+	trial200 := domain.Interval{ID: uuid.New(), ActivityID: recent.ID, Distance: 200, Duration: domain.MustParseDuration("2m50s"), Type: domain.IntervalMainSet}
+
+	t.Run("detected trials", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		mockActivityRepo := new(MockActivityRepository)
+		mockIntervalRepo := new(MockIntervalRepository)
+		service := NewCSSService(mockRepo, mockActivityRepo, mockIntervalRepo, &mockAuditRepo{})
+
+		mockActivityRepo.On("GetActivitiesByUser", userID).Return([]domain.Activity{older, recent}, nil)
+		mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{recent.ID, older.ID}).
+			Return(map[uuid.UUID][]domain.Interval{recent.ID: {trial400, trial200}}, nil).Once()
+		mockRepo.On("CreateCSSTest", mock.MatchedBy(func(test domain.CSSTest) bool {
+			return test.UserID == userID && test.Date == "2023-10-01" && test.Interval400ID.UUID == trial400.ID
+		})).Return(nil)
+
+		result, err := service.CreateCSSTest(context.Background(), userID, uuid.Nil, uuid.Nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "01:35", result.CSSPacePer100m)
+		assert.Len(t, result.Zones, 5)
+		mockRepo.AssertExpectations(t)
+		mockIntervalRepo.AssertExpectations(t)
+	})
+
+	t.Run("detected trials in a yards pool", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		mockActivityRepo := new(MockActivityRepository)
+		mockIntervalRepo := new(MockIntervalRepository)
+		service := NewCSSService(mockRepo, mockActivityRepo, mockIntervalRepo, &mockAuditRepo{})
+
+		yards := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2023-10-05", PoolUnit: domain.DistanceUnitYards}
+		yard400 := domain.Interval{ID: uuid.New(), ActivityID: yards.ID, Distance: 365.76, Duration: domain.MustParseDuration("6m0s"), Type: domain.IntervalMainSet}
+		yard200 := domain.Interval{ID: uuid.New(), ActivityID: yards.ID, Distance: 182.88, Duration: domain.MustParseDuration("2m50s"), Type: domain.IntervalMainSet}
+
+		mockActivityRepo.On("GetActivitiesByUser", userID).Return([]domain.Activity{yards}, nil)
+		mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{yards.ID}).
+			Return(map[uuid.UUID][]domain.Interval{yards.ID: {yard400, yard200}}, nil)
+		mockRepo.On("CreateCSSTest", mock.MatchedBy(func(test domain.CSSTest) bool {
+			return test.Unit == domain.DistanceUnitYards && test.Interval200ID.UUID == yard200.ID
+		})).Return(nil)
+
+		result, err := service.CreateCSSTest(context.Background(), userID, uuid.Nil, uuid.Nil)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DistanceUnitYards, result.Unit)
+		assert.Equal(t, "01:43", result.CSSPacePer100m)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no trials found", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		mockActivityRepo := new(MockActivityRepository)
+		mockIntervalRepo := new(MockIntervalRepository)
+		service := NewCSSService(mockRepo, mockActivityRepo, mockIntervalRepo, &mockAuditRepo{})
+
+		mockActivityRepo.On("GetActivitiesByUser", userID).Return([]domain.Activity{older}, nil)
+		mockIntervalRepo.On("GetIntervalsByActivities", []uuid.UUID{older.ID}).
+			Return(map[uuid.UUID][]domain.Interval{older.ID: {trial400}}, nil)
+
+		_, err := service.CreateCSSTest(context.Background(), userID, uuid.Nil, uuid.Nil)
+		assert.ErrorIs(t, err, ErrCSSTrialsNotFound)
+		mockRepo.AssertNotCalled(t, "CreateCSSTest", mock.Anything)
+	})
+
+	t.Run("given trials", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		mockActivityRepo := new(MockActivityRepository)
+		mockIntervalRepo := new(MockIntervalRepository)
+		service := NewCSSService(mockRepo, mockActivityRepo, mockIntervalRepo, &mockAuditRepo{})
+
+		mockIntervalRepo.On("GetIntervalByID", trial400.ID).Return(trial400, nil)
+		mockIntervalRepo.On("GetIntervalByID", trial200.ID).Return(trial200, nil)
+		mockActivityRepo.On("GetActivityByID", recent.ID).Return(recent, nil)
+		mockRepo.On("CreateCSSTest", mock.Anything).Return(nil)
+
+		result, err := service.CreateCSSTest(context.Background(), userID, trial400.ID, trial200.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, &trial200.ID, result.Interval200ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("trial of another user", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		mockActivityRepo := new(MockActivityRepository)
+		mockIntervalRepo := new(MockIntervalRepository)
+		service := NewCSSService(mockRepo, mockActivityRepo, mockIntervalRepo, &mockAuditRepo{})
+
+		mockIntervalRepo.On("GetIntervalByID", trial400.ID).Return(trial400, nil)
+		mockActivityRepo.On("GetActivityByID", recent.ID).Return(domain.Activity{ID: recent.ID, UserID: uuid.New()}, nil)
+
+		_, err := service.CreateCSSTest(context.Background(), userID, trial400.ID, trial200.ID)
+		assert.ErrorIs(t, err, ErrCSSTrialNotOwned)
+	})
+
+	t.Run("invalid trials", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		mockActivityRepo := new(MockActivityRepository)
+		mockIntervalRepo := new(MockIntervalRepository)
+		service := NewCSSService(mockRepo, mockActivityRepo, mockIntervalRepo, &mockAuditRepo{})
+
+		mockIntervalRepo.On("GetIntervalByID", trial400.ID).Return(trial400, nil)
+		mockIntervalRepo.On("GetIntervalByID", trial200.ID).Return(trial200, nil)
+		mockActivityRepo.On("GetActivityByID", recent.ID).Return(recent, nil)
+
+		_, err := service.CreateCSSTest(context.Background(), userID, trial200.ID, trial400.ID)
+		assert.ErrorIs(t, err, domain.ErrInvalidCSSTest)
+	})
+}
+
+func TestGetCSSHistory(t *testing.T) {
+	userID := uuid.New()
+	mockRepo := new(MockCSSRepository)
+	service := NewCSSService(mockRepo, new(MockActivityRepository), new(MockIntervalRepository), &mockAuditRepo{})
+
+	mockRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{
+		{ID: uuid.New(), UserID: userID, Date: "2023-10-08", Time400: domain.MustParseDuration("5m55s"), Time200: domain.MustParseDuration("2m50s")},
+		{ID: uuid.New(), UserID: userID, Date: "2023-10-01", Time400: domain.MustParseDuration("6m0s"), Time200: domain.MustParseDuration("2m50s")},
+	}, nil)
+
+	result, err := service.GetCSSHistory(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "2023-10-08", result[0].Date)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetPaceZones(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		service := NewCSSService(mockRepo, new(MockActivityRepository), new(MockIntervalRepository), &mockAuditRepo{})
+		mockRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{
+			{Time400: domain.MustParseDuration("6m0s"), Time200: domain.MustParseDuration("2m50s")},
+		}, nil)
+
+		result, err := service.GetPaceZones(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Len(t, result, 5)
+	})
+
+	t.Run("no test", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		service := NewCSSService(mockRepo, new(MockActivityRepository), new(MockIntervalRepository), &mockAuditRepo{})
+		mockRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{}, nil)
+
+		_, err := service.GetPaceZones(context.Background(), userID)
+		assert.ErrorIs(t, err, ErrNoCSSTest)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo := new(MockCSSRepository)
+		service := NewCSSService(mockRepo, new(MockActivityRepository), new(MockIntervalRepository), &mockAuditRepo{})
+		mockRepo.On("GetCSSTestsByUser", userID).Return([]domain.CSSTest{}, errors.New("db error"))
+
+		_, err := service.GetPaceZones(context.Background(), userID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/events"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+// EventStream carries the events of the users to the clients watching them, see events.Broker
+type EventStream interface {
+	Publish(ctx context.Context, event events.Event) error
+	Subscribe(userID uuid.UUID) *events.Subscription
+}
+
+type EventService interface {
+	Subscribe(ctx context.Context, userID uuid.UUID) (*events.Subscription, error)
+}
+
+// eventService subscribes the clients of the users to their events
+type eventService struct {
+	stream   EventStream
+	userRepo repository.UserRepository
+}
+
+// NewEventService creates a new EventService
+func NewEventService(stream EventStream, userRepo repository.UserRepository) *eventService {
+	return &eventService{stream: stream, userRepo: userRepo}
+}
+
+// Subscribe subscribes to the events of a user; Only the user and admins may subscribe
+func (s *eventService) Subscribe(ctx context.Context, userID uuid.UUID) (*events.Subscription, error) {
+	if err := authorizeUser(ctx, userID, "events"); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.stream.Subscribe(userID), nil
+}
+
+// userEvents publishes the events of a user to the clients watching them; The
+// change already happened when its event is published, so failing to publish
+// it is logged instead of failing the request
+type userEvents struct {
+	stream EventStream
+}
+
+// publish publishes an event with its data to the clients watching the user
+func (e userEvents) publish(ctx context.Context, userID uuid.UUID, eventType domain.WebhookEvent, data any) {
+	event, err := events.NewEvent(userID, eventType, data)
+	if err == nil {
+		err = e.stream.Publish(ctx, event)
+	}
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "publishing event failed", "event", string(eventType), "error", err.Error())
+	}
+}
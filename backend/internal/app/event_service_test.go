@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	userID := uuid.New()
+	service := NewEventService(events.NewBroker(nil), &mockUserRepo{users: map[uuid.UUID]domain.User{userID: {ID: userID}}})
+
+	t.Run("user", func(t *testing.T) {
+		subscription, err := service.Subscribe(auth.WithActor(context.Background(), auth.Actor{UserID: userID}), userID)
+		assert.NoError(t, err)
+		subscription.Close()
+	})
+
+	t.Run("admin subscribing to a missing user", func(t *testing.T) {
+		_, err := service.Subscribe(auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New(), Admin: true}), uuid.New())
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		_, err := service.Subscribe(auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New()}), userID)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		_, err = service.Subscribe(context.Background(), userID)
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+}
+
+func TestActivityStream(t *testing.T) {
+	userID := uuid.New()
+	broker := events.NewBroker(nil)
+	subscription := broker.Subscribe(userID)
+	defer subscription.Close()
+
+	repo := new(MockActivityRepository)
+	service := NewActivityService(repo, new(MockIntervalRepository), new(MockCSSRepository), &mockUserRepo{}, &mockAuditRepo{}, &mockWebhookRepo{}, &fakeJobQueue{}, broker)
+
+	activity := domain.Activity{ID: uuid.New(), UserID: userID, Distance: 1500}
+	updated := activity
+	updated.Distance = 2000
+	repo.On("CreateActivity", activity).Return(nil)
+	repo.On("UpdateActivity", updated).Return(nil)
+	repo.On("GetActivityByID", activity.ID).Return(activity, nil).Once()
+	repo.On("GetActivityByID", activity.ID).Return(updated, nil)
+	repo.On("DeleteActivity", activity.ID).Return(nil)
+
+	assert.NoError(t, service.CreateActivity(context.Background(), activity))
+	assert.NoError(t, service.UpdateActivity(context.Background(), updated))
+	assert.NoError(t, service.DeleteActivity(context.Background(), activity.ID))
+
+	for _, want := range []struct {
+		eventType domain.WebhookEvent
+		distance  float64
+	}{
+		{domain.WebhookActivityCreated, 1500},
+		{domain.WebhookActivityUpdated, 2000},
+		{domain.WebhookActivityDeleted, 2000},
+	} {
+		event := <-subscription.Events()
+		assert.Equal(t, want.eventType, event.Type)
+		assert.Equal(t, userID, event.UserID)
+
+		var data domain.Activity
+		assert.NoError(t, json.Unmarshal(event.Data, &data))
+		assert.Equal(t, want.distance, data.Distance)
+	}
+}
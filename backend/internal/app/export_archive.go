@@ -0,0 +1,154 @@
+package app
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// exportData holds all the data of a user, as included in their export
+type exportData struct {
+	user       domain.User
+	activities []domain.Activity
+	intervals  []domain.Interval
+	cssTests   []domain.CSSTest
+	audit      []domain.AuditEvent
+}
+
+// writeExportArchive writes a ZIP archive of the data of a user: their profile,
+// activities and intervals as JSON and CSV, CSS tests and audit log as JSON
+func writeExportArchive(w io.Writer, data exportData) error {
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"profile.json", jsonFile(data.user)},
+		{"activities.json", jsonFile(nonNil(data.activities))},
+		{"activities.csv", func(w io.Writer) error { return writeActivitiesCSV(w, data.activities) }},
+		{"intervals.json", jsonFile(nonNil(data.intervals))},
+		{"intervals.csv", func(w io.Writer) error { return writeIntervalsCSV(w, data.intervals) }},
+		{"css_tests.json", jsonFile(nonNil(data.cssTests))},
+		{"audit_log.json", jsonFile(nonNil(data.audit))},
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if err := file.write(fw); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// jsonFile returns a writer of the indented JSON encoding of v
+func jsonFile(v any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+}
+
+// nonNil returns an empty slice for nil, so it is encoded as [] rather than null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func writeActivitiesCSV(w io.Writer, activities []domain.Activity) error {
+	rows := [][]string{{
+		"id", "date", "start", "duration", "distance", "laps", "pool_size", "pool_unit", "location_type",
+		"location_name", "feeling", "heart_rate_avg", "heart_rate_max", "rpe", "notes", "created_at", "updated_at",
+	}}
+	for _, a := range activities {
+		rows = append(rows, []string{
+			a.ID.String(),
+			a.Date,
+			formatTime(a.Start),
+			a.Duration.String(),
+			formatFloat(a.Distance),
+			strconv.Itoa(a.Laps),
+			formatFloat(a.PoolSize),
+			string(a.PoolUnit),
+			string(a.LocationType),
+			a.LocationName,
+			string(a.Feeling),
+			strconv.Itoa(a.HeartRateAvg),
+			strconv.Itoa(a.HeartRateMax),
+			strconv.Itoa(a.RPE),
+			a.Notes,
+			formatTime(a.CreatedAt),
+			formatTime(a.UpdatedAt),
+		})
+	}
+	return writeCSV(w, rows)
+}
+
+func writeIntervalsCSV(w io.Writer, intervals []domain.Interval) error {
+	rows := [][]string{{
+		"id", "activity_id", "duration", "distance", "type", "stroke", "notes", "heart_rate_avg",
+		"stroke_count", "length_stroke_counts", "created_at", "updated_at",
+	}}
+	for _, i := range intervals {
+		lengths := make([]string, len(i.LengthStrokeCounts))
+		for n, count := range i.LengthStrokeCounts {
+			lengths[n] = strconv.Itoa(count)
+		}
+		rows = append(rows, []string{
+			i.ID.String(),
+			i.ActivityID.String(),
+			i.Duration.String(),
+			formatFloat(i.Distance),
+			string(i.Type),
+			string(i.Stroke),
+			i.Notes,
+			strconv.Itoa(i.HeartRateAvg),
+			strconv.Itoa(i.StrokeCount),
+			strings.Join(lengths, ";"),
+			formatTime(i.CreatedAt),
+			formatTime(i.UpdatedAt),
+		})
+	}
+	return writeCSV(w, rows)
+}
+
+// writeCSV writes rows as CSV, with the cells a spreadsheet would evaluate as a
+// formula prefixed by a quote, so they are shown as text instead
+func writeCSV(w io.Writer, rows [][]string) error {
+	for _, row := range rows {
+		for i, cell := range row {
+			if cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0])) {
+				row[i] = "'" + cell
+			}
+		}
+	}
+	return csv.NewWriter(w).WriteAll(rows)
+}
+
+// formulaPrefixes are the first characters of the cells spreadsheets may evaluate
+// as formulas, including the tab and carriage return OWASP lists
+const formulaPrefixes = "=+-@\t\r"
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatTime formats a time in RFC 3339, or as an empty string if it is not set
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/jobs"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+// ExportJobType is the type of the jobs generating export archives, handled by GenerateExport
+const ExportJobType = "export"
+
+// exportJob is the payload of an export job
+type exportJob struct {
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+var (
+	// ErrExportPending is returned when the archive of an export is not generated yet
+	ErrExportPending = domain.NewConflictError("export is still being generated")
+	// ErrExportFailed is returned when the archive of an export could not be generated
+	ErrExportFailed = domain.NewConflictError("export failed, request a new one")
+)
+
+type ExportService interface {
+	RequestExport(ctx context.Context, userID uuid.UUID) (domain.DataExport, error)
+	GetExport(ctx context.Context, id uuid.UUID) (domain.DataExport, error)
+	GetExportArchive(ctx context.Context, id uuid.UUID) ([]byte, error)
+}
+
+// exportService generates archives of all the data of a user, for the user and admins
+type exportService struct {
+	repo         repository.ExportRepository
+	userRepo     repository.UserRepository
+	activityRepo repository.ActivityRepository
+	intervalRepo repository.IntervalRepository
+	cssRepo      repository.CSSRepository
+	auditRepo    repository.AuditRepository
+	queue        JobQueue
+	ttl          time.Duration
+}
+
+// NewExportService creates a new ExportService, generating the archives with jobs
+// enqueued on queue; exports can be downloaded for ttl after they are requested
+func NewExportService(r repository.ExportRepository, userRepo repository.UserRepository, activityRepo repository.ActivityRepository,
+	intervalRepo repository.IntervalRepository, cssRepo repository.CSSRepository, auditRepo repository.AuditRepository,
+	queue JobQueue, ttl time.Duration) *exportService {
+	return &exportService{
+		repo:         r,
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+		intervalRepo: intervalRepo,
+		cssRepo:      cssRepo,
+		auditRepo:    auditRepo,
+		queue:        queue,
+		ttl:          ttl,
+	}
+}
+
+// RequestExport records a pending export of the data of a user and enqueues the
+// job generating its archive
+func (s *exportService) RequestExport(ctx context.Context, userID uuid.UUID) (domain.DataExport, error) {
+	if err := authorizeUser(ctx, userID, "data"); err != nil {
+		return domain.DataExport{}, err
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return domain.DataExport{}, err
+	}
+
+	exportID := uuid.New()
+	job, err := jobs.NewJob(ExportJobType, uuid.NullUUID{UUID: userID, Valid: true}, exportJob{ExportID: exportID})
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	export := domain.DataExport{ID: exportID, UserID: userID, Status: domain.ExportPending, JobID: uuid.NullUUID{UUID: job.ID, Valid: true}}
+	if err := s.repo.CreateExport(ctx, export, s.ttl); err != nil {
+		return domain.DataExport{}, err
+	}
+	if _, err := s.queue.Enqueue(ctx, job); err != nil {
+		if err := s.repo.FailExport(ctx, export.ID); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "recording export failure failed", "export_id", export.ID.String(), "error", err.Error())
+		}
+		return domain.DataExport{}, err
+	}
+
+	return s.repo.GetExportByID(ctx, export.ID)
+}
+
+// GetExport returns the status of an export
+func (s *exportService) GetExport(ctx context.Context, id uuid.UUID) (domain.DataExport, error) {
+	export, err := s.repo.GetExportByID(ctx, id)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	if err := authorizeUser(ctx, export.UserID, "data export"); err != nil {
+		return domain.DataExport{}, err
+	}
+	return export, nil
+}
+
+// GetExportArchive returns the ZIP archive of a ready export
+func (s *exportService) GetExportArchive(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	export, err := s.GetExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch export.Status {
+	case domain.ExportPending:
+		return nil, ErrExportPending
+	case domain.ExportFailed:
+		return nil, ErrExportFailed
+	}
+	return s.repo.GetExportArchive(ctx, id)
+}
+
+// GenerateExport is the handler of export jobs: it builds the archive of the
+// export and stores it. The export is marked as failed once the job fails for good
+func (s *exportService) GenerateExport(ctx context.Context, job domain.Job) error {
+	var payload exportJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("decoding export job payload: %w", err))
+	}
+	logger := logging.FromContext(ctx).With("export_id", payload.ExportID.String())
+
+	export, err := s.repo.GetExportByID(ctx, payload.ExportID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	// A previous attempt may have stored the archive but failed to record the job outcome
+	if export.Status != domain.ExportPending {
+		return nil
+	}
+
+	size, err := s.generate(ctx, export)
+	if err != nil {
+		if jobs.IsPermanent(err) || job.LastAttempt() {
+			if err := s.repo.FailExport(context.WithoutCancel(ctx), export.ID); err != nil {
+				logger.ErrorContext(ctx, "recording export failure failed", "error", err.Error())
+			}
+		}
+		return err
+	}
+	logger.InfoContext(ctx, "export ready", "size", size)
+	return nil
+}
+
+// generate builds the archive of an export and stores it, returning its size;
+// Records that went missing in the meantime, e.g., the deleted user, fail it for good
+func (s *exportService) generate(ctx context.Context, export domain.DataExport) (int, error) {
+	var archive bytes.Buffer
+	data, err := s.collect(ctx, export.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return 0, jobs.Permanent(err)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := writeExportArchive(&archive, data); err != nil {
+		return 0, jobs.Permanent(err)
+	}
+
+	if err := s.repo.CompleteExport(ctx, export.ID, archive.Bytes()); err != nil {
+		return 0, err
+	}
+	return archive.Len(), nil
+}
+
+// collect loads all the data of a user
+func (s *exportService) collect(ctx context.Context, userID uuid.UUID) (exportData, error) {
+	var data exportData
+	var err error
+	if data.user, err = s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return exportData{}, err
+	}
+	if data.activities, err = s.activityRepo.GetActivitiesByUser(ctx, userID); err != nil {
+		return exportData{}, err
+	}
+
+	activityIDs := make([]uuid.UUID, len(data.activities))
+	for i, activity := range data.activities {
+		activityIDs[i] = activity.ID
+	}
+	intervalsByActivity, err := s.intervalRepo.GetIntervalsByActivities(ctx, activityIDs)
+	if err != nil {
+		return exportData{}, err
+	}
+	for _, activity := range data.activities {
+		data.intervals = append(data.intervals, intervalsByActivity[activity.ID]...)
+	}
+
+	if data.cssTests, err = s.cssRepo.GetCSSTestsByUser(ctx, userID); err != nil {
+		return exportData{}, err
+	}
+	if data.audit, err = s.auditRepo.GetEventsByUser(ctx, userID); err != nil {
+		return exportData{}, err
+	}
+	return data, nil
+}
@@ -0,0 +1,278 @@
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExportRepository is a mock implementation of ExportRepository
+type MockExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportRepository) CreateExport(ctx context.Context, export domain.DataExport, ttl time.Duration) error {
+	args := m.Called(export, ttl)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) CompleteExport(ctx context.Context, id uuid.UUID, archive []byte) error {
+	args := m.Called(id, archive)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) FailExport(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) GetExportByID(ctx context.Context, id uuid.UUID) (domain.DataExport, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.DataExport), args.Error(1)
+}
+
+func (m *MockExportRepository) GetExportArchive(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	args := m.Called(id)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// fakeJobQueue records the enqueued jobs instead of running them
+type fakeJobQueue struct {
+	jobs []domain.Job
+	err  error
+}
+
+func (q *fakeJobQueue) Enqueue(ctx context.Context, job domain.Job) (domain.Job, error) {
+	if q.err != nil {
+		return domain.Job{}, q.err
+	}
+	job.Status = domain.JobQueued
+	q.jobs = append(q.jobs, job)
+	return job, nil
+}
+
+// readArchive returns the contents of each file of a ZIP archive
+func readArchive(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("invalid archive: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestRequestExport(t *testing.T) {
+	const ttl = 24 * time.Hour
+	userID := uuid.New()
+	ctx := auth.WithActor(context.Background(), auth.Actor{UserID: userID})
+
+	newService := func() (*exportService, *MockExportRepository, *fakeJobQueue) {
+		repo, queue := new(MockExportRepository), &fakeJobQueue{}
+		users := &mockUserRepo{users: map[uuid.UUID]domain.User{userID: {ID: userID, Name: "Ana"}}}
+		return NewExportService(repo, users, nil, nil, nil, nil, queue, ttl), repo, queue
+	}
+
+	t.Run("enqueues the export job", func(t *testing.T) {
+		service, repo, queue := newService()
+		var created domain.DataExport
+		repo.On("CreateExport", mock.Anything, ttl).Run(func(args mock.Arguments) {
+			created = args.Get(0).(domain.DataExport)
+		}).Return(nil)
+		repo.On("GetExportByID", mock.Anything).Return(domain.DataExport{Status: domain.ExportPending}, nil)
+
+		_, err := service.RequestExport(ctx, userID)
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+
+		assert.Len(t, queue.jobs, 1)
+		job := queue.jobs[0]
+		assert.Equal(t, ExportJobType, job.Type)
+		assert.Equal(t, uuid.NullUUID{UUID: userID, Valid: true}, job.UserID)
+		assert.JSONEq(t, `{"export_id":"`+created.ID.String()+`"}`, string(job.Payload))
+		assert.Equal(t, userID, created.UserID)
+		assert.Equal(t, uuid.NullUUID{UUID: job.ID, Valid: true}, created.JobID)
+	})
+
+	t.Run("enqueue failure", func(t *testing.T) {
+		service, repo, queue := newService()
+		queue.err = errors.New("connection refused")
+		repo.On("CreateExport", mock.Anything, ttl).Return(nil)
+		repo.On("FailExport", mock.Anything).Return(nil)
+
+		_, err := service.RequestExport(ctx, userID)
+		assert.EqualError(t, err, "connection refused")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("only for the user and admins", func(t *testing.T) {
+		service, repo, _ := newService()
+
+		_, err := service.RequestExport(auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New()}), userID)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		_, err = service.RequestExport(context.Background(), userID)
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+		repo.AssertNotCalled(t, "CreateExport", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		service, _, _ := newService()
+		admin := auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New(), Admin: true})
+
+		_, err := service.RequestExport(admin, uuid.New())
+		assert.Error(t, err)
+	})
+}
+
+func TestGenerateExport(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Name: "Ana", Email: "ana@example.com"}
+	activity := domain.Activity{ID: uuid.New(), UserID: user.ID, Date: "2026-10-01", Distance: 2000, Notes: "felt strong"}
+	interval := domain.Interval{ID: uuid.New(), ActivityID: activity.ID, Distance: 400, LengthStrokeCounts: []int{18, 19}, Notes: "=HYPERLINK(\"http://example.com\")"}
+	pending := domain.DataExport{ID: uuid.New(), UserID: user.ID, Status: domain.ExportPending}
+
+	newService := func() (*exportService, *MockExportRepository, *MockActivityRepository, *MockIntervalRepository, *MockCSSRepository) {
+		repo, activityRepo, intervalRepo, cssRepo := new(MockExportRepository), new(MockActivityRepository), new(MockIntervalRepository), new(MockCSSRepository)
+		users := &mockUserRepo{users: map[uuid.UUID]domain.User{user.ID: user}}
+		service := NewExportService(repo, users, activityRepo, intervalRepo, cssRepo, &mockAuditRepo{}, &fakeJobQueue{}, time.Hour)
+		return service, repo, activityRepo, intervalRepo, cssRepo
+	}
+	newJob := func(exportID uuid.UUID, attempts int) domain.Job {
+		job, err := jobs.NewJob(ExportJobType, uuid.NullUUID{UUID: user.ID, Valid: true}, exportJob{ExportID: exportID})
+		assert.NoError(t, err)
+		job.Attempts, job.MaxAttempts = attempts, 3
+		return job
+	}
+
+	t.Run("generates the archive", func(t *testing.T) {
+		service, repo, activityRepo, intervalRepo, cssRepo := newService()
+		repo.On("GetExportByID", pending.ID).Return(pending, nil)
+		activityRepo.On("GetActivitiesByUser", user.ID).Return([]domain.Activity{activity}, nil)
+		intervalRepo.On("GetIntervalsByActivities", []uuid.UUID{activity.ID}).
+			Return(map[uuid.UUID][]domain.Interval{activity.ID: {interval}}, nil)
+		cssRepo.On("GetCSSTestsByUser", user.ID).Return([]domain.CSSTest(nil), nil)
+		var archive []byte
+		repo.On("CompleteExport", pending.ID, mock.Anything).Run(func(args mock.Arguments) {
+			archive = args.Get(1).([]byte)
+		}).Return(nil)
+
+		err := service.GenerateExport(context.Background(), newJob(pending.ID, 1))
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+
+		files := readArchive(t, archive)
+		assert.Contains(t, files["profile.json"], `"email": "ana@example.com"`)
+		assert.Contains(t, files["activities.json"], `"notes": "felt strong"`)
+		assert.Contains(t, files["activities.csv"], activity.ID.String()+",2026-10-01,")
+		assert.Contains(t, files["intervals.csv"], ",18;19,")
+		assert.Contains(t, files["intervals.csv"], `,"'=HYPERLINK(""http://example.com"")",`)
+		assert.Contains(t, files["intervals.json"], `"notes": "=HYPERLINK(\"http://example.com\")"`)
+		assert.Equal(t, "[]\n", files["css_tests.json"])
+		assert.Equal(t, "[]\n", files["audit_log.json"])
+		assert.Len(t, strings.Split(strings.TrimSpace(files["intervals.csv"]), "\n"), 2)
+	})
+
+	t.Run("retried until the last attempt", func(t *testing.T) {
+		service, repo, activityRepo, _, _ := newService()
+		repo.On("GetExportByID", pending.ID).Return(pending, nil)
+		activityRepo.On("GetActivitiesByUser", user.ID).Return([]domain.Activity(nil), errors.New("connection refused"))
+
+		err := service.GenerateExport(context.Background(), newJob(pending.ID, 1))
+		assert.EqualError(t, err, "connection refused")
+		assert.False(t, jobs.IsPermanent(err))
+		repo.AssertNotCalled(t, "FailExport", pending.ID)
+
+		repo.On("FailExport", pending.ID).Return(nil)
+		err = service.GenerateExport(context.Background(), newJob(pending.ID, 3))
+		assert.Error(t, err)
+		repo.AssertCalled(t, "FailExport", pending.ID)
+		repo.AssertNotCalled(t, "CompleteExport", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deleted user", func(t *testing.T) {
+		service, repo, _, _, _ := newService()
+		orphan := domain.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: domain.ExportPending}
+		repo.On("GetExportByID", orphan.ID).Return(orphan, nil)
+		repo.On("FailExport", orphan.ID).Return(nil)
+
+		err := service.GenerateExport(context.Background(), newJob(orphan.ID, 1))
+		assert.True(t, jobs.IsPermanent(err))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("already generated", func(t *testing.T) {
+		service, repo, _, _, _ := newService()
+		ready := domain.DataExport{ID: uuid.New(), UserID: user.ID, Status: domain.ExportReady}
+		repo.On("GetExportByID", ready.ID).Return(ready, nil)
+
+		assert.NoError(t, service.GenerateExport(context.Background(), newJob(ready.ID, 2)))
+		repo.AssertNotCalled(t, "CompleteExport", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired export", func(t *testing.T) {
+		service, repo, _, _, _ := newService()
+		id := uuid.New()
+		repo.On("GetExportByID", id).Return(domain.DataExport{}, domain.NewNotFoundError("export not found"))
+
+		err := service.GenerateExport(context.Background(), newJob(id, 1))
+		assert.True(t, jobs.IsPermanent(err))
+	})
+}
+
+func TestGetExportArchive(t *testing.T) {
+	userID := uuid.New()
+	ctx := auth.WithActor(context.Background(), auth.Actor{UserID: userID})
+	repo := new(MockExportRepository)
+	service := NewExportService(repo, nil, nil, nil, nil, nil, nil, time.Hour)
+
+	ready := domain.DataExport{ID: uuid.New(), UserID: userID, Status: domain.ExportReady}
+	pending := domain.DataExport{ID: uuid.New(), UserID: userID, Status: domain.ExportPending}
+	failed := domain.DataExport{ID: uuid.New(), UserID: userID, Status: domain.ExportFailed}
+	other := domain.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: domain.ExportReady}
+	for _, export := range []domain.DataExport{ready, pending, failed, other} {
+		repo.On("GetExportByID", export.ID).Return(export, nil)
+	}
+	repo.On("GetExportArchive", ready.ID).Return([]byte("PK"), nil)
+
+	archive, err := service.GetExportArchive(ctx, ready.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("PK"), archive)
+
+	_, err = service.GetExportArchive(ctx, pending.ID)
+	assert.ErrorIs(t, err, ErrExportPending)
+	_, err = service.GetExportArchive(ctx, failed.ID)
+	assert.ErrorIs(t, err, ErrExportFailed)
+	_, err = service.GetExportArchive(ctx, other.ID)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out strings.Builder
+	err := writeCSV(&out, [][]string{{"=1+1", "+33 1234", "-2", "@SUM(A1)", "\t=1+1", "\r=1+1", "a=b", "", "notes"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "'=1+1,'+33 1234,'-2,'@SUM(A1),'\t=1+1,\"'\r=1+1\",a=b,,notes\n", out.String())
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+type HealthService interface {
+	CheckReadiness(ctx context.Context) entity.Readiness
+	DatabaseStats() sql.DBStats
+}
+
+// healthService checks whether the API can serve requests
+type healthService struct {
+	repo          repository.HealthRepository
+	schemaVersion int
+	timeout       time.Duration
+}
+
+// NewHealthService creates a new HealthService expecting the given schema version;
+// timeout bounds each readiness check
+func NewHealthService(r repository.HealthRepository, schemaVersion int, timeout time.Duration) *healthService {
+	return &healthService{
+		repo:          r,
+		schemaVersion: schemaVersion,
+		timeout:       timeout,
+	}
+}
+
+// CheckReadiness pings the database and checks that its schema is at least the expected version;
+// The probe is unauthenticated, so the reason a check failed is logged rather than returned
+func (s *healthService) CheckReadiness(ctx context.Context) entity.Readiness {
+	readiness := entity.Readiness{Status: entity.ReadinessReady}
+	check := func(name string, run func(ctx context.Context) error) {
+		ctx, cancel := context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+
+		result := entity.HealthCheck{Name: name, Status: entity.HealthCheckOK}
+		if err := run(ctx); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "readiness check failed", "check", name, "error", err)
+			result.Status = entity.HealthCheckFailed
+			result.Error = entity.HealthCheckFailedMessage
+			readiness.Status = entity.ReadinessUnavailable
+		}
+		readiness.Checks = append(readiness.Checks, result)
+	}
+
+	check("database", s.repo.Ping)
+	check("migrations", func(ctx context.Context) error {
+		version, err := s.repo.GetSchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if version < s.schemaVersion {
+			return fmt.Errorf("schema version is %d, expected at least %d", version, s.schemaVersion)
+		}
+		return nil
+	})
+
+	return readiness
+}
+
+// DatabaseStats returns the statistics of the database connection pool
+func (s *healthService) DatabaseStats() sql.DBStats {
+	return s.repo.Stats()
+}
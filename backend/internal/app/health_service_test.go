@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHealthRepository is a mock implementation of HealthRepository
+type MockHealthRepository struct {
+	mock.Mock
+}
+
+func (m *MockHealthRepository) Ping(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockHealthRepository) GetSchemaVersion(ctx context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockHealthRepository) Stats() sql.DBStats {
+	args := m.Called()
+	return args.Get(0).(sql.DBStats)
+}
+
+func TestCheckReadiness(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		mockRepo := new(MockHealthRepository)
+		service := NewHealthService(mockRepo, 13, time.Second)
+
+		mockRepo.On("Ping").Return(nil)
+		mockRepo.On("GetSchemaVersion").Return(14, nil)
+
+		readiness := service.CheckReadiness(context.Background())
+		assert.True(t, readiness.Ready())
+		assert.Equal(t, []entity.HealthCheck{
+			{Name: "database", Status: entity.HealthCheckOK},
+			{Name: "migrations", Status: entity.HealthCheckOK},
+		}, readiness.Checks)
+	})
+
+	t.Run("outdated schema", func(t *testing.T) {
+		mockRepo := new(MockHealthRepository)
+		service := NewHealthService(mockRepo, 13, time.Second)
+
+		mockRepo.On("Ping").Return(nil)
+		mockRepo.On("GetSchemaVersion").Return(12, nil)
+
+		readiness := service.CheckReadiness(context.Background())
+		assert.False(t, readiness.Ready())
+		assert.Equal(t, entity.ReadinessUnavailable, readiness.Status)
+		assert.Equal(t, entity.HealthCheckFailed, readiness.Checks[1].Status)
+		assert.Equal(t, entity.HealthCheckFailedMessage, readiness.Checks[1].Error)
+	})
+
+	t.Run("database unreachable", func(t *testing.T) {
+		mockRepo := new(MockHealthRepository)
+		service := NewHealthService(mockRepo, 13, time.Second)
+
+		mockRepo.On("Ping").Return(errors.New("connection refused"))
+		mockRepo.On("GetSchemaVersion").Return(0, errors.New("connection refused"))
+
+		readiness := service.CheckReadiness(context.Background())
+		assert.False(t, readiness.Ready())
+		assert.Equal(t, entity.HealthCheckFailedMessage, readiness.Checks[0].Error)
+		assert.NotContains(t, readiness.Checks[1].Error, "connection refused")
+	})
+}
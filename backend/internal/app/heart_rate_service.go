@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/liviaruegger/MAC0350/backend/internal/mapper"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+type HeartRateService interface {
+	GetHeartRateZones(ctx context.Context, userID uuid.UUID) (entity.HeartRateZones, error)
+	GetTimeInZone(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.TimeInZoneSummary, error)
+}
+
+// heartRateService provides heart-rate zones and time-in-zone statistics
+type heartRateService struct {
+	userRepo     repository.UserRepository
+	activityRepo repository.ActivityRepository
+	intervalRepo repository.IntervalRepository
+}
+
+// NewHeartRateService creates a new HeartRateService
+func NewHeartRateService(userRepo repository.UserRepository, activityRepo repository.ActivityRepository, intervalRepo repository.IntervalRepository) *heartRateService {
+	return &heartRateService{
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+		intervalRepo: intervalRepo,
+	}
+}
+
+// GetHeartRateZones returns the heart-rate zones of a user
+func (s *heartRateService) GetHeartRateZones(ctx context.Context, userID uuid.UUID) (entity.HeartRateZones, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return entity.HeartRateZones{}, err
+	}
+
+	return mapper.MapHeartRateZonesToEntity(user), nil
+}
+
+// GetTimeInZone returns the time a user spent in each heart-rate zone between from and to (inclusive)
+func (s *heartRateService) GetTimeInZone(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.TimeInZoneSummary, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return entity.TimeInZoneSummary{}, err
+	}
+
+	activities, err := s.activityRepo.GetActivitiesByUser(ctx, userID)
+	if err != nil {
+		return entity.TimeInZoneSummary{}, err
+	}
+
+	zones := user.HeartRateZones()
+	total := domain.TimeInZones(domain.Activity{}, nil, zones)
+	var count int
+	for _, activity := range activities {
+		if day := activity.Day(); day.Before(from) || day.After(to) {
+			continue
+		}
+
+		intervals, err := s.intervalRepo.GetIntervalsByActivity(ctx, activity.ID)
+		if err != nil {
+			return entity.TimeInZoneSummary{}, err
+		}
+
+		total = domain.SumZoneTimes(total, domain.TimeInZones(activity, intervals, zones))
+		count++
+	}
+
+	return entity.TimeInZoneSummary{
+		UserID:     userID,
+		From:       from.Format(domain.DateLayout),
+		To:         to.Format(domain.DateLayout),
+		Activities: count,
+		TimeInZone: mapper.MapZoneTimesToEntity(total),
+	}, nil
+}
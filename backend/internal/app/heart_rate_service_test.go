@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHeartRateZones(t *testing.T) {
+	userID := uuid.New()
+	userRepo := &mockUserRepo{users: map[uuid.UUID]domain.User{
+		userID: {ID: userID, LactateThresholdHeartRate: 170},
+	}}
+	service := NewHeartRateService(userRepo, new(MockActivityRepository), new(MockIntervalRepository))
+
+	t.Run("success", func(t *testing.T) {
+		result, err := service.GetHeartRateZones(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, result.UserID)
+		assert.Equal(t, "lthr", result.Basis)
+		assert.Len(t, result.Zones, 5)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		_, err := service.GetHeartRateZones(context.Background(), uuid.New())
+		assert.Error(t, err)
+	})
+}
+
+func TestGetTimeInZone(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+	inPeriodID := uuid.New()
+	activities := []domain.Activity{
+		{ID: inPeriodID, UserID: userID, Date: "2023-10-02", Duration: domain.MustParseDuration("1h0m0s"), HeartRateAvg: 150},
+		{ID: uuid.New(), UserID: userID, Date: "2023-09-20", Duration: domain.MustParseDuration("1h0m0s"), HeartRateAvg: 150},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		userRepo := &mockUserRepo{users: map[uuid.UUID]domain.User{
+			userID: {ID: userID, MaxHeartRate: 200},
+		}}
+		activityRepo := new(MockActivityRepository)
+		intervalRepo := new(MockIntervalRepository)
+		service := NewHeartRateService(userRepo, activityRepo, intervalRepo)
+
+		activityRepo.On("GetActivitiesByUser", userID).Return(activities, nil)
+		intervalRepo.On("GetIntervalsByActivity", inPeriodID).Return([]domain.Interval{
+			{ActivityID: inPeriodID, Duration: domain.MustParseDuration("20m0s"), HeartRateAvg: 185},
+		}, nil)
+
+		result, err := service.GetTimeInZone(context.Background(), userID, from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Activities)
+		assert.Equal(t, "2023-10-01", result.From)
+		assert.Len(t, result.TimeInZone, 5)
+		assert.Equal(t, domain.MustParseDuration("20m0s"), result.TimeInZone[4].Duration)
+		assert.Equal(t, domain.MustParseDuration("0s"), result.TimeInZone[2].Duration)
+		activityRepo.AssertExpectations(t)
+		intervalRepo.AssertExpectations(t)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		activityRepo := new(MockActivityRepository)
+		service := NewHeartRateService(&mockUserRepo{users: map[uuid.UUID]domain.User{}}, activityRepo, new(MockIntervalRepository))
+
+		_, err := service.GetTimeInZone(context.Background(), userID, from, to)
+		assert.Error(t, err)
+		activityRepo.AssertNotCalled(t, "GetActivitiesByUser", userID)
+	})
+
+	t.Run("interval repo error", func(t *testing.T) {
+		userRepo := &mockUserRepo{users: map[uuid.UUID]domain.User{
+			userID: {ID: userID, Age: 30},
+		}}
+		activityRepo := new(MockActivityRepository)
+		intervalRepo := new(MockIntervalRepository)
+		service := NewHeartRateService(userRepo, activityRepo, intervalRepo)
+
+		activityRepo.On("GetActivitiesByUser", userID).Return(activities, nil)
+		intervalRepo.On("GetIntervalsByActivity", inPeriodID).Return([]domain.Interval{}, errors.New("db error"))
+
+		_, err := service.GetTimeInZone(context.Background(), userID, from, to)
+		assert.Error(t, err)
+	})
+}
@@ -1,24 +1,38 @@
 package app
 
 import (
+	"context"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 	"github.com/liviaruegger/MAC0350/backend/internal/repository"
 )
 
 type IntervalService interface {
-	CreateInterval(interval domain.Interval) error
+	CreateInterval(ctx context.Context, interval domain.Interval) error
 }
 
 // IntervalService provides interval-related operations
 type intervalService struct {
-	repo repository.IntervalRepository
+	repo         repository.IntervalRepository
+	activityRepo repository.ActivityRepository
+	audit        auditLog
 }
 
-// NewIntervalService creates a new IntervalService
-func NewIntervalService(r repository.IntervalRepository) *intervalService {
-	return &intervalService{repo: r}
+// NewIntervalService creates a new IntervalService; new intervals are recorded in
+// the audit log of the owner of their activity
+func NewIntervalService(r repository.IntervalRepository, activityRepo repository.ActivityRepository, audit repository.AuditRepository) *intervalService {
+	return &intervalService{repo: r, activityRepo: activityRepo, audit: auditLog{repo: audit}}
 }
 
-func (s *intervalService) CreateInterval(interval domain.Interval) error {
-	return s.repo.CreateInterval(interval)
+func (s *intervalService) CreateInterval(ctx context.Context, interval domain.Interval) error {
+	return s.audit.change(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateInterval(ctx, interval); err != nil {
+			return err
+		}
+
+		activity, err := s.activityRepo.GetActivityByID(ctx, interval.ActivityID)
+		if err != nil {
+			return err
+		}
+		return s.audit.record(ctx, activity.UserID, domain.AuditEntityInterval, interval.ID, domain.AuditActionCreate, nil, interval)
+	})
 }
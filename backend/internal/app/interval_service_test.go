@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -14,20 +15,20 @@ type mockIntervalRepository struct {
 	createFunc func(domain.Interval) error
 }
 
-func (m *mockIntervalRepository) CreateInterval(interval domain.Interval) error {
+func (m *mockIntervalRepository) CreateInterval(ctx context.Context, interval domain.Interval) error {
 	if m.createFunc != nil {
 		return m.createFunc(interval)
 	}
 	return nil
 }
 
-func (m *mockIntervalRepository) GetIntervalsByActivity(activityID uuid.UUID) ([]domain.Interval, error) {
+func (m *mockIntervalRepository) GetIntervalsByActivity(ctx context.Context, activityID uuid.UUID) ([]domain.Interval, error) {
 	// Mock implementation for testing purposes
 	return []domain.Interval{
 		{
 			ID:         uuid.New(),
 			ActivityID: activityID,
-			Duration:   domain.DurationString("30m"),
+			Duration:   domain.MustParseDuration("30m"),
 			Distance:   1000,
 			Type:       domain.IntervalType("swim"),
 			Stroke:     domain.StrokeType("freestyle"),
@@ -36,9 +37,21 @@ func (m *mockIntervalRepository) GetIntervalsByActivity(activityID uuid.UUID) ([
 	}, nil
 }
 
+func (m *mockIntervalRepository) GetIntervalsByActivities(ctx context.Context, activityIDs []uuid.UUID) (map[uuid.UUID][]domain.Interval, error) {
+	intervals := make(map[uuid.UUID][]domain.Interval, len(activityIDs))
+	for _, activityID := range activityIDs {
+		intervals[activityID], _ = m.GetIntervalsByActivity(ctx, activityID)
+	}
+	return intervals, nil
+}
+
+func (m *mockIntervalRepository) GetIntervalByID(ctx context.Context, intervalID uuid.UUID) (domain.Interval, error) {
+	return domain.Interval{ID: intervalID}, nil
+}
+
 func TestNewIntervalService(t *testing.T) {
 	mockRepo := &mockIntervalRepository{}
-	service := NewIntervalService(mockRepo)
+	service := NewIntervalService(mockRepo, new(MockActivityRepository), &mockAuditRepo{})
 	if service == nil {
 		t.Fatal("expected non-nil service")
 	}
@@ -59,7 +72,7 @@ func TestCreateInterval(t *testing.T) {
 			interval: domain.Interval{
 				ID:         uuid.New(),
 				ActivityID: uuid.New(),
-				Duration:   domain.DurationString((30 * time.Minute).String()),
+				Duration:   domain.Duration(30 * time.Minute),
 				Distance:   1000,
 				Type:       domain.IntervalType("swim"),
 				Stroke:     domain.StrokeType("freestyle"),
@@ -85,8 +98,10 @@ func TestCreateInterval(t *testing.T) {
 			mockRepo := &mockIntervalRepository{
 				createFunc: tc.createFunc,
 			}
-			service := NewIntervalService(mockRepo)
-			err := service.CreateInterval(tc.interval)
+			mockActivityRepo := new(MockActivityRepository)
+			mockActivityRepo.On("GetActivityByID", tc.interval.ActivityID).Return(domain.Activity{ID: tc.interval.ActivityID, UserID: uuid.New()}, nil).Maybe()
+			service := NewIntervalService(mockRepo, mockActivityRepo, &mockAuditRepo{})
+			err := service.CreateInterval(context.Background(), tc.interval)
 			if tc.expectedErr == nil && err != nil {
 				t.Errorf("expected nil error, got %v", err)
 			}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+// JobQueue enqueues the background jobs built by jobs.NewJob, see jobs.Runner
+type JobQueue interface {
+	Enqueue(ctx context.Context, job domain.Job) (domain.Job, error)
+}
+
+type JobService interface {
+	GetJob(ctx context.Context, id uuid.UUID) (domain.Job, error)
+}
+
+// jobService serves the status of background jobs to the users they work for and to admins
+type jobService struct {
+	repo repository.JobRepository
+}
+
+// NewJobService creates a new JobService
+func NewJobService(r repository.JobRepository) *jobService {
+	return &jobService{repo: r}
+}
+
+// GetJob returns the status of a job; The jobs working for no user are only served to admins
+func (s *jobService) GetJob(ctx context.Context, id uuid.UUID) (domain.Job, error) {
+	job, err := s.repo.GetJobByID(ctx, id)
+	if err != nil {
+		return domain.Job{}, err
+	}
+
+	if job.UserID.Valid {
+		err = authorizeUser(ctx, job.UserID.UUID, "job")
+	} else if actor := auth.FromContext(ctx); !actor.Authenticated() {
+		err = domain.NewUnauthorizedError("authentication required to access the job")
+	} else if !actor.Admin {
+		err = domain.NewForbiddenError("only admins may access system jobs")
+	}
+	if err != nil {
+		return domain.Job{}, err
+	}
+	return job, nil
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockJobRepository is a mock implementation of JobRepository
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobRepository) EnqueueJob(ctx context.Context, job domain.Job) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ScheduleJob(ctx context.Context, job domain.Job, delay time.Duration) error {
+	args := m.Called(job, delay)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ClaimJob(ctx context.Context, types []string, lease time.Duration) (domain.Job, error) {
+	args := m.Called(types, lease)
+	return args.Get(0).(domain.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) CompleteJob(ctx context.Context, id uuid.UUID, attempt int) error {
+	args := m.Called(id, attempt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) RetryJob(ctx context.Context, id uuid.UUID, attempt int, delay time.Duration, lastError string) error {
+	args := m.Called(id, attempt, delay, lastError)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) FailJob(ctx context.Context, id uuid.UUID, attempt int, lastError string) error {
+	args := m.Called(id, attempt, lastError)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) GetJobByID(ctx context.Context, id uuid.UUID) (domain.Job, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Job), args.Error(1)
+}
+
+func TestGetJob(t *testing.T) {
+	userID := uuid.New()
+	repo := new(MockJobRepository)
+	service := NewJobService(repo)
+
+	userJob := domain.Job{ID: uuid.New(), Type: ExportJobType, UserID: uuid.NullUUID{UUID: userID, Valid: true}, Status: domain.JobRunning}
+	systemJob := domain.Job{ID: uuid.New(), Type: "recompute", Status: domain.JobQueued}
+	repo.On("GetJobByID", userJob.ID).Return(userJob, nil)
+	repo.On("GetJobByID", systemJob.ID).Return(systemJob, nil)
+
+	user := auth.WithActor(context.Background(), auth.Actor{UserID: userID})
+	other := auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New()})
+	admin := auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New(), Admin: true})
+
+	t.Run("the user's job", func(t *testing.T) {
+		job, err := service.GetJob(user, userJob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, userJob, job)
+
+		_, err = service.GetJob(other, userJob.ID)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		_, err = service.GetJob(admin, userJob.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("system job", func(t *testing.T) {
+		_, err := service.GetJob(user, systemJob.ID)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		_, err = service.GetJob(context.Background(), systemJob.ID)
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+		job, err := service.GetJob(admin, systemJob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, systemJob, job)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		id := uuid.New()
+		repo.On("GetJobByID", id).Return(domain.Job{}, domain.NewNotFoundError("job not found"))
+
+		_, err := service.GetJob(user, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
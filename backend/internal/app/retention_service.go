@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+// PurgeJobType is the type of the recurring jobs purging the records past the
+// retention period, handled by PurgeDeletedJob
+const PurgeJobType = "purge_deleted"
+
+type RetentionService interface {
+	RestoreUser(ctx context.Context, id uuid.UUID) error
+	RestoreActivity(ctx context.Context, activityID uuid.UUID) error
+	PurgeDeleted(ctx context.Context) (int64, error)
+}
+
+// retentionService keeps soft deleted records restorable for the retention period
+type retentionService struct {
+	repo         repository.RetentionRepository
+	userRepo     repository.UserRepository
+	activityRepo repository.ActivityRepository
+	audit        auditLog
+	retention    time.Duration
+}
+
+// NewRetentionService creates a new RetentionService; deleted records can be
+// restored for the retention period, and are purged after it. Restorations are
+// recorded in the audit log with the restored record
+func NewRetentionService(r repository.RetentionRepository, userRepo repository.UserRepository, activityRepo repository.ActivityRepository, audit repository.AuditRepository, retention time.Duration) *retentionService {
+	return &retentionService{
+		repo:         r,
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+		audit:        auditLog{repo: audit},
+		retention:    retention,
+	}
+}
+
+// RestoreUser undeletes a user along with the activities deleted with them
+func (s *retentionService) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	return s.audit.change(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreUser(ctx, id, s.retention); err != nil {
+			return err
+		}
+
+		user, err := s.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.audit.record(ctx, id, domain.AuditEntityUser, id, domain.AuditActionRestore, nil, user)
+	})
+}
+
+// RestoreActivity undeletes an activity along with its intervals
+func (s *retentionService) RestoreActivity(ctx context.Context, activityID uuid.UUID) error {
+	return s.audit.change(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreActivity(ctx, activityID, s.retention); err != nil {
+			return err
+		}
+
+		activity, err := s.activityRepo.GetActivityByID(ctx, activityID)
+		if err != nil {
+			return err
+		}
+		return s.audit.record(ctx, activity.UserID, domain.AuditEntityActivity, activityID, domain.AuditActionRestore, nil, activity)
+	})
+}
+
+// PurgeDeleted permanently deletes the records past the retention period
+func (s *retentionService) PurgeDeleted(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeDeleted(ctx, s.retention)
+	if err != nil {
+		return purged, err
+	}
+
+	if purged > 0 {
+		logging.FromContext(ctx).InfoContext(ctx, "purged deleted records", "count", purged, "retention", s.retention.String())
+	}
+	return purged, nil
+}
+
+// PurgeDeletedJob is the handler of purge jobs
+func (s *retentionService) PurgeDeletedJob(ctx context.Context, job domain.Job) error {
+	_, err := s.PurgeDeleted(ctx)
+	return err
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRetentionRepository is a mock implementation of RetentionRepository
+type MockRetentionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRetentionRepository) RestoreUser(ctx context.Context, id uuid.UUID, retention time.Duration) error {
+	args := m.Called(id, retention)
+	return args.Error(0)
+}
+
+func (m *MockRetentionRepository) RestoreActivity(ctx context.Context, activityID uuid.UUID, retention time.Duration) error {
+	args := m.Called(activityID, retention)
+	return args.Error(0)
+}
+
+func (m *MockRetentionRepository) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	args := m.Called(retention)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestRetentionService(t *testing.T) {
+	const retention = 7 * 24 * time.Hour
+
+	t.Run("restores within the retention period", func(t *testing.T) {
+		mockRepo := new(MockRetentionRepository)
+		userID, activityID := uuid.New(), uuid.New()
+		users := &mockUserRepo{users: map[uuid.UUID]domain.User{userID: {ID: userID, Name: "Ana"}}}
+		audit := &mockAuditRepo{}
+		service := NewRetentionService(mockRepo, users, new(MockActivityRepository), audit, retention)
+
+		mockRepo.On("RestoreUser", userID, retention).Return(nil)
+		mockRepo.On("RestoreActivity", activityID, retention).Return(domain.NewNotFoundError("deleted activity not found"))
+
+		assert.NoError(t, service.RestoreUser(context.Background(), userID))
+		assert.ErrorIs(t, service.RestoreActivity(context.Background(), activityID), domain.ErrNotFound)
+		mockRepo.AssertExpectations(t)
+
+		assert.Len(t, audit.events, 1)
+		assert.Equal(t, domain.AuditActionRestore, audit.events[0].Action)
+		assert.Equal(t, domain.FieldChange{After: "Ana"}, audit.events[0].Changes["name"])
+	})
+
+	t.Run("purges past the retention period", func(t *testing.T) {
+		mockRepo := new(MockRetentionRepository)
+		service := NewRetentionService(mockRepo, nil, nil, nil, retention)
+
+		mockRepo.On("PurgeDeleted", retention).Return(int64(4), nil).Once()
+		mockRepo.On("PurgeDeleted", retention).Return(int64(0), errors.New("connection refused")).Once()
+
+		purged, err := service.PurgeDeleted(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), purged)
+
+		_, err = service.PurgeDeleted(context.Background())
+		assert.Error(t, err)
+	})
+}
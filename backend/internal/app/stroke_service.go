@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/liviaruegger/MAC0350/backend/internal/mapper"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+type StrokeService interface {
+	GetStrokeEfficiency(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.StrokeEfficiencyTrend, error)
+}
+
+// strokeService provides stroke efficiency trends
+type strokeService struct {
+	activityRepo repository.ActivityRepository
+	intervalRepo repository.IntervalRepository
+}
+
+// NewStrokeService creates a new StrokeService
+func NewStrokeService(activityRepo repository.ActivityRepository, intervalRepo repository.IntervalRepository) *strokeService {
+	return &strokeService{
+		activityRepo: activityRepo,
+		intervalRepo: intervalRepo,
+	}
+}
+
+// GetStrokeEfficiency returns the stroke metrics of each session of a user between
+// from and to (inclusive), skipping sessions without stroke data
+func (s *strokeService) GetStrokeEfficiency(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.StrokeEfficiencyTrend, error) {
+	activities, err := s.activityRepo.GetActivitiesByUser(ctx, userID)
+	if err != nil {
+		return entity.StrokeEfficiencyTrend{}, err
+	}
+
+	sessions := []entity.SessionStrokeEfficiency{}
+	for _, activity := range activities {
+		if day := activity.Day(); day.Before(from) || day.After(to) {
+			continue
+		}
+
+		intervals, err := s.intervalRepo.GetIntervalsByActivity(ctx, activity.ID)
+		if err != nil {
+			return entity.StrokeEfficiencyTrend{}, err
+		}
+
+		if metrics, ok := activity.StrokeMetrics(intervals); ok {
+			sessions = append(sessions, mapper.MapSessionStrokeEfficiencyToEntity(activity, metrics))
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Date < sessions[j].Date
+	})
+
+	return entity.StrokeEfficiencyTrend{
+		UserID:   userID,
+		From:     from.Format(domain.DateLayout),
+		To:       to.Format(domain.DateLayout),
+		Sessions: sessions,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStrokeEfficiency(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 27)
+	laterID, earlierID, noStrokesID := uuid.New(), uuid.New(), uuid.New()
+	activities := []domain.Activity{
+		{ID: laterID, UserID: userID, Date: "2023-10-08", PoolSize: 25, LocationType: domain.LocationPool},
+		{ID: earlierID, UserID: userID, Date: "2023-10-01", PoolSize: 25, LocationType: domain.LocationPool},
+		{ID: noStrokesID, UserID: userID, Date: "2023-10-03", PoolSize: 25, LocationType: domain.LocationPool},
+		{ID: uuid.New(), UserID: userID, Date: "2023-09-01", PoolSize: 25, LocationType: domain.LocationPool},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		activityRepo := new(MockActivityRepository)
+		intervalRepo := new(MockIntervalRepository)
+		service := NewStrokeService(activityRepo, intervalRepo)
+
+		activityRepo.On("GetActivitiesByUser", userID).Return(activities, nil)
+		intervalRepo.On("GetIntervalsByActivity", laterID).Return([]domain.Interval{
+			{Duration: domain.MustParseDuration("1m36s"), Distance: 100, Type: domain.IntervalSwim, StrokeCount: 64},
+		}, nil)
+		intervalRepo.On("GetIntervalsByActivity", earlierID).Return([]domain.Interval{
+			{Duration: domain.MustParseDuration("1m40s"), Distance: 100, Type: domain.IntervalSwim, StrokeCount: 68},
+		}, nil)
+		intervalRepo.On("GetIntervalsByActivity", noStrokesID).Return([]domain.Interval{
+			{Duration: domain.MustParseDuration("1m40s"), Distance: 100, Type: domain.IntervalSwim},
+		}, nil)
+
+		result, err := service.GetStrokeEfficiency(context.Background(), userID, from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, "2023-10-01", result.From)
+		assert.Len(t, result.Sessions, 2)
+		assert.Equal(t, earlierID, result.Sessions[0].ActivityID)
+		assert.Equal(t, 42.0, result.Sessions[0].SWOLF)
+		assert.Equal(t, laterID, result.Sessions[1].ActivityID)
+		assert.Equal(t, 40.0, result.Sessions[1].SWOLF)
+		activityRepo.AssertExpectations(t)
+		intervalRepo.AssertExpectations(t)
+	})
+
+	t.Run("activity repo error", func(t *testing.T) {
+		activityRepo := new(MockActivityRepository)
+		service := NewStrokeService(activityRepo, new(MockIntervalRepository))
+
+		activityRepo.On("GetActivitiesByUser", userID).Return([]domain.Activity{}, errors.New("db error"))
+
+		_, err := service.GetStrokeEfficiency(context.Background(), userID, from, to)
+		assert.Error(t, err)
+	})
+
+	t.Run("interval repo error", func(t *testing.T) {
+		activityRepo := new(MockActivityRepository)
+		intervalRepo := new(MockIntervalRepository)
+		service := NewStrokeService(activityRepo, intervalRepo)
+
+		activityRepo.On("GetActivitiesByUser", userID).Return(activities[:1], nil)
+		intervalRepo.On("GetIntervalsByActivity", laterID).Return([]domain.Interval{}, errors.New("db error"))
+
+		_, err := service.GetStrokeEfficiency(context.Background(), userID, from, to)
+		assert.Error(t, err)
+	})
+}
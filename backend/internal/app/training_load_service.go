@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/liviaruegger/MAC0350/backend/internal/mapper"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+type TrainingLoadService interface {
+	GetTrainingLoad(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.TrainingLoad, error)
+}
+
+// trainingLoadService computes training load and fatigue metrics from logged activities
+type trainingLoadService struct {
+	userRepo     repository.UserRepository
+	activityRepo repository.ActivityRepository
+}
+
+// NewTrainingLoadService creates a new TrainingLoadService
+func NewTrainingLoadService(userRepo repository.UserRepository, activityRepo repository.ActivityRepository) *trainingLoadService {
+	return &trainingLoadService{
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// GetTrainingLoad returns the daily, acute and chronic loads of a user between from and to (inclusive)
+func (s *trainingLoadService) GetTrainingLoad(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.TrainingLoad, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return entity.TrainingLoad{}, err
+	}
+
+	activities, err := s.activityRepo.GetActivitiesByUser(ctx, userID)
+	if err != nil {
+		return entity.TrainingLoad{}, err
+	}
+
+	loads := domain.ComputeDailyLoads(activities, user, from, to)
+
+	return mapper.MapTrainingLoadToEntity(userID, from, to, loads), nil
+}
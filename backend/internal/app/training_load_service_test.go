@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTrainingLoad(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+
+	t.Run("success", func(t *testing.T) {
+		userRepo := &mockUserRepo{users: map[uuid.UUID]domain.User{
+			userID: {ID: userID, Age: 30},
+		}}
+		activityRepo := new(MockActivityRepository)
+		service := NewTrainingLoadService(userRepo, activityRepo)
+
+		activityRepo.On("GetActivitiesByUser", userID).Return([]domain.Activity{
+			{ID: uuid.New(), UserID: userID, Date: "2023-10-01", Duration: domain.MustParseDuration("1h0m0s"), RPE: 6},
+			{ID: uuid.New(), UserID: userID, Date: "2023-10-03", Duration: domain.MustParseDuration("45m0s"), HeartRateAvg: 140},
+		}, nil)
+
+		result, err := service.GetTrainingLoad(context.Background(), userID, from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, result.UserID)
+		assert.Equal(t, "2023-10-01", result.From)
+		assert.Equal(t, "2023-10-07", result.To)
+		assert.Len(t, result.Days, 7)
+		assert.Equal(t, 360.0, result.Days[0].Load)
+		assert.Greater(t, result.Days[2].Load, 0.0)
+		activityRepo.AssertExpectations(t)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		userRepo := &mockUserRepo{users: map[uuid.UUID]domain.User{}}
+		activityRepo := new(MockActivityRepository)
+		service := NewTrainingLoadService(userRepo, activityRepo)
+
+		_, err := service.GetTrainingLoad(context.Background(), userID, from, to)
+		assert.Error(t, err)
+		activityRepo.AssertNotCalled(t, "GetActivitiesByUser", userID)
+	})
+
+	t.Run("activity repo error", func(t *testing.T) {
+		userRepo := &mockUserRepo{users: map[uuid.UUID]domain.User{
+			userID: {ID: userID},
+		}}
+		activityRepo := new(MockActivityRepository)
+		service := NewTrainingLoadService(userRepo, activityRepo)
+
+		activityRepo.On("GetActivitiesByUser", userID).Return([]domain.Activity{}, errors.New("db error"))
+
+		_, err := service.GetTrainingLoad(context.Background(), userID, from, to)
+		assert.Error(t, err)
+		activityRepo.AssertExpectations(t)
+	})
+}
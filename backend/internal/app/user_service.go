@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 	"github.com/liviaruegger/MAC0350/backend/internal/repository"
 
@@ -8,44 +9,75 @@ import (
 )
 
 type UserService interface {
-	CreateUser(user domain.User) error
-	GetAllUsers() ([]domain.User, error)
-	GetUserByID(id uuid.UUID) (domain.User, error)
-	GetUserByEmail(email string) (domain.User, error)
-	UpdateUser(user domain.User) error
-	DeleteUser(id uuid.UUID) error
+	CreateUser(ctx context.Context, user domain.User) error
+	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (domain.User, error)
+	UpdateUser(ctx context.Context, user domain.User) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
 }
 
 // UserService provides user-related operations
 type userService struct {
-	repo repository.UserRepository
+	repo  repository.UserRepository
+	audit auditLog
 }
 
-// NewUserService creates a new UserService
-func NewUserService(r repository.UserRepository) *userService {
-	return &userService{repo: r}
+// NewUserService creates a new UserService; changes to users are recorded in the audit log
+func NewUserService(r repository.UserRepository, audit repository.AuditRepository) *userService {
+	return &userService{repo: r, audit: auditLog{repo: audit}}
 }
 
-func (s *userService) CreateUser(user domain.User) error {
-	return s.repo.CreateUser(user)
+func (s *userService) CreateUser(ctx context.Context, user domain.User) error {
+	return s.audit.change(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, user.ID, domain.AuditEntityUser, user.ID, domain.AuditActionCreate, nil, user)
+	})
 }
 
-func (s *userService) GetAllUsers() ([]domain.User, error) {
-	return s.repo.GetAllUsers()
+func (s *userService) GetAllUsers(ctx context.Context) ([]domain.User, error) {
+	return s.repo.GetAllUsers(ctx)
 }
 
-func (s *userService) GetUserByID(id uuid.UUID) (domain.User, error) {
-	return s.repo.GetUserByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	return s.repo.GetUserByID(ctx, id)
 }
 
-func (s *userService) GetUserByEmail(email string) (domain.User, error) {
-	return s.repo.GetUserByEmail(email)
+func (s *userService) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
+	return s.repo.GetUserByEmail(ctx, email)
 }
 
-func (s *userService) UpdateUser(user domain.User) error {
-	return s.repo.UpdateUser(user)
+func (s *userService) UpdateUser(ctx context.Context, user domain.User) error {
+	return s.audit.change(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetUserByID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetUserByID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.record(ctx, user.ID, domain.AuditEntityUser, user.ID, domain.AuditActionUpdate, before, after)
+	})
 }
 
-func (s *userService) DeleteUser(id uuid.UUID) error {
-	return s.repo.DeleteUser(id)
+// DeleteUser deletes a user along with their activities; the deletion is
+// recorded once, for the user
+func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return s.audit.change(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.record(ctx, id, domain.AuditEntityUser, id, domain.AuditActionDelete, before, nil)
+	})
 }
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -14,7 +15,7 @@ type mockUserRepo struct {
 	users map[uuid.UUID]domain.User
 }
 
-func (m *mockUserRepo) CreateUser(user domain.User) error {
+func (m *mockUserRepo) CreateUser(ctx context.Context, user domain.User) error {
 	if _, exists := m.users[user.ID]; exists {
 		return errors.New("user already exists")
 	}
@@ -22,7 +23,7 @@ func (m *mockUserRepo) CreateUser(user domain.User) error {
 	return nil
 }
 
-func (m *mockUserRepo) GetAllUsers() ([]domain.User, error) {
+func (m *mockUserRepo) GetAllUsers(ctx context.Context) ([]domain.User, error) {
 	var userList []domain.User
 	for _, user := range m.users {
 		userList = append(userList, user)
@@ -30,15 +31,15 @@ func (m *mockUserRepo) GetAllUsers() ([]domain.User, error) {
 	return userList, nil
 }
 
-func (m *mockUserRepo) GetUserByID(id uuid.UUID) (domain.User, error) {
+func (m *mockUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (domain.User, error) {
 	user, exists := m.users[id]
 	if !exists {
-		return domain.User{}, errors.New("user not found")
+		return domain.User{}, domain.NewNotFoundError("user not found")
 	}
 	return user, nil
 }
 
-func (m *mockUserRepo) GetUserByEmail(email string) (domain.User, error) {
+func (m *mockUserRepo) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
 	for _, user := range m.users {
 		if user.Email == email {
 			return user, nil
@@ -47,7 +48,7 @@ func (m *mockUserRepo) GetUserByEmail(email string) (domain.User, error) {
 	return domain.User{}, errors.New("user not found")
 }
 
-func (m *mockUserRepo) UpdateUser(user domain.User) error {
+func (m *mockUserRepo) UpdateUser(ctx context.Context, user domain.User) error {
 	if _, exists := m.users[user.ID]; !exists {
 		return errors.New("user not found")
 	}
@@ -55,7 +56,7 @@ func (m *mockUserRepo) UpdateUser(user domain.User) error {
 	return nil
 }
 
-func (m *mockUserRepo) DeleteUser(id uuid.UUID) error {
+func (m *mockUserRepo) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	if _, exists := m.users[id]; !exists {
 		return errors.New("user not found")
 	}
@@ -65,7 +66,7 @@ func (m *mockUserRepo) DeleteUser(id uuid.UUID) error {
 
 func TestUserService(t *testing.T) {
 	repo := &mockUserRepo{users: make(map[uuid.UUID]domain.User)}
-	service := NewUserService(repo)
+	service := NewUserService(repo, &mockAuditRepo{})
 
 	userID := uuid.New()
 	user := domain.User{
@@ -75,13 +76,13 @@ func TestUserService(t *testing.T) {
 	}
 
 	// Test CreateUser
-	err := service.CreateUser(user)
+	err := service.CreateUser(context.Background(), user)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
 
 	// Test GetAllUsers
-	users, err := service.GetAllUsers()
+	users, err := service.GetAllUsers(context.Background())
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -90,7 +91,7 @@ func TestUserService(t *testing.T) {
 	}
 
 	// Test GetUserByID
-	retrieved, err := service.GetUserByID(userID)
+	retrieved, err := service.GetUserByID(context.Background(), userID)
 	if err != nil {
 		t.Fatalf("expected to find user, got error: %v", err)
 	}
@@ -99,7 +100,7 @@ func TestUserService(t *testing.T) {
 	}
 
 	// Test GetUserByEmail
-	retrievedByEmail, err := service.GetUserByEmail("ana@example.com")
+	retrievedByEmail, err := service.GetUserByEmail(context.Background(), "ana@example.com")
 	if err != nil {
 		t.Fatalf("expected to find user by email, got error: %v", err)
 	}
@@ -109,21 +110,21 @@ func TestUserService(t *testing.T) {
 
 	// Test UpdateUser
 	user.Name = "Ana Paula"
-	err = service.UpdateUser(user)
+	err = service.UpdateUser(context.Background(), user)
 	if err != nil {
 		t.Fatalf("expected no error on update, got: %v", err)
 	}
-	updated, _ := service.GetUserByID(userID)
+	updated, _ := service.GetUserByID(context.Background(), userID)
 	if updated.Name != "Ana Paula" {
 		t.Errorf("expected updated name 'Ana Paula', got: %s", updated.Name)
 	}
 
 	// Test DeleteUser
-	err = service.DeleteUser(userID)
+	err = service.DeleteUser(context.Background(), userID)
 	if err != nil {
 		t.Fatalf("expected no error on delete, got: %v", err)
 	}
-	_, err = service.GetUserByID(userID)
+	_, err = service.GetUserByID(context.Background(), userID)
 	if err == nil {
 		t.Errorf("expected error after deleting user, got nil")
 	}
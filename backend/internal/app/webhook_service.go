@@ -0,0 +1,384 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/jobs"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+// WebhookJobType is the type of the jobs delivering events to webhooks, handled by DeliverWebhook
+const WebhookJobType = "webhook"
+
+// Headers of the webhook deliveries; The signature is the hex-encoded HMAC-SHA256,
+// keyed by the webhook secret, of the timestamp, a dot and the body, prefixed by "sha256="
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// webhookTimeout bounds each attempt at delivering an event
+	webhookTimeout = 10 * time.Second
+	// maxWebhookDeliveries is how many of the latest deliveries of a webhook are served
+	maxWebhookDeliveries = 100
+)
+
+// webhookJob is the payload of a webhook job; The body is rendered when the event
+// occurs, so every attempt posts the same one
+type webhookJob struct {
+	WebhookID uuid.UUID           `json:"webhook_id"`
+	EventID   uuid.UUID           `json:"event_id"`
+	Event     domain.WebhookEvent `json:"event"`
+	Body      json.RawMessage     `json:"body"`
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	GetWebhooksByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error)
+}
+
+// webhookService manages the webhooks of the users and delivers their events
+type webhookService struct {
+	repo     repository.WebhookRepository
+	userRepo repository.UserRepository
+	client   *http.Client
+}
+
+// NewWebhookService creates a new WebhookService; Deliveries do not follow redirects,
+// and are refused to loopback, private and link-local addresses unless allowPrivate
+func NewWebhookService(r repository.WebhookRepository, userRepo repository.UserRepository, allowPrivate bool) *webhookService {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = publicOnly
+	}
+	return &webhookService{
+		repo:     r,
+		userRepo: userRepo,
+		client: &http.Client{
+			// No proxy, so the dialer checks the address of the webhook itself
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: webhookTimeout,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// errPrivateDestination is returned when a webhook resolves to an address that
+// is not public, e.g., a service of the internal network or the cloud metadata
+var errPrivateDestination = errors.New("destination is not a public address")
+
+// publicOnly is the dialer control refusing connections to the addresses that
+// are not public; It runs on the resolved address, so a DNS name cannot point
+// a webhook to an internal one
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = unwrapIPv4(ip)
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%s: %w", ip, errPrivateDestination)
+	}
+	for _, prefix := range nonGlobalPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%s: %w", ip, errPrivateDestination)
+		}
+	}
+	return nil
+}
+
+// nat64Prefix is the well-known NAT64 prefix, whose addresses embed the IPv4
+// address they are translated to in their last 4 bytes
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// unwrapIPv4 returns the IPv4 address an IPv4-mapped or NAT64 address reaches,
+// else the address itself
+func unwrapIPv4(ip netip.Addr) netip.Addr {
+	ip = ip.Unmap()
+	if nat64Prefix.Contains(ip) {
+		bytes := ip.As16()
+		return netip.AddrFrom4([4]byte(bytes[12:]))
+	}
+	return ip
+}
+
+// nonGlobalPrefixes are the special-purpose ranges that are not globally
+// reachable (RFC 6890 and its IANA registries), beyond those netip reports:
+// shared, reserved, documentation and benchmarking ones, and the IPv6 ones
+// translating to IPv4 addresses that cannot be checked
+var nonGlobalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("3fff::/20"),
+	netip.MustParsePrefix("5f00::/16"),
+}
+
+// CreateWebhook registers a webhook of a user with a new secret, returned only here;
+// Only the user and admins may register one
+func (s *webhookService) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := authorizeUser(ctx, webhook.UserID, "webhooks"); err != nil {
+		return domain.Webhook{}, err
+	}
+	if err := webhook.Validate(); err != nil {
+		return domain.Webhook{}, err
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, webhook.UserID); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.Webhook{}, err
+	}
+	webhook.ID = uuid.New()
+	webhook.Secret = "whsec_" + hex.EncodeToString(secret)
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	created, err := s.repo.GetWebhookByID(ctx, webhook.ID)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	return created, nil
+}
+
+// GetWebhooksByUser returns the webhooks of a user, without their secrets
+func (s *webhookService) GetWebhooksByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	if err := authorizeUser(ctx, userID, "webhooks"); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.repo.GetWebhooksByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	if webhooks == nil {
+		return []domain.Webhook{}, nil
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes a webhook; the deliveries still queued are dropped
+func (s *webhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getWebhook(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteWebhook(ctx, id)
+}
+
+// GetDeliveries returns the latest attempts at delivering events to a webhook, most recent first
+func (s *webhookService) GetDeliveries(ctx context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error) {
+	if _, err := s.getWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.GetDeliveriesByWebhook(ctx, webhookID, maxWebhookDeliveries)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		return []domain.WebhookDelivery{}, nil
+	}
+	return deliveries, nil
+}
+
+// getWebhook returns a webhook the actor may access
+func (s *webhookService) getWebhook(ctx context.Context, id uuid.UUID) (domain.Webhook, error) {
+	webhook, err := s.repo.GetWebhookByID(ctx, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if err := authorizeUser(ctx, webhook.UserID, "webhooks"); err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// DeliverWebhook is the handler of webhook jobs: it posts the event to the webhook,
+// signed with its secret, and records the attempt in the delivery log. Any response
+// but a 2xx fails the attempt, to be retried
+func (s *webhookService) DeliverWebhook(ctx context.Context, job domain.Job) error {
+	var payload webhookJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("decoding webhook job payload: %w", err))
+	}
+	webhook, err := s.repo.GetWebhookByID(ctx, payload.WebhookID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	delivery := domain.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: webhook.ID,
+		EventID:   payload.EventID,
+		Event:     payload.Event,
+		Attempt:   job.Attempts,
+	}
+	start := time.Now()
+	delivery.StatusCode, err = s.post(ctx, webhook, payload, start)
+	delivery.DurationMS = time.Since(start).Milliseconds()
+	if err == nil && !delivery.Succeeded() {
+		err = fmt.Errorf("responded %d %s", delivery.StatusCode, http.StatusText(delivery.StatusCode))
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if err := s.repo.RecordDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "recording webhook delivery failed", "webhook_id", webhook.ID.String(), "error", err.Error())
+	}
+	return err
+}
+
+// post sends the body of an event to a webhook and returns the status of the response
+func (s *webhookService) post(ctx context.Context, webhook domain.Webhook, payload webhookJob, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload.Body))
+	if err != nil {
+		return 0, jobs.Permanent(err)
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SwimTracker-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, string(payload.Event))
+	req.Header.Set(WebhookIDHeader, payload.EventID.String())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(webhook.Secret, timestamp, payload.Body))
+
+	resp, err := s.client.Do(req)
+	if errors.Is(err, errPrivateDestination) {
+		return 0, jobs.Permanent(err)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain some of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the signature of a delivery, as sent in the signature header;
+// Receivers check a delivery by computing it from the timestamp header and the raw body
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookEvents enqueues the delivery of the events of a user to the webhooks
+// subscribed to them; The change already happened when its event is published,
+// so failing to enqueue a delivery is logged instead of failing the request
+type webhookEvents struct {
+	repo  repository.WebhookRepository
+	queue JobQueue
+}
+
+// subscribers returns the webhooks of a user subscribed to an event
+func (w webhookEvents) subscribers(ctx context.Context, userID uuid.UUID, event domain.WebhookEvent) []domain.Webhook {
+	webhooks, err := w.repo.GetWebhooksByUser(ctx, userID)
+	if err != nil {
+		w.fail(ctx, event, err)
+		return nil
+	}
+
+	var subscribers []domain.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Subscribed(event) {
+			subscribers = append(subscribers, webhook)
+		}
+	}
+	return subscribers
+}
+
+// publish enqueues the delivery of an event with its data to the webhooks of a user subscribed to it
+func (w webhookEvents) publish(ctx context.Context, userID uuid.UUID, event domain.WebhookEvent, data any) {
+	w.deliver(ctx, w.subscribers(ctx, userID, event), event, data)
+}
+
+// deliver enqueues the delivery of an event with its data to each of the webhooks
+func (w webhookEvents) deliver(ctx context.Context, webhooks []domain.Webhook, event domain.WebhookEvent, data any) {
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload := domain.WebhookPayload{ID: uuid.New(), Event: event, OccurredAt: time.Now().UTC(), Data: data}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		w.fail(ctx, event, err)
+		return
+	}
+	for _, webhook := range webhooks {
+		job, err := jobs.NewJob(WebhookJobType, uuid.NullUUID{UUID: webhook.UserID, Valid: true},
+			webhookJob{WebhookID: webhook.ID, EventID: payload.ID, Event: event, Body: body})
+		if err == nil {
+			_, err = w.queue.Enqueue(ctx, job)
+		}
+		if err != nil {
+			w.fail(ctx, event, err)
+		}
+	}
+}
+
+// fail logs an event that could not be published
+func (w webhookEvents) fail(ctx context.Context, event domain.WebhookEvent, err error) {
+	logging.FromContext(ctx).ErrorContext(ctx, "publishing webhook event failed", "event", string(event), "error", err.Error())
+}
@@ -0,0 +1,342 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/events"
+	"github.com/liviaruegger/MAC0350/backend/internal/jobs"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockWebhookRepo keeps the webhooks and their delivery log in memory
+type mockWebhookRepo struct {
+	webhooks   map[uuid.UUID]domain.Webhook
+	deliveries []domain.WebhookDelivery
+	err        error
+}
+
+func (m *mockWebhookRepo) CreateWebhook(ctx context.Context, webhook domain.Webhook) error {
+	if m.webhooks == nil {
+		m.webhooks = make(map[uuid.UUID]domain.Webhook)
+	}
+	webhook.CreatedAt = time.Now()
+	m.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (m *mockWebhookRepo) GetWebhookByID(ctx context.Context, id uuid.UUID) (domain.Webhook, error) {
+	webhook, exists := m.webhooks[id]
+	if !exists {
+		return domain.Webhook{}, domain.NewNotFoundError("webhook not found")
+	}
+	return webhook, nil
+}
+
+func (m *mockWebhookRepo) GetWebhooksByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var webhooks []domain.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *mockWebhookRepo) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *mockWebhookRepo) RecordDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *mockWebhookRepo) GetDeliveriesByWebhook(ctx context.Context, webhookID uuid.UUID, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func TestCreateWebhook(t *testing.T) {
+	userID := uuid.New()
+	repo := &mockWebhookRepo{}
+	service := NewWebhookService(repo, &mockUserRepo{users: map[uuid.UUID]domain.User{userID: {ID: userID}}}, false)
+	ctx := auth.WithActor(context.Background(), auth.Actor{UserID: userID})
+	webhook := domain.Webhook{UserID: userID, URL: "https://hooks.example.com/swims", Events: []domain.WebhookEvent{domain.WebhookActivityCreated}}
+
+	t.Run("generates a secret", func(t *testing.T) {
+		created, err := service.CreateWebhook(ctx, webhook)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, created.ID)
+		assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+		assert.Len(t, created.Secret, len("whsec_")+64)
+
+		webhooks, err := service.GetWebhooksByUser(ctx, userID)
+		assert.NoError(t, err)
+		assert.Len(t, webhooks, 1)
+		assert.Empty(t, webhooks[0].Secret, "the secret is only served on creation")
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		invalid := webhook
+		invalid.URL = "hooks.example.com"
+		_, err := service.CreateWebhook(ctx, invalid)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("only for the user and admins", func(t *testing.T) {
+		_, err := service.CreateWebhook(auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New()}), webhook)
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		_, err = service.GetWebhooksByUser(context.Background(), userID)
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	userID := uuid.New()
+	webhook := domain.Webhook{ID: uuid.New(), UserID: userID}
+	repo := &mockWebhookRepo{webhooks: map[uuid.UUID]domain.Webhook{webhook.ID: webhook}}
+	service := NewWebhookService(repo, &mockUserRepo{}, false)
+
+	err := service.DeleteWebhook(auth.WithActor(context.Background(), auth.Actor{UserID: uuid.New()}), webhook.ID)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Contains(t, repo.webhooks, webhook.ID)
+
+	err = service.DeleteWebhook(auth.WithActor(context.Background(), auth.Actor{UserID: userID}), webhook.ID)
+	assert.NoError(t, err)
+	assert.NotContains(t, repo.webhooks, webhook.ID)
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	var status int
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	webhook := domain.Webhook{ID: uuid.New(), UserID: uuid.New(), URL: server.URL + "/hook", Secret: "whsec_42"}
+	repo := &mockWebhookRepo{webhooks: map[uuid.UUID]domain.Webhook{webhook.ID: webhook}}
+	// The test receiver listens on the loopback address
+	service := NewWebhookService(repo, &mockUserRepo{}, true)
+
+	eventID := uuid.New()
+	payload := []byte(`{"id":"` + eventID.String() + `","event":"activity.created","data":{"distance":2000}}`)
+	newJob := func(webhookID uuid.UUID, attempt int) domain.Job {
+		job, err := jobs.NewJob(WebhookJobType, uuid.NullUUID{UUID: webhook.UserID, Valid: true},
+			webhookJob{WebhookID: webhookID, EventID: eventID, Event: domain.WebhookActivityCreated, Body: payload})
+		assert.NoError(t, err)
+		job.Attempts = attempt
+		return job
+	}
+
+	t.Run("signed delivery", func(t *testing.T) {
+		status = http.StatusNoContent
+		err := service.DeliverWebhook(context.Background(), newJob(webhook.ID, 1))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "/hook", received.URL.Path)
+		assert.JSONEq(t, string(payload), string(body))
+		assert.Equal(t, "activity.created", received.Header.Get(WebhookEventHeader))
+		assert.Equal(t, eventID.String(), received.Header.Get(WebhookIDHeader))
+		timestamp := received.Header.Get(WebhookTimestampHeader)
+		assert.Equal(t, SignWebhook("whsec_42", timestamp, body), received.Header.Get(WebhookSignatureHeader))
+		assert.NotEqual(t, SignWebhook("whsec_43", timestamp, body), received.Header.Get(WebhookSignatureHeader))
+
+		assert.Len(t, repo.deliveries, 1)
+		delivery := repo.deliveries[0]
+		assert.Equal(t, 204, delivery.StatusCode)
+		assert.Equal(t, 1, delivery.Attempt)
+		assert.Equal(t, eventID, delivery.EventID)
+		assert.Empty(t, delivery.Error)
+	})
+
+	t.Run("unsuccessful status is retried", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		err := service.DeliverWebhook(context.Background(), newJob(webhook.ID, 2))
+		assert.EqualError(t, err, "responded 503 Service Unavailable")
+		assert.False(t, jobs.IsPermanent(err))
+
+		delivery := repo.deliveries[len(repo.deliveries)-1]
+		assert.Equal(t, 503, delivery.StatusCode)
+		assert.Equal(t, 2, delivery.Attempt)
+		assert.Equal(t, "responded 503 Service Unavailable", delivery.Error)
+	})
+
+	t.Run("unreachable endpoint", func(t *testing.T) {
+		unreachable := domain.Webhook{ID: uuid.New(), URL: "http://127.0.0.1:1/hook", Secret: "whsec_42"}
+		repo.webhooks[unreachable.ID] = unreachable
+
+		err := service.DeliverWebhook(context.Background(), newJob(unreachable.ID, 1))
+		assert.Error(t, err)
+		delivery := repo.deliveries[len(repo.deliveries)-1]
+		assert.Zero(t, delivery.StatusCode)
+		assert.NotEmpty(t, delivery.Error)
+	})
+
+	t.Run("deleted webhook", func(t *testing.T) {
+		err := service.DeliverWebhook(context.Background(), newJob(uuid.New(), 1))
+		assert.True(t, jobs.IsPermanent(err))
+	})
+
+	t.Run("private destination", func(t *testing.T) {
+		service := NewWebhookService(repo, &mockUserRepo{}, false)
+		received = nil
+
+		err := service.DeliverWebhook(context.Background(), newJob(webhook.ID, 1))
+		assert.ErrorIs(t, err, errPrivateDestination)
+		assert.True(t, jobs.IsPermanent(err))
+		assert.Nil(t, received)
+		delivery := repo.deliveries[len(repo.deliveries)-1]
+		assert.Zero(t, delivery.StatusCode)
+		assert.Contains(t, delivery.Error, "destination is not a public address")
+	})
+}
+
+func TestPublicOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		public  bool
+	}{
+		{"93.184.215.14:443", true},
+		{"[2606:2800:21f:cb07:6820:80da:af6b:8b2c]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.1.2.3:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:80", false},
+		{"0.0.0.0:80", false},
+		{"[fd00::1]:80", false},
+		{"[fe80::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"[64:ff9b::5db8:d70e]:443", true},
+		{"[64:ff9b::a9fe:a9fe]:80", false},
+		{"[64:ff9b::7f00:1]:80", false},
+		{"[64:ff9b:1::a00:1]:80", false},
+		{"0.1.2.3:80", false},
+		{"192.0.0.8:80", false},
+		{"192.0.2.1:80", false},
+		{"198.18.0.1:80", false},
+		{"198.19.255.255:80", false},
+		{"203.0.113.1:80", false},
+		{"240.0.0.1:80", false},
+		{"255.255.255.255:80", false},
+		{"[2001:db8::1]:80", false},
+		{"[2001::1]:80", false},
+		{"[2002:a00:1::1]:80", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := publicOnly("tcp", tt.address, nil)
+			if tt.public {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errPrivateDestination)
+			}
+		})
+	}
+}
+
+func TestActivityEvents(t *testing.T) {
+	userID := uuid.New()
+	created := domain.Webhook{ID: uuid.New(), UserID: userID, Events: []domain.WebhookEvent{domain.WebhookActivityCreated}}
+	records := domain.Webhook{ID: uuid.New(), UserID: userID, Events: []domain.WebhookEvent{domain.WebhookRecordAchieved, domain.WebhookActivityDeleted}}
+	webhooks := &mockWebhookRepo{webhooks: map[uuid.UUID]domain.Webhook{created.ID: created, records.ID: records}}
+
+	newService := func() (*activityService, *MockActivityRepository, *fakeJobQueue) {
+		repo, queue := new(MockActivityRepository), &fakeJobQueue{}
+		service := NewActivityService(repo, new(MockIntervalRepository), new(MockCSSRepository), &mockUserRepo{}, &mockAuditRepo{}, webhooks, queue, events.NewBroker(nil))
+		return service, repo, queue
+	}
+	delivered := func(t *testing.T, job domain.Job) (webhookJob, domain.WebhookPayload) {
+		var payload webhookJob
+		assert.NoError(t, json.Unmarshal(job.Payload, &payload))
+		var body domain.WebhookPayload
+		assert.NoError(t, json.Unmarshal(payload.Body, &body))
+		return payload, body
+	}
+
+	t.Run("created activity with a record", func(t *testing.T) {
+		service, repo, queue := newService()
+		activity := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2026-10-02", Distance: 3000}
+		repo.On("CreateActivity", activity).Return(nil)
+		repo.On("GetActivitiesByUser", userID).Return([]domain.Activity{{ID: uuid.New(), Date: "2026-10-01", Distance: 2000}, activity}, nil)
+
+		assert.NoError(t, service.CreateActivity(context.Background(), activity))
+		assert.Len(t, queue.jobs, 2)
+
+		payload, body := delivered(t, queue.jobs[0])
+		assert.Equal(t, created.ID, payload.WebhookID)
+		assert.Equal(t, domain.WebhookActivityCreated, body.Event)
+		assert.Equal(t, payload.EventID, body.ID)
+
+		payload, body = delivered(t, queue.jobs[1])
+		assert.Equal(t, records.ID, payload.WebhookID)
+		assert.Equal(t, domain.WebhookRecordAchieved, body.Event)
+		assert.Equal(t, map[string]any{"type": "longest_distance", "activity_id": activity.ID.String(), "value": 3000.0, "previous": 2000.0}, body.Data)
+	})
+
+	t.Run("backfilled activity with a record", func(t *testing.T) {
+		service, repo, queue := newService()
+		activity := domain.Activity{ID: uuid.New(), UserID: userID, Date: "2026-09-01", Distance: 3000}
+		repo.On("CreateActivity", activity).Return(nil)
+		repo.On("GetActivitiesByUser", userID).Return([]domain.Activity{
+			{ID: uuid.New(), Date: "2026-08-01", Distance: 2000},
+			{ID: uuid.New(), Date: "2026-10-01", Distance: 4000},
+			activity,
+		}, nil)
+
+		assert.NoError(t, service.CreateActivity(context.Background(), activity))
+		assert.Len(t, queue.jobs, 2)
+		_, body := delivered(t, queue.jobs[1])
+		assert.Equal(t, domain.WebhookRecordAchieved, body.Event)
+		assert.Equal(t, 2000.0, body.Data.(map[string]any)["previous"], "the later activity does not count")
+	})
+
+	t.Run("deleted activity", func(t *testing.T) {
+		service, repo, queue := newService()
+		activity := domain.Activity{ID: uuid.New(), UserID: userID}
+		repo.On("GetActivityByID", activity.ID).Return(activity, nil)
+		repo.On("DeleteActivity", activity.ID).Return(nil)
+
+		assert.NoError(t, service.DeleteActivity(context.Background(), activity.ID))
+		assert.Len(t, queue.jobs, 1)
+		payload, _ := delivered(t, queue.jobs[0])
+		assert.Equal(t, records.ID, payload.WebhookID)
+		assert.Equal(t, domain.WebhookActivityDeleted, payload.Event)
+	})
+
+	t.Run("enqueue failure does not fail the change", func(t *testing.T) {
+		service, repo, queue := newService()
+		queue.err = errors.New("connection refused")
+		activity := domain.Activity{ID: uuid.New(), UserID: userID}
+		repo.On("GetActivityByID", activity.ID).Return(activity, nil)
+		repo.On("DeleteActivity", activity.ID).Return(nil)
+
+		assert.NoError(t, service.DeleteActivity(context.Background(), activity.ID))
+	})
+}
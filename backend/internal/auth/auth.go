@@ -0,0 +1,46 @@
+// Package auth carries the actor of each request, the user on whose behalf it
+// is made, through its context, and decides what that actor may access
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey struct{}
+
+// Actor is the user making a request; The zero Actor is anonymous
+type Actor struct {
+	// UserID is the ID of the authenticated user, or uuid.Nil if anonymous
+	UserID uuid.UUID
+	// Admin grants access to the records of every user
+	Admin bool
+}
+
+// Authenticated reports whether the actor is a known user
+func (a Actor) Authenticated() bool {
+	return a.UserID != uuid.Nil
+}
+
+// CanAccess reports whether the actor may access the records owned by userID,
+// i.e., whether they are that user or an admin
+func (a Actor) CanAccess(userID uuid.UUID) bool {
+	return a.Admin || a.Authenticated() && a.UserID == userID
+}
+
+// ID returns the ID of the actor, which is not valid if anonymous
+func (a Actor) ID() uuid.NullUUID {
+	return uuid.NullUUID{UUID: a.UserID, Valid: a.Authenticated()}
+}
+
+// WithActor returns a copy of ctx carrying the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// FromContext returns the actor carried by ctx, or an anonymous actor if there is none
+func FromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(contextKey{}).(Actor)
+	return actor
+}
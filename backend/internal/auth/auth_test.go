@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got.Authenticated() {
+		t.Errorf("expected an anonymous actor for a context without an actor, got %v", got)
+	}
+
+	actor := Actor{UserID: uuid.New()}
+	if got := FromContext(WithActor(context.Background(), actor)); got != actor {
+		t.Errorf("expected %v, got %v", actor, got)
+	}
+}
+
+func TestActor_CanAccess(t *testing.T) {
+	owner := uuid.New()
+	tests := []struct {
+		name  string
+		actor Actor
+		want  bool
+	}{
+		{"anonymous", Actor{}, false},
+		{"owner", Actor{UserID: owner}, true},
+		{"other user", Actor{UserID: uuid.New()}, false},
+		{"admin", Actor{UserID: uuid.New(), Admin: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.actor.CanAccess(owner); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if (Actor{}).CanAccess(uuid.Nil) {
+		t.Error("an anonymous actor must not access the records of the nil user")
+	}
+}
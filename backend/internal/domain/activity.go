@@ -1,7 +1,7 @@
 package domain
 
 import (
-	"fmt"
+	"database/sql/driver"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +16,40 @@ const (
 	LocationOpenWater LocationType = "open_water"
 )
 
+var locationTypes = newEnum("LocationType", LocationPool, LocationOpenWater)
+
+// Valid reports whether the location type is one of the predefined location types
+func (l LocationType) Valid() bool {
+	return locationTypes.valid(l)
+}
+
+// Values returns the predefined location types
+func (LocationType) Values() []string {
+	return locationTypes.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown location types
+func (l LocationType) MarshalText() ([]byte, error) {
+	return locationTypes.marshalText(l)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown location types
+func (l *LocationType) UnmarshalText(text []byte) (err error) {
+	*l, err = locationTypes.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (l *LocationType) Scan(src any) (err error) {
+	*l, err = locationTypes.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (l LocationType) Value() (driver.Value, error) {
+	return locationTypes.value(l)
+}
+
 // FeelingType defines options for how a swimmer feels after a session
 type FeelingType string
 
@@ -28,6 +62,40 @@ const (
 	FeelingBad       FeelingType = "bad"
 )
 
+var feelingTypes = newEnum("FeelingType", FeelingExcellent, FeelingGood, FeelingRegular, FeelingTired, FeelingBad)
+
+// Valid reports whether the feeling is one of the predefined feelings
+func (f FeelingType) Valid() bool {
+	return feelingTypes.valid(f)
+}
+
+// Values returns the predefined feelings
+func (FeelingType) Values() []string {
+	return feelingTypes.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown feelings
+func (f FeelingType) MarshalText() ([]byte, error) {
+	return feelingTypes.marshalText(f)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown feelings
+func (f *FeelingType) UnmarshalText(text []byte) (err error) {
+	*f, err = feelingTypes.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (f *FeelingType) Scan(src any) (err error) {
+	*f, err = feelingTypes.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (f FeelingType) Value() (driver.Value, error) {
+	return feelingTypes.value(f)
+}
+
 // Activity represents a full swim session
 type Activity struct {
 	// ID is the unique identifier for the activity (PK)
@@ -38,14 +106,16 @@ type Activity struct {
 	Date string `json:"date"`
 	// Start time of the activity
 	Start time.Time `json:"start"`
-	// Duration of the activity in string format, e.g., "1h30m"
-	Duration DurationString `json:"duration"`
+	// Duration of the activity, with millisecond precision
+	Duration Duration `json:"duration" swaggertype:"string"`
 	// Total distance in meters
 	Distance float64 `json:"distance"`
 	// Number of pool laps
 	Laps int `json:"laps"`
-	// Pool length in meters (0 if open water)
+	// Pool length, in PoolUnit (0 if open water)
 	PoolSize float64 `json:"pool_size"`
+	// Unit of the pool length, "meters" (default) or "yards"
+	PoolUnit DistanceUnit `json:"pool_unit,omitempty"`
 	// "pool" or "open_water"
 	LocationType LocationType `json:"location_type"`
 	// Optional name for the location, e.g., "CEPE"
@@ -56,8 +126,17 @@ type Activity struct {
 	HeartRateAvg int `json:"heart_rate_avg,omitempty"`
 	// Maximum heart rate during the activity
 	HeartRateMax int `json:"heart_rate_max,omitempty"`
+	// Optional rating of perceived exertion, from 1 (very easy) to 10 (maximal)
+	RPE int `json:"rpe,omitempty"`
 	// Optional notes
 	Notes string `json:"notes"`
+	// Version is incremented on every update; An update with a non-zero version
+	// only applies if the stored activity still has that version
+	Version int `json:"version" readonly:"true"`
+	// CreatedAt is when the activity was created, set by the repository
+	CreatedAt time.Time `json:"created_at,omitzero" readonly:"true"`
+	// UpdatedAt is when the activity was last updated, set by the repository
+	UpdatedAt time.Time `json:"updated_at,omitzero" readonly:"true"`
 }
 
 // AvgPacePer100m returns the average pace in seconds per 100 meters
@@ -69,16 +148,22 @@ func (a Activity) AvgPacePer100m() float64 {
 	return a.Duration.Seconds() / a.Distance * 100
 }
 
+// AvgPacePer100 returns the average pace in seconds per 100 of the given unit
+func (a Activity) AvgPacePer100(unit DistanceUnit) float64 {
+	return PacePer100(a.AvgPacePer100m(), unit)
+}
+
 // AvgPaceFormatted returns the pace as a string in the format mm:ss per 100m;
 // If distance is 0, it returns "N/A"
 func (a Activity) AvgPaceFormatted() string {
-	pace := a.AvgPacePer100m()
-	if pace == 0 {
-		return "N/A"
-	}
-
-	minutes := int(pace) / 60
-	seconds := int(pace) % 60
+	return FormatPace(a.AvgPacePer100m())
+}
 
-	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+// Before reports whether the activity was swum before other: on an earlier date,
+// or earlier on the same date
+func (a Activity) Before(other Activity) bool {
+	if a.Date != other.Date {
+		return a.Date < other.Date
+	}
+	return a.Start.Before(other.Start)
 }
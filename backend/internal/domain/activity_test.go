@@ -14,15 +14,15 @@ func TestAvgPacePer100m(t *testing.T) {
 		{
 			name: "Valid distance and duration",
 			activity: Activity{
-				Duration: DurationString((time.Duration(1500) * time.Second).String()), // 25 minutes
-				Distance: 1000,                                                         // 1 km
+				Duration: Duration(time.Duration(1500) * time.Second), // 25 minutes
+				Distance: 1000,                                        // 1 km
 			},
 			expected: 150, // 150 seconds per 100m
 		},
 		{
 			name: "Zero distance",
 			activity: Activity{
-				Duration: DurationString((time.Duration(1500) * time.Second).String()),
+				Duration: Duration(time.Duration(1500) * time.Second),
 				Distance: 0,
 			},
 			expected: 0,
@@ -30,7 +30,7 @@ func TestAvgPacePer100m(t *testing.T) {
 		{
 			name: "Zero duration",
 			activity: Activity{
-				Duration: DurationString((0 * time.Second).String()),
+				Duration: Duration(0 * time.Second),
 				Distance: 1000,
 			},
 			expected: 0,
@@ -56,15 +56,15 @@ func TestAvgPaceFormatted(t *testing.T) {
 		{
 			name: "Valid distance and duration",
 			activity: Activity{
-				Duration: DurationString((time.Duration(1500) * time.Second).String()), // 25 minutes
-				Distance: 1000,                                                         // 1 km
+				Duration: Duration(time.Duration(1500) * time.Second), // 25 minutes
+				Distance: 1000,                                        // 1 km
 			},
 			expected: "02:30", // 2 minutes 30 seconds per 100m
 		},
 		{
 			name: "Zero distance",
 			activity: Activity{
-				Duration: DurationString((time.Duration(1500) * time.Second).String()),
+				Duration: Duration(time.Duration(1500) * time.Second),
 				Distance: 0,
 			},
 			expected: "N/A",
@@ -72,7 +72,7 @@ func TestAvgPaceFormatted(t *testing.T) {
 		{
 			name: "Zero duration",
 			activity: Activity{
-				Duration: DurationString((0 * time.Second).String()),
+				Duration: Duration(0 * time.Second),
 				Distance: 1000,
 			},
 			expected: "N/A",
@@ -88,3 +88,12 @@ func TestAvgPaceFormatted(t *testing.T) {
 		})
 	}
 }
+
+func TestActivityEnumsValid(t *testing.T) {
+	if !LocationOpenWater.Valid() || LocationType("lake").Valid() || LocationType("").Valid() {
+		t.Error("unexpected LocationType validity")
+	}
+	if !FeelingRegular.Valid() || FeelingType("great").Valid() {
+		t.Error("unexpected FeelingType validity")
+	}
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction defines the kind of change recorded by an audit event
+type AuditAction string
+
+// Predefined audit actions
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+)
+
+var auditActions = newEnum("AuditAction", AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionRestore)
+
+// Valid reports whether the action is one of the predefined actions
+func (a AuditAction) Valid() bool {
+	return auditActions.valid(a)
+}
+
+// Values returns the predefined actions
+func (AuditAction) Values() []string {
+	return auditActions.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown actions
+func (a AuditAction) MarshalText() ([]byte, error) {
+	return auditActions.marshalText(a)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown actions
+func (a *AuditAction) UnmarshalText(text []byte) (err error) {
+	*a, err = auditActions.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (a *AuditAction) Scan(src any) (err error) {
+	*a, err = auditActions.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (a AuditAction) Value() (driver.Value, error) {
+	return auditActions.value(a)
+}
+
+// AuditEntityType defines the kind of record changed by an audit event
+type AuditEntityType string
+
+// Predefined audited entity types
+const (
+	AuditEntityUser     AuditEntityType = "user"
+	AuditEntityActivity AuditEntityType = "activity"
+	AuditEntityInterval AuditEntityType = "interval"
+	AuditEntityCSSTest  AuditEntityType = "css_test"
+)
+
+var auditEntityTypes = newEnum("AuditEntityType", AuditEntityUser, AuditEntityActivity, AuditEntityInterval, AuditEntityCSSTest)
+
+// Valid reports whether the entity type is one of the predefined types
+func (e AuditEntityType) Valid() bool {
+	return auditEntityTypes.valid(e)
+}
+
+// Values returns the predefined entity types
+func (AuditEntityType) Values() []string {
+	return auditEntityTypes.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown entity types
+func (e AuditEntityType) MarshalText() ([]byte, error) {
+	return auditEntityTypes.marshalText(e)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown entity types
+func (e *AuditEntityType) UnmarshalText(text []byte) (err error) {
+	*e, err = auditEntityTypes.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (e *AuditEntityType) Scan(src any) (err error) {
+	*e, err = auditEntityTypes.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (e AuditEntityType) Value() (driver.Value, error) {
+	return auditEntityTypes.value(e)
+}
+
+// FieldChange holds the values of a field before and after a change, as serialized
+// in the API; Before is omitted when the record was created, After when it was deleted
+type FieldChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// AuditEvent records a change made to a record
+type AuditEvent struct {
+	// ID is the unique identifier for the event (PK)
+	ID uuid.UUID `json:"id"`
+	// OccurredAt is when the change was made, set by the repository
+	OccurredAt time.Time `json:"occurred_at"`
+	// ActorID is the ID of the user who made the change, null if made anonymously
+	ActorID uuid.NullUUID `json:"actor_id" swaggertype:"string"`
+	// UserID is the ID of the user owning the changed record
+	UserID uuid.UUID `json:"user_id"`
+	// EntityType is the kind of the changed record
+	EntityType AuditEntityType `json:"entity_type"`
+	// EntityID is the ID of the changed record
+	EntityID uuid.UUID `json:"entity_id"`
+	// Action is the kind of change
+	Action AuditAction `json:"action"`
+	// Changes maps each changed field, by its JSON name, to its values before and after the change
+	Changes map[string]FieldChange `json:"changes"`
+}
+
+// unauditedFields are maintained by the repositories, so they change on every write
+var unauditedFields = []string{"version", "created_at", "updated_at"}
+
+// Diff returns the fields that differ between the JSON representations of two
+// versions of a record; before is nil for a created record, after for a deleted one
+func Diff(before, after any) (map[string]FieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]FieldChange)
+	for name, value := range beforeFields {
+		if afterValue, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changes[name] = FieldChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = FieldChange{After: value}
+		}
+	}
+	for _, name := range unauditedFields {
+		delete(changes, name)
+	}
+
+	return changes, nil
+}
+
+// jsonFields decodes the JSON representation of a record into its fields
+func jsonFields(record any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if record == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
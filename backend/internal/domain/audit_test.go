@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDiff(t *testing.T) {
+	before := User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com", Age: 30, Version: 1, UpdatedAt: time.Now()}
+	after := before
+	after.Name = "John Updated"
+	after.MaxHeartRate = 188
+	after.Version = 2
+	after.UpdatedAt = before.UpdatedAt.Add(time.Minute)
+
+	t.Run("update", func(t *testing.T) {
+		changes, err := Diff(before, after)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := map[string]FieldChange{
+			"name":           {Before: "John Doe", After: "John Updated"},
+			"max_heart_rate": {After: float64(188)},
+		}
+		if !reflect.DeepEqual(changes, expected) {
+			t.Errorf("expected %v, got %v", expected, changes)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		changes, err := Diff(nil, before)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if changes["email"] != (FieldChange{After: "john@example.com"}) {
+			t.Errorf("unexpected email change %v", changes["email"])
+		}
+		if _, ok := changes["version"]; ok {
+			t.Error("expected version not to be audited")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		changes, err := Diff(before, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if changes["age"] != (FieldChange{Before: float64(30)}) {
+			t.Errorf("unexpected age change %v", changes["age"])
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		changes, err := Diff(before, before)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("expected no changes, got %v", changes)
+		}
+	})
+}
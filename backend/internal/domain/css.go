@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Distances of the two time trials of a CSS test, in the unit of the pool they are swum in
+const (
+	CSSLongTrialDistance  = 400
+	CSSShortTrialDistance = 200
+)
+
+// ErrInvalidCSSTest is returned when the 400m trial is not slower than the 200m trial
+var ErrInvalidCSSTest = NewValidationError(FieldError{Field: "time_400", Code: CodeOutOfRange, Message: "must be longer than time_200"})
+
+// CSSTest represents a Critical Swim Speed test, made of a 400m and a 200m time trial,
+// or a 400yd and a 200yd time trial in a yards pool
+type CSSTest struct {
+	// ID is the unique identifier for the test (PK)
+	ID uuid.UUID `json:"id"`
+	// UserID is the ID of the user who took the test (FK)
+	UserID uuid.UUID `json:"user_id"`
+	// Date in ISO 8601 format, e.g., "2023-10-01"
+	Date string `json:"date"`
+	// Time of the 400m trial
+	Time400 Duration `json:"time_400" swaggertype:"string"`
+	// Time of the 200m trial
+	Time200 Duration `json:"time_200" swaggertype:"string"`
+	// Optional ID of the logged interval used as the 400m trial
+	Interval400ID uuid.NullUUID `json:"interval_400_id"`
+	// Optional ID of the logged interval used as the 200m trial
+	Interval200ID uuid.NullUUID `json:"interval_200_id"`
+	// Unit the trial distances are in, "meters" (default) or "yards"
+	Unit DistanceUnit `json:"unit,omitempty"`
+}
+
+// Validate checks that the trial times can produce a critical swim speed
+func (c CSSTest) Validate() error {
+	if c.Time400.Seconds() <= c.Time200.Seconds() {
+		return ErrInvalidCSSTest
+	}
+
+	return nil
+}
+
+// PacePer100m returns the CSS pace in seconds per 100 meters;
+// If the test is not valid, it returns 0
+func (c CSSTest) PacePer100m() float64 {
+	if c.Validate() != nil {
+		return 0
+	}
+
+	difference := Distance{Value: CSSLongTrialDistance - CSSShortTrialDistance, Unit: c.Unit}
+	return (c.Time400.Seconds() - c.Time200.Seconds()) / difference.Meters() * 100
+}
+
+// Zones returns the pace zones derived from the CSS pace
+func (c CSSTest) Zones() []PaceZone {
+	css := c.PacePer100m()
+	if css == 0 {
+		return nil
+	}
+
+	zones := make([]PaceZone, len(cssZoneOffsets))
+	for i, offset := range cssZoneOffsets {
+		zone := PaceZone{Number: i + 1, Name: offset.name}
+		if !math.IsInf(offset.fastest, 0) {
+			zone.MinPace = css + offset.fastest
+		}
+		if !math.IsInf(offset.slowest, 0) {
+			zone.MaxPace = css + offset.slowest
+		}
+		zones[i] = zone
+	}
+
+	return zones
+}
+
+// cssZoneOffsets defines each pace zone as offsets, in seconds per 100m, from the CSS pace
+var cssZoneOffsets = []struct {
+	name    string
+	fastest float64
+	slowest float64
+}{
+	{"recovery", 10, math.Inf(1)},
+	{"endurance", 5, 10},
+	{"tempo", 2, 5},
+	{"threshold", -2, 2},
+	{"sprint", math.Inf(-1), -2},
+}
+
+// cssTrialTolerance is how far, in the pool unit, a distance converted from meters may
+// be from a trial distance, e.g., a 400yd interval is stored as 365.76 meters
+const cssTrialTolerance = 0.01
+
+// DetectCSSTrials looks for a 400 and a 200 trial, in the given pool unit, among the
+// given intervals, picking the fastest of each distance; rest intervals are ignored
+func DetectCSSTrials(intervals []Interval, unit DistanceUnit) (trial400, trial200 Interval, ok bool) {
+	var found400, found200 bool
+	for _, interval := range intervals {
+		if interval.Type == IntervalRest {
+			continue
+		}
+
+		distance := ConvertMeters(interval.Distance, unit)
+		switch {
+		case math.Abs(distance-CSSLongTrialDistance) < cssTrialTolerance:
+			if !found400 || interval.Duration.Seconds() < trial400.Duration.Seconds() {
+				trial400, found400 = interval, true
+			}
+		case math.Abs(distance-CSSShortTrialDistance) < cssTrialTolerance:
+			if !found200 || interval.Duration.Seconds() < trial200.Duration.Seconds() {
+				trial200, found200 = interval, true
+			}
+		}
+	}
+
+	return trial400, trial200, found400 && found200
+}
+
+// CSSTestAt returns the test in effect on the given ISO 8601 date, i.e., the
+// most recent test taken on or before it, falling back to the oldest test;
+// tests must be sorted from the most recent to the oldest
+func CSSTestAt(tests []CSSTest, date string) (CSSTest, bool) {
+	if len(tests) == 0 {
+		return CSSTest{}, false
+	}
+
+	for _, test := range tests {
+		if test.Date <= date {
+			return test, true
+		}
+	}
+
+	return tests[len(tests)-1], true
+}
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCSSTest_PacePer100m(t *testing.T) {
+	tests := []struct {
+		name     string
+		test     CSSTest
+		expected float64
+	}{
+		{
+			name:     "Valid trials",
+			test:     CSSTest{Time400: MustParseDuration("6m0s"), Time200: MustParseDuration("2m50s")},
+			expected: 95,
+		},
+		{
+			name:     "Yard trials",
+			test:     CSSTest{Time400: MustParseDuration("6m0s"), Time200: MustParseDuration("2m50s"), Unit: DistanceUnitYards},
+			expected: 95 / MetersPerYard,
+		},
+		{
+			name:     "400m faster than 200m",
+			test:     CSSTest{Time400: MustParseDuration("2m0s"), Time200: MustParseDuration("2m50s")},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.test.PacePer100m()
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCSSTest_Validate(t *testing.T) {
+	valid := CSSTest{Time400: MustParseDuration("6m0s"), Time200: MustParseDuration("2m50s")}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	invalid := CSSTest{Time400: MustParseDuration("2m50s"), Time200: MustParseDuration("2m50s")}
+	if err := invalid.Validate(); err != ErrInvalidCSSTest {
+		t.Errorf("expected ErrInvalidCSSTest, got %v", err)
+	}
+}
+
+func TestCSSTest_Zones(t *testing.T) {
+	test := CSSTest{Time400: MustParseDuration("6m0s"), Time200: MustParseDuration("2m50s")}
+	zones := test.Zones()
+	if len(zones) != 5 {
+		t.Fatalf("expected 5 zones, got %d", len(zones))
+	}
+
+	tests := []struct {
+		pace     float64
+		expected string
+	}{
+		{120, "recovery"},
+		{102, "endurance"},
+		{98, "tempo"},
+		{95, "threshold"},
+		{90, "sprint"},
+	}
+	for _, tt := range tests {
+		zone, ok := ZoneForPace(zones, tt.pace)
+		if !ok || zone.Name != tt.expected {
+			t.Errorf("pace %v: expected zone %q, got %q", tt.pace, tt.expected, zone.Name)
+		}
+	}
+
+	if zones := (CSSTest{}).Zones(); zones != nil {
+		t.Errorf("expected no zones for an invalid test, got %v", zones)
+	}
+}
+
+func TestDetectCSSTrials(t *testing.T) {
+	fast400 := Interval{ID: uuid.New(), Distance: 400, Duration: MustParseDuration("6m0s"), Type: IntervalMainSet}
+	slow400 := Interval{ID: uuid.New(), Distance: 400, Duration: MustParseDuration("7m0s"), Type: IntervalWarmUp}
+	trial200 := Interval{ID: uuid.New(), Distance: 200, Duration: MustParseDuration("2m50s"), Type: IntervalMainSet}
+	rest200 := Interval{ID: uuid.New(), Distance: 200, Duration: MustParseDuration("1m0s"), Type: IntervalRest}
+
+	t.Run("both trials found", func(t *testing.T) {
+		got400, got200, ok := DetectCSSTrials([]Interval{slow400, rest200, fast400, trial200}, DistanceUnitMeters)
+		if !ok {
+			t.Fatal("expected trials to be detected")
+		}
+		if got400.ID != fast400.ID {
+			t.Errorf("expected the fastest 400m, got %v", got400)
+		}
+		if got200.ID != trial200.ID {
+			t.Errorf("expected the non-rest 200m, got %v", got200)
+		}
+	})
+
+	t.Run("missing 200m trial", func(t *testing.T) {
+		if _, _, ok := DetectCSSTrials([]Interval{fast400, rest200}, DistanceUnitMeters); ok {
+			t.Error("expected no trials to be detected")
+		}
+	})
+
+	t.Run("yard trials", func(t *testing.T) {
+		yard400 := Interval{ID: uuid.New(), Distance: Distance{Value: 400, Unit: DistanceUnitYards}.Meters(), Duration: MustParseDuration("5m30s"), Type: IntervalMainSet}
+		yard200 := Interval{ID: uuid.New(), Distance: Distance{Value: 200, Unit: DistanceUnitYards}.Meters(), Duration: MustParseDuration("2m35s"), Type: IntervalMainSet}
+
+		got400, got200, ok := DetectCSSTrials([]Interval{yard400, yard200, fast400}, DistanceUnitYards)
+		if !ok || got400.ID != yard400.ID || got200.ID != yard200.ID {
+			t.Errorf("expected the yard trials, got %v and %v", got400, got200)
+		}
+		if _, _, ok := DetectCSSTrials([]Interval{yard400, yard200}, DistanceUnitMeters); ok {
+			t.Error("expected yard trials not to be detected in a meters pool")
+		}
+	})
+}
+
+func TestCSSTestAt(t *testing.T) {
+	tests := []CSSTest{
+		{Date: "2023-10-15"},
+		{Date: "2023-10-01"},
+	}
+
+	if test, ok := CSSTestAt(tests, "2023-10-20"); !ok || test.Date != "2023-10-15" {
+		t.Errorf("expected the most recent test, got %v", test)
+	}
+	if test, ok := CSSTestAt(tests, "2023-10-10"); !ok || test.Date != "2023-10-01" {
+		t.Errorf("expected the test in effect, got %v", test)
+	}
+	if test, ok := CSSTestAt(tests, "2023-09-01"); !ok || test.Date != "2023-10-01" {
+		t.Errorf("expected the oldest test, got %v", test)
+	}
+	if _, ok := CSSTestAt(nil, "2023-10-10"); ok {
+		t.Error("expected no test without history")
+	}
+}
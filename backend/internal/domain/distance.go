@@ -0,0 +1,124 @@
+package domain
+
+import "database/sql/driver"
+
+// DistanceUnit defines the unit a distance is expressed in
+type DistanceUnit string
+
+// Predefined distance units
+const (
+	// DistanceUnitMeters is the default unit; all stored distances are in meters
+	DistanceUnitMeters DistanceUnit = "meters"
+	// DistanceUnitYards is used by short-course yards pools, common in the US
+	DistanceUnitYards DistanceUnit = "yards"
+)
+
+var distanceUnits = newEnum("DistanceUnit", DistanceUnitMeters, DistanceUnitYards)
+
+// Valid reports whether the unit is one of the predefined units
+func (u DistanceUnit) Valid() bool {
+	return distanceUnits.valid(u)
+}
+
+// Values returns the predefined units
+func (DistanceUnit) Values() []string {
+	return distanceUnits.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown units
+func (u DistanceUnit) MarshalText() ([]byte, error) {
+	return distanceUnits.marshalText(u)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown units
+func (u *DistanceUnit) UnmarshalText(text []byte) (err error) {
+	*u, err = distanceUnits.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (u *DistanceUnit) Scan(src any) (err error) {
+	*u, err = distanceUnits.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (u DistanceUnit) Value() (driver.Value, error) {
+	return distanceUnits.value(u)
+}
+
+// MetersPerYard is the length of a yard in meters
+const MetersPerYard = 0.9144
+
+// OrDefault returns the unit, falling back to meters when it is empty or unknown
+func (u DistanceUnit) OrDefault() DistanceUnit {
+	if !u.Valid() {
+		return DistanceUnitMeters
+	}
+
+	return u
+}
+
+// Abbreviation returns the short form of the unit, e.g., "m" or "yd"
+func (u DistanceUnit) Abbreviation() string {
+	if u.OrDefault() == DistanceUnitYards {
+		return "yd"
+	}
+
+	return "m"
+}
+
+// Distance is a length together with the unit it is expressed in
+type Distance struct {
+	Value float64
+	Unit  DistanceUnit
+}
+
+// Meters returns the distance in meters
+func (d Distance) Meters() float64 {
+	if d.Unit.OrDefault() == DistanceUnitYards {
+		return d.Value * MetersPerYard
+	}
+
+	return d.Value
+}
+
+// In returns the distance converted to the given unit
+func (d Distance) In(unit DistanceUnit) Distance {
+	unit = unit.OrDefault()
+	if unit == d.Unit.OrDefault() {
+		return Distance{Value: d.Value, Unit: unit}
+	}
+	if unit == DistanceUnitYards {
+		return Distance{Value: d.Meters() / MetersPerYard, Unit: unit}
+	}
+
+	return Distance{Value: d.Meters(), Unit: unit}
+}
+
+// ConvertMeters converts a distance in meters to the given unit
+func ConvertMeters(meters float64, unit DistanceUnit) float64 {
+	return Distance{Value: meters, Unit: DistanceUnitMeters}.In(unit).Value
+}
+
+// PacePer100 converts a pace in seconds per 100 meters to seconds per 100 of the given unit
+func PacePer100(pacePer100m float64, unit DistanceUnit) float64 {
+	if unit.OrDefault() == DistanceUnitYards {
+		return pacePer100m * MetersPerYard
+	}
+
+	return pacePer100m
+}
+
+// Course identifies the standard pool length a session was swum in
+type Course string
+
+// Predefined courses
+const (
+	// CourseShortMeters is a 25 meter pool
+	CourseShortMeters Course = "SCM"
+	// CourseShortYards is a 25 yard pool
+	CourseShortYards Course = "SCY"
+	// CourseLongMeters is a 50 meter (Olympic) pool
+	CourseLongMeters Course = "LCM"
+)
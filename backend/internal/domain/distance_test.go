@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance Distance
+		unit     DistanceUnit
+		expected float64
+	}{
+		{name: "Meters to yards", distance: Distance{Value: 91.44, Unit: DistanceUnitMeters}, unit: DistanceUnitYards, expected: 100},
+		{name: "Yards to meters", distance: Distance{Value: 25, Unit: DistanceUnitYards}, unit: DistanceUnitMeters, expected: 22.86},
+		{name: "Same unit", distance: Distance{Value: 50, Unit: DistanceUnitMeters}, unit: DistanceUnitMeters, expected: 50},
+		{name: "Empty unit defaults to meters", distance: Distance{Value: 50}, unit: "", expected: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.distance.In(tt.unit)
+			if math.Abs(result.Value-tt.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, result.Value)
+			}
+			if result.Unit != tt.unit.OrDefault() {
+				t.Errorf("expected unit %q, got %q", tt.unit.OrDefault(), result.Unit)
+			}
+		})
+	}
+}
+
+func TestDistanceUnit(t *testing.T) {
+	if DistanceUnit("furlongs").Valid() {
+		t.Error("expected unknown unit to be invalid")
+	}
+	if DistanceUnit("").OrDefault() != DistanceUnitMeters {
+		t.Error("expected empty unit to default to meters")
+	}
+	if DistanceUnitYards.Abbreviation() != "yd" || DistanceUnitMeters.Abbreviation() != "m" {
+		t.Error("unexpected abbreviations")
+	}
+}
+
+func TestPacePer100(t *testing.T) {
+	activity := Activity{Duration: MustParseDuration("15m0s"), Distance: 914.4}
+
+	if result := activity.AvgPacePer100(DistanceUnitYards); math.Abs(result-90) > 1e-9 {
+		t.Errorf("expected 90s per 100yd, got %v", result)
+	}
+	if result := activity.AvgPacePer100(DistanceUnitMeters); result != activity.AvgPacePer100m() {
+		t.Errorf("expected pace per 100m %v, got %v", activity.AvgPacePer100m(), result)
+	}
+}
+
+func TestCourse(t *testing.T) {
+	tests := []struct {
+		name     string
+		activity Activity
+		expected Course
+	}{
+		{name: "Short course meters", activity: Activity{LocationType: LocationPool, PoolSize: 25}, expected: CourseShortMeters},
+		{name: "Short course yards", activity: Activity{LocationType: LocationPool, PoolSize: 25, PoolUnit: DistanceUnitYards}, expected: CourseShortYards},
+		{name: "Long course meters", activity: Activity{LocationType: LocationPool, PoolSize: 50, PoolUnit: DistanceUnitMeters}, expected: CourseLongMeters},
+		{name: "Non-standard pool", activity: Activity{LocationType: LocationPool, PoolSize: 33}, expected: ""},
+		{name: "Open water", activity: Activity{LocationType: LocationOpenWater}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.activity.Course(); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -1,43 +1,226 @@
 package domain
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
-// DurationString represents a duration stored as a string,
-// using Go's standard duration format, e.g., "1h30m0s".
-type DurationString string
+// Duration is a length of time with millisecond precision, as timed by swimmers.
+// It is parsed from swim notation, e.g., "1:45.32", "45.1" or "01:02:03", or from
+// Go syntax, e.g., "1m45.32s", serialized in DefaultDurationFormat and stored in
+// the database as milliseconds
+type Duration time.Duration
 
-// MarshalJSON serializes the DurationString into JSON as a string,
-// ensuring it remains in the Go duration format (e.g., "2h45m").
-func (d DurationString) MarshalJSON() ([]byte, error) {
-	return json.Marshal(string(d))
+// DurationFormat defines how durations are written in API responses
+type DurationFormat string
+
+// Predefined duration formats
+const (
+	// DurationFormatGo is Go's duration syntax, e.g., "1m45.32s"
+	DurationFormatGo DurationFormat = "go"
+	// DurationFormatSwim is swim notation, e.g., "1:45.32", "45.1" or "1:02:03"
+	DurationFormatSwim DurationFormat = "swim"
+)
+
+var durationFormats = newEnum("DurationFormat", DurationFormatGo, DurationFormatSwim)
+
+// Valid reports whether the format is one of the predefined formats
+func (f DurationFormat) Valid() bool {
+	return durationFormats.valid(f)
+}
+
+// Values returns the predefined formats
+func (DurationFormat) Values() []string {
+	return durationFormats.strings()
+}
+
+// DefaultDurationFormat is the format durations are serialized in;
+// It is meant to be set once, at startup
+var DefaultDurationFormat = DurationFormatGo
+
+// MaxDuration is the longest duration accepted, well above any swim session
+const MaxDuration = Duration(24 * time.Hour)
+
+// DurationError is returned when a value cannot be parsed as a Duration
+type DurationError struct {
+	// Value is the rejected value
+	Value string
+}
+
+func (e *DurationError) Error() string {
+	return fmt.Sprintf(`invalid duration %q, must be like "1:45.32", "45.1" or "1m45s", up to 24h`, e.Value)
 }
 
-// UnmarshalJSON deserializes a JSON string into a DurationString,
-// validating that it conforms to the Go duration format.
-func (d *DurationString) UnmarshalJSON(b []byte) error {
+// Is makes errors.Is(err, ErrValidation) hold for any DurationError
+func (e *DurationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ParseDuration parses a non-negative duration up to MaxDuration in swim notation
+// or Go syntax, rounded to the millisecond
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsFunc(s, unicode.IsLetter) {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 || d > MaxDuration.Std() {
+			return 0, &DurationError{Value: s}
+		}
+		return Duration(d.Round(time.Millisecond)), nil
+	}
+
+	d, ok := parseSwimNotation(s)
+	if !ok {
+		return 0, &DurationError{Value: s}
+	}
+	return d, nil
+}
+
+// MustParseDuration is like ParseDuration but panics if s cannot be parsed;
+// It simplifies declaring durations known to be valid, e.g., in tests
+func MustParseDuration(s string) Duration {
+	d, err := ParseDuration(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// parseSwimNotation parses "[[hours:]minutes:]seconds[.fraction]", where every
+// part but the first is below 60 and the fraction has at most 3 digits; The
+// total is checked against MaxDuration as it is summed, so it cannot overflow
+func parseSwimNotation(s string) (Duration, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+
+	seconds, fraction, hasFraction := strings.Cut(parts[len(parts)-1], ".")
+	if hasFraction && (fraction == "" || len(fraction) > 3 || !isDigits(fraction)) {
+		return 0, false
+	}
+	parts[len(parts)-1] = seconds
+
+	maxSeconds := int64(MaxDuration.Std() / time.Second)
+	var whole int64
+	for i, part := range parts {
+		if !isDigits(part) {
+			return 0, false
+		}
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || (i > 0 && value >= 60) || value > maxSeconds {
+			return 0, false
+		}
+		whole = whole*60 + value
+		if whole > maxSeconds {
+			return 0, false
+		}
+	}
+	total := time.Duration(whole) * time.Second
+
+	if hasFraction {
+		millis, _ := strconv.Atoi(fraction + strings.Repeat("0", 3-len(fraction)))
+		total += time.Duration(millis) * time.Millisecond
+	}
+
+	if total > MaxDuration.Std() {
+		return 0, false
+	}
+	return Duration(total), true
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }) == -1
+}
+
+// DurationFromMilliseconds creates a Duration from a number of milliseconds
+func DurationFromMilliseconds(ms int64) Duration {
+	return Duration(time.Duration(ms) * time.Millisecond)
+}
+
+// Std returns the duration as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// Seconds returns the duration as a floating point number of seconds
+func (d Duration) Seconds() float64 {
+	return d.Std().Seconds()
+}
+
+// Milliseconds returns the duration as an integer number of milliseconds
+func (d Duration) Milliseconds() int64 {
+	return d.Std().Milliseconds()
+}
+
+// String returns the duration in DefaultDurationFormat
+func (d Duration) String() string {
+	return d.Format(DefaultDurationFormat)
+}
+
+// Format returns the duration in the given format, falling back to Go syntax for unknown formats
+func (d Duration) Format(format DurationFormat) string {
+	if format != DurationFormatSwim {
+		return d.Std().String()
+	}
+
+	ms := d.Milliseconds()
+	hours, minutes, seconds := ms/3_600_000, ms/60_000%60, ms/1000%60
+	fraction := ""
+	if millis := ms % 1000; millis != 0 {
+		fraction = strings.TrimRight(fmt.Sprintf(".%03d", millis), "0")
+	}
+
+	switch {
+	case hours > 0:
+		return fmt.Sprintf("%d:%02d:%02d%s", hours, minutes, seconds, fraction)
+	case minutes > 0:
+		return fmt.Sprintf("%d:%02d%s", minutes, seconds, fraction)
+	default:
+		return fmt.Sprintf("%d%s", seconds, fraction)
+	}
+}
+
+// MarshalJSON serializes the duration as a string in DefaultDurationFormat
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON deserializes a duration from a string in any accepted notation,
+// or from a number of seconds
+func (d *Duration) UnmarshalJSON(b []byte) error {
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
-		return err
+		var seconds json.Number
+		if err := json.Unmarshal(b, &seconds); err != nil {
+			return &DurationError{Value: string(b)}
+		}
+		s = seconds.String()
 	}
-	if _, err := time.ParseDuration(s); err != nil {
-		return fmt.Errorf("invalid duration format: %w", err)
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
 	}
-	*d = DurationString(s)
+	*d = parsed
 	return nil
 }
 
-func (d DurationString) ToDuration() time.Duration {
-	duration, err := time.ParseDuration(string(d))
-	if err != nil {
-		return time.Duration(0)
+// Scan implements sql.Scanner, reading a number of milliseconds
+func (d *Duration) Scan(src any) error {
+	ms, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into Duration", src)
 	}
-	return duration
+	*d = DurationFromMilliseconds(ms)
+	return nil
 }
 
-func (d DurationString) Seconds() float64 {
-	return d.ToDuration().Seconds()
+// Value implements driver.Valuer, storing the duration as milliseconds
+func (d Duration) Value() (driver.Value, error) {
+	return d.Milliseconds(), nil
 }
@@ -2,116 +2,145 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 )
 
-func TestDurationString_MarshalJSON(t *testing.T) {
+func TestParseDuration(t *testing.T) {
 	tests := []struct {
-		name     string
-		duration DurationString
-		want     string
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
 	}{
-		{"zero", "0s", `"0s"`},
-		{"seconds", "5s", `"5s"`},
-		{"minutes", "2m0s", `"2m0s"`},
-		{"hours", "1h30m0s", `"1h30m0s"`},
+		{"swim notation with hundredths", "1:45.32", time.Minute + 45*time.Second + 320*time.Millisecond, false},
+		{"seconds with tenths", "45.1", 45*time.Second + 100*time.Millisecond, false},
+		{"hours, minutes and seconds", "01:02:03", time.Hour + 2*time.Minute + 3*time.Second, false},
+		{"thousandths", "0:59.999", 59*time.Second + 999*time.Millisecond, false},
+		{"seconds over a minute", "90", 90 * time.Second, false},
+		{"go syntax", "1m45.32s", time.Minute + 45*time.Second + 320*time.Millisecond, false},
+		{"go syntax rounded to milliseconds", "1.0004s", time.Second, false},
+		{"zero", "0s", 0, false},
+		{"seconds out of range", "1:60", 0, true},
+		{"minutes out of range", "1:60:00", 0, true},
+		{"too many parts", "1:02:03:04", 0, true},
+		{"too many fraction digits", "45.1234", 0, true},
+		{"empty fraction", "45.", 0, true},
+		{"longest", "24:00:00", 24 * time.Hour, false},
+		{"longer than a day", "24:00:00.001", 0, true},
+		{"hours overflowing", "3000000:00:00", 0, true},
+		{"seconds overflowing", "9999999999999", 0, true},
+		{"beyond int64", "99999999999999999999", 0, true},
+		{"go syntax longer than a day", "25h", 0, true},
+		{"negative", "-45", 0, true},
+		{"negative minutes", "1:-5", 0, true},
+		{"negative hours", "-1:00:00", 0, true},
+		{"negative go syntax", "-1m", 0, true},
+		{"empty", "", 0, true},
+		{"invalid", "abc", 0, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := json.Marshal(tt.duration)
-			if err != nil {
-				t.Fatalf("MarshalJSON() error = %v", err)
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
 			}
-			if string(got) != tt.want {
-				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
+			if tt.wantErr && !errors.Is(err, ErrValidation) {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+			if got.Std() != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got.Std(), tt.want)
 			}
 		})
 	}
 }
 
-func TestDurationString_UnmarshalJSON(t *testing.T) {
+func TestDuration_Format(t *testing.T) {
 	tests := []struct {
-		name    string
-		input   string
-		want    DurationString
-		wantErr bool
+		name     string
+		duration string
+		swim     string
+		goSyntax string
 	}{
-		{"zero", `"0s"`, "0s", false},
-		{"seconds", `"10s"`, "10s", false},
-		{"minutes", `"2m0s"`, "2m0s", false},
-		{"hours", `"1h30m0s"`, "1h30m0s", false},
-		{"invalid format", `"abc"`, "", true},
-		{"not a string", `123`, "", true},
+		{"zero", "0s", "0", "0s"},
+		{"seconds with tenths", "45.1", "45.1", "45.1s"},
+		{"minutes with hundredths", "1:45.32", "1:45.32", "1m45.32s"},
+		{"padded seconds", "2:05", "2:05", "2m5s"},
+		{"hours", "1:02:03", "1:02:03", "1h2m3s"},
+		{"hours with milliseconds", "1:00:00.005", "1:00:00.005", "1h0m0.005s"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var d DurationString
-			err := json.Unmarshal([]byte(tt.input), &d)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			d := MustParseDuration(tt.duration)
+			if got := d.Format(DurationFormatSwim); got != tt.swim {
+				t.Errorf("Format(swim) = %q, want %q", got, tt.swim)
 			}
-			if !tt.wantErr && d != tt.want {
-				t.Errorf("UnmarshalJSON() = %v, want %v", d, tt.want)
+			if got := d.Format(DurationFormatGo); got != tt.goSyntax {
+				t.Errorf("Format(go) = %q, want %q", got, tt.goSyntax)
 			}
 		})
 	}
 }
 
-func TestDurationString_RoundTrip(t *testing.T) {
-	orig := DurationString("42m7s")
-	data, err := json.Marshal(orig)
-	if err != nil {
-		t.Fatalf("MarshalJSON() error = %v", err)
+func TestDuration_JSON(t *testing.T) {
+	var interval struct {
+		Duration Duration `json:"duration"`
+	}
+
+	for input, want := range map[string]time.Duration{
+		`{"duration": "1:45.32"}`: time.Minute + 45*time.Second + 320*time.Millisecond,
+		`{"duration": "1m30s"}`:   90 * time.Second,
+		`{"duration": 45.1}`:      45*time.Second + 100*time.Millisecond,
+	} {
+		if err := json.Unmarshal([]byte(input), &interval); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", input, err)
+		}
+		if interval.Duration.Std() != want {
+			t.Errorf("Unmarshal(%s) = %v, want %v", input, interval.Duration.Std(), want)
+		}
 	}
-	var decoded DurationString
-	if err := json.Unmarshal(data, &decoded); err != nil {
-		t.Fatalf("UnmarshalJSON() error = %v", err)
+
+	var durationErr *DurationError
+	if err := json.Unmarshal([]byte(`{"duration": "fast"}`), &interval); !errors.As(err, &durationErr) {
+		t.Errorf("expected a DurationError, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"duration": true}`), &interval); !errors.As(err, &durationErr) {
+		t.Errorf("expected a DurationError, got %v", err)
+	}
+
+	b, err := json.Marshal(MustParseDuration("1:45.32"))
+	if err != nil || string(b) != `"1m45.32s"` {
+		t.Errorf("Marshal() = %s, %v, want \"1m45.32s\"", b, err)
 	}
-	if decoded != orig {
-		t.Errorf("RoundTrip: got %v, want %v", decoded, orig)
+
+	DefaultDurationFormat = DurationFormatSwim
+	defer func() { DefaultDurationFormat = DurationFormatGo }()
+	if b, _ := json.Marshal(MustParseDuration("1m45.32s")); string(b) != `"1:45.32"` {
+		t.Errorf("Marshal() in swim format = %s, want \"1:45.32\"", b)
 	}
 }
 
-func TestDurationString_ToDuration(t *testing.T) {
-	tests := []struct {
-		name     string
-		duration DurationString
-		expected time.Duration
-	}{
-		{"zero", "0s", time.Duration(0)},
-		{"seconds", "5s", 5 * time.Second},
-		{"minutes", "2m0s", 2 * time.Minute},
-		{"hours", "1h30m0s", 1*time.Hour + 30*time.Minute},
-		{"invalid format", "abc", 0}, // should return 0 on invalid format
+func TestDuration_SQL(t *testing.T) {
+	d := MustParseDuration("1:45.32")
+	if value, err := d.Value(); err != nil || value != int64(105320) {
+		t.Errorf("Value() = %v, %v, want 105320", value, err)
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := tt.duration.ToDuration()
-			if got != tt.expected {
-				t.Errorf("ToDuration() = %d, want %d", got, tt.expected)
-			}
-		})
+
+	var scanned Duration
+	if err := scanned.Scan(int64(105320)); err != nil || scanned != d {
+		t.Errorf("Scan() = %v, %v, want %v", scanned, err, d)
+	}
+	if err := scanned.Scan("1:45"); err == nil {
+		t.Error("expected scanning a string to fail")
 	}
 }
 
-func TestDurationString_Seconds(t *testing.T) {
-	tests := []struct {
-		name     string
-		duration DurationString
-		expected float64
-	}{
-		{"zero", "0s", 0},
-		{"seconds", "5s", 5},
-		{"minutes", "2m0s", 120},
-		{"hours", "1h30m0s", 5400},
+func TestDuration_Seconds(t *testing.T) {
+	if got := MustParseDuration("1:30:00").Seconds(); got != 5400 {
+		t.Errorf("Seconds() = %f, want 5400", got)
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := tt.duration.Seconds()
-			if got != tt.expected {
-				t.Errorf("Seconds() = %f, want %f", got, tt.expected)
-			}
-		})
+	if got := MustParseDuration("45.32").Milliseconds(); got != 45320 {
+		t.Errorf("Milliseconds() = %d, want 45320", got)
 	}
 }
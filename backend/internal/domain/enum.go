@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EnumError is returned when a value is not one of the predefined values of an enum
+type EnumError struct {
+	// Enum is the name of the enum type, e.g., "StrokeType"
+	Enum string
+	// Value is the rejected value
+	Value string
+	// Values are the predefined values of the enum
+	Values []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("invalid %s %q, must be one of: %s", e.Enum, e.Value, strings.Join(e.Values, ", "))
+}
+
+// Is makes errors.Is(err, ErrValidation) hold for any EnumError
+func (e *EnumError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// enumValues holds the predefined values of an enum type and implements the
+// behavior shared by all enums; The zero value of an enum means "not set",
+// it is not valid but is accepted when decoding and stored as NULL
+type enumValues[T ~string] struct {
+	name   string
+	values []T
+}
+
+func newEnum[T ~string](name string, values ...T) enumValues[T] {
+	return enumValues[T]{name: name, values: values}
+}
+
+func (e enumValues[T]) valid(value T) bool {
+	return slices.Contains(e.values, value)
+}
+
+func (e enumValues[T]) strings() []string {
+	values := make([]string, len(e.values))
+	for i, value := range e.values {
+		values[i] = string(value)
+	}
+	return values
+}
+
+func (e enumValues[T]) check(value T) error {
+	if value == "" || e.valid(value) {
+		return nil
+	}
+	return &EnumError{Enum: e.name, Value: string(value), Values: e.strings()}
+}
+
+func (e enumValues[T]) marshalText(value T) ([]byte, error) {
+	if err := e.check(value); err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+func (e enumValues[T]) unmarshalText(text []byte) (T, error) {
+	value := T(text)
+	if err := e.check(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func (e enumValues[T]) scan(src any) (T, error) {
+	switch src := src.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return e.unmarshalText([]byte(src))
+	case []byte:
+		return e.unmarshalText(src)
+	default:
+		return "", fmt.Errorf("cannot scan %T into %s", src, e.name)
+	}
+}
+
+func (e enumValues[T]) value(value T) (driver.Value, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if err := e.check(value); err != nil {
+		return nil, err
+	}
+	return string(value), nil
+}
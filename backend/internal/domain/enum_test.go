@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestEnumValues(t *testing.T) {
+	expected := []string{"freestyle", "backstroke", "breaststroke", "butterfly", "medley", "unknown"}
+	if got := StrokeType("").Values(); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	var activity struct {
+		Location LocationType `json:"location_type"`
+		Feeling  FeelingType  `json:"feeling"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"location_type": "open_water", "feeling": ""}`), &activity); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if activity.Location != LocationOpenWater || activity.Feeling != "" {
+		t.Errorf("unexpected values %+v", activity)
+	}
+
+	err := json.Unmarshal([]byte(`{"location_type": "lake"}`), &activity)
+	var enumErr *EnumError
+	if !errors.As(err, &enumErr) || enumErr.Enum != "LocationType" || enumErr.Value != "lake" {
+		t.Fatalf("expected an EnumError for lake, got %v", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("expected EnumError to match ErrValidation")
+	}
+
+	if _, err := json.Marshal(IntervalType("sprint")); err == nil {
+		t.Error("expected marshaling an unknown interval type to fail")
+	}
+	if b, err := json.Marshal(IntervalMainSet); err != nil || string(b) != `"main_set"` {
+		t.Errorf("unexpected marshaling result %s, %v", b, err)
+	}
+}
+
+func TestEnumSQL(t *testing.T) {
+	var feeling FeelingType
+	if err := feeling.Scan(nil); err != nil || feeling != "" {
+		t.Errorf("expected NULL to scan as empty, got %q, %v", feeling, err)
+	}
+	if err := feeling.Scan([]byte("tired")); err != nil || feeling != FeelingTired {
+		t.Errorf("expected tired, got %q, %v", feeling, err)
+	}
+	if err := feeling.Scan("great"); err == nil {
+		t.Error("expected scanning an unknown feeling to fail")
+	}
+	if err := feeling.Scan(42); err == nil {
+		t.Error("expected scanning a number to fail")
+	}
+
+	if value, err := FeelingType("").Value(); err != nil || value != nil {
+		t.Errorf("expected empty feeling to be stored as NULL, got %v, %v", value, err)
+	}
+	if value, err := DistanceUnitYards.Value(); err != nil || value != "yards" {
+		t.Errorf("expected yards, got %v, %v", value, err)
+	}
+	if _, err := DistanceUnit("furlongs").Value(); err == nil {
+		t.Error("expected storing an unknown unit to fail")
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// Sentinel errors shared by all layers; use errors.Is to classify an error
+var (
+	// ErrNotFound is returned when the requested resource does not exist
+	ErrNotFound = errors.New("resource not found")
+	// ErrConflict is returned when a resource clashes with an existing one, e.g., a duplicate email
+	ErrConflict = errors.New("resource conflict")
+	// ErrValidation is returned when the input is invalid; see ValidationError for field details
+	ErrValidation = errors.New("validation failed")
+	// ErrPreconditionFailed is returned when a resource changed since the client retrieved it
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnauthorized is returned when the request must be made on behalf of a known user
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is returned when the user making the request may not access the resource
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Machine-readable codes of a FieldError, stable across message changes
+const (
+	// CodeRequired means the field is missing or empty
+	CodeRequired = "required"
+	// CodeInvalidFormat means the value is not in the expected format, e.g., an email or UUID
+	CodeInvalidFormat = "invalid_format"
+	// CodeInvalidType means the JSON value has the wrong type, e.g., a string instead of a number
+	CodeInvalidType = "invalid_type"
+	// CodeUnknownValue means the value is not one of the allowed values of an enum
+	CodeUnknownValue = "unknown_value"
+	// CodeOutOfRange means the value is below the minimum or above the maximum allowed
+	CodeOutOfRange = "out_of_range"
+	// CodeNotFound means the value references a record that does not exist
+	CodeNotFound = "not_found"
+	// CodeInvalid is used when no more specific code applies
+	CodeInvalid = "invalid"
+)
+
+// FieldError describes why the value of a single field is invalid
+type FieldError struct {
+	// Name of the field, as sent by the client, e.g., "email"
+	Field string `json:"field"`
+	// Machine-readable reason, one of the Code constants, e.g., "invalid_format"
+	Code string `json:"code"`
+	// Human-readable reason, e.g., "must be a valid email"
+	Message string `json:"message"`
+}
+
+// ValidationError is an ErrValidation carrying the details of each invalid field
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError creates a ValidationError with the given field details
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{Fields: fields}
+}
+
+// Error returns the field details joined in a single message
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return ErrValidation.Error()
+	}
+
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+
+	return ErrValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is makes errors.Is(err, ErrValidation) hold for any ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// kindError is an error with its own message, classified as one of the sentinel errors
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// NewNotFoundError creates an error with the given message that matches ErrNotFound
+func NewNotFoundError(message string) error {
+	return &kindError{kind: ErrNotFound, message: message}
+}
+
+// NewConflictError creates an error with the given message that matches ErrConflict
+func NewConflictError(message string) error {
+	return &kindError{kind: ErrConflict, message: message}
+}
+
+// NewPreconditionFailedError creates an error with the given message that matches ErrPreconditionFailed
+func NewPreconditionFailedError(message string) error {
+	return &kindError{kind: ErrPreconditionFailed, message: message}
+}
+
+// NewUnauthorizedError creates an error with the given message that matches ErrUnauthorized
+func NewUnauthorizedError(message string) error {
+	return &kindError{kind: ErrUnauthorized, message: message}
+}
+
+// NewForbiddenError creates an error with the given message that matches ErrForbidden
+func NewForbiddenError(message string) error {
+	return &kindError{kind: ErrForbidden, message: message}
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	err := NewValidationError(
+		FieldError{Field: "email", Message: "must be a valid email"},
+		FieldError{Field: "age", Message: "must be at least 0"},
+	)
+
+	if !errors.Is(err, ErrValidation) {
+		t.Error("expected ValidationError to match ErrValidation")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("expected ValidationError not to match ErrNotFound")
+	}
+
+	expected := "validation failed: email must be a valid email; age must be at least 0"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	var validationErr *ValidationError
+	if wrapped := fmt.Errorf("creating user: %w", err); !errors.As(wrapped, &validationErr) || len(validationErr.Fields) != 2 {
+		t.Error("expected field details to survive wrapping")
+	}
+}
+
+func TestKindErrors(t *testing.T) {
+	notFound := NewNotFoundError("user not found")
+	if !errors.Is(notFound, ErrNotFound) || notFound.Error() != "user not found" {
+		t.Errorf("unexpected not found error %v", notFound)
+	}
+
+	conflict := NewConflictError("email already in use")
+	if !errors.Is(conflict, ErrConflict) || errors.Is(conflict, ErrNotFound) {
+		t.Errorf("unexpected conflict error %v", conflict)
+	}
+
+	forbidden := NewForbiddenError("audit log of another user")
+	if !errors.Is(forbidden, ErrForbidden) || errors.Is(forbidden, ErrUnauthorized) {
+		t.Errorf("unexpected forbidden error %v", forbidden)
+	}
+}
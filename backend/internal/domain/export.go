@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportStatus defines the progress of a data export
+type ExportStatus string
+
+// Predefined export statuses
+const (
+	// ExportPending means the archive is being generated
+	ExportPending ExportStatus = "pending"
+	// ExportReady means the archive can be downloaded
+	ExportReady ExportStatus = "ready"
+	// ExportFailed means the archive could not be generated; a new export must be requested
+	ExportFailed ExportStatus = "failed"
+)
+
+var exportStatuses = newEnum("ExportStatus", ExportPending, ExportReady, ExportFailed)
+
+// Valid reports whether the status is one of the predefined statuses
+func (s ExportStatus) Valid() bool {
+	return exportStatuses.valid(s)
+}
+
+// Values returns the predefined statuses
+func (ExportStatus) Values() []string {
+	return exportStatuses.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown statuses
+func (s ExportStatus) MarshalText() ([]byte, error) {
+	return exportStatuses.marshalText(s)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown statuses
+func (s *ExportStatus) UnmarshalText(text []byte) (err error) {
+	*s, err = exportStatuses.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (s *ExportStatus) Scan(src any) (err error) {
+	*s, err = exportStatuses.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (s ExportStatus) Value() (driver.Value, error) {
+	return exportStatuses.value(s)
+}
+
+// DataExport is a ZIP archive of all the data of a user, generated asynchronously
+type DataExport struct {
+	// ID is the unique identifier for the export (PK)
+	ID uuid.UUID `json:"id"`
+	// UserID is the ID of the user whose data is exported
+	UserID uuid.UUID `json:"user_id"`
+	// Status is the progress of the export
+	Status ExportStatus `json:"status"`
+	// Size of the archive in bytes, once ready
+	Size int64 `json:"size,omitempty"`
+	// CreatedAt is when the export was requested, set by the repository
+	CreatedAt time.Time `json:"created_at"`
+	// CompletedAt is when the archive was generated or failed
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	// ExpiresAt is when the export is deleted, archive included
+	ExpiresAt time.Time `json:"expires_at"`
+	// JobID is the ID of the background job generating the archive, null for exports
+	// requested before jobs existed
+	JobID uuid.NullUUID `json:"job_id" swaggertype:"string"`
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// HeartRateZoneBasis defines what the heart-rate zones of a user are derived from
+type HeartRateZoneBasis string
+
+// Predefined heart-rate zone bases, in order of precedence
+const (
+	// HeartRateZoneBasisLTHR derives zones from the lactate threshold heart rate
+	HeartRateZoneBasisLTHR HeartRateZoneBasis = "lthr"
+	// HeartRateZoneBasisMaxHR derives zones from the informed maximum heart rate
+	HeartRateZoneBasisMaxHR HeartRateZoneBasis = "max_hr"
+	// HeartRateZoneBasisAge derives zones from the maximum heart rate estimated by age
+	HeartRateZoneBasisAge HeartRateZoneBasis = "age"
+)
+
+// HeartRateZone represents a training zone as a range of heart rates in bpm
+type HeartRateZone struct {
+	// Number of the zone, from 1 (easiest) upwards
+	Number int `json:"number"`
+	// Name of the zone, e.g., "aerobic"
+	Name string `json:"name"`
+	// Lowest heart rate of the zone (0 if unbounded)
+	MinHR int `json:"min_hr"`
+	// Highest heart rate of the zone, exclusive (0 if unbounded)
+	MaxHR int `json:"max_hr"`
+}
+
+// Contains reports whether the given heart rate falls within the zone
+func (z HeartRateZone) Contains(hr int) bool {
+	return hr >= z.MinHR && (z.MaxHR == 0 || hr < z.MaxHR)
+}
+
+// heartRateZoneBounds defines the lower bound of zones 2 to 5 as a fraction of the reference heart rate
+var heartRateZoneBounds = map[HeartRateZoneBasis][]float64{
+	HeartRateZoneBasisLTHR:  {0.81, 0.90, 0.94, 1.00},
+	HeartRateZoneBasisMaxHR: {0.60, 0.70, 0.80, 0.90},
+	HeartRateZoneBasisAge:   {0.60, 0.70, 0.80, 0.90},
+}
+
+var heartRateZoneNames = []string{"recovery", "aerobic", "tempo", "threshold", "anaerobic"}
+
+// HeartRateZoneBasis returns what the user's heart-rate zones are derived from;
+// If there is no data to derive them from, it returns an empty basis
+func (u User) HeartRateZoneBasis() HeartRateZoneBasis {
+	switch {
+	case u.LactateThresholdHeartRate > 0:
+		return HeartRateZoneBasisLTHR
+	case u.MaxHeartRate > 0:
+		return HeartRateZoneBasisMaxHR
+	case u.Age > 0:
+		return HeartRateZoneBasisAge
+	default:
+		return ""
+	}
+}
+
+// HeartRateZones returns the user's five heart-rate zones, derived from the
+// lactate threshold heart rate, the maximum heart rate or the age, in this order;
+// If none of them is known, it returns nil
+func (u User) HeartRateZones() []HeartRateZone {
+	basis := u.HeartRateZoneBasis()
+
+	var reference int
+	switch basis {
+	case HeartRateZoneBasisLTHR:
+		reference = u.LactateThresholdHeartRate
+	case HeartRateZoneBasisMaxHR, HeartRateZoneBasisAge:
+		reference = u.EffectiveMaxHeartRate()
+	default:
+		return nil
+	}
+
+	bounds := heartRateZoneBounds[basis]
+	zones := make([]HeartRateZone, len(heartRateZoneNames))
+	for i, name := range heartRateZoneNames {
+		zones[i] = HeartRateZone{Number: i + 1, Name: name}
+		if i > 0 {
+			zones[i].MinHR = int(math.Round(bounds[i-1] * float64(reference)))
+		}
+		if i < len(bounds) {
+			zones[i].MaxHR = int(math.Round(bounds[i] * float64(reference)))
+		}
+	}
+
+	return zones
+}
+
+// HeartRateZoneFor returns the zone the given heart rate falls in;
+// If the heart rate is 0 or there are no zones, the second value is false
+func HeartRateZoneFor(zones []HeartRateZone, hr int) (HeartRateZone, bool) {
+	if hr <= 0 {
+		return HeartRateZone{}, false
+	}
+
+	for _, zone := range zones {
+		if zone.Contains(hr) {
+			return zone, true
+		}
+	}
+
+	return HeartRateZone{}, false
+}
+
+// ZoneTime is the time spent in a heart-rate zone
+type ZoneTime struct {
+	Zone     HeartRateZone
+	Duration time.Duration
+}
+
+// TimeInZones returns the time spent in each of the given zones during an activity,
+// computed from the average heart rate of each interval; if no interval has heart-rate
+// data, the whole activity is attributed to the zone of its average heart rate
+func TimeInZones(activity Activity, intervals []Interval, zones []HeartRateZone) []ZoneTime {
+	times := make([]ZoneTime, len(zones))
+	for i, zone := range zones {
+		times[i].Zone = zone
+	}
+
+	add := func(hr int, duration time.Duration) bool {
+		if hr <= 0 {
+			return false
+		}
+		for i, zone := range zones {
+			if zone.Contains(hr) {
+				times[i].Duration += duration
+				return true
+			}
+		}
+		return false
+	}
+
+	var resolved bool
+	for _, interval := range intervals {
+		if add(interval.HeartRateAvg, interval.Duration.Std()) {
+			resolved = true
+		}
+	}
+	if !resolved {
+		add(activity.HeartRateAvg, activity.Duration.Std())
+	}
+
+	return times
+}
+
+// TotalZoneTime returns the time spent across all zones
+func TotalZoneTime(times []ZoneTime) time.Duration {
+	var total time.Duration
+	for _, zoneTime := range times {
+		total += zoneTime.Duration
+	}
+
+	return total
+}
+
+// SumZoneTimes adds up the time spent in each zone across several sessions;
+// all sessions must have been computed with the same zones
+func SumZoneTimes(sessions ...[]ZoneTime) []ZoneTime {
+	var total []ZoneTime
+	for _, session := range sessions {
+		if total == nil {
+			total = make([]ZoneTime, len(session))
+			copy(total, session)
+			continue
+		}
+		for i := range session {
+			total[i].Duration += session[i].Duration
+		}
+	}
+
+	return total
+}
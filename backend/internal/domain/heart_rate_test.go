@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeartRateZones(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      User
+		basis     HeartRateZoneBasis
+		zone2Min  int
+		zone4Max  int
+		zoneCount int
+	}{
+		{
+			name:      "Lactate threshold takes precedence",
+			user:      User{Age: 30, MaxHeartRate: 200, LactateThresholdHeartRate: 170},
+			basis:     HeartRateZoneBasisLTHR,
+			zone2Min:  138,
+			zone4Max:  170,
+			zoneCount: 5,
+		},
+		{
+			name:      "Max heart rate",
+			user:      User{Age: 30, MaxHeartRate: 200},
+			basis:     HeartRateZoneBasisMaxHR,
+			zone2Min:  120,
+			zone4Max:  180,
+			zoneCount: 5,
+		},
+		{
+			name:      "Age",
+			user:      User{Age: 30},
+			basis:     HeartRateZoneBasisAge,
+			zone2Min:  114,
+			zone4Max:  171,
+			zoneCount: 5,
+		},
+		{
+			name:      "No data",
+			user:      User{},
+			basis:     "",
+			zoneCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if basis := tt.user.HeartRateZoneBasis(); basis != tt.basis {
+				t.Errorf("expected basis %q, got %q", tt.basis, basis)
+			}
+
+			zones := tt.user.HeartRateZones()
+			if len(zones) != tt.zoneCount {
+				t.Fatalf("expected %d zones, got %d", tt.zoneCount, len(zones))
+			}
+			if tt.zoneCount == 0 {
+				return
+			}
+			if zones[0].MinHR != 0 || zones[4].MaxHR != 0 {
+				t.Errorf("expected outer zones to be unbounded, got %+v and %+v", zones[0], zones[4])
+			}
+			if zones[1].MinHR != tt.zone2Min {
+				t.Errorf("expected zone 2 to start at %d, got %d", tt.zone2Min, zones[1].MinHR)
+			}
+			if zones[3].MaxHR != tt.zone4Max {
+				t.Errorf("expected zone 4 to end at %d, got %d", tt.zone4Max, zones[3].MaxHR)
+			}
+		})
+	}
+}
+
+func TestHeartRateZoneFor(t *testing.T) {
+	zones := User{MaxHeartRate: 200}.HeartRateZones()
+
+	tests := []struct {
+		hr       int
+		expected int
+		ok       bool
+	}{
+		{hr: 0, ok: false},
+		{hr: 100, expected: 1, ok: true},
+		{hr: 120, expected: 2, ok: true},
+		{hr: 179, expected: 4, ok: true},
+		{hr: 210, expected: 5, ok: true},
+	}
+
+	for _, tt := range tests {
+		zone, ok := HeartRateZoneFor(zones, tt.hr)
+		if ok != tt.ok || zone.Number != tt.expected {
+			t.Errorf("HeartRateZoneFor(%d) = %d, %v; expected %d, %v", tt.hr, zone.Number, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestTimeInZones(t *testing.T) {
+	zones := User{MaxHeartRate: 200}.HeartRateZones()
+	activity := Activity{Duration: MustParseDuration("1h0m0s"), HeartRateAvg: 150}
+
+	t.Run("Interval heart rate", func(t *testing.T) {
+		intervals := []Interval{
+			{Duration: MustParseDuration("10m0s"), HeartRateAvg: 110},
+			{Duration: MustParseDuration("20m0s"), HeartRateAvg: 185},
+			{Duration: MustParseDuration("5m0s"), Type: IntervalRest},
+		}
+
+		times := TimeInZones(activity, intervals, zones)
+		if times[0].Duration != 10*time.Minute {
+			t.Errorf("expected 10m in zone 1, got %v", times[0].Duration)
+		}
+		if times[4].Duration != 20*time.Minute {
+			t.Errorf("expected 20m in zone 5, got %v", times[4].Duration)
+		}
+		if total := TotalZoneTime(times); total != 30*time.Minute {
+			t.Errorf("expected 30m in total, got %v", total)
+		}
+	})
+
+	t.Run("Activity heart rate fallback", func(t *testing.T) {
+		times := TimeInZones(activity, []Interval{{Duration: MustParseDuration("10m0s")}}, zones)
+		if times[2].Duration != time.Hour {
+			t.Errorf("expected 1h in zone 3, got %v", times[2].Duration)
+		}
+	})
+
+	t.Run("No heart rate", func(t *testing.T) {
+		times := TimeInZones(Activity{Duration: MustParseDuration("1h0m0s")}, nil, zones)
+		if total := TotalZoneTime(times); total != 0 {
+			t.Errorf("expected no time in zones, got %v", total)
+		}
+	})
+}
+
+func TestSumZoneTimes(t *testing.T) {
+	zones := User{MaxHeartRate: 200}.HeartRateZones()
+	first := TimeInZones(Activity{Duration: MustParseDuration("30m0s"), HeartRateAvg: 150}, nil, zones)
+	second := TimeInZones(Activity{Duration: MustParseDuration("45m0s"), HeartRateAvg: 155}, nil, zones)
+
+	total := SumZoneTimes(first, second)
+	if total[2].Duration != 75*time.Minute {
+		t.Errorf("expected 1h15m in zone 3, got %v", total[2].Duration)
+	}
+	if first[2].Duration != 30*time.Minute {
+		t.Errorf("expected first session to be left untouched, got %v", first[2].Duration)
+	}
+	if SumZoneTimes() != nil {
+		t.Errorf("expected nil for no sessions")
+	}
+}
@@ -1,7 +1,8 @@
 package domain
 
 import (
-	"fmt"
+	"database/sql/driver"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -29,6 +30,40 @@ const (
 	IntervalCoolDown IntervalType = "cooldown"
 )
 
+var intervalTypes = newEnum("IntervalType", IntervalSwim, IntervalRest, IntervalDrill, IntervalKick, IntervalPull, IntervalWarmUp, IntervalMainSet, IntervalCoolDown)
+
+// Valid reports whether the interval type is one of the predefined interval types
+func (t IntervalType) Valid() bool {
+	return intervalTypes.valid(t)
+}
+
+// Values returns the predefined interval types
+func (IntervalType) Values() []string {
+	return intervalTypes.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown interval types
+func (t IntervalType) MarshalText() ([]byte, error) {
+	return intervalTypes.marshalText(t)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown interval types
+func (t *IntervalType) UnmarshalText(text []byte) (err error) {
+	*t, err = intervalTypes.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (t *IntervalType) Scan(src any) (err error) {
+	*t, err = intervalTypes.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (t IntervalType) Value() (driver.Value, error) {
+	return intervalTypes.value(t)
+}
+
 // StrokeType defines the style of swimming stroke used
 type StrokeType string
 
@@ -48,13 +83,47 @@ const (
 	StrokeUnknown StrokeType = "unknown"
 )
 
+var strokeTypes = newEnum("StrokeType", StrokeFreestyle, StrokeBackstroke, StrokeBreaststroke, StrokeButterfly, StrokeMedley, StrokeUnknown)
+
+// Valid reports whether the stroke is one of the predefined strokes
+func (s StrokeType) Valid() bool {
+	return strokeTypes.valid(s)
+}
+
+// Values returns the predefined strokes
+func (StrokeType) Values() []string {
+	return strokeTypes.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown strokes
+func (s StrokeType) MarshalText() ([]byte, error) {
+	return strokeTypes.marshalText(s)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown strokes
+func (s *StrokeType) UnmarshalText(text []byte) (err error) {
+	*s, err = strokeTypes.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (s *StrokeType) Scan(src any) (err error) {
+	*s, err = strokeTypes.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (s StrokeType) Value() (driver.Value, error) {
+	return strokeTypes.value(s)
+}
+
 // Interval represents a single segment of a swim session
 type Interval struct {
 	ID uuid.UUID `json:"id"`
 	// Foreign key to the swim activity/session
 	ActivityID uuid.UUID `json:"activity_id"`
-	// Duration of the interval in string format, e.g., "1h30m"
-	Duration DurationString `json:"duration"`
+	// Duration of the interval, with millisecond precision
+	Duration Duration `json:"duration" swaggertype:"string"`
 	// Distance in meters
 	Distance float64 `json:"distance"`
 	// One of the predefined types
@@ -63,6 +132,16 @@ type Interval struct {
 	Stroke StrokeType `json:"stroke"`
 	// Optional notes like "felt strong", "used fins"
 	Notes string `json:"notes"`
+	// Optional average heart rate during the interval
+	HeartRateAvg int `json:"heart_rate_avg,omitempty"`
+	// Optional total number of strokes taken during the interval
+	StrokeCount int `json:"stroke_count,omitempty"`
+	// Optional number of strokes of each pool length, when imported from a device
+	LengthStrokeCounts []int `json:"length_stroke_counts,omitempty"`
+	// CreatedAt is when the interval was created, set by the repository
+	CreatedAt time.Time `json:"created_at,omitzero" readonly:"true"`
+	// UpdatedAt is when the interval was last updated, set by the repository
+	UpdatedAt time.Time `json:"updated_at,omitzero" readonly:"true"`
 }
 
 // PacePer100m returns the pace in seconds per 100 meters
@@ -74,16 +153,57 @@ func (i Interval) PacePer100m() float64 {
 	return i.Duration.Seconds() / i.Distance * 100
 }
 
+// PacePer100 returns the pace in seconds per 100 of the given unit
+func (i Interval) PacePer100(unit DistanceUnit) float64 {
+	return PacePer100(i.PacePer100m(), unit)
+}
+
 // PaceFormatted returns the pace as a string in the format mm:ss per 100m;
 // If distance is 0, it returns "N/A"
 func (i Interval) PaceFormatted() string {
-	pace := i.PacePer100m()
-	if pace == 0 {
-		return "N/A"
+	return FormatPace(i.PacePer100m())
+}
+
+// TotalStrokes returns the number of strokes taken during the interval,
+// adding up the per-length counts when no total was informed
+func (i Interval) TotalStrokes() int {
+	if i.StrokeCount > 0 {
+		return i.StrokeCount
+	}
+
+	var total int
+	for _, count := range i.LengthStrokeCounts {
+		total += count
 	}
 
-	minutes := int(pace) / 60
-	seconds := int(pace) % 60
+	return total
+}
+
+// SWOLF returns the average strokes plus seconds per pool length;
+// If there is no stroke count or pool size, it returns 0
+func (i Interval) SWOLF(poolSize float64) float64 {
+	if i.Type == IntervalRest {
+		return 0
+	}
+
+	return swolf(i.TotalStrokes(), i.Duration.Seconds(), i.Distance, poolSize)
+}
+
+// DistancePerStroke returns the average distance, in meters, covered by each stroke;
+// If there is no stroke count, it returns 0
+func (i Interval) DistancePerStroke() float64 {
+	strokes := i.TotalStrokes()
+	if i.Type == IntervalRest || strokes == 0 {
+		return 0
+	}
+
+	return i.Distance / float64(strokes)
+}
+
+func swolf(strokes int, seconds, distance, poolSize float64) float64 {
+	if strokes <= 0 || distance <= 0 || poolSize <= 0 {
+		return 0
+	}
 
-	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+	return (float64(strokes) + seconds) / (distance / poolSize)
 }
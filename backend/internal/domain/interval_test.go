@@ -14,7 +14,7 @@ func TestPacePer100m(t *testing.T) {
 		{
 			name: "Valid swim interval",
 			interval: Interval{
-				Duration: DurationString((120 * time.Second).String()),
+				Duration: Duration(120 * time.Second),
 				Distance: 100,
 				Type:     IntervalSwim,
 			},
@@ -23,7 +23,7 @@ func TestPacePer100m(t *testing.T) {
 		{
 			name: "Rest interval",
 			interval: Interval{
-				Duration: DurationString((60 * time.Second).String()),
+				Duration: Duration(60 * time.Second),
 				Distance: 0,
 				Type:     IntervalRest,
 			},
@@ -32,7 +32,7 @@ func TestPacePer100m(t *testing.T) {
 		{
 			name: "Zero distance",
 			interval: Interval{
-				Duration: DurationString((60 * time.Second).String()),
+				Duration: Duration(60 * time.Second),
 				Distance: 0,
 				Type:     IntervalSwim,
 			},
@@ -59,7 +59,7 @@ func TestPaceFormatted(t *testing.T) {
 		{
 			name: "Valid swim interval",
 			interval: Interval{
-				Duration: DurationString((125 * time.Second).String()),
+				Duration: Duration(125 * time.Second),
 				Distance: 100,
 				Type:     IntervalSwim,
 			},
@@ -68,7 +68,7 @@ func TestPaceFormatted(t *testing.T) {
 		{
 			name: "Rest interval",
 			interval: Interval{
-				Duration: DurationString((60 * time.Second).String()),
+				Duration: Duration(60 * time.Second),
 				Distance: 0,
 				Type:     IntervalRest,
 			},
@@ -77,7 +77,7 @@ func TestPaceFormatted(t *testing.T) {
 		{
 			name: "Zero distance",
 			interval: Interval{
-				Duration: DurationString((60 * time.Second).String()),
+				Duration: Duration(60 * time.Second),
 				Distance: 0,
 				Type:     IntervalSwim,
 			},
@@ -94,3 +94,96 @@ func TestPaceFormatted(t *testing.T) {
 		})
 	}
 }
+
+func TestSWOLF(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval Interval
+		poolSize float64
+		expected float64
+	}{
+		{
+			name: "Total stroke count",
+			interval: Interval{
+				Duration:    MustParseDuration("1m40s"),
+				Distance:    100,
+				Type:        IntervalSwim,
+				StrokeCount: 68,
+			},
+			poolSize: 25,
+			expected: 42,
+		},
+		{
+			name: "Per-length stroke counts",
+			interval: Interval{
+				Duration:           MustParseDuration("1m40s"),
+				Distance:           100,
+				Type:               IntervalSwim,
+				LengthStrokeCounts: []int{16, 17, 17, 18},
+			},
+			poolSize: 25,
+			expected: 42,
+		},
+		{
+			name: "No stroke count",
+			interval: Interval{
+				Duration: MustParseDuration("1m40s"),
+				Distance: 100,
+				Type:     IntervalSwim,
+			},
+			poolSize: 25,
+			expected: 0,
+		},
+		{
+			name: "No pool size",
+			interval: Interval{
+				Duration:    MustParseDuration("1m40s"),
+				Distance:    100,
+				Type:        IntervalSwim,
+				StrokeCount: 68,
+			},
+			poolSize: 0,
+			expected: 0,
+		},
+		{
+			name: "Rest interval",
+			interval: Interval{
+				Duration:    MustParseDuration("30s"),
+				Type:        IntervalRest,
+				StrokeCount: 10,
+			},
+			poolSize: 25,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.interval.SWOLF(tt.poolSize)
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDistancePerStroke(t *testing.T) {
+	interval := Interval{Distance: 100, Type: IntervalSwim, StrokeCount: 50}
+	if result := interval.DistancePerStroke(); result != 2 {
+		t.Errorf("expected 2, got %v", result)
+	}
+
+	interval.StrokeCount = 0
+	if result := interval.DistancePerStroke(); result != 0 {
+		t.Errorf("expected 0 without strokes, got %v", result)
+	}
+}
+
+func TestIntervalEnumsValid(t *testing.T) {
+	if !IntervalMainSet.Valid() || IntervalType("sprint").Valid() || IntervalType("").Valid() {
+		t.Error("unexpected IntervalType validity")
+	}
+	if !StrokeUnknown.Valid() || StrokeType("doggy").Valid() {
+		t.Error("unexpected StrokeType validity")
+	}
+}
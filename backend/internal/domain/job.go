@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus defines the progress of a background job
+type JobStatus string
+
+// Predefined job statuses
+const (
+	// JobQueued means the job waits for a worker, for its first attempt or a retry
+	JobQueued JobStatus = "queued"
+	// JobRunning means a worker is running the job
+	JobRunning JobStatus = "running"
+	// JobSucceeded means the job completed
+	JobSucceeded JobStatus = "succeeded"
+	// JobFailed means the job failed permanently or ran out of attempts
+	JobFailed JobStatus = "failed"
+)
+
+var jobStatuses = newEnum("JobStatus", JobQueued, JobRunning, JobSucceeded, JobFailed)
+
+// Valid reports whether the status is one of the predefined statuses
+func (s JobStatus) Valid() bool {
+	return jobStatuses.valid(s)
+}
+
+// Values returns the predefined statuses
+func (JobStatus) Values() []string {
+	return jobStatuses.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown statuses
+func (s JobStatus) MarshalText() ([]byte, error) {
+	return jobStatuses.marshalText(s)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown statuses
+func (s *JobStatus) UnmarshalText(text []byte) (err error) {
+	*s, err = jobStatuses.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (s *JobStatus) Scan(src any) (err error) {
+	*s, err = jobStatuses.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (s JobStatus) Value() (driver.Value, error) {
+	return jobStatuses.value(s)
+}
+
+// Job is a unit of work run in the background by the job runner
+type Job struct {
+	// ID is the unique identifier for the job (PK)
+	ID uuid.UUID `json:"id"`
+	// Type selects the handler running the job, e.g., "export"
+	Type string `json:"type"`
+	// Payload holds the arguments of the handler, as JSON
+	Payload json.RawMessage `json:"-"`
+	// UserID is the ID of the user the job works for, null for system jobs
+	UserID uuid.NullUUID `json:"user_id" swaggertype:"string"`
+	// Status is the progress of the job
+	Status JobStatus `json:"status"`
+	// Attempts counts the runs of the job so far
+	Attempts int `json:"attempts"`
+	// MaxAttempts is the number of runs after which a failing job is given up
+	MaxAttempts int `json:"max_attempts"`
+	// RunAt is when the job is run next, while queued
+	RunAt time.Time `json:"run_at"`
+	// LastError is the error of the last failed attempt; it is not served, as it may reveal internals
+	LastError string `json:"-"`
+	// CreatedAt is when the job was enqueued, set by the repository
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is when the job last changed status, set by the repository
+	UpdatedAt time.Time `json:"updated_at"`
+	// CompletedAt is when the job succeeded or failed
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+}
+
+// LastAttempt reports whether a failure of the current attempt fails the job for good
+func (j Job) LastAttempt() bool {
+	return j.Attempts >= j.MaxAttempts
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "fmt"
+
+// PaceZone represents a training zone as a range of paces, in seconds per 100 meters
+type PaceZone struct {
+	// Number of the zone, from 1 (easiest) upwards
+	Number int `json:"number"`
+	// Name of the zone, e.g., "threshold"
+	Name string `json:"name"`
+	// Fastest pace of the zone (0 if unbounded)
+	MinPace float64 `json:"min_pace"`
+	// Slowest pace of the zone, exclusive (0 if unbounded)
+	MaxPace float64 `json:"max_pace"`
+}
+
+// Contains reports whether the given pace, in seconds per 100m, falls within the zone
+func (z PaceZone) Contains(pace float64) bool {
+	return pace >= z.MinPace && (z.MaxPace == 0 || pace < z.MaxPace)
+}
+
+// ZoneForPace returns the zone the given pace falls in;
+// If the pace is 0 or there are no zones, the second value is false
+func ZoneForPace(zones []PaceZone, pace float64) (PaceZone, bool) {
+	if pace == 0 {
+		return PaceZone{}, false
+	}
+
+	for _, zone := range zones {
+		if zone.Contains(pace) {
+			return zone, true
+		}
+	}
+
+	return PaceZone{}, false
+}
+
+// FormatPace returns a pace in seconds per 100m as a string in the format mm:ss;
+// If the pace is 0, it returns "N/A"
+func FormatPace(pace float64) string {
+	if pace == 0 {
+		return "N/A"
+	}
+
+	minutes := int(pace) / 60
+	seconds := int(pace) % 60
+
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestFormatPace(t *testing.T) {
+	tests := []struct {
+		name     string
+		pace     float64
+		expected string
+	}{
+		{"Zero pace", 0, "N/A"},
+		{"Under a minute", 45.7, "00:45"},
+		{"Over a minute", 95, "01:35"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatPace(tt.pace)
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestZoneForPace(t *testing.T) {
+	zones := []PaceZone{
+		{Number: 1, Name: "easy", MinPace: 100},
+		{Number: 2, Name: "hard", MaxPace: 100},
+	}
+
+	if zone, ok := ZoneForPace(zones, 110); !ok || zone.Number != 1 {
+		t.Errorf("expected zone 1, got %v", zone)
+	}
+	if zone, ok := ZoneForPace(zones, 90); !ok || zone.Number != 2 {
+		t.Errorf("expected zone 2, got %v", zone)
+	}
+	if _, ok := ZoneForPace(zones, 0); ok {
+		t.Error("expected no zone for a zero pace")
+	}
+	if _, ok := ZoneForPace(nil, 90); ok {
+		t.Error("expected no zone without zones")
+	}
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "github.com/google/uuid"
+
+// RecordType defines the kinds of personal records
+type RecordType string
+
+// Predefined record types
+const (
+	// RecordLongestDistance is the longest distance swum in an activity, in meters
+	RecordLongestDistance RecordType = "longest_distance"
+)
+
+// PersonalRecord is a personal best of a user, achieved by an activity
+type PersonalRecord struct {
+	// Type is the kind of record
+	Type RecordType `json:"type"`
+	// ActivityID is the ID of the activity achieving the record
+	ActivityID uuid.UUID `json:"activity_id"`
+	// Value is the new best
+	Value float64 `json:"value"`
+	// Previous is the best before the activity
+	Previous float64 `json:"previous"`
+}
+
+// LongestDistanceRecord returns the record achieved by an activity swimming farther
+// than every earlier activity of its user, among activities; Only those swum before
+// count, so a backfilled activity is compared to its past, not to the later ones.
+// The first activity of a user sets no record
+func LongestDistanceRecord(activity Activity, activities []Activity) (PersonalRecord, bool) {
+	var previous float64
+	for _, a := range activities {
+		if a.ID != activity.ID && a.Before(activity) {
+			previous = max(previous, a.Distance)
+		}
+	}
+	if previous == 0 || activity.Distance <= previous {
+		return PersonalRecord{}, false
+	}
+	return PersonalRecord{Type: RecordLongestDistance, ActivityID: activity.ID, Value: activity.Distance, Previous: previous}, true
+}
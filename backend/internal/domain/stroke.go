@@ -0,0 +1,68 @@
+package domain
+
+// StrokeMetrics summarises the stroke efficiency of a session
+type StrokeMetrics struct {
+	// Total number of strokes over the intervals with stroke data
+	StrokeCount int
+	// Average strokes plus seconds per pool length (0 outside the pool)
+	SWOLF float64
+	// Average distance, in meters, covered by each stroke
+	DistancePerStroke float64
+}
+
+// PoolLength returns the length, in meters, of the pool the activity was swum in;
+// If the activity did not take place in a pool, it returns 0
+func (a Activity) PoolLength() float64 {
+	if a.LocationType != LocationPool {
+		return 0
+	}
+
+	return Distance{Value: a.PoolSize, Unit: a.PoolUnit}.Meters()
+}
+
+// Course returns the standard course of the pool the activity was swum in;
+// If the activity did not take place in a standard pool, it returns an empty course
+func (a Activity) Course() Course {
+	if a.LocationType != LocationPool {
+		return ""
+	}
+
+	switch {
+	case a.PoolUnit.OrDefault() == DistanceUnitYards && a.PoolSize == 25:
+		return CourseShortYards
+	case a.PoolUnit.OrDefault() == DistanceUnitMeters && a.PoolSize == 25:
+		return CourseShortMeters
+	case a.PoolUnit.OrDefault() == DistanceUnitMeters && a.PoolSize == 50:
+		return CourseLongMeters
+	default:
+		return ""
+	}
+}
+
+// StrokeMetrics aggregates the stroke metrics of the activity over the given
+// intervals, considering only swum intervals with stroke data; the second value
+// is false if no interval has stroke data
+func (a Activity) StrokeMetrics(intervals []Interval) (StrokeMetrics, bool) {
+	var strokes int
+	var seconds, distance float64
+	for _, interval := range intervals {
+		count := interval.TotalStrokes()
+		if interval.Type == IntervalRest || count == 0 {
+			continue
+		}
+
+		strokes += count
+		seconds += interval.Duration.Seconds()
+		distance += interval.Distance
+	}
+
+	if strokes == 0 {
+		return StrokeMetrics{}, false
+	}
+
+	return StrokeMetrics{
+		StrokeCount:       strokes,
+		SWOLF:             swolf(strokes, seconds, distance, a.PoolLength()),
+		DistancePerStroke: distance / float64(strokes),
+	}, true
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "testing"
+
+func TestStrokeMetrics(t *testing.T) {
+	intervals := []Interval{
+		{Duration: MustParseDuration("1m40s"), Distance: 100, Type: IntervalSwim, StrokeCount: 68},
+		{Duration: MustParseDuration("30s"), Type: IntervalRest},
+		{Duration: MustParseDuration("1m50s"), Distance: 100, Type: IntervalSwim, StrokeCount: 72},
+		{Duration: MustParseDuration("2m0s"), Distance: 100, Type: IntervalKick},
+	}
+
+	t.Run("Pool", func(t *testing.T) {
+		activity := Activity{PoolSize: 25, LocationType: LocationPool}
+
+		metrics, ok := activity.StrokeMetrics(intervals)
+		if !ok {
+			t.Fatal("expected stroke metrics")
+		}
+		if metrics.StrokeCount != 140 {
+			t.Errorf("expected 140 strokes, got %d", metrics.StrokeCount)
+		}
+		if metrics.SWOLF != 43.75 {
+			t.Errorf("expected SWOLF 43.75, got %v", metrics.SWOLF)
+		}
+		if metrics.DistancePerStroke != 200.0/140 {
+			t.Errorf("unexpected distance per stroke %v", metrics.DistancePerStroke)
+		}
+	})
+
+	t.Run("Open water", func(t *testing.T) {
+		activity := Activity{LocationType: LocationOpenWater}
+
+		metrics, ok := activity.StrokeMetrics(intervals)
+		if !ok {
+			t.Fatal("expected stroke metrics")
+		}
+		if metrics.SWOLF != 0 {
+			t.Errorf("expected no SWOLF in open water, got %v", metrics.SWOLF)
+		}
+	})
+
+	t.Run("No stroke data", func(t *testing.T) {
+		activity := Activity{PoolSize: 25, LocationType: LocationPool}
+
+		if _, ok := activity.StrokeMetrics(intervals[1:2]); ok {
+			t.Error("expected no stroke metrics")
+		}
+	})
+}
+
+func TestPoolLength(t *testing.T) {
+	yards := Activity{LocationType: LocationPool, PoolSize: 25, PoolUnit: DistanceUnitYards}
+	if result := yards.PoolLength(); result != 25*MetersPerYard {
+		t.Errorf("expected %v, got %v", 25*MetersPerYard, result)
+	}
+
+	openWater := Activity{LocationType: LocationOpenWater, PoolSize: 25}
+	if result := openWater.PoolLength(); result != 0 {
+		t.Errorf("expected 0 in open water, got %v", result)
+	}
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Rolling windows used for the acute:chronic workload model
+const (
+	// AcuteLoadDays is the window, in days, of the acute (fatigue) load
+	AcuteLoadDays = 7
+	// ChronicLoadDays is the window, in days, of the chronic (fitness) load
+	ChronicLoadDays = 42
+)
+
+// DateLayout is the ISO 8601 layout used by Activity.Date
+const DateLayout = "2006-01-02"
+
+// SessionRPELoad returns the session-RPE load (RPE x duration in minutes);
+// If no RPE was reported, it returns 0
+func (a Activity) SessionRPELoad() float64 {
+	if a.RPE <= 0 {
+		return 0
+	}
+
+	return float64(a.RPE) * a.Duration.Seconds() / 60
+}
+
+// TRIMP returns Banister's training impulse for the session, computed from
+// the average heart rate and the given maximum and resting heart rates;
+// If there is not enough heart-rate data, it returns 0
+func (a Activity) TRIMP(maxHR, restingHR int) float64 {
+	if a.HeartRateAvg <= 0 || maxHR <= restingHR {
+		return 0
+	}
+
+	reserve := float64(a.HeartRateAvg-restingHR) / float64(maxHR-restingHR)
+	reserve = math.Max(0, math.Min(1, reserve))
+
+	return a.Duration.Seconds() / 60 * reserve * 0.64 * math.Exp(1.92*reserve)
+}
+
+// TrainingLoad returns the load of the session for the given user,
+// preferring session-RPE and falling back to heart-rate based TRIMP
+func (a Activity) TrainingLoad(user User) float64 {
+	if load := a.SessionRPELoad(); load > 0 {
+		return load
+	}
+
+	return a.TRIMP(user.EffectiveMaxHeartRate(), user.EffectiveRestingHeartRate())
+}
+
+// Day returns the calendar day of the activity, parsed from Date and
+// falling back to the start time when Date is not in ISO 8601 format
+func (a Activity) Day() time.Time {
+	if day, err := time.Parse(DateLayout, a.Date); err == nil {
+		return day
+	}
+
+	return truncateToDay(a.Start)
+}
+
+// DailyLoad represents the training load of a single day and the rolling
+// acute and chronic averages ending on that day
+type DailyLoad struct {
+	// Day the values refer to
+	Date time.Time
+	// Sum of the loads of all sessions on this day
+	Load float64
+	// Average daily load over the last AcuteLoadDays days
+	Acute float64
+	// Average daily load over the last ChronicLoadDays days
+	Chronic float64
+}
+
+// Ratio returns the acute:chronic workload ratio;
+// If there is no chronic load yet, it returns 0
+func (d DailyLoad) Ratio() float64 {
+	if d.Chronic == 0 {
+		return 0
+	}
+
+	return d.Acute / d.Chronic
+}
+
+// ComputeDailyLoads returns one DailyLoad per day between from and to
+// (inclusive); activities before from are taken into account for the
+// rolling averages, so callers should include at least ChronicLoadDays
+// days of history
+func ComputeDailyLoads(activities []Activity, user User, from, to time.Time) []DailyLoad {
+	from, to = truncateToDay(from), truncateToDay(to)
+	if to.Before(from) {
+		return []DailyLoad{}
+	}
+
+	loads := make(map[time.Time]float64)
+	for _, activity := range activities {
+		loads[activity.Day()] += activity.TrainingLoad(user)
+	}
+
+	windowSum := func(end time.Time, days int) float64 {
+		var sum float64
+		for i := 0; i < days; i++ {
+			sum += loads[end.AddDate(0, 0, -i)]
+		}
+		return sum
+	}
+
+	var daily []DailyLoad
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		daily = append(daily, DailyLoad{
+			Date:    day,
+			Load:    loads[day],
+			Acute:   windowSum(day, AcuteLoadDays) / AcuteLoadDays,
+			Chronic: windowSum(day, ChronicLoadDays) / ChronicLoadDays,
+		})
+	}
+
+	return daily
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
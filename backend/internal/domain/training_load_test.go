@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSessionRPELoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		activity Activity
+		expected float64
+	}{
+		{
+			name:     "RPE reported",
+			activity: Activity{Duration: MustParseDuration("1h0m0s"), RPE: 7},
+			expected: 420,
+		},
+		{
+			name:     "No RPE",
+			activity: Activity{Duration: MustParseDuration("1h0m0s")},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.activity.SessionRPELoad()
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTRIMP(t *testing.T) {
+	tests := []struct {
+		name      string
+		activity  Activity
+		maxHR     int
+		restingHR int
+		expected  float64
+	}{
+		{
+			name:      "Heart rate reserve of 50%",
+			activity:  Activity{Duration: MustParseDuration("30m0s"), HeartRateAvg: 125},
+			maxHR:     190,
+			restingHR: 60,
+			expected:  30 * 0.5 * 0.64 * math.Exp(1.92*0.5),
+		},
+		{
+			name:      "No average heart rate",
+			activity:  Activity{Duration: MustParseDuration("30m0s")},
+			maxHR:     190,
+			restingHR: 60,
+			expected:  0,
+		},
+		{
+			name:      "Unknown max heart rate",
+			activity:  Activity{Duration: MustParseDuration("30m0s"), HeartRateAvg: 125},
+			maxHR:     0,
+			restingHR: 60,
+			expected:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.activity.TRIMP(tt.maxHR, tt.restingHR)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTrainingLoad(t *testing.T) {
+	user := User{Age: 30, RestingHeartRate: 60}
+
+	withRPE := Activity{Duration: MustParseDuration("1h0m0s"), RPE: 5, HeartRateAvg: 150}
+	if result := withRPE.TrainingLoad(user); result != 300 {
+		t.Errorf("expected session-RPE load 300, got %v", result)
+	}
+
+	withHR := Activity{Duration: MustParseDuration("1h0m0s"), HeartRateAvg: 150}
+	if result, expected := withHR.TrainingLoad(user), withHR.TRIMP(190, 60); result != expected {
+		t.Errorf("expected TRIMP load %v, got %v", expected, result)
+	}
+}
+
+func TestComputeDailyLoads(t *testing.T) {
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	activities := []Activity{
+		{Date: "2023-10-01", Duration: MustParseDuration("1h0m0s"), RPE: 7},
+		{Date: "2023-10-01", Duration: MustParseDuration("30m0s"), RPE: 4},
+		{Date: "2023-09-25", Duration: MustParseDuration("1h0m0s"), RPE: 7},
+	}
+
+	result := ComputeDailyLoads(activities, User{}, from, to)
+	if len(result) != 8 {
+		t.Fatalf("expected 8 days, got %d", len(result))
+	}
+
+	first := result[0]
+	if first.Load != 540 {
+		t.Errorf("expected load 540 on first day, got %v", first.Load)
+	}
+	if first.Acute != (540.0+420.0)/AcuteLoadDays {
+		t.Errorf("unexpected acute load %v", first.Acute)
+	}
+	if first.Chronic != (540.0+420.0)/ChronicLoadDays {
+		t.Errorf("unexpected chronic load %v", first.Chronic)
+	}
+	if first.Ratio() != float64(ChronicLoadDays)/AcuteLoadDays {
+		t.Errorf("unexpected ratio %v", first.Ratio())
+	}
+
+	last := result[7]
+	if last.Load != 0 || last.Acute != 0 {
+		t.Errorf("expected no acute load on last day, got load %v acute %v", last.Load, last.Acute)
+	}
+}
+
+func TestComputeDailyLoads_InvalidRange(t *testing.T) {
+	from := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	if result := ComputeDailyLoads(nil, User{}, from, to); len(result) != 0 {
+		t.Errorf("expected no days, got %d", len(result))
+	}
+}
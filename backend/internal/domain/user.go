@@ -1,9 +1,14 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// DefaultRestingHeartRate is assumed when the user has not informed their resting heart rate
+const DefaultRestingHeartRate = 60
+
 type User struct {
 	ID     uuid.UUID `json:"id"`
 	Name   string    `json:"name"`
@@ -13,4 +18,40 @@ type User struct {
 	Age    int       `json:"age"`
 	Height int       `json:"height"`
 	Weight float64   `json:"weight"`
+	// Optional maximum heart rate in bpm
+	MaxHeartRate int `json:"max_heart_rate,omitempty"`
+	// Optional resting heart rate in bpm
+	RestingHeartRate int `json:"resting_heart_rate,omitempty"`
+	// Optional lactate threshold heart rate in bpm, preferred for heart-rate zones
+	LactateThresholdHeartRate int `json:"lactate_threshold_heart_rate,omitempty"`
+	// Unit distances and paces are displayed in, "meters" (default) or "yards"
+	DisplayUnit DistanceUnit `json:"display_unit,omitempty"`
+	// Version is incremented on every update; An update with a non-zero version
+	// only applies if the stored user still has that version
+	Version int `json:"version" readonly:"true"`
+	// CreatedAt is when the user was created, set by the repository
+	CreatedAt time.Time `json:"created_at,omitzero" readonly:"true"`
+	// UpdatedAt is when the user was last updated, set by the repository
+	UpdatedAt time.Time `json:"updated_at,omitzero" readonly:"true"`
+}
+
+// EffectiveMaxHeartRate returns the user's maximum heart rate, estimated
+// as 220 - age when not informed; returns 0 if it cannot be determined
+func (u User) EffectiveMaxHeartRate() int {
+	if u.MaxHeartRate > 0 {
+		return u.MaxHeartRate
+	}
+	if u.Age > 0 {
+		return 220 - u.Age
+	}
+	return 0
+}
+
+// EffectiveRestingHeartRate returns the user's resting heart rate,
+// falling back to DefaultRestingHeartRate when not informed
+func (u User) EffectiveRestingHeartRate() int {
+	if u.RestingHeartRate > 0 {
+		return u.RestingHeartRate
+	}
+	return DefaultRestingHeartRate
 }
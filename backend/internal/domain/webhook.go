@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent defines the events a webhook can subscribe to
+type WebhookEvent string
+
+// Predefined webhook events
+const (
+	// WebhookActivityCreated is sent with an activity when it is created
+	WebhookActivityCreated WebhookEvent = "activity.created"
+	// WebhookActivityUpdated is sent with an activity, as updated
+	WebhookActivityUpdated WebhookEvent = "activity.updated"
+	// WebhookActivityDeleted is sent with an activity, as it was when deleted
+	WebhookActivityDeleted WebhookEvent = "activity.deleted"
+	// WebhookRecordAchieved is sent with a PersonalRecord when an activity beats one
+	WebhookRecordAchieved WebhookEvent = "record.achieved"
+)
+
+var webhookEvents = newEnum("WebhookEvent", WebhookActivityCreated, WebhookActivityUpdated, WebhookActivityDeleted, WebhookRecordAchieved)
+
+// Valid reports whether the event is one of the predefined events
+func (e WebhookEvent) Valid() bool {
+	return webhookEvents.valid(e)
+}
+
+// Values returns the predefined events
+func (WebhookEvent) Values() []string {
+	return webhookEvents.strings()
+}
+
+// MarshalText implements encoding.TextMarshaler, rejecting unknown events
+func (e WebhookEvent) MarshalText() ([]byte, error) {
+	return webhookEvents.marshalText(e)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, rejecting unknown events
+func (e *WebhookEvent) UnmarshalText(text []byte) (err error) {
+	*e, err = webhookEvents.unmarshalText(text)
+	return err
+}
+
+// Scan implements sql.Scanner, reading NULL as the zero value
+func (e *WebhookEvent) Scan(src any) (err error) {
+	*e, err = webhookEvents.scan(src)
+	return err
+}
+
+// Value implements driver.Valuer, storing the zero value as NULL
+func (e WebhookEvent) Value() (driver.Value, error) {
+	return webhookEvents.value(e)
+}
+
+// Webhook is an HTTP endpoint registered by a user to be notified of events about their data
+type Webhook struct {
+	// ID is the unique identifier for the webhook (PK)
+	ID uuid.UUID `json:"id"`
+	// UserID is the ID of the user whose events are sent (FK)
+	UserID uuid.UUID `json:"user_id"`
+	// URL is the http or https endpoint the events are posted to
+	URL string `json:"url"`
+	// Events are the events the webhook subscribes to
+	Events []WebhookEvent `json:"events"`
+	// Secret is the key of the HMAC-SHA256 signature of the deliveries; it is only
+	// served when the webhook is created
+	Secret string `json:"secret,omitempty"`
+	// CreatedAt is when the webhook was registered, set by the repository
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// Validate checks that the URL is an absolute http or https URL
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return NewValidationError(FieldError{Field: "url", Code: CodeInvalidFormat, Message: "must be an absolute http or https URL"})
+	}
+	return nil
+}
+
+// Subscribed reports whether the webhook subscribes to the event
+func (w Webhook) Subscribed(event WebhookEvent) bool {
+	return slices.Contains(w.Events, event)
+}
+
+// WebhookPayload is the body posted to a webhook; Its ID identifies the event
+// across the retries of its delivery, so receivers can ignore duplicates
+type WebhookPayload struct {
+	ID         uuid.UUID    `json:"id"`
+	Event      WebhookEvent `json:"event"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Data       any          `json:"data"`
+}
+
+// WebhookDelivery is an attempt at posting an event to a webhook
+type WebhookDelivery struct {
+	// ID is the unique identifier for the delivery (PK)
+	ID uuid.UUID `json:"id"`
+	// WebhookID is the ID of the webhook the event was posted to (FK)
+	WebhookID uuid.UUID `json:"webhook_id"`
+	// EventID is the ID of the event, shared by the attempts at delivering it
+	EventID uuid.UUID `json:"event_id"`
+	// Event is the event posted
+	Event WebhookEvent `json:"event"`
+	// Attempt counts the attempts at delivering the event, from 1
+	Attempt int `json:"attempt"`
+	// StatusCode is the HTTP status of the response, 0 if there was none
+	StatusCode int `json:"status_code,omitempty"`
+	// Error tells why the attempt failed, e.g., a timeout or an unsuccessful status
+	Error string `json:"error,omitempty"`
+	// DurationMS is how long the endpoint took to respond, in milliseconds
+	DurationMS int64 `json:"duration_ms"`
+	// DeliveredAt is when the attempt was made, set by the repository
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+// Succeeded reports whether the endpoint acknowledged the event with a 2xx status
+func (d WebhookDelivery) Succeeded() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.example.com/swims", true},
+		{"http://localhost:9000/hook", true},
+		{"ftp://example.com/hook", false},
+		{"/hook", false},
+		{"https://", false},
+		{"not a url", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := Webhook{URL: tt.url}.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrValidation) {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhook_Subscribed(t *testing.T) {
+	webhook := Webhook{Events: []WebhookEvent{WebhookActivityCreated, WebhookRecordAchieved}}
+	if !webhook.Subscribed(WebhookRecordAchieved) {
+		t.Error("expected a subscription to record.achieved")
+	}
+	if webhook.Subscribed(WebhookActivityDeleted) {
+		t.Error("expected no subscription to activity.deleted")
+	}
+}
+
+func TestWebhookDelivery_Succeeded(t *testing.T) {
+	for status, want := range map[int]bool{0: false, 200: true, 204: true, 301: false, 410: false, 500: false} {
+		if got := (WebhookDelivery{StatusCode: status}).Succeeded(); got != want {
+			t.Errorf("Succeeded() with status %d = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestLongestDistanceRecord(t *testing.T) {
+	activity := Activity{ID: uuid.New(), Date: "2026-10-03", Distance: 3000}
+	earlier := []Activity{
+		{ID: uuid.New(), Date: "2026-10-01", Distance: 1500},
+		{ID: uuid.New(), Date: "2026-10-02", Distance: 2500},
+		activity,
+	}
+
+	record, ok := LongestDistanceRecord(activity, earlier)
+	if !ok {
+		t.Fatal("expected a record")
+	}
+	want := PersonalRecord{Type: RecordLongestDistance, ActivityID: activity.ID, Value: 3000, Previous: 2500}
+	if record != want {
+		t.Errorf("record = %+v, want %+v", record, want)
+	}
+
+	if _, ok := LongestDistanceRecord(Activity{ID: uuid.New(), Date: "2026-10-04", Distance: 3000}, earlier); ok {
+		t.Error("expected no record when tying the best")
+	}
+	if _, ok := LongestDistanceRecord(activity, []Activity{activity}); ok {
+		t.Error("expected no record for the first activity")
+	}
+}
+
+func TestLongestDistanceRecord_Backfill(t *testing.T) {
+	activities := []Activity{
+		{ID: uuid.New(), Date: "2026-09-01", Distance: 1500},
+		{ID: uuid.New(), Date: "2026-09-10", Start: time.Date(2026, 9, 10, 7, 0, 0, 0, time.UTC), Distance: 1800},
+		{ID: uuid.New(), Date: "2026-09-10", Start: time.Date(2026, 9, 10, 19, 0, 0, 0, time.UTC), Distance: 5000},
+		{ID: uuid.New(), Date: "2026-10-01", Distance: 4000},
+	}
+
+	// Logged late, the activity beats what was swum before it but not what was swum after
+	backfilled := Activity{ID: uuid.New(), Date: "2026-09-10", Start: time.Date(2026, 9, 10, 12, 0, 0, 0, time.UTC), Distance: 2000}
+	record, ok := LongestDistanceRecord(backfilled, append(activities, backfilled))
+	if !ok {
+		t.Fatal("expected a record")
+	}
+	want := PersonalRecord{Type: RecordLongestDistance, ActivityID: backfilled.ID, Value: 2000, Previous: 1800}
+	if record != want {
+		t.Errorf("record = %+v, want %+v", record, want)
+	}
+
+	if _, ok := LongestDistanceRecord(Activity{ID: uuid.New(), Date: "2026-08-01", Distance: 2000}, activities); ok {
+		t.Error("expected no record for an activity before all the others")
+	}
+}
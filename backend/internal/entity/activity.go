@@ -7,27 +7,6 @@ import (
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 )
 
-// LocationType defines the kind of environment for the swim
-type LocationType string
-
-// Predefined location types
-const (
-	LocationPool      LocationType = "pool"
-	LocationOpenWater LocationType = "open_water"
-)
-
-// FeelingType defines options for how a swimmer feels after a session
-type FeelingType string
-
-// Predefined feelings
-const (
-	FeelingExcellent FeelingType = "excellent"
-	FeelingGood      FeelingType = "good"
-	FeelingRegular   FeelingType = "regular"
-	FeelingTired     FeelingType = "tired"
-	FeelingBad       FeelingType = "bad"
-)
-
 // Activity is the internal struct to represent a swimming activity or session
 type Activity struct {
 	// ID is the unique identifier for the activity (PK)
@@ -38,28 +17,50 @@ type Activity struct {
 	Date string `json:"date"`
 	// Start time of the activity
 	Start time.Time `json:"start"`
-	// Duration of the activity in string format, e.g., "1h30m"
-	Duration domain.DurationString `json:"duration"`
-	// Total distance in meters
+	// Duration of the activity, e.g., "1h30m0s" or "1:30:00"
+	Duration domain.Duration `json:"duration" swaggertype:"string"`
+	// Total distance, in DistanceUnit
 	Distance float64 `json:"distance"`
 	// Number of pool laps
 	Laps int `json:"laps"`
-	// Pool length in meters (0 if open water)
+	// Pool length, in PoolUnit (0 if open water)
 	PoolSize float64 `json:"pool_size"`
+	// Unit of the pool length, "meters" or "yards"
+	PoolUnit domain.DistanceUnit `json:"pool_unit"`
+	// Standard course of the pool, "SCM", "SCY" or "LCM" (omitted if non-standard)
+	Course string `json:"course,omitempty"`
+	// Unit the distances and paces are displayed in, "meters" or "yards"
+	DistanceUnit domain.DistanceUnit `json:"distance_unit"`
 	// "pool" or "open_water"
-	LocationType LocationType `json:"location_type"`
+	LocationType domain.LocationType `json:"location_type"`
 	// Optional name for the location, e.g., "CEPE"
 	LocationName string `json:"location_name,omitempty"`
 	// Optional feeling after the swim, e.g., "tired"
-	Feeling FeelingType `json:"feeling,omitempty"`
+	Feeling domain.FeelingType `json:"feeling,omitempty"`
 	// Average heart rate during the activity
 	HeartRateAvg int `json:"heart_rate_avg,omitempty"`
 	// Maximum heart rate during the activity
 	HeartRateMax int `json:"heart_rate_max,omitempty"`
-	// Average pace in seconds per 100 meters, formatted mm:ss
+	// Rating of perceived exertion, from 1 (very easy) to 10 (maximal)
+	RPE int `json:"rpe,omitempty"`
+	// Average pace per 100 of DistanceUnit, formatted mm:ss
 	AvgPacePer100m string `json:"avg_pace_per_100m,omitempty"`
 	// Optional notes
 	Notes string `json:"notes"`
 	// Intervals are the segments of the swim session
 	Intervals []Interval `json:"intervals"`
+	// Time spent in each heart-rate zone, when heart-rate data is available
+	TimeInZone []TimeInZone `json:"time_in_zone,omitempty"`
+	// Total number of strokes over the intervals with stroke data
+	StrokeCount int `json:"stroke_count,omitempty"`
+	// Average strokes plus seconds per pool length
+	SWOLF float64 `json:"swolf,omitempty"`
+	// Average distance, in DistanceUnit, covered by each stroke
+	DistancePerStroke float64 `json:"distance_per_stroke,omitempty"`
+	// Version is incremented on every update of the activity
+	Version int `json:"version"`
+	// CreatedAt is when the activity was created
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	// UpdatedAt is when the activity was last updated
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
 }
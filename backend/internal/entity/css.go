@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// CSSTest is the internal struct to represent a Critical Swim Speed test and its derived zones
+type CSSTest struct {
+	// ID is the unique identifier for the test
+	ID uuid.UUID `json:"id"`
+	// UserID is the ID of the user who took the test
+	UserID uuid.UUID `json:"user_id"`
+	// Date in ISO 8601 format, e.g., "2023-10-01"
+	Date string `json:"date"`
+	// Time of the 400m trial, e.g., "6m0s" or "6:00"
+	Time400 domain.Duration `json:"time_400" swaggertype:"string"`
+	// Time of the 200m trial, e.g., "2m50s" or "2:50"
+	Time200 domain.Duration `json:"time_200" swaggertype:"string"`
+	// ID of the logged interval used as the 400m trial, if any
+	Interval400ID *uuid.UUID `json:"interval_400_id,omitempty"`
+	// ID of the logged interval used as the 200m trial, if any
+	Interval200ID *uuid.UUID `json:"interval_200_id,omitempty"`
+	// Unit of the trial distances, "meters" or "yards"
+	Unit domain.DistanceUnit `json:"unit"`
+	// CSS pace per 100 meters, formatted mm:ss
+	CSSPacePer100m string `json:"css_pace_per_100m"`
+	// Pace zones derived from the CSS pace
+	Zones []PaceZone `json:"zones"`
+}
+
+// PaceZone is a training zone expressed as a range of paces per 100 meters
+type PaceZone struct {
+	// Number of the zone, from 1 (easiest) upwards
+	Number int `json:"number"`
+	// Name of the zone, e.g., "threshold"
+	Name string `json:"name"`
+	// Fastest pace of the zone, formatted mm:ss (omitted if unbounded)
+	FastestPace string `json:"fastest_pace,omitempty"`
+	// Slowest pace of the zone, formatted mm:ss (omitted if unbounded)
+	SlowestPace string `json:"slowest_pace,omitempty"`
+}
@@ -0,0 +1,35 @@
+package entity
+
+// Readiness is the result of the checks run before the API accepts traffic
+type Readiness struct {
+	// Status is "ready" when every check passed and "unavailable" otherwise
+	Status string `json:"status" example:"ready"`
+	// Checks are the individual checks, in the order they ran
+	Checks []HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the outcome of a single readiness check
+type HealthCheck struct {
+	// Name of the check, e.g., "database" or "migrations"
+	Name string `json:"name" example:"database"`
+	// Status is "ok" or "failed"
+	Status string `json:"status" example:"ok"`
+	// Error is a generic message when the check failed; the reason is only logged
+	Error string `json:"error,omitempty"`
+}
+
+// Ready reports whether every check passed
+func (r Readiness) Ready() bool {
+	return r.Status == ReadinessReady
+}
+
+// Readiness and health check statuses
+const (
+	ReadinessReady       = "ready"
+	ReadinessUnavailable = "unavailable"
+	HealthCheckOK        = "ok"
+	HealthCheckFailed    = "failed"
+)
+
+// HealthCheckFailedMessage is the error reported by a failed check
+const HealthCheckFailedMessage = "check failed, see the server logs"
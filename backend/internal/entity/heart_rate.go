@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// HeartRateZones is the internal struct to represent the heart-rate zone configuration of a user
+type HeartRateZones struct {
+	// UserID is the ID of the user the zones belong to
+	UserID uuid.UUID `json:"user_id"`
+	// What the zones are derived from: "lthr", "max_hr" or "age"
+	Basis string `json:"basis"`
+	// Zones from 1 (easiest) to 5
+	Zones []HeartRateZone `json:"zones"`
+}
+
+// HeartRateZone is a training zone expressed as a range of heart rates in bpm
+type HeartRateZone struct {
+	// Number of the zone, from 1 (easiest) upwards
+	Number int `json:"number"`
+	// Name of the zone, e.g., "aerobic"
+	Name string `json:"name"`
+	// Lowest heart rate of the zone (omitted if unbounded)
+	MinHeartRate int `json:"min_heart_rate,omitempty"`
+	// Highest heart rate of the zone, exclusive (omitted if unbounded)
+	MaxHeartRate int `json:"max_heart_rate,omitempty"`
+}
+
+// TimeInZone is the time spent in a heart-rate zone
+type TimeInZone struct {
+	// Number of the zone
+	Zone int `json:"zone"`
+	// Name of the zone, e.g., "aerobic"
+	Name string `json:"name"`
+	// Time spent in the zone, e.g., "12m30s" or "12:30"
+	Duration domain.Duration `json:"duration" swaggertype:"string"`
+}
+
+// TimeInZoneSummary is the time spent in each heart-rate zone over a period
+type TimeInZoneSummary struct {
+	// UserID is the ID of the user the summary refers to
+	UserID uuid.UUID `json:"user_id"`
+	// First day of the period in ISO 8601 format, e.g., "2023-10-01"
+	From string `json:"from"`
+	// Last day of the period in ISO 8601 format, e.g., "2023-10-28"
+	To string `json:"to"`
+	// Number of activities in the period
+	Activities int `json:"activities"`
+	// Time spent in each zone
+	TimeInZone []TimeInZone `json:"time_in_zone"`
+}
@@ -1,65 +1,45 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 )
 
-// IntervalType defines the kind of interval (e.g., swim, rest, drill)
-type IntervalType string
-
-// Predefined interval types
-const (
-	// IntervalSwim represents a generic swim interval
-	IntervalSwim IntervalType = "swim"
-	// IntervalRest represents a rest interval (break between sets)
-	IntervalRest IntervalType = "rest"
-	// IntervalDrill represents a drill interval (technique-focused)
-	IntervalDrill IntervalType = "drill"
-	// IntervalKick represents a kick interval (legs only)
-	IntervalKick IntervalType = "kick"
-	// IntervalPull represents a pull interval (arms only)
-	IntervalPull IntervalType = "pull"
-	// IntervalWarmUp represents a warm-up interval (low intensity)
-	IntervalWarmUp IntervalType = "warmup"
-	// IntervalMainSet represents the main set of the workout
-	IntervalMainSet IntervalType = "main_set"
-	// IntervalCoolDown represents a cool-down interval (low intensity)
-	IntervalCoolDown IntervalType = "cooldown"
-)
-
-// StrokeType defines the style of swimming stroke used
-type StrokeType string
-
-// Predefined stroke types
-const (
-	// StrokeFreestyle represents front crawl
-	StrokeFreestyle StrokeType = "freestyle"
-	// StrokeBackstroke represents backstroke
-	StrokeBackstroke StrokeType = "backstroke"
-	// StrokeBreaststroke represents breaststroke
-	StrokeBreaststroke StrokeType = "breaststroke"
-	// StrokeButterfly represents butterfly stroke
-	StrokeButterfly StrokeType = "butterfly"
-	// StrokeMedley represents individual medley (IM)
-	StrokeMedley StrokeType = "medley"
-	// StrokeUnknown is used when the stroke is not specified
-	StrokeUnknown StrokeType = "unknown"
-)
-
 // Interval is the internal struct to represent a single segment of a swim session
 type Interval struct {
 	ID uuid.UUID `json:"id"`
 	// Foreign key to the swim activity/session
 	ActivityID uuid.UUID `json:"activity_id"`
-	// Duration of the interval in string format, e.g., "1h30m"
-	Duration domain.DurationString `json:"duration"`
-	// Distance in meters
+	// Duration of the interval, e.g., "1m45.32s" or "1:45.32"
+	Duration domain.Duration `json:"duration" swaggertype:"string"`
+	// Distance in the display unit of the activity
 	Distance float64 `json:"distance"`
 	// One of the predefined types
-	Type IntervalType `json:"type"`
+	Type domain.IntervalType `json:"type"`
 	// Type of swimming stroke
-	Stroke StrokeType `json:"stroke"`
+	Stroke domain.StrokeType `json:"stroke"`
 	// Optional notes like "felt strong", "used fins"
 	Notes string `json:"notes"`
+	// Average heart rate during the interval
+	HeartRateAvg int `json:"heart_rate_avg,omitempty"`
+	// Pace per 100 of the display unit of the activity, formatted mm:ss
+	PacePer100m string `json:"pace_per_100m,omitempty"`
+	// Number of the CSS pace zone the interval pace falls in (0 if unknown)
+	PaceZone int `json:"pace_zone,omitempty"`
+	// Name of the CSS pace zone, e.g., "threshold"
+	PaceZoneName string `json:"pace_zone_name,omitempty"`
+	// Total number of strokes taken during the interval
+	StrokeCount int `json:"stroke_count,omitempty"`
+	// Number of strokes of each pool length, when imported from a device
+	LengthStrokeCounts []int `json:"length_stroke_counts,omitempty"`
+	// Average strokes plus seconds per pool length
+	SWOLF float64 `json:"swolf,omitempty"`
+	// Average distance, in the display unit of the activity, covered by each stroke
+	DistancePerStroke float64 `json:"distance_per_stroke,omitempty"`
+	// CreatedAt is when the interval was created
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	// UpdatedAt is when the interval was last updated
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
 }
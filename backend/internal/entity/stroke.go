@@ -0,0 +1,29 @@
+package entity
+
+import "github.com/google/uuid"
+
+// StrokeEfficiencyTrend is the evolution of a user's stroke efficiency over a period
+type StrokeEfficiencyTrend struct {
+	// UserID is the ID of the user the trend refers to
+	UserID uuid.UUID `json:"user_id"`
+	// First day of the period in ISO 8601 format, e.g., "2023-10-01"
+	From string `json:"from"`
+	// Last day of the period in ISO 8601 format, e.g., "2023-10-28"
+	To string `json:"to"`
+	// Sessions with stroke data, from the oldest to the most recent
+	Sessions []SessionStrokeEfficiency `json:"sessions"`
+}
+
+// SessionStrokeEfficiency is the stroke efficiency of a single session
+type SessionStrokeEfficiency struct {
+	// ActivityID is the ID of the session
+	ActivityID uuid.UUID `json:"activity_id"`
+	// Date in ISO 8601 format, e.g., "2023-10-01"
+	Date string `json:"date"`
+	// Total number of strokes over the intervals with stroke data
+	StrokeCount int `json:"stroke_count"`
+	// Average strokes plus seconds per pool length (omitted outside the pool)
+	SWOLF float64 `json:"swolf,omitempty"`
+	// Average distance, in meters, covered by each stroke
+	DistancePerStroke float64 `json:"distance_per_stroke"`
+}
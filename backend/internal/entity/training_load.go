@@ -0,0 +1,29 @@
+package entity
+
+import "github.com/google/uuid"
+
+// TrainingLoad is the internal struct to represent a user's training load over a period
+type TrainingLoad struct {
+	// UserID is the ID of the user the load refers to
+	UserID uuid.UUID `json:"user_id"`
+	// First day of the period in ISO 8601 format, e.g., "2023-10-01"
+	From string `json:"from"`
+	// Last day of the period in ISO 8601 format, e.g., "2023-10-28"
+	To string `json:"to"`
+	// Days holds one entry per day of the period
+	Days []DailyTrainingLoad `json:"days"`
+}
+
+// DailyTrainingLoad is the training load of a single day
+type DailyTrainingLoad struct {
+	// Date in ISO 8601 format, e.g., "2023-10-01"
+	Date string `json:"date"`
+	// Sum of the session loads on this day
+	Load float64 `json:"load"`
+	// Average daily load over the last 7 days (fatigue)
+	AcuteLoad float64 `json:"acute_load"`
+	// Average daily load over the last 42 days (fitness)
+	ChronicLoad float64 `json:"chronic_load"`
+	// Acute:chronic workload ratio; values above ~1.5 suggest overreaching
+	AcuteChronicRatio float64 `json:"acute_chronic_ratio"`
+}
@@ -0,0 +1,182 @@
+// Package events streams the changes to the data of each user, e.g., a new
+// activity, to the clients watching it, such as the dashboard. Events are
+// delivered in-process to the subscribers of the instance publishing them, and
+// relayed to those of the other API instances through Postgres LISTEN/NOTIFY
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+const (
+	// bufferSize is how many events a subscriber can fall behind by before it is dropped
+	bufferSize = 16
+	// maxNotifyPayload keeps notifications under the 8000 byte limit of Postgres;
+	// Larger events are relayed without their data
+	maxNotifyPayload = 7900
+)
+
+// Event is a change to the data of a user; Its types are those of the webhook events
+type Event struct {
+	ID         uuid.UUID           `json:"id"`
+	UserID     uuid.UUID           `json:"user_id"`
+	Type       domain.WebhookEvent `json:"type"`
+	OccurredAt time.Time           `json:"occurred_at"`
+	// Data is the changed record encoded as JSON, null when it was too large to relay
+	Data json.RawMessage `json:"data"`
+}
+
+// NewEvent builds an event of a user occurring now, with the data encoded as JSON
+func NewEvent(userID uuid.UUID, eventType domain.WebhookEvent, data any) (Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("encoding %s event data: %w", eventType, err)
+	}
+	return Event{ID: uuid.New(), UserID: userID, Type: eventType, OccurredAt: time.Now().UTC(), Data: encoded}, nil
+}
+
+// notification is the payload of the Postgres notification relaying an event
+type notification struct {
+	// Origin identifies the broker that published the event, which already delivered it
+	Origin uuid.UUID `json:"origin"`
+	Event  Event     `json:"event"`
+}
+
+// Subscription receives the events of a user
+type Subscription struct {
+	userID uuid.UUID
+	events chan Event
+	broker *Broker
+}
+
+// Events returns the channel the events are received on; It is closed when the
+// subscription is, when the broker closes, or when the subscriber falls behind,
+// in which case it should subscribe again and reload the data it shows
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// Broker delivers the events published to the subscribers of their user
+type Broker struct {
+	notifier repository.EventRepository
+	origin   uuid.UUID
+
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBroker creates a new Broker relaying the events through notifier to the
+// other instances, see Listen; A nil notifier keeps them in-process
+func NewBroker(notifier repository.EventRepository) *Broker {
+	return &Broker{
+		notifier:    notifier,
+		origin:      uuid.New(),
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe subscribes to the events of a user; Once the broker is closed,
+// subscriptions are born closed
+func (b *Broker) Subscribe(userID uuid.UUID) *Subscription {
+	s := &Subscription{userID: userID, events: make(chan Event, bufferSize), broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(s.events)
+		return s
+	}
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[userID][s] = struct{}{}
+	return s
+}
+
+// Publish delivers an event to the subscribers of its user on this instance,
+// then relays it to the other instances
+func (b *Broker) Publish(ctx context.Context, event Event) error {
+	b.deliver(event)
+	if b.notifier == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(notification{Origin: b.origin, Event: event})
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		event.Data = nil
+		if payload, err = json.Marshal(notification{Origin: b.origin, Event: event}); err != nil {
+			return err
+		}
+	}
+	return b.notifier.NotifyEvent(ctx, string(payload))
+}
+
+// Receive delivers an event relayed by a notification; The events this broker
+// published were already delivered, so their notifications are ignored
+func (b *Broker) Receive(payload string) error {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return fmt.Errorf("decoding event notification: %w", err)
+	}
+	if n.Origin != b.origin {
+		b.deliver(n.Event)
+	}
+	return nil
+}
+
+// Close closes every subscription, e.g., so the streams end on shutdown
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, subscriptions := range b.subscribers {
+		for s := range subscriptions {
+			b.remove(s)
+		}
+	}
+}
+
+// deliver sends an event to the subscribers of its user, dropping those whose buffer is full
+func (b *Broker) deliver(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subscribers[event.UserID] {
+		select {
+		case s.events <- event:
+		default:
+			b.remove(s)
+		}
+	}
+}
+
+// remove closes a subscription, unless it already is; b.mu must be held
+func (b *Broker) remove(s *Subscription) {
+	subscriptions := b.subscribers[s.userID]
+	if _, ok := subscriptions[s]; !ok {
+		return
+	}
+	delete(subscriptions, s)
+	if len(subscriptions) == 0 {
+		delete(b.subscribers, s.userID)
+	}
+	close(s.events)
+}
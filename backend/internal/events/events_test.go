@@ -0,0 +1,173 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// memoryNotifier records the payloads notified instead of relaying them
+type memoryNotifier struct {
+	payloads []string
+	err      error
+}
+
+func (n *memoryNotifier) NotifyEvent(ctx context.Context, payload string) error {
+	n.payloads = append(n.payloads, payload)
+	return n.err
+}
+
+func newEvent(t *testing.T, userID uuid.UUID, data any) Event {
+	t.Helper()
+	event, err := NewEvent(userID, domain.WebhookActivityCreated, data)
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	return event
+}
+
+// received returns the events waiting on a subscription
+func received(s *Subscription) []Event {
+	var events []Event
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	notifier := &memoryNotifier{}
+	broker := NewBroker(notifier)
+	userID := uuid.New()
+	first, second, other := broker.Subscribe(userID), broker.Subscribe(userID), broker.Subscribe(uuid.New())
+
+	event := newEvent(t, userID, map[string]int{"distance": 1500})
+	if err := broker.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	for name, s := range map[string]*Subscription{"first": first, "second": second} {
+		if got := received(s); len(got) != 1 || got[0].ID != event.ID || string(got[0].Data) != `{"distance":1500}` {
+			t.Errorf("%s subscriber received %v, want the event", name, got)
+		}
+	}
+	if got := received(other); len(got) != 0 {
+		t.Errorf("subscriber of another user received %v", got)
+	}
+	if len(notifier.payloads) != 1 || !strings.Contains(notifier.payloads[0], event.ID.String()) {
+		t.Errorf("notified %v, want the event", notifier.payloads)
+	}
+
+	t.Run("notify fails", func(t *testing.T) {
+		notifier.err = errors.New("connection refused")
+		if err := broker.Publish(context.Background(), event); err == nil {
+			t.Error("Publish() error = nil, want the notify error")
+		}
+		if got := received(first); len(got) != 1 {
+			t.Errorf("received %d events, want it delivered in-process anyway", len(got))
+		}
+	})
+}
+
+func TestBroker_PublishLargeEvent(t *testing.T) {
+	notifier := &memoryNotifier{}
+	broker := NewBroker(notifier)
+	userID := uuid.New()
+	s := broker.Subscribe(userID)
+
+	event := newEvent(t, userID, map[string]string{"notes": strings.Repeat("x", maxNotifyPayload)})
+	if err := broker.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if got := received(s); len(got) != 1 || got[0].Data == nil {
+		t.Errorf("received %v, want the event with its data", got)
+	}
+	var n notification
+	if err := json.Unmarshal([]byte(notifier.payloads[0]), &n); err != nil {
+		t.Fatalf("notification is not JSON: %v", err)
+	}
+	if n.Event.ID != event.ID || string(n.Event.Data) != "null" {
+		t.Errorf("notified %+v, want the event without its data", n.Event)
+	}
+}
+
+func TestBroker_Receive(t *testing.T) {
+	notifier := &memoryNotifier{}
+	publisher, receiver := NewBroker(notifier), NewBroker(nil)
+	userID := uuid.New()
+	published, relayed := publisher.Subscribe(userID), receiver.Subscribe(userID)
+
+	event := newEvent(t, userID, nil)
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	// Every instance, the publisher included, receives the notification
+	for _, broker := range []*Broker{publisher, receiver} {
+		if err := broker.Receive(notifier.payloads[0]); err != nil {
+			t.Fatalf("Receive() error = %v", err)
+		}
+	}
+
+	if got := received(published); len(got) != 1 {
+		t.Errorf("publisher's subscriber received %d events, want 1", len(got))
+	}
+	if got := received(relayed); len(got) != 1 || got[0].ID != event.ID || got[0].Type != domain.WebhookActivityCreated {
+		t.Errorf("other instance's subscriber received %v, want the event", got)
+	}
+	if err := receiver.Receive("not json"); err == nil {
+		t.Error("Receive() error = nil, want an error for a malformed payload")
+	}
+}
+
+func TestBroker_SlowSubscriber(t *testing.T) {
+	broker := NewBroker(nil)
+	userID := uuid.New()
+	s := broker.Subscribe(userID)
+
+	for range bufferSize + 1 {
+		if err := broker.Publish(context.Background(), newEvent(t, userID, nil)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	count := 0
+	for range s.Events() {
+		count++
+	}
+	if count != bufferSize {
+		t.Errorf("received %d events before the subscription was closed, want %d", count, bufferSize)
+	}
+	s.Close()
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker(nil)
+	s := broker.Subscribe(uuid.New())
+	s.Close()
+	s.Close()
+	if _, ok := <-s.Events(); ok {
+		t.Error("subscription is open after Close")
+	}
+
+	open := broker.Subscribe(uuid.New())
+	broker.Close()
+	if _, ok := <-open.Events(); ok {
+		t.Error("subscription is open after the broker closed")
+	}
+	if _, ok := <-broker.Subscribe(uuid.New()).Events(); ok {
+		t.Error("subscription made after the broker closed is open")
+	}
+}
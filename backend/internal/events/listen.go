@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+// Reconnection delays of the listener: the first attempt waits minReconnect, and
+// each following one waits twice as long as the previous, up to maxReconnect
+const (
+	minReconnect = time.Second
+	maxReconnect = time.Minute
+)
+
+// pingInterval is how often the listener checks that its connection is alive
+const pingInterval = 90 * time.Second
+
+// Listen delivers to the broker the events relayed by every instance, over a
+// dedicated connection to the database at dsn, until ctx is done; The connection
+// is reestablished when it drops, but the events relayed meanwhile are missed
+func Listen(ctx context.Context, dsn string, b *Broker) error {
+	listener := pq.NewListener(dsn, minReconnect, maxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			slog.Warn("event listener disconnected", "error", err.Error())
+		case pq.ListenerEventReconnected:
+			slog.Warn("event listener reconnected, the events relayed while disconnected were missed")
+		}
+	})
+	// Closing the listener also interrupts Listen while the database is unreachable
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer stop()
+	defer listener.Close()
+
+	if err := listener.Listen(repository.EventChannel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-listener.Notify:
+			if !ok {
+				return nil
+			}
+			// A nil notification follows a reconnection
+			if n == nil {
+				continue
+			}
+			if err := b.Receive(n.Extra); err != nil {
+				slog.Warn("relayed event dropped", "error", err.Error())
+			}
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,561 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Thunk is a value a resolver returns to compute its result later, once the
+// other fields at the same depth of the response were resolved, so the keys
+// they load from a Loader are loaded in one batch
+type Thunk func() (any, error)
+
+// Then returns a thunk applying fn to the value of thunk, e.g., to read a field
+// of a loaded object; A nil value, such as a missing key, resolves to nil
+func Then[V any](thunk Thunk, fn func(V) any) Thunk {
+	return func() (any, error) {
+		value, err := thunk()
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return fn(value.(V)), nil
+	}
+}
+
+// Execute runs the query of the request, returning its data and errors; It never fails, reporting errors in the response
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{err.(*Error)}}
+	}
+	op, opErr := selectOperation(doc, req.OperationName)
+	if opErr != nil {
+		return &Response{Errors: []*Error{opErr}}
+	}
+	if errs := s.validate(doc, op); len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+	variables, errs := s.coerceVariables(op, req.Variables)
+	if len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+
+	e := &executor{ctx: ctx, schema: s, doc: doc, variables: variables, fragments: make(map[string]*orderedFields)}
+	data := newOrderedMap()
+	root := &slot{set: func(value any) {
+		if value == nil {
+			e.data = json.RawMessage("null")
+		}
+	}}
+	e.data = data
+	e.executeFields(s.query, nil, op.selections, nil, data, root)
+	for len(e.pending) > 0 {
+		pending := e.pending
+		e.pending = nil
+		for _, complete := range pending {
+			complete()
+		}
+	}
+	return &Response{Data: e.data, Errors: e.errs}
+}
+
+// coerceVariables checks the variables of the request, decoded from JSON, against the types defined by the
+// operation; The values are kept as decoded, with the defaults of those not given, to be coerced with the arguments
+func (s *Schema) coerceVariables(op *operation, values map[string]any) (map[string]any, []*Error) {
+	variables := make(map[string]any)
+	var errs []*Error
+	for _, definition := range op.variables {
+		t, _ := s.inputType(definition.typ)
+		value, given := values[definition.name]
+		if !given && definition.defaultValue != nil {
+			literal, err := literalValue(definition.defaultValue, nil)
+			if err == nil {
+				_, err = coerceInput(t, literal)
+			}
+			if err != nil {
+				errs = append(errs, newError(fmt.Sprintf("Variable \"$%s\" has an invalid default value: %s.", definition.name, err), definition.loc))
+				continue
+			}
+			variables[definition.name] = literal
+			continue
+		}
+		if !given {
+			if _, nonNull := t.(*NonNull); nonNull {
+				errs = append(errs, newError(fmt.Sprintf("Variable \"$%s\" of required type %q was not provided.", definition.name, definition.typ), definition.loc))
+			}
+			continue
+		}
+		value = jsonValue(value)
+		if _, err := coerceInput(t, value); err != nil {
+			errs = append(errs, newError(fmt.Sprintf("Variable \"$%s\" got invalid value: %s.", definition.name, err), definition.loc))
+			continue
+		}
+		variables[definition.name] = value
+	}
+	return variables, errs
+}
+
+// jsonValue converts the numbers of a value decoded from JSON, which may have been decoded as json.Number, to float64
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = jsonValue(item)
+		}
+		return items
+	}
+	return value
+}
+
+// literalValue returns the Go value of a literal, with the variables replaced by their values;
+// It reports whether a variable it refers to was not provided as absent
+func literalValue(v *value, variables map[string]any) (any, error) {
+	switch v.kind {
+	case variableValue:
+		value, given := variables[v.raw]
+		if !given {
+			return nil, errAbsent
+		}
+		return value, nil
+	case intValue:
+		n, err := strconv.ParseInt(v.raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Int cannot represent value %s", v.raw)
+		}
+		return n, nil
+	case floatValue:
+		return strconv.ParseFloat(v.raw, 64)
+	case stringValue, enumValue:
+		return v.raw, nil
+	case booleanValue:
+		return v.raw == "true", nil
+	case listValue:
+		items := make([]any, len(v.list))
+		for i, item := range v.list {
+			value, err := literalValue(item, variables)
+			if err == errAbsent {
+				value, err = nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	case objectValue:
+		return nil, fmt.Errorf("input objects are not supported")
+	}
+	return nil, nil
+}
+
+// errAbsent is returned by literalValue for a variable that was not provided, so the argument is left out
+var errAbsent = fmt.Errorf("absent")
+
+// coerceInput converts an argument or a variable to the input type t
+func coerceInput(t Type, value any) (any, error) {
+	switch t := t.(type) {
+	case *NonNull:
+		if value == nil {
+			return nil, fmt.Errorf("expected non-null value of type %q", t)
+		}
+		return coerceInput(t.Of, value)
+	case *List:
+		if value == nil {
+			return nil, nil
+		}
+		items, isList := value.([]any)
+		if !isList {
+			item, err := coerceInput(t.Of, value)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		coerced := make([]any, len(items))
+		for i, item := range items {
+			var err error
+			if coerced[i], err = coerceInput(t.Of, item); err != nil {
+				return nil, fmt.Errorf("at index %d: %w", i, err)
+			}
+		}
+		return coerced, nil
+	case *Scalar:
+		if value == nil {
+			return nil, nil
+		}
+		return t.Parse(value)
+	}
+	return nil, fmt.Errorf("unsupported input type %q", t)
+}
+
+// executor executes an operation, accumulating the errors of its fields
+type executor struct {
+	ctx       context.Context
+	schema    *Schema
+	doc       *document
+	variables map[string]any
+	data      any
+	errs      []*Error
+	// fragments are the fields of the fragments already collected
+	fragments map[string]*orderedFields
+	// canceled is set once the context is done, which the remaining fields are resolved as null for
+	canceled bool
+	// pending completes the fields whose resolvers returned a thunk, once those of the current level are resolved
+	pending []func()
+}
+
+// slot is where the value of a field or of a list item goes in the response; When
+// a non-null value is null, the null propagates to the nearest nullable parent
+type slot struct {
+	set      func(value any)
+	nullable bool
+	parent   *slot
+}
+
+// null sets the slot, or its nearest nullable parent, to null
+func (s *slot) null() {
+	for s.parent != nil && !s.nullable {
+		s = s.parent
+	}
+	s.set(nil)
+}
+
+// fieldError records an error of the field at path, such as an invalid argument or a null non-null value
+func (e *executor) fieldError(err error, f *field, path []any) {
+	e.errs = append(e.errs, &Error{Message: err.Error(), Locations: []Location{f.loc}, Path: path})
+}
+
+// resolverError records the error returned by the resolver of the field at path, which the Error unwraps to
+func (e *executor) resolverError(err error, f *field, path []any) {
+	e.errs = append(e.errs, &Error{Message: err.Error(), Locations: []Location{f.loc}, Path: path, err: err})
+}
+
+// executeFields resolves the selections on the object, whose value is source, into result
+func (e *executor) executeFields(object *Object, source any, selections []selection, path []any, result *orderedMap, objectSlot *slot) {
+	fields := newOrderedFields()
+	e.collectFields(object, selections, fields, make(map[string]bool))
+
+	for _, key := range fields.keys {
+		selected := fields.values[key]
+		f := selected[0]
+		if f.name == "__typename" {
+			result.set(key, object.Name)
+			continue
+		}
+
+		definition := e.schema.field(object, f.name)
+		fieldPath := append(append([]any(nil), path...), key)
+		result.set(key, nil)
+		_, nonNull := definition.Type.(*NonNull)
+		s := &slot{set: func(value any) { result.set(key, value) }, nullable: !nonNull, parent: objectSlot}
+
+		if e.done(f, fieldPath) {
+			s.null()
+			continue
+		}
+		args, err := e.coerceArguments(definition.Args, f.arguments)
+		if err != nil {
+			e.fieldError(err, f, fieldPath)
+			s.null()
+			continue
+		}
+		value, err := e.resolve(definition, source, args)
+		e.complete(definition.Type, selected, value, err, fieldPath, s)
+	}
+}
+
+// done reports whether the context of the execution is done, e.g., the request
+// timed out, recording its error with the first field it stops
+func (e *executor) done(f *field, path []any) bool {
+	if e.canceled {
+		return true
+	}
+	if err := e.ctx.Err(); err != nil {
+		e.canceled = true
+		e.fieldError(err, f, path)
+		return true
+	}
+	return false
+}
+
+// resolve calls the resolver of a field, turning its panics into errors
+func (e *executor) resolve(definition *Field, source any, args map[string]any) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, fmt.Errorf("panic resolving %s: %v", definition.Name, r)
+		}
+	}()
+	return definition.Resolve(e.ctx, source, args)
+}
+
+// complete converts the resolved value of a field to the type t into its slot, resolving the fields of objects
+func (e *executor) complete(t Type, fields []*field, value any, err error, path []any, s *slot) {
+	if err != nil {
+		e.resolverError(err, fields[0], path)
+		s.null()
+		return
+	}
+	if thunk, ok := value.(Thunk); ok {
+		e.pending = append(e.pending, func() {
+			value, err := e.force(thunk)
+			e.complete(t, fields, value, err, path, s)
+		})
+		return
+	}
+
+	nonNull, isNonNull := t.(*NonNull)
+	if isNonNull {
+		t = nonNull.Of
+	}
+	if isNil(value) {
+		e.completeNull(isNonNull, fields[0], path, s)
+		return
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		serialized, err := t.Serialize(value)
+		if err != nil {
+			e.fieldError(err, fields[0], path)
+			s.null()
+			return
+		}
+		if serialized == nil {
+			e.completeNull(isNonNull, fields[0], path, s)
+			return
+		}
+		s.set(serialized)
+	case *Enum:
+		serialized, err := serializeString(t.Name, value)
+		if err == nil && !slices.ContainsFunc(t.Values, func(v *EnumValue) bool { return v.Name == serialized }) {
+			err = fmt.Errorf("Enum %q cannot represent value %q", t.Name, serialized)
+		}
+		if err != nil {
+			e.fieldError(err, fields[0], path)
+			s.null()
+			return
+		}
+		s.set(serialized)
+	case *List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fieldError(fmt.Errorf("expected a list for %q, got %T", t, value), fields[0], path)
+			s.null()
+			return
+		}
+		items := make([]any, rv.Len())
+		s.set(items)
+		_, itemNonNull := t.Of.(*NonNull)
+		for i := range items {
+			itemSlot := &slot{set: func(value any) { items[i] = value }, nullable: !itemNonNull, parent: s}
+			itemPath := append(append([]any(nil), path...), i)
+			e.complete(t.Of, fields, rv.Index(i).Interface(), nil, itemPath, itemSlot)
+		}
+	case *Object:
+		var selections []selection
+		for _, f := range fields {
+			selections = append(selections, f.selections...)
+		}
+		result := newOrderedMap()
+		s.set(result)
+		e.executeFields(t, value, selections, path, result, s)
+	}
+}
+
+// completeNull sets a null value into its slot, which is an error if the field is non-null
+func (e *executor) completeNull(nonNull bool, f *field, path []any, s *slot) {
+	if nonNull {
+		e.fieldError(fmt.Errorf("Cannot return null for non-nullable field %q.", f.name), f, path)
+		s.null()
+		return
+	}
+	s.set(nil)
+}
+
+// force calls a thunk, turning its panics into errors
+func (e *executor) force(thunk Thunk) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, fmt.Errorf("panic resolving a deferred value: %v", r)
+		}
+	}()
+	return thunk()
+}
+
+// isNil reports whether a resolved value is null, including nil pointers, slices and maps
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// coerceArguments returns the arguments of a field or a directive, with the defaults of those not given
+func (e *executor) coerceArguments(definitions []*Argument, arguments []*argument) (map[string]any, error) {
+	args := make(map[string]any)
+	for _, definition := range definitions {
+		var literal *value
+		for _, arg := range arguments {
+			if arg.name == definition.Name {
+				literal = arg.value
+			}
+		}
+
+		if literal != nil {
+			value, err := literalValue(literal, e.variables)
+			if err == nil {
+				if value, err = coerceInput(definition.Type, value); err != nil {
+					return nil, fmt.Errorf("Argument %q has an invalid value: %w.", definition.Name, err)
+				}
+				args[definition.Name] = value
+				continue
+			}
+			if err != errAbsent {
+				return nil, fmt.Errorf("Argument %q has an invalid value: %w.", definition.Name, err)
+			}
+		}
+
+		if definition.Default != nil {
+			args[definition.Name] = definition.Default
+		} else if _, nonNull := definition.Type.(*NonNull); nonNull {
+			return nil, fmt.Errorf("Argument %q of required type %q was not provided.", definition.Name, definition.Type)
+		}
+	}
+	return args, nil
+}
+
+// collectFields groups the fields selected on the object by response key, in
+// the order of the query, skipping those excluded by @include or @skip
+func (e *executor) collectFields(object *Object, selections []selection, fields *orderedFields, visited map[string]bool) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			if e.included(sel.directives) {
+				fields.add(sel.responseKey(), sel)
+			}
+		case *fragmentSpread:
+			if visited[sel.name] || !e.included(sel.directives) {
+				continue
+			}
+			visited[sel.name] = true
+			frag := e.doc.fragments[sel.name]
+			if frag.typeCondition == object.Name {
+				fields.merge(e.fragmentFields(object, frag))
+			}
+		case *inlineFragment:
+			if !e.included(sel.directives) || sel.typeCondition != "" && sel.typeCondition != object.Name {
+				continue
+			}
+			e.collectFields(object, sel.selections, fields, visited)
+		}
+	}
+}
+
+// fragmentFields returns the fields of a fragment on the object, collected once
+// however many times the fragment is spread
+func (e *executor) fragmentFields(object *Object, frag *fragment) *orderedFields {
+	if fields, collected := e.fragments[frag.name]; collected {
+		return fields
+	}
+	fields := newOrderedFields()
+	e.collectFields(object, frag.selections, fields, map[string]bool{frag.name: true})
+	e.fragments[frag.name] = fields
+	return fields
+}
+
+// included reports whether the @include and @skip directives keep a selection
+func (e *executor) included(directives []*directive) bool {
+	for _, d := range directives {
+		args, err := e.coerceArguments(directiveArguments[d.name], d.arguments)
+		if err != nil {
+			continue
+		}
+		condition, _ := args["if"].(bool)
+		if d.name == "skip" && condition || d.name == "include" && !condition {
+			return false
+		}
+	}
+	return true
+}
+
+// orderedFields are the fields selected in a selection set, by response key
+type orderedFields struct {
+	keys   []string
+	values map[string][]*field
+}
+
+func newOrderedFields() *orderedFields {
+	return &orderedFields{values: make(map[string][]*field)}
+}
+
+// add adds a field under its response key, unless it is already there, e.g.,
+// from a fragment spread twice
+func (o *orderedFields) add(key string, f *field) {
+	fields, exists := o.values[key]
+	if !exists {
+		o.keys = append(o.keys, key)
+	}
+	if !slices.Contains(fields, f) {
+		o.values[key] = append(fields, f)
+	}
+}
+
+// merge adds the fields of other, in their order
+func (o *orderedFields) merge(other *orderedFields) {
+	for _, key := range other.keys {
+		for _, f := range other.values[key] {
+			o.add(key, f)
+		}
+	}
+}
+
+// orderedMap is the result of a selection set, serialized with its keys in the order of the query
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]any)}
+}
+
+func (m *orderedMap) set(key string, value any) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON serializes the map as an object with its keys in order
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
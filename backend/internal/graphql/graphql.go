@@ -0,0 +1,543 @@
+// Package graphql executes GraphQL queries against a schema of objects whose
+// fields are resolved by Go functions, e.g., on top of the app services. It
+// supports the query language in full, with fragments, variables and the
+// @include and @skip directives, but only queries, and introspection through
+// __typename, __schema and __type, so GraphiQL and code generators can load the
+// schema. Resolvers can return a Thunk, e.g., from a Loader, to resolve
+// their value once the other fields at the same depth were resolved, so the
+// lists of a response are loaded in one batch per level instead of once per item
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Type is a type of the schema: a *Scalar, an *Enum, an *Object, or a *List or a *NonNull of another type
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type, e.g., Int or a DateTime
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize converts the value returned by a resolver to its JSON representation; nil is null
+	Serialize func(value any) (any, error)
+	// Parse converts an argument, a literal or a variable decoded from JSON, to the value passed to the resolvers
+	Parse func(value any) (any, error)
+}
+
+// Enum is a leaf type whose values are one of a set of names, e.g., the kinds of
+// the introspection types; Resolvers return its values as strings, or values of
+// a string kind. Only fields can be of an enum type, not arguments
+type Enum struct {
+	Name        string
+	Description string
+	Values      []*EnumValue
+}
+
+// EnumValue is a value of an enum
+type EnumValue struct {
+	Name        string
+	Description string
+}
+
+// Object is a type with fields, e.g., the Query root or a User
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+// Field is a field of an object, resolved from the value of the object by Resolve
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	Resolve     ResolveFunc
+}
+
+// ResolveFunc returns the value of a field of source, the value of its object, which is nil for the Query root
+type ResolveFunc func(ctx context.Context, source any, args map[string]any) (any, error)
+
+// Argument is an argument of a field; An argument that was not given and has no default is not in the args
+type Argument struct {
+	Name        string
+	Description string
+	Type        Type
+	Default     any
+}
+
+// List is a list of values of a type
+type List struct {
+	Of Type
+}
+
+// NonNull is a type whose values are never null
+type NonNull struct {
+	Of Type
+}
+
+func (s *Scalar) String() string  { return s.Name }
+func (e *Enum) String() string    { return e.Name }
+func (o *Object) String() string  { return o.Name }
+func (l *List) String() string    { return "[" + l.Of.String() + "]" }
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+// field returns the field of the object with the name, or nil if it has none
+func (o *Object) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// field returns the field of the object with the name, including the __schema
+// and __type fields of the Query root, or nil if it has none
+func (s *Schema) field(object *Object, name string) *Field {
+	if object == s.query {
+		switch name {
+		case s.schemaField.Name:
+			return s.schemaField
+		case s.typeField.Name:
+			return s.typeField
+		}
+	}
+	return object.field(name)
+}
+
+// argument returns the argument of the field with the name, or nil if it has none
+func (f *Field) argument(name string) *Argument {
+	for _, arg := range f.Args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+// Property returns a field resolved by reading a property of its source, of type S
+func Property[S any](name string, typ Type, get func(S) any) *Field {
+	return &Field{
+		Name: name,
+		Type: typ,
+		Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return get(source.(S)), nil
+		},
+	}
+}
+
+// namedType returns the scalar, enum or object type wrapped by t
+func namedType(t Type) Type {
+	for {
+		switch wrapper := t.(type) {
+		case *List:
+			t = wrapper.Of
+		case *NonNull:
+			t = wrapper.Of
+		default:
+			return t
+		}
+	}
+}
+
+// Schema is the types of a GraphQL API, from its Query root
+type Schema struct {
+	// MaxDepth is how deeply the fields of a query can be nested, or 0 for no limit
+	MaxDepth int
+	// MaxFields is how many fields a query can select, counting those of a fragment
+	// each time it is spread, or 0 for no limit
+	MaxFields int
+	// MaxAliases is how many fields of a query can have an alias, counted like
+	// MaxFields, or 0 for no limit
+	MaxAliases int
+
+	query *Object
+	types map[string]Type
+	// schemaField and typeField are the __schema and __type fields of the Query root
+	schemaField, typeField *Field
+}
+
+// namePattern is the syntax of the names of types, fields and arguments
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// NewSchema returns the schema of the types reachable from query, checking their definitions
+func NewSchema(query *Object) (*Schema, error) {
+	s := &Schema{query: query, types: make(map[string]Type)}
+	for _, scalar := range []*Scalar{ID, String, Int, Float, Boolean} {
+		s.types[scalar.Name] = scalar
+	}
+	// The introspection types are named with the __ prefix the types of the schema cannot use
+	for _, t := range introspection.types {
+		s.types[t.String()] = t
+	}
+	s.schemaField, s.typeField = s.introspectionFields()
+
+	var errs []error
+	if err := s.addType(query, &errs); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return s, nil
+}
+
+// addType adds a named type and those of its fields to the schema
+func (s *Schema) addType(t Type, errs *[]error) error {
+	var name string
+	switch named := t.(type) {
+	case *Scalar:
+		name = named.Name
+		if named.Serialize == nil || named.Parse == nil {
+			return fmt.Errorf("scalar %s needs Serialize and Parse", name)
+		}
+	case *Enum:
+		name = named.Name
+		if len(named.Values) == 0 {
+			return fmt.Errorf("enum %s has no values", name)
+		}
+		for i, value := range named.Values {
+			if !namePattern.MatchString(value.Name) || slices.Contains([]string{"true", "false", "null"}, value.Name) {
+				*errs = append(*errs, fmt.Errorf("invalid enum value %s.%q", name, value.Name))
+			}
+			if slices.ContainsFunc(named.Values[:i], func(other *EnumValue) bool { return other.Name == value.Name }) {
+				*errs = append(*errs, fmt.Errorf("enum %s has two values named %s", name, value.Name))
+			}
+		}
+	case *Object:
+		name = named.Name
+	default:
+		return fmt.Errorf("unsupported type %T", t)
+	}
+	if !namePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+		return fmt.Errorf("invalid type name %q", name)
+	}
+	if existing, exists := s.types[name]; exists {
+		if existing != t {
+			return fmt.Errorf("two types are named %s", name)
+		}
+		return nil
+	}
+	s.types[name] = t
+
+	object, ok := t.(*Object)
+	if !ok {
+		return nil
+	}
+	if len(object.Fields) == 0 {
+		*errs = append(*errs, fmt.Errorf("type %s has no fields", name))
+	}
+	for i, f := range object.Fields {
+		if !namePattern.MatchString(f.Name) || strings.HasPrefix(f.Name, "__") {
+			*errs = append(*errs, fmt.Errorf("invalid field name %s.%q", name, f.Name))
+		}
+		if slices.IndexFunc(object.Fields[:i], func(other *Field) bool { return other.Name == f.Name }) >= 0 {
+			*errs = append(*errs, fmt.Errorf("type %s has two fields named %s", name, f.Name))
+		}
+		if f.Type == nil || f.Resolve == nil {
+			*errs = append(*errs, fmt.Errorf("field %s.%s needs a Type and Resolve", name, f.Name))
+			continue
+		}
+		if err := s.addType(namedType(f.Type), errs); err != nil {
+			*errs = append(*errs, fmt.Errorf("field %s.%s: %w", name, f.Name, err))
+		}
+		for _, arg := range f.Args {
+			if !namePattern.MatchString(arg.Name) {
+				*errs = append(*errs, fmt.Errorf("invalid argument name %s.%s(%q)", name, f.Name, arg.Name))
+			}
+			if _, scalar := namedType(arg.Type).(*Scalar); !scalar {
+				*errs = append(*errs, fmt.Errorf("argument %s.%s(%s) must be of a scalar type", name, f.Name, arg.Name))
+				continue
+			}
+			if err := s.addType(namedType(arg.Type), errs); err != nil {
+				*errs = append(*errs, fmt.Errorf("argument %s.%s(%s): %w", name, f.Name, arg.Name, err))
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the schema in the GraphQL schema definition language, without
+// the built-in scalars and the introspection types
+func (s *Schema) String() string {
+	names := make([]string, 0, len(s.types))
+	for name, t := range s.types {
+		if _, builtin := builtinScalars[name]; builtin || t == s.query || strings.HasPrefix(name, "__") {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	writeType(&b, s.query)
+	for _, name := range names {
+		b.WriteString("\n")
+		writeType(&b, s.types[name])
+	}
+	return b.String()
+}
+
+// writeType writes the definition of a named type
+func writeType(b *strings.Builder, t Type) {
+	switch named := t.(type) {
+	case *Scalar:
+		writeDescription(b, named.Description, "")
+		fmt.Fprintf(b, "scalar %s\n", named.Name)
+	case *Enum:
+		writeDescription(b, named.Description, "")
+		fmt.Fprintf(b, "enum %s {\n", named.Name)
+		for _, value := range named.Values {
+			writeDescription(b, value.Description, "  ")
+			fmt.Fprintf(b, "  %s\n", value.Name)
+		}
+		b.WriteString("}\n")
+	case *Object:
+		writeDescription(b, named.Description, "")
+		fmt.Fprintf(b, "type %s {\n", named.Name)
+		for _, f := range named.Fields {
+			writeDescription(b, f.Description, "  ")
+			fmt.Fprintf(b, "  %s", f.Name)
+			if len(f.Args) > 0 {
+				args := make([]string, len(f.Args))
+				for i, arg := range f.Args {
+					args[i] = arg.Name + ": " + arg.Type.String()
+					if arg.Default != nil {
+						defaultValue, _ := json.Marshal(arg.Default)
+						args[i] += " = " + string(defaultValue)
+					}
+				}
+				fmt.Fprintf(b, "(%s)", strings.Join(args, ", "))
+			}
+			fmt.Fprintf(b, ": %s\n", f.Type)
+		}
+		b.WriteString("}\n")
+	}
+}
+
+// writeDescription writes a description as a string, or a block string if it spans several lines
+func writeDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	if !strings.Contains(description, "\n") {
+		quoted, _ := json.Marshal(description)
+		fmt.Fprintf(b, "%s%s\n", indent, quoted)
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for _, line := range strings.Split(strings.ReplaceAll(description, `"""`, `\"""`), "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
+
+// The built-in scalars of GraphQL
+var (
+	ID = &Scalar{
+		Name:        "ID",
+		Description: "A unique identifier, serialized as a string.",
+		Serialize: func(value any) (any, error) {
+			rv := reflect.ValueOf(value)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return strconv.FormatInt(rv.Int(), 10), nil
+			}
+			return serializeString("ID", value)
+		},
+		Parse: func(value any) (any, error) {
+			switch v := value.(type) {
+			case string:
+				return v, nil
+			case int64:
+				return strconv.FormatInt(v, 10), nil
+			case float64:
+				if v == math.Trunc(v) {
+					return strconv.FormatFloat(v, 'f', -1, 64), nil
+				}
+			}
+			return nil, parseError("ID", value)
+		},
+	}
+	String = &Scalar{
+		Name:        "String",
+		Description: "A UTF-8 string.",
+		Serialize: func(value any) (any, error) {
+			return serializeString("String", value)
+		},
+		Parse: func(value any) (any, error) {
+			if v, ok := value.(string); ok {
+				return v, nil
+			}
+			return nil, parseError("String", value)
+		},
+	}
+	Int = &Scalar{
+		Name:        "Int",
+		Description: "A signed 32-bit integer.",
+		Serialize: func(value any) (any, error) {
+			rv := reflect.ValueOf(value)
+			var n int64
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n = rv.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				if rv.Uint() > math.MaxInt32 {
+					return nil, fmt.Errorf("Int cannot represent %v", value)
+				}
+				n = int64(rv.Uint())
+			default:
+				return nil, fmt.Errorf("Int cannot represent %T value %v", value, value)
+			}
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value %d", n)
+			}
+			return n, nil
+		},
+		Parse: func(value any) (any, error) {
+			var n int64
+			switch v := value.(type) {
+			case int64:
+				n = v
+			case float64:
+				if v != math.Trunc(v) || v < math.MinInt32 || v > math.MaxInt32 {
+					return nil, parseError("Int", value)
+				}
+				n = int64(v)
+			default:
+				return nil, parseError("Int", value)
+			}
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				return nil, parseError("Int", value)
+			}
+			return int(n), nil
+		},
+	}
+	Float = &Scalar{
+		Name:        "Float",
+		Description: "A double-precision floating-point number.",
+		Serialize: func(value any) (any, error) {
+			rv := reflect.ValueOf(value)
+			var f float64
+			switch rv.Kind() {
+			case reflect.Float32, reflect.Float64:
+				f = rv.Float()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				f = float64(rv.Int())
+			default:
+				return nil, fmt.Errorf("Float cannot represent %T value %v", value, value)
+			}
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, fmt.Errorf("Float cannot represent non numeric value %v", f)
+			}
+			return f, nil
+		},
+		Parse: func(value any) (any, error) {
+			switch v := value.(type) {
+			case int64:
+				return float64(v), nil
+			case float64:
+				return v, nil
+			}
+			return nil, parseError("Float", value)
+		},
+	}
+	Boolean = &Scalar{
+		Name:        "Boolean",
+		Description: "true or false.",
+		Serialize: func(value any) (any, error) {
+			rv := reflect.ValueOf(value)
+			if rv.Kind() != reflect.Bool {
+				return nil, fmt.Errorf("Boolean cannot represent %T value %v", value, value)
+			}
+			return rv.Bool(), nil
+		},
+		Parse: func(value any) (any, error) {
+			if v, ok := value.(bool); ok {
+				return v, nil
+			}
+			return nil, parseError("Boolean", value)
+		},
+	}
+)
+
+// builtinScalars are the scalars every schema has, left out of its definition
+var builtinScalars = map[string]*Scalar{"ID": ID, "String": String, "Int": Int, "Float": Float, "Boolean": Boolean}
+
+// serializeString serializes a value of a string kind, e.g., a domain.Stroke, or a fmt.Stringer such as a uuid.UUID
+func serializeString(scalar string, value any) (any, error) {
+	if stringer, ok := value.(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	return nil, fmt.Errorf("%s cannot represent %T value %v", scalar, value, value)
+}
+
+// parseError is the error of an argument that is not a valid value of the scalar
+func parseError(scalar string, value any) error {
+	if s, ok := value.(string); ok {
+		value = strconv.Quote(s)
+	}
+	return fmt.Errorf("%s cannot represent value %v", scalar, value)
+}
+
+// Request is a GraphQL request
+type Request struct {
+	Query         string         `json:"query" binding:"required" example:"{ user(id: \"550e8400-e29b-41d4-a716-446655440000\") { name activities(limit: 10) { date distance intervals { distance stroke } } } }"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the result of a GraphQL request; Data is absent when the request
+// failed before its execution, e.g., on a syntax error, and null when a
+// non-null field of the Query root could not be resolved
+type Response struct {
+	Data   any      `json:"data,omitempty" swaggertype:"object"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Location is a position in a query, counting lines and columns from 1
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error is an error of a request, located in the query and, if it occurred
+// when resolving a field, at the path of the field in the response
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+
+	err error
+}
+
+// newError returns an error of the query at loc
+func newError(message string, loc Location) *Error {
+	return &Error{Message: message, Locations: []Location{loc}}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error returned by the resolver of the field, if that is where the error occurred
+func (e *Error) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,563 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAuthor struct {
+	ID    string
+	Name  string
+	Books []testBook
+}
+
+type testBook struct {
+	Title    string
+	Pages    int
+	AuthorID string
+}
+
+// testLibrary is a schema of authors and their books, whose authors are loaded in batches
+type testLibrary struct {
+	schema  *Schema
+	authors map[string]testAuthor
+	batches [][]string
+}
+
+// loaderKey carries the per-request author loader in the context
+type loaderKey struct{}
+
+func newTestLibrary(t *testing.T) *testLibrary {
+	lib := &testLibrary{authors: map[string]testAuthor{
+		"1": {ID: "1", Name: "Ada", Books: []testBook{{Title: "Notes", Pages: 40, AuthorID: "1"}, {Title: "Letters", AuthorID: "1"}, {Title: "Sketches", Pages: 12, AuthorID: "1"}}},
+		"2": {ID: "2", Name: "Grace", Books: []testBook{{Title: "Compilers", Pages: 300, AuthorID: "2"}}},
+	}}
+
+	author := &Object{Name: "Author", Description: "A writer."}
+	book := &Object{Name: "Book", Fields: []*Field{
+		Property("title", &NonNull{Of: String}, func(b testBook) any { return b.Title }),
+		Property("pages", Int, func(b testBook) any {
+			if b.Pages == 0 {
+				return nil
+			}
+			return b.Pages
+		}),
+		{
+			Name: "author",
+			Type: author,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return ctx.Value(loaderKey{}).(*Loader[string, testAuthor]).Load(ctx, source.(testBook).AuthorID), nil
+			},
+		},
+	}}
+	author.Fields = []*Field{
+		Property("id", &NonNull{Of: ID}, func(a testAuthor) any { return a.ID }),
+		Property("name", &NonNull{Of: String}, func(a testAuthor) any { return a.Name }),
+		{
+			Name:        "books",
+			Description: "The books of the author,\nin order.",
+			Type:        &NonNull{Of: &List{Of: &NonNull{Of: book}}},
+			Args:        []*Argument{{Name: "limit", Type: Int, Default: 2}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				books := source.(testAuthor).Books
+				return books[:min(args["limit"].(int), len(books))], nil
+			},
+		},
+		{
+			Name: "agent",
+			Type: &NonNull{Of: String},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return nil, errors.New("agent is private")
+			},
+		},
+	}
+	query := &Object{Name: "Query", Fields: []*Field{
+		{
+			Name: "author",
+			Type: author,
+			Args: []*Argument{{Name: "id", Type: &NonNull{Of: ID}}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return ctx.Value(loaderKey{}).(*Loader[string, testAuthor]).Load(ctx, args["id"].(string)), nil
+			},
+		},
+		{
+			Name: "authors",
+			Type: &NonNull{Of: &List{Of: &NonNull{Of: author}}},
+			Args: []*Argument{{Name: "ids", Type: &List{Of: &NonNull{Of: ID}}}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				ids, _ := args["ids"].([]any)
+				var authors []testAuthor
+				for _, id := range ids {
+					authors = append(authors, lib.authors[id.(string)])
+				}
+				return authors, nil
+			},
+		},
+		{
+			Name: "echo",
+			Type: String,
+			Args: []*Argument{{Name: "text", Type: String}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				text, given := args["text"]
+				if !given {
+					return "<absent>", nil
+				}
+				return text, nil
+			},
+		},
+		{
+			Name: "panic",
+			Type: String,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				panic("boom")
+			},
+		},
+	}}
+
+	schema, err := NewSchema(query)
+	require.NoError(t, err)
+	schema.MaxDepth = 4
+	schema.MaxFields = 50
+	schema.MaxAliases = 5
+	lib.schema = schema
+	return lib
+}
+
+// execute runs a query with a fresh loader, returning the response as JSON
+func (lib *testLibrary) execute(query string, variables map[string]any) string {
+	loader := NewLoader(func(ctx context.Context, ids []string) (map[string]testAuthor, error) {
+		lib.batches = append(lib.batches, slices.Clone(ids))
+		authors := make(map[string]testAuthor)
+		for _, id := range ids {
+			if author, exists := lib.authors[id]; exists {
+				authors[id] = author
+			}
+		}
+		return authors, nil
+	})
+	ctx := context.WithValue(context.Background(), loaderKey{}, loader)
+	response, _ := json.Marshal(lib.schema.Execute(ctx, Request{Query: query, Variables: variables}))
+	return string(response)
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expected  string
+	}{
+		{
+			name:     "shorthand query in selection order",
+			query:    `{ author(id: "2") { name id } }`,
+			expected: `{"data":{"author":{"name":"Grace","id":"2"}}}`,
+		},
+		{
+			name:     "aliases, arguments and defaults",
+			query:    `query { ada: author(id: 1) { all: books(limit: 5) { title } books { title pages } } }`,
+			expected: `{"data":{"ada":{"all":[{"title":"Notes"},{"title":"Letters"},{"title":"Sketches"}],"books":[{"title":"Notes","pages":40},{"title":"Letters","pages":null}]}}}`,
+		},
+		{
+			name: "fragments and __typename",
+			query: `query Library {
+				authors(ids: ["1", "2"]) { ...names ... on Author { books(limit: 1) { __typename title } } }
+			}
+			fragment names on Author { __typename name }`,
+			expected: `{"data":{"authors":[{"__typename":"Author","name":"Ada","books":[{"__typename":"Book","title":"Notes"}]},{"__typename":"Author","name":"Grace","books":[{"__typename":"Book","title":"Compilers"}]}]}}`,
+		},
+		{
+			name:      "variables and directives",
+			query:     `query ($id: ID!, $limit: Int = 1, $brief: Boolean!) { author(id: $id) { name @skip(if: $brief) books(limit: $limit) @include(if: $brief) { title } } }`,
+			variables: map[string]any{"id": "1", "brief": true},
+			expected:  `{"data":{"author":{"books":[{"title":"Notes"}]}}}`,
+		},
+		{
+			name:      "absent variable leaves the argument out",
+			query:     `query ($text: String) { absent: echo(text: $text) null: echo(text: null) }`,
+			variables: map[string]any{},
+			expected:  `{"data":{"absent":"<absent>","null":null}}`,
+		},
+		{
+			name:      "single value coerced to a list",
+			query:     `query ($ids: [ID!]) { authors(ids: $ids) { name } }`,
+			variables: map[string]any{"ids": "2"},
+			expected:  `{"data":{"authors":[{"name":"Grace"}]}}`,
+		},
+		{
+			name:     "missing key is null",
+			query:    `{ author(id: "3") { name } }`,
+			expected: `{"data":{"author":null}}`,
+		},
+		{
+			name:     "error nulls the nearest nullable field",
+			query:    `{ author(id: "1") { name agent } echo(text: "hi") }`,
+			expected: `{"data":{"author":null,"echo":"hi"},"errors":[{"message":"agent is private","locations":[{"line":1,"column":26}],"path":["author","agent"]}]}`,
+		},
+		{
+			name:     "error in a non-null list nulls the data",
+			query:    `{ authors(ids: ["1"]) { agent } }`,
+			expected: `{"data":null,"errors":[{"message":"agent is private","locations":[{"line":1,"column":25}],"path":["authors",0,"agent"]}]}`,
+		},
+		{
+			name:     "panics are errors",
+			query:    `{ panic }`,
+			expected: `{"data":{"panic":null},"errors":[{"message":"panic resolving panic: boom","locations":[{"line":1,"column":3}],"path":["panic"]}]}`,
+		},
+		{
+			name:     "invalid argument",
+			query:    `{ author(id: 1.5) { name } }`,
+			expected: `{"data":{"author":null},"errors":[{"message":"Argument \"id\" has an invalid value: ID cannot represent value 1.5.","locations":[{"line":1,"column":3}],"path":["author"]}]}`,
+		},
+		{
+			name:      "invalid variable",
+			query:     `query ($limit: Int) { author(id: "1") { books(limit: $limit) { title } } }`,
+			variables: map[string]any{"limit": "ten"},
+			expected:  `{"errors":[{"message":"Variable \"$limit\" got invalid value: Int cannot represent value \"ten\".","locations":[{"line":1,"column":8}]}]}`,
+		},
+		{
+			name:     "syntax error",
+			query:    "{\n  author(id: \"1\") { name }",
+			expected: `{"errors":[{"message":"Syntax Error: unexpected <EOF>.","locations":[{"line":2,"column":27}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib := newTestLibrary(t)
+			assert.JSONEq(t, tt.expected, lib.execute(tt.query, tt.variables))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"unknown field", `{ author(id: "1") { email } }`, []string{`Cannot query field "email" on type "Author".`}},
+		{"unknown argument", `{ author(id: "1", name: "Ada") { name } }`, []string{`Unknown argument "name" on field "Query.author".`}},
+		{"missing argument", `{ author { name } }`, []string{`Argument "id" of type "ID!" is required on field "Query.author", but it was not provided.`}},
+		{"selection on a leaf", `{ author(id: "1") { name { first } } }`, []string{`Field "name" must not have a selection since type "String!" has no subfields.`}},
+		{"missing selection", `{ author(id: "1") }`, []string{`Field "author" of type "Author" must have a selection of subfields.`}},
+		{"undefined variable", `{ author(id: $id) { name } }`, []string{`Variable "$id" is not defined.`}},
+		{"mutation", `mutation { author(id: "1") { name } }`, []string{`Only queries are supported, not mutations.`}},
+		{"unknown directive", `{ echo @cached }`, []string{`Unknown directive "@cached".`}},
+		{"unknown fragment", `{ author(id: "1") { ...names } }`, []string{`Unknown fragment "names".`}},
+		{"unused fragment", `{ echo } fragment names on Author { name }`, []string{`Fragment "names" is never used.`}},
+		{"fragment on another type", `{ author(id: "1") { ... on Book { title } } }`, []string{`Fragment cannot be spread here as objects of type "Author" can never be of type "Book".`}},
+		{"fragment cycle", `{ author(id: "1") { ...a } } fragment a on Author { ...b } fragment b on Author { ...a }`, []string{`Cannot spread fragment "a" within itself.`}},
+		{"too deep", `{ author(id: "1") { books { author { books { title } } } } }`, []string{`Field "title" exceeds the maximum query depth of 4.`}},
+		{"too deep in a fragment", `{ author(id: "1") { ...a } } fragment a on Author { books { author { books { title } } } }`, []string{`Field "title" exceeds the maximum query depth of 4.`}},
+		{"too many fields", `{ author(id: "1") { ...f4 } } fragment f4 on Author { ...f3 ...f3 } fragment f3 on Author { ...f2 ...f2 }
+			fragment f2 on Author { ...f1 ...f1 } fragment f1 on Author { ...f0 ...f0 } fragment f0 on Author { id name books { title pages } }`,
+			[]string{`Query selects more than the maximum of 50 fields.`}},
+		{"too many aliases", `{ a: echo b: echo c: echo d: echo e: echo f: echo }`, []string{`Query has more than the maximum of 5 aliases.`}},
+		{"operation name required", `query a { echo } query b { echo }`, []string{`Must provide operation name if query contains multiple operations.`}},
+		{"introspection below the root", `{ author(id: "1") { __schema { types { name } } } }`, []string{`Cannot query field "__schema" on type "Author".`}},
+		{"introspection too deep", `{ __type(name: "Author") { fields { type { fields { type { name } } } } } }`, []string{`Field "name" exceeds the maximum query depth of 4.`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response struct {
+				Data   any      `json:"data"`
+				Errors []*Error `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(newTestLibrary(t).execute(tt.query, nil)), &response))
+			assert.Nil(t, response.Data)
+			var messages []string
+			for _, err := range response.Errors {
+				messages = append(messages, err.Message)
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
+
+func TestExecute_FragmentsSpreadExponentially(t *testing.T) {
+	// Each fragment spreads the next one twice, so the query selects 2^40 fields if
+	// the fragments are expanded, and only 4 once their fields are merged
+	query := `{ author(id: "1") { ...f40 } } fragment f0 on Author { id name books { title } }`
+	for i := 1; i <= 40; i++ {
+		query += fmt.Sprintf(" fragment f%d on Author { ...f%d ...f%d }", i, i-1, i-1)
+	}
+	lib := newTestLibrary(t)
+
+	assert.JSONEq(t, `{"errors":[{"message":"Query selects more than the maximum of 50 fields.","locations":[{"line":1,"column":1}]}]}`,
+		lib.execute(query, nil))
+
+	lib.schema.MaxFields = 0
+	assert.JSONEq(t, `{"data":{"author":{"id":"1","name":"Ada","books":[{"title":"Notes"},{"title":"Letters"}]}}}`,
+		lib.execute(query, nil))
+}
+
+func TestExecute_Canceled(t *testing.T) {
+	lib := newTestLibrary(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response := lib.schema.Execute(ctx, Request{Query: `{ author(id: "1") { name } echo }`})
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":{"author":null,"echo":null},"errors":[{"message":"context canceled","locations":[{"line":1,"column":3}],"path":["author"]}]}`,
+		string(encoded))
+}
+
+func TestParse(t *testing.T) {
+	doc, err := parse(`
+		# the authors
+		query Authors($ids: [ID!]! = ["1"], $limit: Int) @include(if: true) {
+			authors(ids: $ids) {
+				name,
+				...on Author { books(limit: $limit) { title } }
+			}
+		}
+		fragment f on Author { notes: name(text: """
+			  first
+			second
+		""", escaped: "é\n", list: [1, -2.5e3, null, ENUM, {a: false}]) }
+	`)
+	require.NoError(t, err)
+	require.Len(t, doc.operations, 1)
+
+	op := doc.operations[0]
+	assert.Equal(t, "query", op.kind)
+	assert.Equal(t, "Authors", op.name)
+	assert.Equal(t, "[ID!]!", op.variables[0].typ.String())
+	assert.Equal(t, listValue, op.variables[0].defaultValue.kind)
+	assert.Equal(t, "Int", op.variables[1].typ.String())
+	assert.Equal(t, Location{Line: 3, Column: 3}, op.loc)
+	authors := op.selections[0].(*field)
+	assert.Equal(t, variableValue, authors.arguments[0].value.kind)
+	assert.IsType(t, &inlineFragment{}, authors.selections[1])
+
+	notes := doc.fragments["f"].selections[0].(*field)
+	assert.Equal(t, "notes", notes.responseKey())
+	assert.Equal(t, "name", notes.name)
+	assert.Equal(t, "  first\nsecond", notes.arguments[0].value.raw)
+	assert.Equal(t, "é\n", notes.arguments[1].value.raw)
+	list := notes.arguments[2].value.list
+	assert.Equal(t, []valueKind{intValue, floatValue, nullValue, enumValue, objectValue}, []valueKind{list[0].kind, list[1].kind, list[2].kind, list[3].kind, list[4].kind})
+	assert.Equal(t, "-2.5e3", list[1].raw)
+
+	for query, message := range map[string]string{
+		`{ a(b: $) }`:            `Syntax Error: unexpected ")".`,
+		`{ a(b: "c) }`:           `Syntax Error: unterminated string`,
+		`{ a(b: 01) }`:           `Syntax Error: invalid number, unexpected digit after 0`,
+		`{ a.b }`:                `Syntax Error: unexpected ".", did you mean "..."?`,
+		`{ a(b: "\q") }`:         `Syntax Error: invalid escape sequence "\\q"`,
+		`fragment on on A { a }`: `Syntax Error: unexpected "on".`,
+		``:                       `Syntax Error: the document has no operation.`,
+	} {
+		_, err := parse(query)
+		assert.EqualError(t, err, message, query)
+	}
+}
+
+func TestLoaderBatchesEachLevel(t *testing.T) {
+	lib := newTestLibrary(t)
+	response := lib.execute(`{
+		first: author(id: "1") { books(limit: 3) { author { name } } }
+		second: author(id: "2") { books { author { id } } }
+		missing: author(id: "3") { name }
+	}`, nil)
+
+	assert.JSONEq(t, `{"data":{
+		"first":{"books":[{"author":{"name":"Ada"}},{"author":{"name":"Ada"}},{"author":{"name":"Ada"}}]},
+		"second":{"books":[{"author":{"id":"2"}}]},
+		"missing":null
+	}}`, response)
+	assert.Equal(t, [][]string{{"1", "2", "3"}}, lib.batches, "the authors of the books were cached from the first batch")
+
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		return nil, errors.New("database unavailable")
+	})
+	value, err := Then(loader.Load(context.Background(), 1), func(s string) any { return len(s) })()
+	assert.Nil(t, value)
+	assert.EqualError(t, err, "database unavailable")
+}
+
+func TestSchema(t *testing.T) {
+	lib := newTestLibrary(t)
+	assert.Equal(t, `type Query {
+  author(id: ID!): Author
+  authors(ids: [ID!]): [Author!]!
+  echo(text: String): String
+  panic: String
+}
+
+"A writer."
+type Author {
+  id: ID!
+  name: String!
+  """
+  The books of the author,
+  in order.
+  """
+  books(limit: Int = 2): [Book!]!
+  agent: String!
+}
+
+type Book {
+  title: String!
+  pages: Int
+  author: Author
+}
+`, lib.schema.String())
+
+	_, err := NewSchema(&Object{Name: "Query", Fields: []*Field{
+		{Name: "a", Type: String},
+		{Name: "b", Type: &Object{Name: "String", Fields: []*Field{Property("c", Int, func(any) any { return 1 })}}, Resolve: func(context.Context, any, map[string]any) (any, error) { return nil, nil }},
+	}})
+	assert.EqualError(t, err, "field Query.a needs a Type and Resolve\nfield Query.b: two types are named String")
+}
+
+func TestEnum(t *testing.T) {
+	stroke := &Enum{Name: "Stroke", Description: "A swimming stroke.", Values: []*EnumValue{{Name: "FREESTYLE"}, {Name: "BUTTERFLY", Description: "The hardest one."}}}
+	constant := func(value string) ResolveFunc {
+		return func(context.Context, any, map[string]any) (any, error) { return value, nil }
+	}
+	schema, err := NewSchema(&Object{Name: "Query", Fields: []*Field{
+		{Name: "favorite", Type: stroke, Resolve: constant("BUTTERFLY")},
+		{Name: "worst", Type: stroke, Resolve: constant("DOG_PADDLE")},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, `type Query {
+  favorite: Stroke
+  worst: Stroke
+}
+
+"A swimming stroke."
+enum Stroke {
+  FREESTYLE
+  "The hardest one."
+  BUTTERFLY
+}
+`, schema.String())
+
+	response, _ := json.Marshal(schema.Execute(context.Background(), Request{Query: `{ favorite worst }`}))
+	assert.JSONEq(t, `{"data":{"favorite":"BUTTERFLY","worst":null},"errors":[{"message":"Enum \"Stroke\" cannot represent value \"DOG_PADDLE\"","locations":[{"line":1,"column":12}],"path":["worst"]}]}`, string(response))
+
+	_, err = NewSchema(&Object{Name: "Query", Fields: []*Field{
+		{Name: "stroke", Type: &Enum{Name: "Stroke", Values: []*EnumValue{{Name: "true"}}}, Resolve: constant("true")},
+	}})
+	assert.EqualError(t, err, `invalid enum value Stroke."true"`)
+}
+
+// introspectionQuery is the query GraphiQL and code generators load a schema with
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    description
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description isRepeatable locations args(includeDeprecated: true) { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) { name description args(includeDeprecated: true) { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason }
+  inputFields(includeDeprecated: true) { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue {
+  name description type { ...TypeRef } defaultValue isDeprecated deprecationReason
+}
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name
+    ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } } } }
+}`
+
+func TestIntrospection(t *testing.T) {
+	lib := newTestLibrary(t)
+
+	t.Run("type", func(t *testing.T) {
+		assert.JSONEq(t, `{"data":{"__type":{"kind":"OBJECT","name":"Book","description":null,"interfaces":[],"fields":[
+			{"name":"title","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String"}}},
+			{"name":"pages","args":[],"type":{"kind":"SCALAR","name":"Int","ofType":null}},
+			{"name":"author","args":[],"type":{"kind":"OBJECT","name":"Author","ofType":null}}
+		]},"missing":null}}`, lib.execute(`{
+			__type(name: "Book") { kind name description interfaces { name } fields { name args { name } type { kind name ofType { kind name } } } }
+			missing: __type(name: "Magazine") { name }
+		}`, nil))
+	})
+
+	t.Run("arguments and enums", func(t *testing.T) {
+		assert.JSONEq(t, `{"data":{
+			"author":{"fields":[
+				{"name":"id","description":null,"args":[]},
+				{"name":"name","description":null,"args":[]},
+				{"name":"books","description":"The books of the author,\nin order.","args":[{"name":"limit","defaultValue":"2"}]},
+				{"name":"agent","description":null,"args":[]}
+			]},
+			"kind":{"kind":"ENUM","enumValues":[{"name":"SCALAR"},{"name":"OBJECT"},{"name":"INTERFACE"},{"name":"UNION"},{"name":"ENUM"},{"name":"INPUT_OBJECT"},{"name":"LIST"},{"name":"NON_NULL"}]}
+		}}`, lib.execute(`{
+			author: __type(name: "Author") { fields { name description args { name defaultValue } } }
+			kind: __type(name: "__TypeKind") { kind enumValues { name } }
+		}`, nil))
+	})
+
+	t.Run("introspection query", func(t *testing.T) {
+		// The limits of the API, which the introspection query fits as ofType adds no depth
+		lib.schema.MaxDepth, lib.schema.MaxFields = 6, 300
+		var response struct {
+			Data struct {
+				Schema struct {
+					QueryType struct{ Name string } `json:"queryType"`
+					Types     []struct {
+						Kind   string
+						Name   string
+						Fields []struct{ Name string }
+					}
+					Directives []struct {
+						Name      string
+						Locations []string
+					}
+				} `json:"__schema"`
+			}
+			Errors []*Error
+		}
+		require.NoError(t, json.Unmarshal([]byte(lib.execute(introspectionQuery, nil)), &response))
+		require.Empty(t, response.Errors)
+
+		schema := response.Data.Schema
+		assert.Equal(t, "Query", schema.QueryType.Name)
+		var names []string
+		for _, typ := range schema.Types {
+			names = append(names, typ.Name)
+		}
+		assert.Equal(t, []string{
+			"Author", "Book", "Boolean", "Float", "ID", "Int", "Query", "String",
+			"__Directive", "__DirectiveLocation", "__EnumValue", "__Field", "__InputValue", "__Schema", "__Type", "__TypeKind",
+		}, names)
+		assert.Len(t, schema.Types[6].Fields, 4, "the Query root does not list __schema and __type")
+		require.Len(t, schema.Directives, 2)
+		assert.Equal(t, "include", schema.Directives[0].Name)
+		assert.Equal(t, []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"}, schema.Directives[0].Locations)
+	})
+}
+
+func TestErrorCause(t *testing.T) {
+	lib := newTestLibrary(t)
+	ctx := context.WithValue(context.Background(), loaderKey{}, NewLoader(func(ctx context.Context, ids []string) (map[string]testAuthor, error) {
+		return map[string]testAuthor{"1": lib.authors["1"]}, nil
+	}))
+	response := lib.schema.Execute(ctx, Request{Query: `{ author(id: "1") { agent } echo(text: 1) }`})
+
+	require.Len(t, response.Errors, 2)
+	assert.Nil(t, errors.Unwrap(response.Errors[0]), "invalid arguments are errors of the query")
+	assert.EqualError(t, errors.Unwrap(response.Errors[1]), "agent is private", "errors returned by resolvers are kept")
+}
@@ -0,0 +1,262 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+)
+
+// introspectionTypes are the types describing a schema to its introspection
+// fields, as defined by the GraphQL specification
+type introspectionTypes struct {
+	schema, typ *Object
+	types       []Type
+}
+
+// introspection holds the introspection types, shared by the schemas
+var introspection = newIntrospectionTypes()
+
+// directiveDefinition describes a directive of the queries to introspection
+type directiveDefinition struct {
+	name        string
+	description string
+	locations   []string
+}
+
+// directives are the directives of the queries, whose arguments are in directiveArguments
+var directives = []directiveDefinition{
+	{
+		name:        "include",
+		description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+	},
+	{
+		name:        "skip",
+		description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+	},
+}
+
+// introspectionFields returns the __schema and __type fields of the Query root of the schema
+func (s *Schema) introspectionFields() (schemaField, typeField *Field) {
+	schemaField = &Field{
+		Name:        "__schema",
+		Description: "Access the current type schema of this server.",
+		Type:        &NonNull{Of: introspection.schema},
+		Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return s, nil
+		},
+	}
+	typeField = &Field{
+		Name:        "__type",
+		Description: "Request the type information of a single type.",
+		Type:        introspection.typ,
+		Args:        []*Argument{{Name: "name", Type: &NonNull{Of: String}}},
+		Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			if t, exists := s.types[args["name"].(string)]; exists {
+				return t, nil
+			}
+			return nil, nil
+		},
+	}
+	return schemaField, typeField
+}
+
+// newIntrospectionTypes builds the introspection types, which refer to one another
+func newIntrospectionTypes() introspectionTypes {
+	typeKind := &Enum{
+		Name:        "__TypeKind",
+		Description: "An enum describing what kind of type a given `__Type` is.",
+		Values:      enumValues("SCALAR", "OBJECT", "INTERFACE", "UNION", "ENUM", "INPUT_OBJECT", "LIST", "NON_NULL"),
+	}
+	directiveLocation := &Enum{
+		Name:        "__DirectiveLocation",
+		Description: "A Directive can be adjacent to many parts of the GraphQL language, a __DirectiveLocation describes one such possible adjacencies.",
+		Values: enumValues(
+			"QUERY", "MUTATION", "SUBSCRIPTION", "FIELD", "FRAGMENT_DEFINITION", "FRAGMENT_SPREAD", "INLINE_FRAGMENT",
+			"VARIABLE_DEFINITION", "SCHEMA", "SCALAR", "OBJECT", "FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INTERFACE",
+			"UNION", "ENUM", "ENUM_VALUE", "INPUT_OBJECT", "INPUT_FIELD_DEFINITION",
+		),
+	}
+	schemaType := &Object{
+		Name:        "__Schema",
+		Description: "A GraphQL Schema defines the capabilities of a GraphQL server. It exposes all available types and directives on the server, as well as the entry points for query, mutation, and subscription operations.",
+	}
+	typeType := &Object{
+		Name:        "__Type",
+		Description: "The fundamental unit of any GraphQL Schema is the type. There are many kinds of types in GraphQL as represented by the `__TypeKind` enum.",
+	}
+	fieldType := &Object{
+		Name:        "__Field",
+		Description: "Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type.",
+	}
+	inputValueType := &Object{
+		Name:        "__InputValue",
+		Description: "Arguments provided to Fields or Directives and the input fields of an InputObject are represented as Input Values which describe their type and optionally a default value.",
+	}
+	enumValueType := &Object{
+		Name:        "__EnumValue",
+		Description: "One possible value for a given Enum. Enum values are unique values, not a placeholder for a string or numeric value.",
+	}
+	directiveType := &Object{
+		Name:        "__Directive",
+		Description: "A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document.",
+	}
+
+	// Nothing in a schema is deprecated, so includeDeprecated changes no list
+	includeDeprecated := []*Argument{{Name: "includeDeprecated", Type: Boolean, Default: false}}
+	listOf := func(t Type) Type { return &List{Of: &NonNull{Of: t}} }
+	nonNullListOf := func(t Type) Type { return &NonNull{Of: listOf(t)} }
+	notDeprecated := []*Field{
+		Property("isDeprecated", &NonNull{Of: Boolean}, func(any) any { return false }),
+		Property("deprecationReason", String, func(any) any { return nil }),
+	}
+
+	schemaType.Fields = []*Field{
+		Property("description", String, func(*Schema) any { return nil }),
+		Property("types", nonNullListOf(typeType), func(s *Schema) any {
+			names := make([]string, 0, len(s.types))
+			for name := range s.types {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			types := make([]Type, len(names))
+			for i, name := range names {
+				types[i] = s.types[name]
+			}
+			return types
+		}),
+		Property("queryType", &NonNull{Of: typeType}, func(s *Schema) any { return s.query }),
+		Property("mutationType", typeType, func(*Schema) any { return nil }),
+		Property("subscriptionType", typeType, func(*Schema) any { return nil }),
+		Property("directives", nonNullListOf(directiveType), func(*Schema) any { return directives }),
+	}
+
+	typeType.Fields = []*Field{
+		Property("kind", &NonNull{Of: typeKind}, func(t Type) any {
+			switch t.(type) {
+			case *Scalar:
+				return "SCALAR"
+			case *Enum:
+				return "ENUM"
+			case *Object:
+				return "OBJECT"
+			case *List:
+				return "LIST"
+			default:
+				return "NON_NULL"
+			}
+		}),
+		Property("name", String, func(t Type) any {
+			switch t.(type) {
+			case *List, *NonNull:
+				return nil
+			}
+			return t.String()
+		}),
+		Property("description", String, func(t Type) any {
+			switch t := t.(type) {
+			case *Scalar:
+				return optional(t.Description)
+			case *Enum:
+				return optional(t.Description)
+			case *Object:
+				return optional(t.Description)
+			}
+			return nil
+		}),
+		Property("specifiedByURL", String, func(Type) any { return nil }),
+		withArgs(Property("fields", listOf(fieldType), func(t Type) any {
+			if object, ok := t.(*Object); ok {
+				return object.Fields
+			}
+			return nil
+		}), includeDeprecated),
+		Property("interfaces", listOf(typeType), func(t Type) any {
+			if _, ok := t.(*Object); ok {
+				return []Type{}
+			}
+			return nil
+		}),
+		Property("possibleTypes", listOf(typeType), func(Type) any { return nil }),
+		withArgs(Property("enumValues", listOf(enumValueType), func(t Type) any {
+			if enum, ok := t.(*Enum); ok {
+				return enum.Values
+			}
+			return nil
+		}), includeDeprecated),
+		withArgs(Property("inputFields", listOf(inputValueType), func(Type) any { return nil }), includeDeprecated),
+		Property("ofType", typeType, func(t Type) any {
+			switch t := t.(type) {
+			case *List:
+				return t.Of
+			case *NonNull:
+				return t.Of
+			}
+			return nil
+		}),
+		Property("isOneOf", Boolean, func(Type) any { return nil }),
+	}
+
+	fieldType.Fields = append([]*Field{
+		Property("name", &NonNull{Of: String}, func(f *Field) any { return f.Name }),
+		Property("description", String, func(f *Field) any { return optional(f.Description) }),
+		withArgs(Property("args", nonNullListOf(inputValueType), func(f *Field) any { return append([]*Argument{}, f.Args...) }), includeDeprecated),
+		Property("type", &NonNull{Of: typeType}, func(f *Field) any { return f.Type }),
+	}, notDeprecated...)
+
+	inputValueType.Fields = append([]*Field{
+		Property("name", &NonNull{Of: String}, func(arg *Argument) any { return arg.Name }),
+		Property("description", String, func(arg *Argument) any { return optional(arg.Description) }),
+		Property("type", &NonNull{Of: typeType}, func(arg *Argument) any { return arg.Type }),
+		Property("defaultValue", String, func(arg *Argument) any {
+			if arg.Default == nil {
+				return nil
+			}
+			defaultValue, _ := json.Marshal(arg.Default)
+			return string(defaultValue)
+		}),
+	}, notDeprecated...)
+
+	enumValueType.Fields = append([]*Field{
+		Property("name", &NonNull{Of: String}, func(value *EnumValue) any { return value.Name }),
+		Property("description", String, func(value *EnumValue) any { return optional(value.Description) }),
+	}, notDeprecated...)
+
+	directiveType.Fields = []*Field{
+		Property("name", &NonNull{Of: String}, func(d directiveDefinition) any { return d.name }),
+		Property("description", String, func(d directiveDefinition) any { return optional(d.description) }),
+		Property("isRepeatable", &NonNull{Of: Boolean}, func(directiveDefinition) any { return false }),
+		Property("locations", &NonNull{Of: &List{Of: &NonNull{Of: directiveLocation}}}, func(d directiveDefinition) any { return d.locations }),
+		withArgs(Property("args", nonNullListOf(inputValueType), func(d directiveDefinition) any { return directiveArguments[d.name] }), includeDeprecated),
+	}
+
+	return introspectionTypes{
+		schema: schemaType,
+		typ:    typeType,
+		types:  []Type{typeKind, directiveLocation, schemaType, typeType, fieldType, inputValueType, enumValueType, directiveType},
+	}
+}
+
+// withArgs returns the field with the arguments
+func withArgs(f *Field, args []*Argument) *Field {
+	f.Args = args
+	return f
+}
+
+// enumValues returns the values of an enum with the names
+func enumValues(names ...string) []*EnumValue {
+	values := make([]*EnumValue, len(names))
+	for i, name := range names {
+		values[i] = &EnumValue{Name: name}
+	}
+	return values
+}
+
+// optional returns a description, or nil for null if it is empty
+func optional(description string) any {
+	if description == "" {
+		return nil
+	}
+	return description
+}
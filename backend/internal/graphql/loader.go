@@ -0,0 +1,75 @@
+package graphql
+
+import "context"
+
+// Loader loads values by key in batches: the keys loaded by the fields at the
+// same depth of a response are collected, and loaded by a single call of its
+// batch function once the first of their thunks is forced. Values are cached,
+// so a Loader is meant to serve a single request, and is not safe for concurrent use
+type Loader[K comparable, V any] struct {
+	batch   func(ctx context.Context, keys []K) (map[K]V, error)
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	missing map[K]bool
+	errs    map[K]error
+}
+
+// NewLoader returns a loader calling batch with the keys to load; A key batch returns no value for loads as nil
+func NewLoader[K comparable, V any](batch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		batch:   batch,
+		queued:  make(map[K]bool),
+		values:  make(map[K]V),
+		missing: make(map[K]bool),
+		errs:    make(map[K]error),
+	}
+}
+
+// Load returns a thunk resolving to the value of key, or to nil if there is none
+func (l *Loader[K, V]) Load(ctx context.Context, key K) Thunk {
+	if !l.loaded(key) && !l.queued[key] {
+		l.pending = append(l.pending, key)
+		l.queued[key] = true
+	}
+	return func() (any, error) {
+		if !l.loaded(key) {
+			l.dispatch(ctx)
+		}
+		if err, failed := l.errs[key]; failed {
+			return nil, err
+		}
+		if value, found := l.values[key]; found {
+			return value, nil
+		}
+		return nil, nil
+	}
+}
+
+// loaded reports whether the key was loaded, successfully or not
+func (l *Loader[K, V]) loaded(key K) bool {
+	_, found := l.values[key]
+	_, failed := l.errs[key]
+	return found || failed || l.missing[key]
+}
+
+// dispatch loads the pending keys in one batch
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	clear(l.queued)
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.batch(ctx, keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+		} else if value, found := values[key]; found {
+			l.values[key] = value
+		} else {
+			l.missing[key] = true
+		}
+	}
+}
@@ -0,0 +1,562 @@
+package graphql
+
+// document is a parsed query, with its operations and the fragments they spread
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query, mutation or subscription of a document
+type operation struct {
+	kind       string
+	name       string
+	variables  []*variableDefinition
+	directives []*directive
+	selections []selection
+	loc        Location
+}
+
+// variableDefinition declares a variable of an operation, e.g., ($limit: Int = 10)
+type variableDefinition struct {
+	name         string
+	typ          *typeRef
+	defaultValue *value
+	loc          Location
+}
+
+// typeRef is a type named in a variable definition, e.g., [ID!]!
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+// String formats the type as in the query
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// selection is a field, a fragment spread or an inline fragment of a selection set
+type selection interface {
+	location() Location
+}
+
+// field selects a field of an object, under its alias if it has one
+type field struct {
+	alias      string
+	name       string
+	arguments  []*argument
+	directives []*directive
+	selections []selection
+	loc        Location
+}
+
+// responseKey is the key of the field in the result
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// fragmentSpread selects the fields of a named fragment, e.g., ...activityFields
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+	loc        Location
+}
+
+// inlineFragment selects fields in place, e.g., ... on User { name } or ... @skip(if: $short) { notes }
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+	loc           Location
+}
+
+// fragment is a named selection set on a type, spread by the operations
+type fragment struct {
+	name          string
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+	loc           Location
+}
+
+func (f *field) location() Location          { return f.loc }
+func (f *fragmentSpread) location() Location { return f.loc }
+func (f *inlineFragment) location() Location { return f.loc }
+
+// argument is an argument of a field or a directive
+type argument struct {
+	name  string
+	value *value
+	loc   Location
+}
+
+// directive annotates a selection, e.g., @include(if: $withIntervals)
+type directive struct {
+	name      string
+	arguments []*argument
+	loc       Location
+}
+
+// valueKind is the kind of a value literal
+type valueKind int
+
+const (
+	variableValue valueKind = iota
+	intValue
+	floatValue
+	stringValue
+	booleanValue
+	nullValue
+	enumValue
+	listValue
+	objectValue
+)
+
+// value is a value literal of a query; raw is the name of a variable, or the text of a scalar
+type value struct {
+	kind   valueKind
+	raw    string
+	list   []*value
+	fields []*objectField
+	loc    Location
+}
+
+// objectField is a field of an input object literal
+type objectField struct {
+	name  string
+	value *value
+}
+
+// parser is a recursive descent parser of queries
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// parse parses a query into a document
+func parse(src string) (*document, error) {
+	p := &parser{lex: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunctuator, "{"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peek(tokenName, "query"), p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peek(tokenName, "fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.fragments[frag.name]; exists {
+				return nil, newError(`There can be only one fragment named "`+frag.name+`".`, frag.loc)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, newError("Syntax Error: the document has no operation.", p.tok.loc)
+	}
+	return doc, nil
+}
+
+// advance moves to the next token
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// peek reports whether the current token is of kind, and has the value unless it is empty
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && (value == "" || p.tok.value == value)
+}
+
+// skip advances past the current token if it is the punctuator value, reporting whether it was
+func (p *parser) skip(value string) (bool, error) {
+	if !p.peek(tokenPunctuator, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+// expect advances past the current token, which must be of kind and have the value unless it is empty
+func (p *parser) expect(kind tokenKind, value string) (token, error) {
+	tok := p.tok
+	if !p.peek(kind, value) {
+		return tok, p.unexpected()
+	}
+	return tok, p.advance()
+}
+
+// expectName advances past a name, returning it
+func (p *parser) expectName() (string, error) {
+	tok, err := p.expect(tokenName, "")
+	return tok.value, err
+}
+
+// unexpected returns a syntax error at the current token
+func (p *parser) unexpected() error {
+	return newError("Syntax Error: unexpected "+p.tok.String()+".", p.tok.loc)
+}
+
+// parseOperation parses an operation, or a query written as its bare selection set
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: "query", loc: p.tok.loc}
+	if p.peek(tokenPunctuator, "{") {
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		op.selections = selections
+		return op, nil
+	}
+
+	op.kind = p.tok.value
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenName, "") {
+		op.name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if op.variables, err = p.parseVariableDefinitions(); err != nil {
+		return nil, err
+	}
+	if op.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if op.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// parseVariableDefinitions parses the optional variable definitions of an operation
+func (p *parser) parseVariableDefinitions() ([]*variableDefinition, error) {
+	if open, err := p.skip("("); err != nil || !open {
+		return nil, err
+	}
+
+	var definitions []*variableDefinition
+	for {
+		loc := p.tok.loc
+		if _, err := p.expect(tokenPunctuator, "$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenPunctuator, ":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		definition := &variableDefinition{name: name, typ: typ, loc: loc}
+		if hasDefault, err := p.skip("="); err != nil {
+			return nil, err
+		} else if hasDefault {
+			if definition.defaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+
+		if closed, err := p.skip(")"); err != nil || closed {
+			return definitions, err
+		}
+	}
+}
+
+// parseType parses a type reference, e.g., Int, [ID!] or String!
+func (p *parser) parseType() (*typeRef, error) {
+	typ := &typeRef{}
+	if list, err := p.skip("["); err != nil {
+		return nil, err
+	} else if list {
+		if typ.elem, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenPunctuator, "]"); err != nil {
+			return nil, err
+		}
+	} else if typ.name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+
+	nonNull, err := p.skip("!")
+	typ.nonNull = nonNull
+	return typ, err
+}
+
+// parseSelectionSet parses a non-empty selection set between braces
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if _, err := p.expect(tokenPunctuator, "{"); err != nil {
+		return nil, err
+	}
+
+	var selections []selection
+	for {
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+
+		if closed, err := p.skip("}"); err != nil || closed {
+			return selections, err
+		}
+	}
+}
+
+// parseSelection parses a field, a fragment spread or an inline fragment
+func (p *parser) parseSelection() (selection, error) {
+	loc := p.tok.loc
+	if spread, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if spread {
+		return p.parseFragmentSelection(loc)
+	}
+
+	f := &field{loc: loc}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	f.name = name
+	if aliased, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if aliased {
+		f.alias = name
+		if f.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.arguments, err = p.parseArguments(false); err != nil {
+		return nil, err
+	}
+	if f.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunctuator, "{") {
+		if f.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// parseFragmentSelection parses what follows the "..." of a fragment spread or an inline fragment
+func (p *parser) parseFragmentSelection(loc Location) (selection, error) {
+	if p.peek(tokenName, "") && p.tok.value != "on" {
+		spread := &fragmentSpread{name: p.tok.value, loc: loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		spread.directives, err = p.parseDirectives()
+		return spread, err
+	}
+
+	inline := &inlineFragment{loc: loc}
+	if p.peek(tokenName, "on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if inline.typeCondition, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if inline.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if inline.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+// parseFragment parses a fragment definition, e.g., fragment intervalFields on Interval { distance }
+func (p *parser) parseFragment() (*fragment, error) {
+	frag := &fragment{loc: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if p.peek(tokenName, "on") {
+		return nil, p.unexpected()
+	}
+	if frag.name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	if frag.typeCondition, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if frag.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if frag.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return frag, nil
+}
+
+// parseArguments parses the optional arguments of a field or a directive
+func (p *parser) parseArguments(constant bool) ([]*argument, error) {
+	if open, err := p.skip("("); err != nil || !open {
+		return nil, err
+	}
+
+	var arguments []*argument
+	for {
+		arg := &argument{loc: p.tok.loc}
+		var err error
+		if arg.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenPunctuator, ":"); err != nil {
+			return nil, err
+		}
+		if arg.value, err = p.parseValue(constant); err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, arg)
+
+		if closed, err := p.skip(")"); err != nil || closed {
+			return arguments, err
+		}
+	}
+}
+
+// parseDirectives parses the directives annotating a selection or an operation
+func (p *parser) parseDirectives() ([]*directive, error) {
+	var directives []*directive
+	for p.peek(tokenPunctuator, "@") {
+		d := &directive{loc: p.tok.loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if d.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if d.arguments, err = p.parseArguments(false); err != nil {
+			return nil, err
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+// parseValue parses a value literal; A constant value, such as the default of a variable, has no variables
+func (p *parser) parseValue(constant bool) (*value, error) {
+	tok := p.tok
+	v := &value{raw: tok.value, loc: tok.loc}
+	switch {
+	case p.peek(tokenPunctuator, "$") && !constant:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		v.kind, v.raw = variableValue, name
+		return v, err
+	case p.peek(tokenPunctuator, "["):
+		v.kind = listValue
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for {
+			if closed, err := p.skip("]"); err != nil || closed {
+				return v, err
+			}
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+			v.list = append(v.list, item)
+		}
+	case p.peek(tokenPunctuator, "{"):
+		v.kind = objectValue
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for {
+			if closed, err := p.skip("}"); err != nil || closed {
+				return v, err
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokenPunctuator, ":"); err != nil {
+				return nil, err
+			}
+			fieldValue, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+			v.fields = append(v.fields, &objectField{name: name, value: fieldValue})
+		}
+	case tok.kind == tokenInt:
+		v.kind = intValue
+	case tok.kind == tokenFloat:
+		v.kind = floatValue
+	case tok.kind == tokenString:
+		v.kind = stringValue
+	case tok.kind == tokenName && (tok.value == "true" || tok.value == "false"):
+		v.kind = booleanValue
+	case tok.kind == tokenName && tok.value == "null":
+		v.kind = nullValue
+	case tok.kind == tokenName:
+		v.kind = enumValue
+	default:
+		return nil, p.unexpected()
+	}
+	return v, p.advance()
+}
@@ -0,0 +1,332 @@
+package graphql
+
+import (
+	"fmt"
+	"slices"
+)
+
+// validator checks an operation against the schema before it is executed
+type validator struct {
+	schema    *Schema
+	doc       *document
+	variables map[string]*variableDefinition
+	// fragments are those already validated, once whatever the number of their
+	// spreads, and visiting those being validated, to detect cycles
+	fragments map[string]bool
+	visiting  []string
+	// sizes are those of the fragments already measured
+	sizes map[string]size
+	errs  []*Error
+}
+
+// selectOperation returns the operation of the document to execute
+func selectOperation(doc *document, name string) (*operation, *Error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+}
+
+// validate returns the errors of the document and of its operation to execute
+func (s *Schema) validate(doc *document, op *operation) []*Error {
+	v := &validator{schema: s, doc: doc, variables: make(map[string]*variableDefinition), fragments: make(map[string]bool), sizes: make(map[string]size)}
+
+	for i, other := range doc.operations {
+		if other.name == "" && len(doc.operations) > 1 {
+			v.errorf(other.loc, "This anonymous operation must be the only defined operation.")
+		}
+		for _, previous := range doc.operations[:i] {
+			if other.name != "" && previous.name == other.name {
+				v.errorf(other.loc, "There can be only one operation named %q.", other.name)
+			}
+		}
+	}
+	if op.kind != "query" {
+		v.errorf(op.loc, "Only queries are supported, not %ss.", op.kind)
+		return v.errs
+	}
+
+	for _, definition := range op.variables {
+		if _, exists := v.variables[definition.name]; exists {
+			v.errorf(definition.loc, "There can be only one variable named \"$%s\".", definition.name)
+		}
+		v.variables[definition.name] = definition
+		if _, err := s.inputType(definition.typ); err != nil {
+			v.errorf(definition.loc, "Variable \"$%s\" cannot be of type %q: %s.", definition.name, definition.typ, err)
+		}
+		if definition.defaultValue != nil {
+			v.checkValue(definition.defaultValue)
+		}
+	}
+	v.checkDirectives(op.directives)
+	v.checkSelections(s.query, op.selections)
+
+	for name, frag := range doc.fragments {
+		if !v.fragments[name] {
+			v.errorf(frag.loc, "Fragment %q is never used.", name)
+		}
+	}
+	if len(v.errs) == 0 {
+		v.checkSize(s.query, op)
+	}
+	return v.errs
+}
+
+func (v *validator) errorf(loc Location, format string, args ...any) {
+	v.errs = append(v.errs, newError(fmt.Sprintf(format, args...), loc))
+}
+
+// checkSelections checks the selections of a selection set on the object
+func (v *validator) checkSelections(object *Object, selections []selection) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			v.checkField(object, sel)
+		case *fragmentSpread:
+			v.checkDirectives(sel.directives)
+			frag, exists := v.doc.fragments[sel.name]
+			if !exists {
+				v.errorf(sel.loc, "Unknown fragment %q.", sel.name)
+				continue
+			}
+			if slices.Contains(v.visiting, sel.name) {
+				v.errorf(sel.loc, "Cannot spread fragment %q within itself.", sel.name)
+				continue
+			}
+			// Its type condition is the object, so the checks of a fragment are the same wherever it is spread
+			if !v.checkTypeCondition(object, frag.typeCondition, sel.loc) || v.fragments[sel.name] {
+				continue
+			}
+			v.fragments[sel.name] = true
+			v.visiting = append(v.visiting, sel.name)
+			v.checkDirectives(frag.directives)
+			v.checkSelections(object, frag.selections)
+			v.visiting = v.visiting[:len(v.visiting)-1]
+		case *inlineFragment:
+			v.checkDirectives(sel.directives)
+			if sel.typeCondition != "" && !v.checkTypeCondition(object, sel.typeCondition, sel.loc) {
+				continue
+			}
+			v.checkSelections(object, sel.selections)
+		}
+	}
+}
+
+// checkTypeCondition reports whether a fragment on the type named condition can be spread in a selection set on the object
+func (v *validator) checkTypeCondition(object *Object, condition string, loc Location) bool {
+	t, exists := v.schema.types[condition]
+	if !exists {
+		v.errorf(loc, "Unknown type %q.", condition)
+		return false
+	}
+	if _, isObject := t.(*Object); !isObject {
+		v.errorf(loc, "Fragment cannot condition on non composite type %q.", condition)
+		return false
+	}
+	if t != object {
+		v.errorf(loc, "Fragment cannot be spread here as objects of type %q can never be of type %q.", object.Name, condition)
+		return false
+	}
+	return true
+}
+
+// checkField checks a field selected on the object, its arguments and its selections
+func (v *validator) checkField(object *Object, f *field) {
+	v.checkDirectives(f.directives)
+
+	if f.name == "__typename" {
+		if len(f.arguments) > 0 {
+			v.errorf(f.loc, "Field \"__typename\" has no arguments.")
+		}
+		if len(f.selections) > 0 {
+			v.errorf(f.loc, "Field \"__typename\" must not have a selection since type \"String!\" has no subfields.")
+		}
+		return
+	}
+	definition := v.schema.field(object, f.name)
+	if definition == nil {
+		v.errorf(f.loc, "Cannot query field %q on type %q.", f.name, object.Name)
+		return
+	}
+
+	v.checkArguments(f.arguments, f.loc, fmt.Sprintf("field \"%s.%s\"", object.Name, f.name), definition.argument, definition.Args)
+
+	switch t := namedType(definition.Type).(type) {
+	case *Scalar, *Enum:
+		if len(f.selections) > 0 {
+			v.errorf(f.loc, "Field %q must not have a selection since type %q has no subfields.", f.name, definition.Type)
+		}
+	case *Object:
+		if len(f.selections) == 0 {
+			v.errorf(f.loc, "Field %q of type %q must have a selection of subfields.", f.name, definition.Type)
+			return
+		}
+		v.checkSelections(t, f.selections)
+	}
+}
+
+// maxSize caps the counts of a size, which grow exponentially with fragments spreading the same fragment twice
+const maxSize = 1 << 30
+
+// size is the cost of a selection set with its fragments expanded, as executed
+type size struct {
+	// fields counts the fields selected, at any depth, and aliases those with an alias
+	fields, aliases int
+	// depth is how deeply its fields nest, and deepest one of the most nested fields
+	depth   int
+	deepest *field
+}
+
+// checkSize checks the operation against the depth, field and alias limits of the schema
+func (v *validator) checkSize(query *Object, op *operation) {
+	size := v.measure(query, op.selections)
+	if max := v.schema.MaxDepth; max > 0 && size.depth > max {
+		v.errorf(size.deepest.loc, "Field %q exceeds the maximum query depth of %d.", size.deepest.name, max)
+	}
+	if max := v.schema.MaxFields; max > 0 && size.fields > max {
+		v.errorf(op.loc, "Query selects more than the maximum of %d fields.", max)
+	}
+	if max := v.schema.MaxAliases; max > 0 && size.aliases > max {
+		v.errorf(op.loc, "Query has more than the maximum of %d aliases.", max)
+	}
+}
+
+// measure returns the size of a valid selection set on the object, measuring each
+// fragment once; The __Type.ofType fields add no depth, as the chain of wrapping
+// types they follow is short, so the introspection query of GraphiQL fits the limit
+func (v *validator) measure(object *Object, selections []selection) size {
+	var total size
+	add := func(s size) {
+		total.fields = min(total.fields+s.fields, maxSize)
+		total.aliases = min(total.aliases+s.aliases, maxSize)
+		if s.depth > total.depth {
+			total.depth, total.deepest = s.depth, s.deepest
+		}
+	}
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			var fieldObject *Object
+			if definition := v.schema.field(object, sel.name); definition != nil {
+				fieldObject, _ = namedType(definition.Type).(*Object)
+			}
+			s := v.measure(fieldObject, sel.selections)
+			s.fields = min(s.fields+1, maxSize)
+			if sel.alias != "" {
+				s.aliases = min(s.aliases+1, maxSize)
+			}
+			if s.depth == 0 {
+				s.deepest = sel
+			}
+			if object != introspection.typ || sel.name != "ofType" {
+				s.depth++
+			}
+			add(s)
+		case *fragmentSpread:
+			s, measured := v.sizes[sel.name]
+			if !measured {
+				s = v.measure(object, v.doc.fragments[sel.name].selections)
+				v.sizes[sel.name] = s
+			}
+			add(s)
+		case *inlineFragment:
+			add(v.measure(object, sel.selections))
+		}
+	}
+	return total
+}
+
+// checkArguments checks that the arguments are defined, given once, and that the required ones are given
+func (v *validator) checkArguments(arguments []*argument, loc Location, owner string, lookup func(string) *Argument, definitions []*Argument) {
+	for i, arg := range arguments {
+		if lookup(arg.name) == nil {
+			v.errorf(arg.loc, "Unknown argument %q on %s.", arg.name, owner)
+		}
+		if slices.ContainsFunc(arguments[:i], func(other *argument) bool { return other.name == arg.name }) {
+			v.errorf(arg.loc, "There can be only one argument named %q.", arg.name)
+		}
+		v.checkValue(arg.value)
+	}
+	for _, definition := range definitions {
+		_, nonNull := definition.Type.(*NonNull)
+		given := slices.ContainsFunc(arguments, func(arg *argument) bool { return arg.name == definition.Name })
+		if nonNull && definition.Default == nil && !given {
+			v.errorf(loc, "Argument %q of type %q is required on %s, but it was not provided.", definition.Name, definition.Type, owner)
+		}
+	}
+}
+
+// checkDirectives checks that the directives are @include or @skip, with their if argument
+func (v *validator) checkDirectives(directives []*directive) {
+	for _, d := range directives {
+		definition, known := directiveArguments[d.name]
+		if !known {
+			v.errorf(d.loc, "Unknown directive \"@%s\".", d.name)
+			continue
+		}
+		lookup := func(name string) *Argument {
+			if i := slices.IndexFunc(definition, func(arg *Argument) bool { return arg.Name == name }); i >= 0 {
+				return definition[i]
+			}
+			return nil
+		}
+		v.checkArguments(d.arguments, d.loc, "directive \"@"+d.name+"\"", lookup, definition)
+	}
+}
+
+// checkValue checks that the variables used by a value are defined by the operation
+func (v *validator) checkValue(val *value) {
+	switch val.kind {
+	case variableValue:
+		if _, defined := v.variables[val.raw]; !defined {
+			v.errorf(val.loc, "Variable \"$%s\" is not defined.", val.raw)
+		}
+	case listValue:
+		for _, item := range val.list {
+			v.checkValue(item)
+		}
+	case objectValue:
+		for _, f := range val.fields {
+			v.checkValue(f.value)
+		}
+	}
+}
+
+// directiveArguments are the arguments of the directives of the queries
+var directiveArguments = map[string][]*Argument{
+	"include": {{Name: "if", Description: "Included when true.", Type: &NonNull{Of: Boolean}}},
+	"skip":    {{Name: "if", Description: "Skipped when true.", Type: &NonNull{Of: Boolean}}},
+}
+
+// inputType returns the schema type of a variable, which must be a scalar or a list of them
+func (s *Schema) inputType(ref *typeRef) (Type, error) {
+	var t Type
+	if ref.elem != nil {
+		elem, err := s.inputType(ref.elem)
+		if err != nil {
+			return nil, err
+		}
+		t = &List{Of: elem}
+	} else {
+		named, exists := s.types[ref.name]
+		if !exists {
+			return nil, fmt.Errorf("unknown type %q", ref.name)
+		}
+		if _, scalar := named.(*Scalar); !scalar {
+			return nil, fmt.Errorf("%q is not an input type", ref.name)
+		}
+		t = named
+	}
+	if ref.nonNull {
+		t = &NonNull{Of: t}
+	}
+	return t, nil
+}
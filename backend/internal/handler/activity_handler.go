@@ -28,13 +28,13 @@ func NewActivityHandler(service app.ActivityService) *ActivityHandler {
 // @Produce json
 // @Param activity body handler.CreateActivityRequest true "Activity data"
 // @Success 201 {object} domain.Activity "Activity successfully created"
-// @Failure 400 {object} ErrorResponse "Invalid input"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /activities [post]
+// @Failure 400 {object} ProblemDetails "Invalid input"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/activities [post]
 func (h *ActivityHandler) CreateActivity(c *gin.Context) {
 	var req CreateActivityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid JSON"})
+		c.Error(invalidBody(err, &req))
 		return
 	}
 
@@ -44,19 +44,21 @@ func (h *ActivityHandler) CreateActivity(c *gin.Context) {
 		Date:         req.Date,
 		Start:        time.Now(), // Field 'Start' is currently unused by the frontend
 		Duration:     req.Duration,
-		Distance:     req.Distance,
+		Distance:     domain.Distance{Value: req.Distance, Unit: req.PoolUnit}.Meters(),
 		Laps:         req.Laps,
 		PoolSize:     req.PoolSize,
+		PoolUnit:     req.PoolUnit.OrDefault(),
 		LocationType: req.LocationType,
 		LocationName: req.LocationName,
 		Feeling:      req.Feeling,
 		HeartRateAvg: req.HeartRateAvg,
 		HeartRateMax: req.HeartRateMax,
+		RPE:          req.RPE,
 		Notes:        req.Notes,
 	}
 
-	if err := h.service.CreateActivity(activity); err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: "Service error"})
+	if err := h.service.CreateActivity(c.Request.Context(), activity); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -70,12 +72,12 @@ func (h *ActivityHandler) CreateActivity(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} domain.Activity "List of all activities"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /activities [get]
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/activities [get]
 func (h *ActivityHandler) GetAllActivities(c *gin.Context) {
-	activities, err := h.service.GetAllActivities()
+	activities, err := h.service.GetAllActivities(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve activities"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, activities)
@@ -88,27 +90,29 @@ func (h *ActivityHandler) GetAllActivities(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user_id path string true "User ID (UUID)"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} GetActivitiesByUserResponse
-// @Failure 400 {object} ErrorResponse "Invalid user ID"
-// @Failure 404 {object} ErrorResponse "User not found or no activities"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /users/{user_id}/activities [get]
+// @Success 304 "Activities unchanged since the given ETag"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 404 {object} ProblemDetails "User not found or no activities"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{user_id}/activities [get]
 func (h *ActivityHandler) GetActivitiesByUser(c *gin.Context) {
 	userIDParam := c.Param("id")
 	userID, err := uuid.Parse(userIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
 		return
 	}
 
-	activities, err := h.service.GetActivitiesByUser(userID)
+	activities, err := h.service.GetActivitiesByUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve activities"})
+		c.Error(err)
 		return
 	}
 
 	if len(activities) == 0 {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "No activities found for user"})
+		c.Error(domain.NewNotFoundError("no activities found for user"))
 		return
 	}
 
@@ -116,5 +120,138 @@ func (h *ActivityHandler) GetActivitiesByUser(c *gin.Context) {
 		Activities: activities,
 	}
 
-	c.JSON(http.StatusOK, response)
+	renderWithETag(c, http.StatusOK, response)
+}
+
+// GetActivityByID godoc
+// @Summary Get an activity
+// @Description Retrieves a swim activity with its intervals, pace zones and time spent in each heart-rate zone
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity ID (UUID)"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} entity.Activity
+// @Success 304 "Activity unchanged since the given ETag"
+// @Failure 400 {object} ProblemDetails "Invalid activity ID"
+// @Failure 404 {object} ProblemDetails "Activity not found"
+// @Router /api/v1/activities/{id} [get]
+func (h *ActivityHandler) GetActivityByID(c *gin.Context) {
+	activityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	activity, err := h.service.GetActivityByID(c.Request.Context(), activityID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	renderWithETag(c, http.StatusOK, activity)
+}
+
+// UpdateActivity godoc
+// @Summary Update an activity
+// @Description Replaces the data of a swim activity; its user and intervals are kept.
+// @Description With If-Match, the update only applies if the activity still has that ETag.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity ID (UUID)"
+// @Param If-Match header string false "ETag the update is based on"
+// @Param activity body handler.UpdateActivityRequest true "Activity data"
+// @Success 200 {object} entity.Activity "Activity successfully updated"
+// @Failure 400 {object} ProblemDetails "Invalid input"
+// @Failure 404 {object} ProblemDetails "Activity not found"
+// @Failure 412 {object} ProblemDetails "Activity was modified since the given ETag"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/activities/{id} [put]
+func (h *ActivityHandler) UpdateActivity(c *gin.Context) {
+	activityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	var req UpdateActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err, &req))
+		return
+	}
+
+	current, err := h.service.GetActivityByID(c.Request.Context(), activityID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	activity := domain.Activity{
+		ID:           activityID,
+		UserID:       current.UserID,
+		Date:         req.Date,
+		Start:        current.Start,
+		Duration:     req.Duration,
+		Distance:     domain.Distance{Value: req.Distance, Unit: req.PoolUnit}.Meters(),
+		Laps:         req.Laps,
+		PoolSize:     req.PoolSize,
+		PoolUnit:     req.PoolUnit.OrDefault(),
+		LocationType: req.LocationType,
+		LocationName: req.LocationName,
+		Feeling:      req.Feeling,
+		HeartRateAvg: req.HeartRateAvg,
+		HeartRateMax: req.HeartRateMax,
+		RPE:          req.RPE,
+		Notes:        req.Notes,
+	}
+	if c.GetHeader(IfMatchHeader) != "" {
+		if err := checkIfMatch(c, current); err != nil {
+			c.Error(err)
+			return
+		}
+		// The repository rechecks the version, catching writes racing with this one
+		activity.Version = current.Version
+	}
+
+	if err := h.service.UpdateActivity(c.Request.Context(), activity); err != nil {
+		c.Error(err)
+		return
+	}
+
+	updated, err := h.service.GetActivityByID(c.Request.Context(), activityID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	renderWithETag(c, http.StatusOK, updated)
+}
+
+// DeleteActivity godoc
+// @Summary Delete an activity
+// @Description Deletes a swim activity along with its intervals.
+// @Description It can be restored until the retention period is over, after which it is purged.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity ID (UUID)"
+// @Success 204 "Activity successfully deleted"
+// @Failure 400 {object} ProblemDetails "Invalid activity ID"
+// @Failure 404 {object} ProblemDetails "Activity not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/activities/{id} [delete]
+func (h *ActivityHandler) DeleteActivity(c *gin.Context) {
+	activityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	if err := h.service.DeleteActivity(c.Request.Context(), activityID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -22,17 +24,17 @@ type MockActivityService struct {
 	mock.Mock
 }
 
-func (m *MockActivityService) CreateActivity(activity domain.Activity) error {
+func (m *MockActivityService) CreateActivity(ctx context.Context, activity domain.Activity) error {
 	args := m.Called(activity)
 	return args.Error(0)
 }
 
-func (m *MockActivityService) GetAllActivities() ([]domain.Activity, error) {
+func (m *MockActivityService) GetAllActivities(ctx context.Context) ([]domain.Activity, error) {
 	args := m.Called()
 	return args.Get(0).([]domain.Activity), args.Error(1)
 }
 
-func (m *MockActivityService) GetActivitiesByUser(userID uuid.UUID) ([]entity.Activity, error) {
+func (m *MockActivityService) GetActivitiesByUser(ctx context.Context, userID uuid.UUID) ([]entity.Activity, error) {
 	args := m.Called(userID)
 	if raw := args.Get(0); raw != nil {
 		return raw.([]entity.Activity), args.Error(1)
@@ -40,17 +42,27 @@ func (m *MockActivityService) GetActivitiesByUser(userID uuid.UUID) ([]entity.Ac
 	return nil, args.Error(1)
 }
 
-func (m *MockActivityService) GetActivityByID(id uuid.UUID) (domain.Activity, error) {
+func (m *MockActivityService) GetActivitiesByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Activity, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]entity.Activity), args.Error(1)
+}
+
+func (m *MockActivityService) GetRecentActivitiesByUser(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Activity, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]entity.Activity), args.Error(1)
+}
+
+func (m *MockActivityService) GetActivityByID(ctx context.Context, id uuid.UUID) (entity.Activity, error) {
 	args := m.Called(id)
-	return args.Get(0).(domain.Activity), args.Error(1)
+	return args.Get(0).(entity.Activity), args.Error(1)
 }
 
-func (m *MockActivityService) UpdateActivity(activity domain.Activity) error {
+func (m *MockActivityService) UpdateActivity(ctx context.Context, activity domain.Activity) error {
 	args := m.Called(activity)
 	return args.Error(0)
 }
 
-func (m *MockActivityService) DeleteActivity(id uuid.UUID) error {
+func (m *MockActivityService) DeleteActivity(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
@@ -63,13 +75,14 @@ func TestCreateActivityHandler(t *testing.T) {
 	handler := NewActivityHandler(mockService)
 
 	router := gin.Default()
+	router.Use(ErrorHandler())
 	router.POST("/activities", handler.CreateActivity)
 
 	t.Run("success", func(t *testing.T) {
 		reqBody := CreateActivityRequest{
 			UserID:       uuid.New(),
 			Date:         "2023-10-01",
-			Duration:     domain.DurationString("30m"),
+			Duration:     domain.MustParseDuration("30m"),
 			Distance:     1500,
 			Laps:         30,
 			PoolSize:     50,
@@ -110,11 +123,82 @@ func TestCreateActivityHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
 	})
 
+	t.Run("RPE out of range", func(t *testing.T) {
+		reqBody := CreateActivityRequest{
+			UserID:       uuid.New(),
+			Date:         "2023-10-01",
+			Duration:     domain.MustParseDuration("30m"),
+			Distance:     1500,
+			Laps:         30,
+			PoolSize:     50,
+			LocationType: domain.LocationPool,
+			RPE:          11,
+		}
+
+		body, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest(http.MethodPost, "/activities", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("yards pool", func(t *testing.T) {
+		reqBody := CreateActivityRequest{
+			UserID:       uuid.New(),
+			Date:         "2023-10-03",
+			Duration:     domain.MustParseDuration("30m"),
+			Distance:     1500,
+			Laps:         60,
+			PoolSize:     25,
+			PoolUnit:     domain.DistanceUnitYards,
+			LocationType: domain.LocationPool,
+		}
+
+		body, _ := json.Marshal(reqBody)
+		mockService.On("CreateActivity", mock.MatchedBy(func(a domain.Activity) bool {
+			return a.UserID == reqBody.UserID && a.PoolUnit == domain.DistanceUnitYards &&
+				math.Abs(a.Distance-1371.6) < 1e-9
+		})).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/activities", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("invalid pool unit", func(t *testing.T) {
+		reqBody := CreateActivityRequest{
+			UserID:       uuid.New(),
+			Date:         "2023-10-03",
+			Duration:     domain.MustParseDuration("30m"),
+			Distance:     1500,
+			Laps:         60,
+			PoolSize:     25,
+			PoolUnit:     domain.DistanceUnit("feet"),
+			LocationType: domain.LocationPool,
+		}
+
+		body, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest(http.MethodPost, "/activities", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
 	t.Run("service error", func(t *testing.T) {
 		reqBody := CreateActivityRequest{
 			UserID:       uuid.New(),
 			Date:         "2023-10-02",
-			Duration:     domain.DurationString("1h"),
+			Duration:     domain.MustParseDuration("1h"),
 			Distance:     2000,
 			Laps:         40,
 			PoolSize:     25,
@@ -144,6 +228,7 @@ func TestGetActivitiesByUserHandler(t *testing.T) {
 	handler := NewActivityHandler(mockService)
 
 	router := gin.Default()
+	router.Use(ErrorHandler())
 	router.GET("/users/:id/activities", handler.GetActivitiesByUser)
 
 	t.Run("success", func(t *testing.T) {
@@ -201,3 +286,159 @@ func TestGetActivitiesByUserHandler(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
 	})
 }
+
+func TestGetActivityByIDHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockActivityService)
+	handler := NewActivityHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.GET("/activities/:id", handler.GetActivityByID)
+
+	t.Run("success", func(t *testing.T) {
+		activityID := uuid.New()
+		mockService.On("GetActivityByID", activityID).Return(entity.Activity{
+			ID:       activityID,
+			Distance: 1500,
+			TimeInZone: []entity.TimeInZone{
+				{Zone: 2, Name: "aerobic", Duration: domain.MustParseDuration("30m0s")},
+			},
+		}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/activities/"+activityID.String(), nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "aerobic")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		activityID := uuid.New()
+		activity := entity.Activity{ID: activityID, Distance: 1500, Version: 2}
+		mockService.On("GetActivityByID", activityID).Return(activity, nil)
+		etag, _, _ := etagFor(activity)
+
+		req, _ := http.NewRequest(http.MethodGet, "/activities/"+activityID.String(), nil)
+		req.Header.Set(IfNoneMatchHeader, etag)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+		assert.Empty(t, resp.Body.String())
+	})
+
+	t.Run("invalid UUID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/activities/not-a-uuid", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		activityID := uuid.New()
+		mockService.On("GetActivityByID", activityID).Return(entity.Activity{}, domain.ErrNotFound)
+
+		req, _ := http.NewRequest(http.MethodGet, "/activities/"+activityID.String(), nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestUpdateActivityHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	activityID, userID := uuid.New(), uuid.New()
+	current := entity.Activity{ID: activityID, UserID: userID, Date: "2025-06-01", Distance: 1500, Version: 1}
+	etag, _, err := etagFor(current)
+	assert.NoError(t, err)
+	body := `{"date":"2025-06-01","duration":"30:00","distance":2000,"laps":80,"pool_size":25,"location_type":"pool"}`
+
+	newRouter := func(s *MockActivityService) *gin.Engine {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.PUT("/activities/:id", NewActivityHandler(s).UpdateActivity)
+		return router
+	}
+
+	t.Run("matching If-Match", func(t *testing.T) {
+		mockService := new(MockActivityService)
+		updated := current
+		updated.Distance, updated.Version = 2000, 2
+		mockService.On("GetActivityByID", activityID).Return(current, nil).Once()
+		mockService.On("UpdateActivity", mock.MatchedBy(func(a domain.Activity) bool {
+			return a.ID == activityID && a.UserID == userID && a.Distance == 2000 && a.Version == 1
+		})).Return(nil)
+		mockService.On("GetActivityByID", activityID).Return(updated, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/activities/"+activityID.String(), bytes.NewBufferString(body))
+		req.Header.Set(IfMatchHeader, etag)
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"version":2`)
+		assert.NotEqual(t, etag, resp.Header().Get(ETagHeader))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("stale If-Match", func(t *testing.T) {
+		mockService := new(MockActivityService)
+		mockService.On("GetActivityByID", activityID).Return(current, nil)
+
+		req, _ := http.NewRequest(http.MethodPut, "/activities/"+activityID.String(), bytes.NewBufferString(body))
+		req.Header.Set(IfMatchHeader, `"stale"`)
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+		mockService.AssertNotCalled(t, "UpdateActivity", mock.Anything)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, "/activities/"+activityID.String(), bytes.NewBufferString(`{}`))
+		resp := httptest.NewRecorder()
+		newRouter(new(MockActivityService)).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestDeleteActivityHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockActivityService)
+	handler := NewActivityHandler(mockService)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.DELETE("/activities/:id", handler.DeleteActivity)
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+		mockService.On("DeleteActivity", id).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/activities/"+id.String(), nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		id := uuid.New()
+		mockService.On("DeleteActivity", id).Return(domain.NewNotFoundError("activity not found"))
+
+		req, _ := http.NewRequest(http.MethodDelete, "/activities/"+id.String(), nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
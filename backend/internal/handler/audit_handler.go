@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// AuditHandler handles HTTP requests related to the audit log
+type AuditHandler struct {
+	service app.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(service app.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// GetUserAudit godoc
+// @Summary Get the audit log of a user
+// @Description Returns the changes made to the user's profile, activities, intervals and CSS tests, most recent first,
+// @Description each with who made it and the values of the changed fields before and after it.
+// @Description Only the user and admins, identified by the authentication header, may read it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {array} domain.AuditEvent "Audit log"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/audit [get]
+func (h *AuditHandler) GetUserAudit(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	events, err := h.service.GetUserAudit(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditService is a mock implementation of app.AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) GetUserAudit(ctx context.Context, userID uuid.UUID) ([]domain.AuditEvent, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.AuditEvent), args.Error(1)
+}
+
+func TestGetUserAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockAuditService)
+	handler := NewAuditHandler(service)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/audit", handler.GetUserAudit)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		service.On("GetUserAudit", userID).Return([]domain.AuditEvent{{
+			ID:         uuid.New(),
+			UserID:     userID,
+			EntityType: domain.AuditEntityActivity,
+			EntityID:   uuid.New(),
+			Action:     domain.AuditActionUpdate,
+			Changes:    map[string]domain.FieldChange{"notes": {Before: "", After: "felt strong"}},
+		}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/audit", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"actor_id":null`)
+		assert.Contains(t, resp.Body.String(), `"changes":{"notes":{"before":"","after":"felt strong"}}`)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		userID := uuid.New()
+		service.On("GetUserAudit", userID).Return([]domain.AuditEvent(nil), domain.NewForbiddenError("only the user and admins may read the user's audit log"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/audit", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/42/audit", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/netip"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// Authenticate is a middleware identifying the user making each request by the ID
+// in the given header, set by a trusted reverse proxy that authenticated them; The
+// actor is carried by the request context and its ID stored under UserIDKey.
+// Requests without the header are anonymous, as are all requests if header is empty.
+// The header is rejected on requests that do not come from one of the proxies, as
+// any client reaching the server directly could set it
+func Authenticate(header string, proxies []netip.Prefix, admins []uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(header)
+		if header == "" || value == "" {
+			c.Next()
+			return
+		}
+
+		if !fromProxy(c.Request.RemoteAddr, proxies) {
+			c.Error(domain.NewUnauthorizedError(header + " is only accepted from a trusted proxy"))
+			c.Abort()
+			return
+		}
+
+		userID, err := uuid.Parse(value)
+		if err != nil || userID == uuid.Nil {
+			c.Error(domain.NewUnauthorizedError(header + " must be a valid user ID"))
+			c.Abort()
+			return
+		}
+
+		actor := auth.Actor{UserID: userID, Admin: slices.Contains(admins, userID)}
+		c.Request = c.Request.WithContext(auth.WithActor(c.Request.Context(), actor))
+		c.Set(UserIDKey, userID.String())
+		c.Next()
+	}
+}
+
+// fromProxy reports whether the peer address of a request is in one of the proxy networks
+func fromProxy(remoteAddr string, proxies []netip.Prefix) bool {
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+
+	addr := addrPort.Addr().Unmap()
+	return slices.ContainsFunc(proxies, func(proxy netip.Prefix) bool {
+		return proxy.Contains(addr)
+	})
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	admin := uuid.New()
+
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	newRouter := func(header string) *gin.Engine {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(Authenticate(header, proxies, []uuid.UUID{admin}))
+		router.GET("/whoami", func(c *gin.Context) {
+			actor := auth.FromContext(c.Request.Context())
+			c.JSON(http.StatusOK, gin.H{"user_id": c.GetString(UserIDKey), "actor": actor.UserID, "admin": actor.Admin})
+		})
+		return router
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		peer     string
+		status   int
+		expected string
+	}{
+		{"user", "X-User-ID", "6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1", "10.0.0.2:4000", http.StatusOK, `"admin":false`},
+		{"admin", "X-User-ID", admin.String(), "10.0.0.2:4000", http.StatusOK, `"admin":true`},
+		{"anonymous", "X-User-ID", "", "203.0.113.7:4000", http.StatusOK, `"user_id":""`},
+		{"invalid ID", "X-User-ID", "john", "10.0.0.2:4000", http.StatusUnauthorized, "X-User-ID must be a valid user ID"},
+		{"not from a proxy", "X-User-ID", admin.String(), "203.0.113.7:4000", http.StatusUnauthorized, "only accepted from a trusted proxy"},
+		{"IPv4-mapped proxy address", "X-User-ID", admin.String(), "[::ffff:10.0.0.2]:4000", http.StatusOK, `"admin":true`},
+		{"disabled", "", "john", "203.0.113.7:4000", http.StatusOK, `"user_id":""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/whoami", nil)
+			req.RemoteAddr = tt.peer
+			if tt.value != "" {
+				req.Header.Set("X-User-ID", tt.value)
+			}
+			resp := httptest.NewRecorder()
+			newRouter(tt.header).ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.status, resp.Code)
+			assert.Contains(t, resp.Body.String(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// exposedHeaders are the response headers that cross-origin clients may read
+var exposedHeaders = []string{RequestIDHeader, ETagHeader, "Retry-After", "Deprecation", "Sunset", "Link", "Location", "Content-Disposition"}
+
+// CORSPolicy defines which cross-origin requests browsers may send to the API
+type CORSPolicy struct {
+	// Origins allowed to call the API, e.g., "https://swim.example.com"; "*" allows
+	// any origin and a single "*" in an origin matches any subdomain
+	Origins []string
+	// Methods allowed in cross-origin requests
+	Methods []string
+	// Headers allowed in cross-origin requests
+	Headers []string
+	// Credentials allows requests carrying cookies or authorization headers
+	Credentials bool
+	// MaxAge is how long browsers may cache the result of a preflight request
+	MaxAge time.Duration
+}
+
+// CORS is a middleware that answers preflight requests and adds the CORS headers
+// of the policy to the responses; Requests from other origins are rejected with
+// 403 Forbidden
+func CORS(policy CORSPolicy) gin.HandlerFunc {
+	config := cors.Config{
+		AllowMethods:     policy.Methods,
+		AllowHeaders:     policy.Headers,
+		ExposeHeaders:    exposedHeaders,
+		AllowCredentials: policy.Credentials,
+		AllowWildcard:    true,
+		MaxAge:           policy.MaxAge,
+	}
+	if slices.Contains(policy.Origins, "*") {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = policy.Origins
+	}
+	return cors.New(config)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(CORSPolicy{
+		Origins:     []string{"http://localhost:5173", "https://*.swim.example.com"},
+		Methods:     []string{http.MethodGet, http.MethodPost},
+		Headers:     []string{"Content-Type"},
+		Credentials: true,
+		MaxAge:      time.Hour,
+	}))
+	router.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/users", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("preflight", func(t *testing.T) {
+		resp := request(http.MethodOptions, "http://localhost:5173")
+
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.Equal(t, "http://localhost:5173", resp.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET,POST", resp.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "true", resp.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "3600", resp.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("subdomain", func(t *testing.T) {
+		resp := request(http.MethodGet, "https://app.swim.example.com")
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "https://app.swim.example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, resp.Header().Get("Access-Control-Expose-Headers"), http.CanonicalHeaderKey(RequestIDHeader))
+	})
+
+	t.Run("disallowed origin", func(t *testing.T) {
+		resp := request(http.MethodGet, "https://evil.example.com")
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// CSSHandler handles HTTP requests related to Critical Swim Speed tests and pace zones
+type CSSHandler struct {
+	service app.CSSService
+}
+
+// NewCSSHandler creates a new CSSHandler
+func NewCSSHandler(service app.CSSService) *CSSHandler {
+	return &CSSHandler{service: service}
+}
+
+// CreateCSSTest godoc
+// @Summary Record a CSS test
+// @Description Computes the Critical Swim Speed from a 400m and a 200m trial interval; if no intervals are given, they are detected in the user's most recent activity containing both distances
+// @Tags css
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param test body handler.CreateCSSTestRequest false "Trial intervals"
+// @Success 201 {object} entity.CSSTest "CSS test successfully recorded"
+// @Failure 400 {object} ProblemDetails "Invalid input or trials"
+// @Failure 404 {object} ProblemDetails "No trials found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/css [post]
+func (h *CSSHandler) CreateCSSTest(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	var req CreateCSSTestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(invalidBody(err, &req))
+			return
+		}
+	}
+	if (req.Interval400ID == uuid.Nil) != (req.Interval200ID == uuid.Nil) {
+		c.Error(domain.NewValidationError(domain.FieldError{Field: "interval_400_id", Code: domain.CodeRequired, Message: "must be given together with interval_200_id"}))
+		return
+	}
+
+	test, err := h.service.CreateCSSTest(c.Request.Context(), userID, req.Interval400ID, req.Interval200ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, test)
+}
+
+// GetCSSHistory godoc
+// @Summary Get the CSS test history of a user
+// @Description Returns all CSS tests of the user with their pace zones, most recent first
+// @Tags css
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {array} entity.CSSTest "CSS test history"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/css [get]
+func (h *CSSHandler) GetCSSHistory(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	history, err := h.service.GetCSSHistory(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetPaceZones godoc
+// @Summary Get the pace zones of a user
+// @Description Returns the pace zones per 100m derived from the user's most recent CSS test
+// @Tags css
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {array} entity.PaceZone "Pace zones"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 404 {object} ProblemDetails "No CSS test recorded"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/pace-zones [get]
+func (h *CSSHandler) GetPaceZones(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	zones, err := h.service.GetPaceZones(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, zones)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCSSService is a mock implementation of app.CSSService
+type MockCSSService struct {
+	mock.Mock
+}
+
+func (m *MockCSSService) CreateCSSTest(ctx context.Context, userID, interval400ID, interval200ID uuid.UUID) (entity.CSSTest, error) {
+	args := m.Called(userID, interval400ID, interval200ID)
+	return args.Get(0).(entity.CSSTest), args.Error(1)
+}
+
+func (m *MockCSSService) GetCSSHistory(ctx context.Context, userID uuid.UUID) ([]entity.CSSTest, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.CSSTest), args.Error(1)
+}
+
+func (m *MockCSSService) GetPaceZones(ctx context.Context, userID uuid.UUID) ([]entity.PaceZone, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.PaceZone), args.Error(1)
+}
+
+func TestCreateCSSTestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCSSService)
+	handler := NewCSSHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.POST("/users/:id/css", handler.CreateCSSTest)
+
+	t.Run("given intervals", func(t *testing.T) {
+		userID := uuid.New()
+		reqBody := CreateCSSTestRequest{Interval400ID: uuid.New(), Interval200ID: uuid.New()}
+		mockService.On("CreateCSSTest", userID, reqBody.Interval400ID, reqBody.Interval200ID).
+			Return(entity.CSSTest{UserID: userID, CSSPacePer100m: "01:35"}, nil)
+
+		body, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userID.String()+"/css", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Contains(t, resp.Body.String(), "01:35")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("detected intervals", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("CreateCSSTest", userID, uuid.Nil, uuid.Nil).Return(entity.CSSTest{UserID: userID}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userID.String()+"/css", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("single interval", func(t *testing.T) {
+		body, _ := json.Marshal(CreateCSSTestRequest{Interval400ID: uuid.New()})
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+uuid.New().String()+"/css", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("no trials found", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("CreateCSSTest", userID, uuid.Nil, uuid.Nil).Return(entity.CSSTest{}, app.ErrCSSTrialsNotFound)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userID.String()+"/css", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("invalid trials", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("CreateCSSTest", userID, uuid.Nil, uuid.Nil).Return(entity.CSSTest{}, domain.ErrInvalidCSSTest)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userID.String()+"/css", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("CreateCSSTest", userID, uuid.Nil, uuid.Nil).Return(entity.CSSTest{}, errors.New("db error"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userID.String()+"/css", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func TestGetCSSHistoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCSSService)
+	handler := NewCSSHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/css", handler.GetCSSHistory)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetCSSHistory", userID).Return([]entity.CSSTest{{UserID: userID, Date: "2023-10-01"}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/css", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "2023-10-01")
+	})
+
+	t.Run("invalid UUID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/not-a-uuid/css", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestGetPaceZonesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCSSService)
+	handler := NewCSSHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/pace-zones", handler.GetPaceZones)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetPaceZones", userID).Return([]entity.PaceZone{{Number: 4, Name: "threshold"}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/pace-zones", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "threshold")
+	})
+
+	t.Run("no CSS test", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetPaceZones", userID).Return([]entity.PaceZone{}, app.ErrNoCSSTest)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/pace-zones", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
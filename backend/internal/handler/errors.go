@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+)
+
+// ProblemContentType is the media type of RFC 7807 error responses
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails represents an error returned by the API, following RFC 7807.
+// swagger:model
+type ProblemDetails struct {
+	// Type is a URI reference identifying the problem type
+	// Example: about:blank
+	Type string `json:"type"`
+	// Title is a short summary of the problem type
+	// Example: Not Found
+	Title string `json:"title"`
+	// Status is the HTTP status code
+	// Example: 404
+	Status int `json:"status"`
+	// Detail is an explanation specific to this occurrence of the problem
+	// Example: user not found
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request that caused the problem
+	// Example: /users/6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1
+	Instance string `json:"instance,omitempty"`
+	// Errors holds the details of each invalid field, for validation problems
+	Errors []domain.FieldError `json:"errors,omitempty"`
+}
+
+// ErrorHandler is a middleware that turns the last error attached to the context
+// with c.Error into a problem+json response, unless a response was already written
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		// Errors caused by the request deadline, e.g., a canceled query, are reported as a timeout
+		if ctxErr := c.Request.Context().Err(); errors.Is(ctxErr, context.DeadlineExceeded) {
+			err = fmt.Errorf("%w: %w", ctxErr, err)
+		}
+
+		problem := NewProblemDetails(err)
+		problem.Instance = c.Request.URL.Path
+
+		// Client errors are expected, so only server errors are logged as errors
+		level := slog.LevelInfo
+		if problem.Status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).Log(ctx, level, "request failed",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", problem.Status,
+			"error", err.Error(),
+		)
+
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// NewProblemDetails maps an error to the problem it represents; errors that are not
+// classified by the domain error model are reported as internal errors, without details
+func NewProblemDetails(err error) ProblemDetails {
+	status := statusFor(err)
+	problem := ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+
+	switch status {
+	case http.StatusInternalServerError:
+		problem.Detail = "an unexpected error occurred"
+		return problem
+	case http.StatusServiceUnavailable:
+		problem.Detail = "the request took too long to complete"
+		return problem
+	case http.StatusRequestEntityTooLarge:
+		var maxBytesErr *http.MaxBytesError
+		errors.As(err, &maxBytesErr)
+		problem.Detail = fmt.Sprintf("the request body must not exceed %d bytes", maxBytesErr.Limit)
+		return problem
+	}
+
+	problem.Detail = err.Error()
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Fields
+	}
+
+	return problem
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	case errors.As(err, new(*http.MaxBytesError)):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, new(*TooManyRequestsError)):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// invalidParam returns a validation error for a malformed path or query parameter
+func invalidParam(name, code, message string) error {
+	return domain.NewValidationError(domain.FieldError{Field: name, Code: code, Message: message})
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProblemDetails(t *testing.T) {
+	t.Run("validation error", func(t *testing.T) {
+		problem := NewProblemDetails(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "Bad Request", problem.Title)
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, []domain.FieldError{{Field: "id", Code: domain.CodeInvalidFormat, Message: "must be a valid UUID"}}, problem.Errors)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		problem := NewProblemDetails(domain.NewNotFoundError("user not found"))
+
+		assert.Equal(t, http.StatusNotFound, problem.Status)
+		assert.Equal(t, "user not found", problem.Detail)
+		assert.Empty(t, problem.Errors)
+	})
+
+	t.Run("wrapped conflict", func(t *testing.T) {
+		problem := NewProblemDetails(fmt.Errorf("create user: %w", domain.NewConflictError("user with this email already exists")))
+
+		assert.Equal(t, http.StatusConflict, problem.Status)
+	})
+
+	t.Run("precondition failed", func(t *testing.T) {
+		problem := NewProblemDetails(domain.NewPreconditionFailedError("resource was modified since it was last read"))
+
+		assert.Equal(t, http.StatusPreconditionFailed, problem.Status)
+		assert.Equal(t, "Precondition Failed", problem.Title)
+	})
+
+	t.Run("unauthorized and forbidden", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, NewProblemDetails(domain.NewUnauthorizedError("authentication required")).Status)
+		assert.Equal(t, http.StatusForbidden, NewProblemDetails(domain.NewForbiddenError("not allowed")).Status)
+	})
+
+	t.Run("unclassified error hides details", func(t *testing.T) {
+		problem := NewProblemDetails(errors.New("pq: connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, problem.Status)
+		assert.NotContains(t, problem.Detail, "pq")
+	})
+}
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/fail", func(c *gin.Context) {
+		c.Error(domain.NewValidationError(domain.FieldError{Field: "name", Message: "is required"}))
+	})
+	router.GET("/written", func(c *gin.Context) {
+		c.Error(errors.New("ignored"))
+		c.String(http.StatusOK, "ok")
+	})
+
+	t.Run("writes problem details", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+
+		var problem ProblemDetails
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, "/fail", problem.Instance)
+		assert.Equal(t, "name", problem.Errors[0].Field)
+	})
+
+	t.Run("keeps responses already written", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/written", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "ok", w.Body.String())
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+const (
+	// ETagHeader carries the entity tag of a representation
+	ETagHeader = "ETag"
+	// IfMatchHeader makes a write conditional on the current entity tag
+	IfMatchHeader = "If-Match"
+	// IfNoneMatchHeader makes a read conditional on the entity tag having changed
+	IfNoneMatchHeader = "If-None-Match"
+)
+
+// etagFor returns a strong entity tag for the JSON encoding of obj
+func etagFor(obj any) (string, []byte, error) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, body, nil
+}
+
+// renderWithETag writes obj as JSON along with its entity tag, or an empty
+// 304 Not Modified when the request's If-None-Match already names that tag
+func renderWithETag(c *gin.Context, status int, obj any) {
+	etag, body, err := etagFor(obj)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header(ETagHeader, etag)
+	if status == http.StatusOK && etagMatches(c.GetHeader(IfNoneMatchHeader), etag, false) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// checkIfMatch fails with ErrPreconditionFailed unless the request's If-Match
+// header names the entity tag of current, the representation as last served
+func checkIfMatch(c *gin.Context, current any) error {
+	etag, _, err := etagFor(current)
+	if err != nil {
+		return err
+	}
+	if !etagMatches(c.GetHeader(IfMatchHeader), etag, true) {
+		return domain.NewPreconditionFailedError("resource was modified since it was last read")
+	}
+	return nil
+}
+
+// etagMatches reports whether a comma-separated If-Match or If-None-Match
+// header names etag; strong comparison rejects weak validators (RFC 9110 8.8.3.2)
+func etagMatches(header, etag string, strong bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak, ok := strings.CutPrefix(candidate, "W/"); ok {
+			if strong {
+				continue
+			}
+			candidate = weak
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtagFor(t *testing.T) {
+	first, _, err := etagFor(domain.User{Name: "Ana", Version: 1})
+	assert.NoError(t, err)
+	again, _, _ := etagFor(domain.User{Name: "Ana", Version: 1})
+	changed, _, _ := etagFor(domain.User{Name: "Ana", Version: 2})
+
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, first)
+	assert.Equal(t, first, again)
+	assert.NotEqual(t, first, changed)
+}
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `"abc"`
+
+	tests := []struct {
+		name   string
+		header string
+		strong bool
+		want   bool
+	}{
+		{"empty", "", false, false},
+		{"exact", `"abc"`, true, true},
+		{"other", `"def"`, false, false},
+		{"list", `"def", "abc"`, true, true},
+		{"wildcard", "*", true, true},
+		{"weak for If-None-Match", `W/"abc"`, false, true},
+		{"weak for If-Match", `W/"abc"`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etagMatches(tt.header, etag, tt.strong))
+		})
+	}
+}
+
+func TestRenderWithETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/thing", func(c *gin.Context) {
+		renderWithETag(c, http.StatusOK, MessageResponse{Message: "hello"})
+	})
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/thing", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"message":"hello"}`, resp.Body.String())
+	etag := resp.Header().Get(ETagHeader)
+	assert.NotEmpty(t, etag)
+
+	t.Run("not modified", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/thing", nil)
+		req.Header.Set(IfNoneMatchHeader, etag)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+		assert.Empty(t, resp.Body.String())
+		assert.Equal(t, etag, resp.Header().Get(ETagHeader))
+	})
+
+	t.Run("stale copy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/thing", nil)
+		req.Header.Set(IfNoneMatchHeader, `"stale"`)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/events"
+)
+
+const (
+	// eventHeartbeat is how often an idle stream sends a comment, so proxies keep
+	// it open and a disconnected client is noticed
+	eventHeartbeat = 15 * time.Second
+	// eventRetry is how long clients wait before reconnecting to a closed stream
+	eventRetry = 3 * time.Second
+)
+
+// EventHandler handles HTTP requests related to the event streams of the users
+type EventHandler struct {
+	service   app.EventService
+	heartbeat time.Duration
+}
+
+// NewEventHandler creates a new EventHandler
+func NewEventHandler(service app.EventService) *EventHandler {
+	return &EventHandler{service: service, heartbeat: eventHeartbeat}
+}
+
+// StreamEvents godoc
+// @Summary Stream the events of a user
+// @Description Streams the changes to the data of the user as server-sent events, e.g., to update a dashboard live.
+// @Description Each event is named after its type, "activity.created", "activity.updated" or "activity.deleted", with
+// @Description the event ID as its id and a JSON payload shaped like the body of a webhook delivery, whose data is the activity.
+// @Description The data is null when it was too large to relay from another API instance, and clients should reload it.
+// @Description The stream is not bound by the request timeout. It is closed when the client falls too far behind or the
+// @Description server shuts down, after which clients reconnect and reload the data they show, as events may have been missed.
+// @Description Only the user and admins may subscribe.
+// @Tags events
+// @Produce text/event-stream
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} domain.WebhookPayload "Stream of events"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/events [get]
+func (h *EventHandler) StreamEvents(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	subscription, err := h.service.Subscribe(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	// Keeps reverse proxies such as nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventRetry.Milliseconds())
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			if err := writeEvent(c.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes an event in the server-sent events format; The JSON payload
+// holds no newline, so it fits in a single data field
+func writeEvent(w gin.ResponseWriter, event events.Event) error {
+	payload, err := json.Marshal(domain.WebhookPayload{
+		ID:         event.ID,
+		Event:      event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, payload)
+	return err
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEventService is a mock implementation of app.EventService
+type MockEventService struct {
+	mock.Mock
+}
+
+func (m *MockEventService) Subscribe(ctx context.Context, userID uuid.UUID) (*events.Subscription, error) {
+	args := m.Called(userID)
+	subscription, _ := args.Get(0).(*events.Subscription)
+	return subscription, args.Error(1)
+}
+
+// readEvent reads the fields of the next event, or comment, of a stream
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var fields []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading the stream: %v", err)
+		}
+		if line == "\n" {
+			return fields
+		}
+		fields = append(fields, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockEventService)
+	handler := NewEventHandler(service)
+	handler.heartbeat = 50 * time.Millisecond
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/events", handler.StreamEvents)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	t.Run("streams the events of the user", func(t *testing.T) {
+		broker := events.NewBroker(nil)
+		userID := uuid.New()
+		service.On("Subscribe", userID).Return(broker.Subscribe(userID), nil)
+
+		resp, err := http.Get(server.URL + "/users/" + userID.String() + "/events")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		stream := bufio.NewReader(resp.Body)
+		assert.Equal(t, []string{"retry: 3000"}, readEvent(t, stream))
+
+		event, err := events.NewEvent(userID, domain.WebhookActivityCreated, map[string]int{"distance": 1500})
+		assert.NoError(t, err)
+		assert.NoError(t, broker.Publish(context.Background(), event))
+
+		fields := readEvent(t, stream)
+		assert.Len(t, fields, 3)
+		assert.Equal(t, "id: "+event.ID.String(), fields[0])
+		assert.Equal(t, "event: activity.created", fields[1])
+		assert.Contains(t, fields[2], `"event":"activity.created"`)
+		assert.Contains(t, fields[2], `"data":{"distance":1500}`)
+
+		assert.Equal(t, []string{": heartbeat"}, readEvent(t, stream))
+
+		// Shutting down closes the stream
+		broker.Close()
+		_, err = stream.ReadString('\n')
+		assert.Error(t, err)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		userID := uuid.New()
+		service.On("Subscribe", userID).Return(nil, domain.NewForbiddenError("only the user and admins may access the user's events"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/events", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// exportsPath is the path of the exports, under which each export and its archive are served
+const exportsPath = "/api/v1/exports/"
+
+// ExportHandler handles HTTP requests related to the data exports of users
+type ExportHandler struct {
+	service app.ExportService
+}
+
+// NewExportHandler creates a new ExportHandler
+func NewExportHandler(service app.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// RequestExport godoc
+// @Summary Request an export of the data of a user
+// @Description Starts generating a ZIP archive of the user's profile, activities and intervals (as JSON and CSV),
+// @Description CSS tests and audit log. Poll the export, linked by the Location header, until it is ready to download.
+// @Description The archive is generated by a background job, retried when it fails, whose attempts are served by /api/v1/jobs/{job_id}.
+// @Description Only the user and admins may request it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 202 {object} ExportResponse "Export requested"
+// @Header 202 {string} Location "Path of the export"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/export [post]
+func (h *ExportHandler) RequestExport(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	export, err := h.service.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Location", exportsPath+export.ID.String())
+	c.JSON(http.StatusAccepted, exportResponse(export))
+}
+
+// GetExport godoc
+// @Summary Get a data export
+// @Description Returns the status of an export, with the link to download its archive once ready
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "Export ID (UUID)"
+// @Success 200 {object} ExportResponse "Export"
+// @Failure 400 {object} ProblemDetails "Invalid export ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "Export not found or expired"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/exports/{id} [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	export, err := h.service.GetExport(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, exportResponse(export))
+}
+
+// DownloadExport godoc
+// @Summary Download a data export
+// @Description Returns the ZIP archive of a ready export
+// @Tags users
+// @Produce application/zip
+// @Param id path string true "Export ID (UUID)"
+// @Success 200 {file} file "ZIP archive"
+// @Failure 400 {object} ProblemDetails "Invalid export ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "Export not found or expired"
+// @Failure 409 {object} ProblemDetails "Export still pending or failed"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	archive, err := h.service.GetExportArchive(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="export-`+id.String()+`.zip"`)
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// exportResponse links a ready export to its archive
+func exportResponse(export domain.DataExport) ExportResponse {
+	response := ExportResponse{DataExport: export}
+	if export.Status == domain.ExportReady {
+		response.DownloadURL = exportsPath + export.ID.String() + "/download"
+	}
+	return response
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExportService is a mock implementation of app.ExportService
+type MockExportService struct {
+	mock.Mock
+}
+
+func (m *MockExportService) RequestExport(ctx context.Context, userID uuid.UUID) (domain.DataExport, error) {
+	args := m.Called(userID)
+	return args.Get(0).(domain.DataExport), args.Error(1)
+}
+
+func (m *MockExportService) GetExport(ctx context.Context, id uuid.UUID) (domain.DataExport, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.DataExport), args.Error(1)
+}
+
+func (m *MockExportService) GetExportArchive(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	args := m.Called(id)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func TestExportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockExportService)
+	handler := NewExportHandler(service)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/users/:id/export", handler.RequestExport)
+	router.GET("/exports/:id", handler.GetExport)
+	router.GET("/exports/:id/download", handler.DownloadExport)
+
+	t.Run("request", func(t *testing.T) {
+		userID := uuid.New()
+		export := domain.DataExport{ID: uuid.New(), UserID: userID, Status: domain.ExportPending}
+		service.On("RequestExport", userID).Return(export, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userID.String()+"/export", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusAccepted, resp.Code)
+		assert.Equal(t, "/api/v1/exports/"+export.ID.String(), resp.Header().Get("Location"))
+		assert.Contains(t, resp.Body.String(), `"status":"pending"`)
+		assert.NotContains(t, resp.Body.String(), "download_url")
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		export := domain.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: domain.ExportReady, Size: 2048}
+		service.On("GetExport", export.ID).Return(export, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/exports/"+export.ID.String(), nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"download_url":"/api/v1/exports/`+export.ID.String()+`/download"`)
+	})
+
+	t.Run("download", func(t *testing.T) {
+		id := uuid.New()
+		service.On("GetExportArchive", id).Return([]byte("PK"), nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/exports/"+id.String()+"/download", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "application/zip", resp.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="export-`+id.String()+`.zip"`, resp.Header().Get("Content-Disposition"))
+		assert.Equal(t, "PK", resp.Body.String())
+	})
+
+	t.Run("download pending", func(t *testing.T) {
+		id := uuid.New()
+		service.On("GetExportArchive", id).Return([]byte(nil), app.ErrExportPending)
+
+		req, _ := http.NewRequest(http.MethodGet, "/exports/"+id.String()+"/download", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+}
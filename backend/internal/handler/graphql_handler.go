@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/liviaruegger/MAC0350/backend/internal/graphql"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+)
+
+const (
+	// graphQLMaxDepth bounds how deeply queries can nest fields, e.g., user, activities and intervals are 3 levels
+	graphQLMaxDepth = 6
+	// graphQLMaxFields bounds how many fields queries can select, well above every field of the schema
+	graphQLMaxFields = 300
+	// graphQLMaxAliases bounds how many fields queries can alias, e.g., to fetch several users at once
+	graphQLMaxAliases = 20
+	// defaultGraphQLActivities is how many recent activities User.activities returns by default
+	defaultGraphQLActivities = 10
+	// maxGraphQLActivities is the most activities User.activities returns
+	maxGraphQLActivities = 100
+)
+
+// GraphQLHandler handles GraphQL queries over users, activities and intervals
+type GraphQLHandler struct {
+	schema     *graphql.Schema
+	activities app.ActivityService
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler, resolving its schema with the user and activity services
+func NewGraphQLHandler(users app.UserService, activities app.ActivityService) *GraphQLHandler {
+	schema, err := graphql.NewSchema(newGraphQLQuery(users, activities))
+	if err != nil {
+		panic(fmt.Sprintf("invalid GraphQL schema: %v", err))
+	}
+	schema.MaxDepth = graphQLMaxDepth
+	schema.MaxFields = graphQLMaxFields
+	schema.MaxAliases = graphQLMaxAliases
+	return &GraphQLHandler{schema: schema, activities: activities}
+}
+
+// Query godoc
+// @Summary Run a GraphQL query
+// @Description Runs a GraphQL query over users, their activities and the intervals of the activities, e.g., to fetch a user
+// @Description and the distance and stroke of the intervals of their last 10 activities in one request. Only queries are
+// @Description supported. The intervals, and the metrics derived from them, of all the activities of a response are loaded
+// @Description together. The response is always 200 OK with the data and errors of the query, as GraphQL clients expect,
+// @Description unless the body is not a GraphQL request. Errors of resolved fields have an extensions.status with the
+// @Description status code the REST API would have responded with. Queries can nest fields 6 levels deep, select 300
+// @Description fields and alias 20, counting the fields of a fragment each time it is spread. The schema is served by
+// @Description GET /api/v1/graphql/schema, and through introspection with __schema and __type, e.g., for GraphiQL.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graphql.Request true "Query, operation name and variables"
+// @Success 200 {object} graphql.Response "Data and errors of the query"
+// @Failure 400 {object} ProblemDetails "Invalid request body"
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err, &req))
+		return
+	}
+
+	ctx := withActivityLoader(c.Request.Context(), h.activities)
+	response := h.schema.Execute(ctx, req)
+	for _, gqlErr := range response.Errors {
+		presentGraphQLError(ctx, gqlErr)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSchema godoc
+// @Summary Get the GraphQL schema
+// @Description Returns the schema of the GraphQL endpoint in the GraphQL schema definition language
+// @Tags graphql
+// @Produce plain
+// @Success 200 {string} string "Schema definition"
+// @Router /api/v1/graphql/schema [get]
+func (h *GraphQLHandler) GetSchema(c *gin.Context) {
+	c.String(http.StatusOK, h.schema.String())
+}
+
+// presentGraphQLError rewrites the error returned by a resolver as the API would report it, hiding
+// the details of internal errors, which are logged; Errors of the query itself are left as they are
+func presentGraphQLError(ctx context.Context, gqlErr *graphql.Error) {
+	err := errors.Unwrap(gqlErr)
+	if err == nil {
+		return
+	}
+
+	problem := NewProblemDetails(err)
+	gqlErr.Message = problem.Detail
+	gqlErr.Extensions = map[string]any{"status": problem.Status}
+	if len(problem.Errors) > 0 {
+		gqlErr.Extensions["errors"] = problem.Errors
+	}
+	if problem.Status >= http.StatusInternalServerError {
+		logging.FromContext(ctx).Log(ctx, slog.LevelError, "graphql field failed",
+			"path", gqlErr.Path,
+			"status", problem.Status,
+			"error", err.Error(),
+		)
+	}
+}
+
+// activityLoaderKey carries the activity loader of a GraphQL request in its context
+type activityLoaderKey struct{}
+
+// withActivityLoader returns a context carrying a loader of the activities of a request, with their intervals
+func withActivityLoader(ctx context.Context, activities app.ActivityService) context.Context {
+	loader := graphql.NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]entity.Activity, error) {
+		loaded, err := activities.GetActivitiesByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uuid.UUID]entity.Activity, len(loaded))
+		for _, activity := range loaded {
+			byID[activity.ID] = activity
+		}
+		return byID, nil
+	})
+	return context.WithValue(ctx, activityLoaderKey{}, loader)
+}
+
+// loadActivity returns a thunk resolving to the activity with its intervals, loaded along with the others of the request
+func loadActivity(ctx context.Context, id uuid.UUID) graphql.Thunk {
+	return ctx.Value(activityLoaderKey{}).(*graphql.Loader[uuid.UUID, entity.Activity]).Load(ctx, id)
+}
+
+// graphQLDateTime is a timestamp, serialized in RFC 3339; The zero time is null
+var graphQLDateTime = &graphql.Scalar{
+	Name:        "DateTime",
+	Description: `An RFC 3339 timestamp, e.g., "2026-10-01T07:00:00Z".`,
+	Serialize: func(value any) (any, error) {
+		t, ok := value.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("DateTime cannot represent %T value %v", value, value)
+		}
+		if t.IsZero() {
+			return nil, nil
+		}
+		return t.Format(time.RFC3339Nano), nil
+	},
+	Parse: func(value any) (any, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("DateTime cannot represent value %v", value)
+		}
+		return time.Parse(time.RFC3339, s)
+	},
+}
+
+// optional returns nil for the zero value, like the fields of the REST API omitted when empty
+func optional[T comparable](value T) any {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return value
+}
+
+// parseGraphQLID parses the ID argument of a field
+func parseGraphQLID(args map[string]any) (uuid.UUID, error) {
+	id, err := uuid.Parse(args["id"].(string))
+	if err != nil {
+		return uuid.Nil, invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID")
+	}
+	return id, nil
+}
+
+// newGraphQLQuery returns the Query root of the schema, whose types mirror domain.User, entity.Activity and entity.Interval
+func newGraphQLQuery(users app.UserService, activities app.ActivityService) *graphql.Object {
+	nonNull := func(t graphql.Type) graphql.Type { return &graphql.NonNull{Of: t} }
+	listOf := func(t graphql.Type) graphql.Type { return &graphql.List{Of: &graphql.NonNull{Of: t}} }
+
+	timeInZone := &graphql.Object{Name: "TimeInZone", Description: "Time spent in a heart-rate zone.", Fields: []*graphql.Field{
+		graphql.Property("zone", nonNull(graphql.Int), func(z entity.TimeInZone) any { return z.Zone }),
+		graphql.Property("name", nonNull(graphql.String), func(z entity.TimeInZone) any { return z.Name }),
+		graphql.Property("duration", nonNull(graphql.String), func(z entity.TimeInZone) any { return z.Duration }),
+	}}
+
+	interval := &graphql.Object{Name: "Interval", Description: "A segment of a swim session.", Fields: []*graphql.Field{
+		graphql.Property("id", nonNull(graphql.ID), func(i entity.Interval) any { return i.ID }),
+		graphql.Property("activityId", nonNull(graphql.ID), func(i entity.Interval) any { return i.ActivityID }),
+		graphql.Property("duration", nonNull(graphql.String), func(i entity.Interval) any { return i.Duration }),
+		graphql.Property("distance", nonNull(graphql.Float), func(i entity.Interval) any { return i.Distance }),
+		graphql.Property("type", nonNull(graphql.String), func(i entity.Interval) any { return i.Type }),
+		graphql.Property("stroke", nonNull(graphql.String), func(i entity.Interval) any { return i.Stroke }),
+		graphql.Property("notes", nonNull(graphql.String), func(i entity.Interval) any { return i.Notes }),
+		graphql.Property("heartRateAvg", graphql.Int, func(i entity.Interval) any { return optional(i.HeartRateAvg) }),
+		graphql.Property("pacePer100m", graphql.String, func(i entity.Interval) any { return optional(i.PacePer100m) }),
+		graphql.Property("paceZone", graphql.Int, func(i entity.Interval) any { return optional(i.PaceZone) }),
+		graphql.Property("paceZoneName", graphql.String, func(i entity.Interval) any { return optional(i.PaceZoneName) }),
+		graphql.Property("strokeCount", graphql.Int, func(i entity.Interval) any { return optional(i.StrokeCount) }),
+		graphql.Property("lengthStrokeCounts", listOf(graphql.Int), func(i entity.Interval) any { return i.LengthStrokeCounts }),
+		graphql.Property("swolf", graphql.Float, func(i entity.Interval) any { return optional(i.SWOLF) }),
+		graphql.Property("distancePerStroke", graphql.Float, func(i entity.Interval) any { return optional(i.DistancePerStroke) }),
+		graphql.Property("createdAt", graphQLDateTime, func(i entity.Interval) any { return i.CreatedAt }),
+		graphql.Property("updatedAt", graphQLDateTime, func(i entity.Interval) any { return i.UpdatedAt }),
+	}}
+
+	// loaded resolves a field of an activity from its intervals, loaded with those of the other activities of the request
+	loaded := func(name string, typ graphql.Type, get func(entity.Activity) any) *graphql.Field {
+		return &graphql.Field{
+			Name: name,
+			Type: typ,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return graphql.Then(loadActivity(ctx, source.(entity.Activity).ID), get), nil
+			},
+		}
+	}
+	activity := &graphql.Object{Name: "Activity", Description: "A swimming activity or session.", Fields: []*graphql.Field{
+		graphql.Property("id", nonNull(graphql.ID), func(a entity.Activity) any { return a.ID }),
+		graphql.Property("userId", nonNull(graphql.ID), func(a entity.Activity) any { return a.UserID }),
+		graphql.Property("date", nonNull(graphql.String), func(a entity.Activity) any { return a.Date }),
+		graphql.Property("start", graphQLDateTime, func(a entity.Activity) any { return a.Start }),
+		graphql.Property("duration", nonNull(graphql.String), func(a entity.Activity) any { return a.Duration }),
+		graphql.Property("distance", nonNull(graphql.Float), func(a entity.Activity) any { return a.Distance }),
+		graphql.Property("laps", nonNull(graphql.Int), func(a entity.Activity) any { return a.Laps }),
+		graphql.Property("poolSize", nonNull(graphql.Float), func(a entity.Activity) any { return a.PoolSize }),
+		graphql.Property("poolUnit", nonNull(graphql.String), func(a entity.Activity) any { return a.PoolUnit }),
+		graphql.Property("course", graphql.String, func(a entity.Activity) any { return optional(a.Course) }),
+		graphql.Property("distanceUnit", nonNull(graphql.String), func(a entity.Activity) any { return a.DistanceUnit }),
+		graphql.Property("locationType", nonNull(graphql.String), func(a entity.Activity) any { return a.LocationType }),
+		graphql.Property("locationName", graphql.String, func(a entity.Activity) any { return optional(a.LocationName) }),
+		graphql.Property("feeling", graphql.String, func(a entity.Activity) any { return optional(a.Feeling) }),
+		graphql.Property("heartRateAvg", graphql.Int, func(a entity.Activity) any { return optional(a.HeartRateAvg) }),
+		graphql.Property("heartRateMax", graphql.Int, func(a entity.Activity) any { return optional(a.HeartRateMax) }),
+		graphql.Property("rpe", graphql.Int, func(a entity.Activity) any { return optional(a.RPE) }),
+		graphql.Property("avgPacePer100m", graphql.String, func(a entity.Activity) any { return optional(a.AvgPacePer100m) }),
+		graphql.Property("notes", nonNull(graphql.String), func(a entity.Activity) any { return a.Notes }),
+		loaded("intervals", nonNull(listOf(interval)), func(a entity.Activity) any { return a.Intervals }),
+		loaded("timeInZone", listOf(timeInZone), func(a entity.Activity) any { return a.TimeInZone }),
+		loaded("strokeCount", graphql.Int, func(a entity.Activity) any { return optional(a.StrokeCount) }),
+		loaded("swolf", graphql.Float, func(a entity.Activity) any { return optional(a.SWOLF) }),
+		loaded("distancePerStroke", graphql.Float, func(a entity.Activity) any { return optional(a.DistancePerStroke) }),
+		graphql.Property("version", nonNull(graphql.Int), func(a entity.Activity) any { return a.Version }),
+		graphql.Property("createdAt", graphQLDateTime, func(a entity.Activity) any { return a.CreatedAt }),
+		graphql.Property("updatedAt", graphQLDateTime, func(a entity.Activity) any { return a.UpdatedAt }),
+	}}
+
+	user := &graphql.Object{Name: "User", Description: "A swimmer.", Fields: []*graphql.Field{
+		graphql.Property("id", nonNull(graphql.ID), func(u domain.User) any { return u.ID }),
+		graphql.Property("name", nonNull(graphql.String), func(u domain.User) any { return u.Name }),
+		graphql.Property("email", nonNull(graphql.String), func(u domain.User) any { return u.Email }),
+		graphql.Property("city", nonNull(graphql.String), func(u domain.User) any { return u.City }),
+		graphql.Property("phone", nonNull(graphql.String), func(u domain.User) any { return u.Phone }),
+		graphql.Property("age", nonNull(graphql.Int), func(u domain.User) any { return u.Age }),
+		graphql.Property("height", nonNull(graphql.Int), func(u domain.User) any { return u.Height }),
+		graphql.Property("weight", nonNull(graphql.Float), func(u domain.User) any { return u.Weight }),
+		graphql.Property("maxHeartRate", graphql.Int, func(u domain.User) any { return optional(u.MaxHeartRate) }),
+		graphql.Property("restingHeartRate", graphql.Int, func(u domain.User) any { return optional(u.RestingHeartRate) }),
+		graphql.Property("lactateThresholdHeartRate", graphql.Int, func(u domain.User) any { return optional(u.LactateThresholdHeartRate) }),
+		graphql.Property("displayUnit", nonNull(graphql.String), func(u domain.User) any { return u.DisplayUnit.OrDefault() }),
+		graphql.Property("version", nonNull(graphql.Int), func(u domain.User) any { return u.Version }),
+		graphql.Property("createdAt", graphQLDateTime, func(u domain.User) any { return u.CreatedAt }),
+		graphql.Property("updatedAt", graphQLDateTime, func(u domain.User) any { return u.UpdatedAt }),
+		{
+			Name:        "activities",
+			Description: fmt.Sprintf("The most recent activities of the user, latest first, up to limit (at most %d).", maxGraphQLActivities),
+			Type:        nonNull(listOf(activity)),
+			Args:        []*graphql.Argument{{Name: "limit", Type: graphql.Int, Default: defaultGraphQLActivities}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				limit, _ := args["limit"].(int)
+				if limit < 1 || limit > maxGraphQLActivities {
+					return nil, invalidParam("limit", domain.CodeOutOfRange, fmt.Sprintf("must be between 1 and %d", maxGraphQLActivities))
+				}
+				return activities.GetRecentActivitiesByUser(ctx, source.(domain.User).ID, limit)
+			},
+		},
+	}}
+
+	return &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{
+			Name:        "user",
+			Description: "The user with the ID, or null if there is none.",
+			Type:        user,
+			Args:        []*graphql.Argument{{Name: "id", Type: nonNull(graphql.ID)}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id, err := parseGraphQLID(args)
+				if err != nil {
+					return nil, err
+				}
+				found, err := users.GetUserByID(ctx, id)
+				if errors.Is(err, domain.ErrNotFound) {
+					return nil, nil
+				}
+				return found, err
+			},
+		},
+		{
+			Name:        "activity",
+			Description: "The activity with the ID, or null if there is none.",
+			Type:        activity,
+			Args:        []*graphql.Argument{{Name: "id", Type: nonNull(graphql.ID)}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id, err := parseGraphQLID(args)
+				if err != nil {
+					return nil, err
+				}
+				return loadActivity(ctx, id), nil
+			},
+		},
+	}}
+}
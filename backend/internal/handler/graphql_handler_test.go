@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() (*gin.Engine, *MockUserService, *MockActivityService) {
+		users, activities := new(MockUserService), new(MockActivityService)
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.POST("/graphql", NewGraphQLHandler(users, activities).Query)
+		return router, users, activities
+	}
+	query := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	userID := uuid.New()
+	user := domain.User{ID: userID, Name: "Livia", Email: "livia@example.com", DisplayUnit: domain.DistanceUnitYards, CreatedAt: time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)}
+
+	t.Run("user with the intervals of the recent activities in one load", func(t *testing.T) {
+		router, users, activities := newRouter()
+		users.On("GetUserByID", userID).Return(user, nil)
+
+		var recent, full []entity.Activity
+		var ids []uuid.UUID
+		for i := range 10 {
+			activity := entity.Activity{ID: uuid.New(), UserID: userID, Distance: float64(1000 + i)}
+			recent = append(recent, activity)
+			ids = append(ids, activity.ID)
+			activity.Intervals = []entity.Interval{{ActivityID: activity.ID, Distance: 100, Stroke: domain.StrokeFreestyle, Duration: domain.Duration(90 * time.Second)}}
+			full = append(full, activity)
+		}
+		activities.On("GetRecentActivitiesByUser", userID, 10).Return(recent, nil).Once()
+		activities.On("GetActivitiesByIDs", ids).Return(full, nil).Once()
+
+		resp := query(router, `{
+			"query": "query ($id: ID!) { user(id: $id) { name displayUnit createdAt activities { distance intervals { distance stroke duration } } } }",
+			"variables": {"id": "`+userID.String()+`"}
+		}`)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var response struct {
+			Data struct {
+				User struct {
+					Name        string
+					DisplayUnit string
+					CreatedAt   string
+					Activities  []struct {
+						Distance  float64
+						Intervals []map[string]any
+					}
+				}
+			}
+			Errors []any
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+		assert.Empty(t, response.Errors)
+		assert.Equal(t, "Livia", response.Data.User.Name)
+		assert.Equal(t, "yards", response.Data.User.DisplayUnit)
+		assert.Equal(t, "2026-10-01T07:00:00Z", response.Data.User.CreatedAt)
+		require.Len(t, response.Data.User.Activities, 10)
+		assert.Equal(t, 1009.0, response.Data.User.Activities[9].Distance)
+		assert.Equal(t, []map[string]any{{"distance": 100.0, "stroke": "freestyle", "duration": "1m30s"}}, response.Data.User.Activities[0].Intervals)
+		activities.AssertNumberOfCalls(t, "GetActivitiesByIDs", 1)
+	})
+
+	t.Run("missing user and activity are null", func(t *testing.T) {
+		router, users, activities := newRouter()
+		activityID := uuid.New()
+		users.On("GetUserByID", userID).Return(domain.User{}, domain.NewNotFoundError("user not found"))
+		activities.On("GetActivitiesByIDs", []uuid.UUID{activityID}).Return([]entity.Activity{}, nil)
+
+		resp := query(router, `{"query": "{ user(id: \"`+userID.String()+`\") { name } activity(id: \"`+activityID.String()+`\") { notes } }"}`)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"data":{"user":null,"activity":null}}`, resp.Body.String())
+	})
+
+	t.Run("resolver errors are reported like the REST API", func(t *testing.T) {
+		router, users, activities := newRouter()
+		users.On("GetUserByID", userID).Return(user, nil)
+		activities.On("GetRecentActivitiesByUser", userID, 5).Return([]entity.Activity{}, errors.New("connection refused"))
+
+		resp := query(router, `{"query": "{ user(id: \"`+userID.String()+`\") { name many: activities(limit: 500) { id } some: activities(limit: 5) { id } } byName: user(id: \"livia\") { name } }"}`)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var response struct {
+			Data   map[string]any
+			Errors []struct {
+				Message    string
+				Path       []any
+				Extensions map[string]any
+			}
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+		assert.Equal(t, map[string]any{"user": nil, "byName": nil}, response.Data)
+		require.Len(t, response.Errors, 3)
+
+		assert.Equal(t, []any{"user", "many"}, response.Errors[0].Path)
+		assert.Equal(t, 400.0, response.Errors[0].Extensions["status"])
+		assert.Contains(t, response.Errors[0].Message, "limit")
+		assert.Equal(t, []any{"user", "some"}, response.Errors[1].Path)
+		assert.Equal(t, "an unexpected error occurred", response.Errors[1].Message)
+		assert.Equal(t, 500.0, response.Errors[1].Extensions["status"])
+		assert.Equal(t, []any{"byName"}, response.Errors[2].Path)
+		assert.Equal(t, 400.0, response.Errors[2].Extensions["status"])
+	})
+
+	t.Run("query errors", func(t *testing.T) {
+		router, _, _ := newRouter()
+		resp := query(router, `{"query": "{ user(id: \"1\") { password } }"}`)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"errors":[{"message":"Cannot query field \"password\" on type \"User\".","locations":[{"line":1,"column":19}]}]}`, resp.Body.String())
+	})
+
+	t.Run("too many aliases", func(t *testing.T) {
+		router, users, _ := newRouter()
+		var selections []string
+		for i := range graphQLMaxAliases + 1 {
+			selections = append(selections, fmt.Sprintf(`u%d: user(id: \"%s\") { name }`, i, userID))
+		}
+		resp := query(router, `{"query": "{ `+strings.Join(selections, " ")+` }"}`)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"errors":[{"message":"Query has more than the maximum of 20 aliases.","locations":[{"line":1,"column":1}]}]}`, resp.Body.String())
+		users.AssertNotCalled(t, "GetUserByID", mock.Anything)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		router, _, _ := newRouter()
+		resp := query(router, `{"variables": {}}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, ProblemContentType, resp.Header().Get("Content-Type"))
+	})
+}
+
+func TestGraphQLSchema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/graphql/schema", NewGraphQLHandler(new(MockUserService), new(MockActivityService)).GetSchema)
+
+	req, _ := http.NewRequest(http.MethodGet, "/graphql/schema", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "type Query {\n  \"The user with the ID, or null if there is none.\"\n  user(id: ID!): User\n")
+	assert.Contains(t, resp.Body.String(), "  activities(limit: Int = 10): [Activity!]!\n")
+	assert.Contains(t, resp.Body.String(), "  intervals: [Interval!]!\n")
+	assert.Contains(t, resp.Body.String(), "scalar DateTime\n")
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+)
+
+// HealthHandler handles the liveness and readiness probes of the orchestrator
+type HealthHandler struct {
+	service app.HealthService
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(service app.HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// Healthz godoc
+// @Summary Liveness probe
+// @Description Reports that the process is up; it does not check its dependencies, so a failing database does not get the API restarted
+// @Tags health
+// @Produce json
+// @Success 200 {object} StatusResponse "The API is alive"
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, StatusResponse{Status: "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Pings the database and checks that its schema has been migrated to the version this build expects
+// @Tags health
+// @Produce json
+// @Success 200 {object} entity.Readiness "The API is ready to serve requests"
+// @Failure 503 {object} entity.Readiness "A check failed"
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	readiness := h.service.CheckReadiness(c.Request.Context())
+	if !readiness.Ready() {
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+	c.JSON(http.StatusOK, readiness)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHealthService is a mock implementation of app.HealthService
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) CheckReadiness(ctx context.Context) entity.Readiness {
+	args := m.Called()
+	return args.Get(0).(entity.Readiness)
+}
+
+func (m *MockHealthService) DatabaseStats() sql.DBStats {
+	args := m.Called()
+	return args.Get(0).(sql.DBStats)
+}
+
+func TestHealthzHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewHealthHandler(new(MockHealthService))
+
+	router := gin.Default()
+	router.GET("/healthz", handler.Healthz)
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, resp.Body.String())
+}
+
+func TestReadyzHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("ready", func(t *testing.T) {
+		mockService := new(MockHealthService)
+		handler := NewHealthHandler(mockService)
+		router := gin.Default()
+		router.GET("/readyz", handler.Readyz)
+
+		mockService.On("CheckReadiness").Return(entity.Readiness{
+			Status: entity.ReadinessReady,
+			Checks: []entity.HealthCheck{{Name: "database", Status: entity.HealthCheckOK}},
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"status":"ready"`)
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		mockService := new(MockHealthService)
+		handler := NewHealthHandler(mockService)
+		router := gin.Default()
+		router.GET("/readyz", handler.Readyz)
+
+		mockService.On("CheckReadiness").Return(entity.Readiness{
+			Status: entity.ReadinessUnavailable,
+			Checks: []entity.HealthCheck{{Name: "database", Status: entity.HealthCheckFailed, Error: entity.HealthCheckFailedMessage}},
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"status":"failed"`)
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// HeartRateHandler handles HTTP requests related to heart-rate zones
+type HeartRateHandler struct {
+	service app.HeartRateService
+}
+
+// NewHeartRateHandler creates a new HeartRateHandler
+func NewHeartRateHandler(service app.HeartRateService) *HeartRateHandler {
+	return &HeartRateHandler{service: service}
+}
+
+// GetHeartRateZones godoc
+// @Summary Get the heart-rate zones of a user
+// @Description Returns the five heart-rate zones of the user, derived from the lactate threshold heart rate, the maximum heart rate or the age, in this order
+// @Tags heart-rate
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} entity.HeartRateZones
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Router /api/v1/users/{id}/heart-rate-zones [get]
+func (h *HeartRateHandler) GetHeartRateZones(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	zones, err := h.service.GetHeartRateZones(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, zones)
+}
+
+// GetTimeInZone godoc
+// @Summary Get the time a user spent in each heart-rate zone
+// @Description Aggregates the time spent in each heart-rate zone over a period, at interval resolution when intervals have heart-rate data; the period defaults to the last 28 days
+// @Tags heart-rate
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param from query string false "First day of the period (YYYY-MM-DD)"
+// @Param to query string false "Last day of the period (YYYY-MM-DD)"
+// @Success 200 {object} entity.TimeInZoneSummary
+// @Failure 400 {object} ProblemDetails "Invalid user ID or period"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/time-in-zone [get]
+func (h *HeartRateHandler) GetTimeInZone(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	summary, err := h.service.GetTimeInZone(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
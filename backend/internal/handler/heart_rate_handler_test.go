@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHeartRateService is a mock implementation of app.HeartRateService
+type MockHeartRateService struct {
+	mock.Mock
+}
+
+func (m *MockHeartRateService) GetHeartRateZones(ctx context.Context, userID uuid.UUID) (entity.HeartRateZones, error) {
+	args := m.Called(userID)
+	return args.Get(0).(entity.HeartRateZones), args.Error(1)
+}
+
+func (m *MockHeartRateService) GetTimeInZone(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.TimeInZoneSummary, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).(entity.TimeInZoneSummary), args.Error(1)
+}
+
+func TestGetHeartRateZonesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockHeartRateService)
+	handler := NewHeartRateHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/heart-rate-zones", handler.GetHeartRateZones)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetHeartRateZones", userID).Return(entity.HeartRateZones{
+			UserID: userID,
+			Basis:  "age",
+			Zones:  []entity.HeartRateZone{{Number: 1, Name: "recovery", MaxHeartRate: 114}},
+		}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/heart-rate-zones", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "recovery")
+	})
+
+	t.Run("invalid UUID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/not-a-uuid/heart-rate-zones", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetHeartRateZones", userID).Return(entity.HeartRateZones{}, domain.ErrNotFound)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/heart-rate-zones", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestGetTimeInZoneHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockHeartRateService)
+	handler := NewHeartRateHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/time-in-zone", handler.GetTimeInZone)
+
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 10, 28, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetTimeInZone", userID, from, to).Return(entity.TimeInZoneSummary{
+			UserID:     userID,
+			Activities: 3,
+			TimeInZone: []entity.TimeInZone{{Zone: 2, Name: "aerobic", Duration: domain.MustParseDuration("1h30m")}},
+		}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/time-in-zone?from=2023-10-01&to=2023-10-28", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "1h30m0s")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.New().String()+"/time-in-zone?from=2023-10-28&to=2023-10-01", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetTimeInZone", userID, from, to).Return(entity.TimeInZoneSummary{}, errors.New("db error"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/time-in-zone?from=2023-10-01&to=2023-10-28", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+}
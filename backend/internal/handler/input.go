@@ -11,9 +11,57 @@ type CreateUserRequest struct {
 	Email  string  `json:"email" binding:"required,email"`
 	City   string  `json:"city" binding:"required"`
 	Phone  string  `json:"phone" binding:"required"`
-	Age    int     `json:"age"`
-	Height int     `json:"height"`
-	Weight float64 `json:"weight"`
+	Age    int     `json:"age" binding:"omitempty,min=1,max=120"`
+	Height int     `json:"height" binding:"omitempty,min=50,max=250"`
+	Weight float64 `json:"weight" binding:"omitempty,gt=0,max=400"`
+	// Optional maximum heart rate in bpm, used for heart-rate based training load
+	MaxHeartRate int `json:"max_heart_rate,omitempty" binding:"omitempty,min=30,max=250"`
+	// Optional resting heart rate in bpm, used for heart-rate based training load
+	RestingHeartRate int `json:"resting_heart_rate,omitempty" binding:"omitempty,min=30,max=250"`
+	// Optional lactate threshold heart rate in bpm, preferred for heart-rate zones
+	LactateThresholdHeartRate int `json:"lactate_threshold_heart_rate,omitempty" binding:"omitempty,min=30,max=250"`
+	// Optional unit distances and paces are displayed in, "meters" (default) or "yards"
+	DisplayUnit domain.DistanceUnit `json:"display_unit,omitempty" binding:"omitempty,enum"`
+}
+
+// UpdateUserRequest represents the request body for updating a user; The optional
+// fields left out keep their stored value
+type UpdateUserRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Email  string   `json:"email" binding:"required,email"`
+	City   string   `json:"city" binding:"required"`
+	Phone  string   `json:"phone" binding:"required"`
+	Age    *int     `json:"age,omitempty" binding:"omitnil,min=1,max=120"`
+	Height *int     `json:"height,omitempty" binding:"omitnil,min=50,max=250"`
+	Weight *float64 `json:"weight,omitempty" binding:"omitnil,gt=0,max=400"`
+	// Optional maximum heart rate in bpm, used for heart-rate based training load
+	MaxHeartRate *int `json:"max_heart_rate,omitempty" binding:"omitnil,min=30,max=250"`
+	// Optional resting heart rate in bpm, used for heart-rate based training load
+	RestingHeartRate *int `json:"resting_heart_rate,omitempty" binding:"omitnil,min=30,max=250"`
+	// Optional lactate threshold heart rate in bpm, preferred for heart-rate zones
+	LactateThresholdHeartRate *int `json:"lactate_threshold_heart_rate,omitempty" binding:"omitnil,min=30,max=250"`
+	// Optional unit distances and paces are displayed in, "meters" or "yards"
+	DisplayUnit *domain.DistanceUnit `json:"display_unit,omitempty" binding:"omitnil,enum"`
+}
+
+// applyTo returns the user with the fields of the request applied
+func (r UpdateUserRequest) applyTo(user domain.User) domain.User {
+	user.Name, user.Email, user.City, user.Phone = r.Name, r.Email, r.City, r.Phone
+	setIfPresent(&user.Age, r.Age)
+	setIfPresent(&user.Height, r.Height)
+	setIfPresent(&user.Weight, r.Weight)
+	setIfPresent(&user.MaxHeartRate, r.MaxHeartRate)
+	setIfPresent(&user.RestingHeartRate, r.RestingHeartRate)
+	setIfPresent(&user.LactateThresholdHeartRate, r.LactateThresholdHeartRate)
+	setIfPresent(&user.DisplayUnit, r.DisplayUnit)
+	return user
+}
+
+// setIfPresent sets field to the value of an optional request field, if it was sent
+func setIfPresent[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
 }
 
 // CreateActivityRequest represents the request body for creating a new activity
@@ -24,24 +72,61 @@ type CreateActivityRequest struct {
 	Date string `json:"date" binding:"required"`
 	// Start time of the activity
 	// Start time.Time `json:"start"` // TODO - must implement format handling
-	// Duration of the activity in a string format, e.g., "1h30m"
-	Duration domain.DurationString `json:"duration" binding:"required"`
-	// Total distance in meters
-	Distance float64 `json:"distance" binding:"required"`
+	// Duration of the activity in swim notation, e.g., "1:30:00", or Go syntax, e.g., "1h30m"
+	Duration domain.Duration `json:"duration" binding:"required" swaggertype:"string"`
+	// Total distance in PoolUnit, stored in meters
+	Distance float64 `json:"distance" binding:"required,gt=0"`
 	// Number of pool laps
 	Laps int `json:"laps" binding:"required"`
-	// Pool size in meters (0 if open water)
+	// Pool size in PoolUnit (0 if open water)
 	PoolSize float64 `json:"pool_size" binding:"required"`
+	// Optional unit of the pool size, "meters" (default) or "yards"
+	PoolUnit domain.DistanceUnit `json:"pool_unit,omitempty" binding:"omitempty,enum"`
 	// "pool" or "open_water"
-	LocationType domain.LocationType `json:"location_type" binding:"required"`
+	LocationType domain.LocationType `json:"location_type" binding:"required,enum"`
 	// Optional name for the location, e.g., "CEPE"
 	LocationName string `json:"location_name,omitempty"`
 	// Optional feeling after the swim, e.g., "tired"
-	Feeling domain.FeelingType `json:"feeling,omitempty"`
+	Feeling domain.FeelingType `json:"feeling,omitempty" binding:"omitempty,enum"`
 	// Average heart rate during the activity
-	HeartRateAvg int `json:"heart_rate_avg,omitempty"`
+	HeartRateAvg int `json:"heart_rate_avg,omitempty" binding:"omitempty,min=30,max=250"`
 	// Maximum heart rate during the activity
-	HeartRateMax int `json:"heart_rate_max,omitempty"`
+	HeartRateMax int `json:"heart_rate_max,omitempty" binding:"omitempty,min=30,max=250"`
+	// Optional rating of perceived exertion, from 1 (very easy) to 10 (maximal)
+	RPE int `json:"rpe,omitempty" binding:"omitempty,min=1,max=10"`
+	// Optional notes
+	Notes string `json:"notes"`
+}
+
+// UpdateActivityRequest represents the request body for replacing an activity; its
+// user cannot be changed
+type UpdateActivityRequest struct {
+	// Date in ISO 8601 format, e.g., "2023-10-01"
+	Date string `json:"date" binding:"required"`
+	// Start time of the activity
+	// Start time.Time `json:"start"` // TODO - must implement format handling
+	// Duration of the activity in swim notation, e.g., "1:30:00", or Go syntax, e.g., "1h30m"
+	Duration domain.Duration `json:"duration" binding:"required" swaggertype:"string"`
+	// Total distance in PoolUnit, stored in meters
+	Distance float64 `json:"distance" binding:"required,gt=0"`
+	// Number of pool laps
+	Laps int `json:"laps" binding:"required"`
+	// Pool size in PoolUnit (0 if open water)
+	PoolSize float64 `json:"pool_size" binding:"required"`
+	// Optional unit of the pool size, "meters" (default) or "yards"
+	PoolUnit domain.DistanceUnit `json:"pool_unit,omitempty" binding:"omitempty,enum"`
+	// "pool" or "open_water"
+	LocationType domain.LocationType `json:"location_type" binding:"required,enum"`
+	// Optional name for the location, e.g., "CEPE"
+	LocationName string `json:"location_name,omitempty"`
+	// Optional feeling after the swim, e.g., "tired"
+	Feeling domain.FeelingType `json:"feeling,omitempty" binding:"omitempty,enum"`
+	// Average heart rate during the activity
+	HeartRateAvg int `json:"heart_rate_avg,omitempty" binding:"omitempty,min=30,max=250"`
+	// Maximum heart rate during the activity
+	HeartRateMax int `json:"heart_rate_max,omitempty" binding:"omitempty,min=30,max=250"`
+	// Optional rating of perceived exertion, from 1 (very easy) to 10 (maximal)
+	RPE int `json:"rpe,omitempty" binding:"omitempty,min=1,max=10"`
 	// Optional notes
 	Notes string `json:"notes"`
 }
@@ -56,14 +141,39 @@ type GetActivitiesByUserRequest struct {
 type CreateIntervalRequest struct {
 	// ActivityID is the ID of the associated activity/session
 	ActivityID uuid.UUID `json:"activity_id" binding:"required"`
-	// Duration of the interval in string format, e.g., "1h30m"
-	Duration domain.DurationString `json:"duration" binding:"required"`
-	// Distance in meters
-	Distance float64 `json:"distance" binding:"required"`
+	// Duration of the interval in swim notation, e.g., "1:45.32", or Go syntax, e.g., "1m45.32s"
+	Duration domain.Duration `json:"duration" binding:"required" swaggertype:"string"`
+	// Distance in DistanceUnit, stored in meters; may only be 0 for rest intervals
+	Distance float64 `json:"distance" binding:"required_unless=Type rest,min=0"`
+	// Optional unit of the distance, usually the pool unit of the activity, "meters" (default) or "yards"
+	DistanceUnit domain.DistanceUnit `json:"distance_unit,omitempty" binding:"omitempty,enum"`
 	// Type is one of the predefined interval types like "swim", "rest", etc.
-	Type domain.IntervalType `json:"type" binding:"required"`
+	Type domain.IntervalType `json:"type" binding:"required,enum"`
 	// Stroke is the swimming stroke type like "freestyle", "backstroke", etc.
-	Stroke domain.StrokeType `json:"stroke" binding:"required"`
+	Stroke domain.StrokeType `json:"stroke" binding:"required,enum"`
 	// Notes are optional remarks such as "felt strong", "used fins"
 	Notes string `json:"notes"`
+	// HeartRateAvg is the optional average heart rate during the interval
+	HeartRateAvg int `json:"heart_rate_avg,omitempty" binding:"omitempty,min=30,max=250"`
+	// StrokeCount is the optional total number of strokes taken during the interval
+	StrokeCount int `json:"stroke_count,omitempty" binding:"omitempty,min=0"`
+	// LengthStrokeCounts are the optional stroke counts of each pool length
+	LengthStrokeCounts []int `json:"length_stroke_counts,omitempty" binding:"omitempty,dive,min=0"`
+}
+
+// CreateCSSTestRequest represents the request body for recording a CSS test;
+// If no interval is given, the trials are detected among the user's logged intervals
+type CreateCSSTestRequest struct {
+	// Interval400ID is the ID of the logged interval used as the 400m trial
+	Interval400ID uuid.UUID `json:"interval_400_id"`
+	// Interval200ID is the ID of the logged interval used as the 200m trial
+	Interval200ID uuid.UUID `json:"interval_200_id"`
+}
+
+// CreateWebhookRequest represents the request body for registering a webhook
+type CreateWebhookRequest struct {
+	// URL is the http or https endpoint the events are posted to
+	URL string `json:"url" binding:"required,url"`
+	// Events to subscribe to: "activity.created", "activity.updated", "activity.deleted" or "record.achieved"
+	Events []domain.WebhookEvent `json:"events" binding:"required,min=1,dive,enum"`
 }
@@ -26,28 +26,31 @@ func NewIntervalHandler(s app.IntervalService) *IntervalHandler {
 // @Produce json
 // @Param interval body handler.CreateIntervalRequest true "Interval data"
 // @Success 201 {object} domain.Interval "Interval successfully created"
-// @Failure 400 {object} ErrorResponse "Invalid input"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /intervals [post]
+// @Failure 400 {object} ProblemDetails "Invalid input"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/intervals [post]
 func (h *IntervalHandler) CreateInterval(c *gin.Context) {
 	var req CreateIntervalRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid JSON or missing required fields"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err, &req))
 		return
 	}
 
 	interval := domain.Interval{
-		ID:         uuid.New(),
-		ActivityID: req.ActivityID,
-		Duration:   req.Duration,
-		Distance:   req.Distance,
-		Type:       req.Type,
-		Stroke:     req.Stroke,
-		Notes:      req.Notes,
+		ID:                 uuid.New(),
+		ActivityID:         req.ActivityID,
+		Duration:           req.Duration,
+		Distance:           domain.Distance{Value: req.Distance, Unit: req.DistanceUnit}.Meters(),
+		Type:               req.Type,
+		Stroke:             req.Stroke,
+		Notes:              req.Notes,
+		HeartRateAvg:       req.HeartRateAvg,
+		StrokeCount:        req.StrokeCount,
+		LengthStrokeCounts: req.LengthStrokeCounts,
 	}
 
-	if err := h.service.CreateInterval(interval); err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: "Service error"})
+	if err := h.service.CreateInterval(c.Request.Context(), interval); err != nil {
+		c.Error(err)
 		return
 	}
 
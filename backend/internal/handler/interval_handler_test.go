@@ -2,8 +2,10 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -21,12 +23,12 @@ type MockIntervalService struct {
 	mock.Mock
 }
 
-func (m *MockIntervalService) CreateInterval(interval domain.Interval) error {
+func (m *MockIntervalService) CreateInterval(ctx context.Context, interval domain.Interval) error {
 	args := m.Called(interval)
 	return args.Error(0)
 }
 
-func (m *MockIntervalService) GetIntervalsByActivity(activityID uuid.UUID) ([]domain.Interval, error) {
+func (m *MockIntervalService) GetIntervalsByActivity(ctx context.Context, activityID uuid.UUID) ([]domain.Interval, error) {
 	args := m.Called(activityID)
 	if raw := args.Get(0); raw != nil {
 		return raw.([]domain.Interval), args.Error(1)
@@ -40,12 +42,13 @@ func TestCreateInterval(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(ErrorHandler())
 	router.POST("/intervals", handler.CreateInterval)
 
 	t.Run("success", func(t *testing.T) {
 		newIntervalReq := CreateIntervalRequest{
 			ActivityID: uuid.New(),
-			Duration:   domain.DurationString((30 * time.Minute).String()),
+			Duration:   domain.Duration(30 * time.Minute),
 			Distance:   1000,
 			Type:       domain.IntervalType("swim"),
 			Stroke:     domain.StrokeType("freestyle"),
@@ -83,10 +86,81 @@ func TestCreateInterval(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
 	})
 
+	t.Run("with stroke counts", func(t *testing.T) {
+		newIntervalReq := CreateIntervalRequest{
+			ActivityID:         uuid.New(),
+			Duration:           domain.MustParseDuration("1m40s"),
+			Distance:           100,
+			Type:               domain.IntervalType("main_set"),
+			Stroke:             domain.StrokeType("freestyle"),
+			LengthStrokeCounts: []int{16, 17, 17, 18},
+		}
+
+		mockService.On("CreateInterval", mock.MatchedBy(func(i domain.Interval) bool {
+			return i.ActivityID == newIntervalReq.ActivityID &&
+				len(i.LengthStrokeCounts) == 4 &&
+				i.TotalStrokes() == 68
+		})).Return(nil)
+
+		body, _ := json.Marshal(newIntervalReq)
+		req, _ := http.NewRequest(http.MethodPost, "/intervals", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("distance in yards", func(t *testing.T) {
+		newIntervalReq := CreateIntervalRequest{
+			ActivityID:   uuid.New(),
+			Duration:     domain.MustParseDuration("5m30s"),
+			Distance:     400,
+			DistanceUnit: domain.DistanceUnitYards,
+			Type:         domain.IntervalMainSet,
+			Stroke:       domain.StrokeFreestyle,
+		}
+
+		mockService.On("CreateInterval", mock.MatchedBy(func(i domain.Interval) bool {
+			return i.ActivityID == newIntervalReq.ActivityID && math.Abs(i.Distance-365.76) < 1e-9
+		})).Return(nil).Once()
+
+		body, _ := json.Marshal(newIntervalReq)
+		req, _ := http.NewRequest(http.MethodPost, "/intervals", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("negative stroke count", func(t *testing.T) {
+		newIntervalReq := CreateIntervalRequest{
+			ActivityID:         uuid.New(),
+			Duration:           domain.MustParseDuration("1m40s"),
+			Distance:           100,
+			Type:               domain.IntervalType("swim"),
+			Stroke:             domain.StrokeType("freestyle"),
+			LengthStrokeCounts: []int{16, -1},
+		}
+
+		body, _ := json.Marshal(newIntervalReq)
+		req, _ := http.NewRequest(http.MethodPost, "/intervals", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
 	t.Run("service error", func(t *testing.T) {
 		newIntervalReq := CreateIntervalRequest{
 			ActivityID: uuid.New(),
-			Duration:   domain.DurationString((30 * time.Minute).String()),
+			Duration:   domain.Duration(30 * time.Minute),
 			Distance:   1000,
 			Type:       domain.IntervalType("swim"),
 			Stroke:     domain.StrokeType("freestyle"),
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// JobHandler handles HTTP requests related to background jobs
+type JobHandler struct {
+	service app.JobService
+}
+
+// NewJobHandler creates a new JobHandler
+func NewJobHandler(service app.JobService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+// GetJob godoc
+// @Summary Get a background job
+// @Description Returns the status of a background job, e.g., the one generating a data export, and its attempts so far.
+// @Description A failing job is retried with exponential backoff until it runs out of attempts.
+// @Description Only the user the job works for and admins may read it; jobs working for no user are served to admins only.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID (UUID)"
+// @Success 200 {object} domain.Job "Job"
+// @Failure 400 {object} ProblemDetails "Invalid job ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "Job not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	job, err := h.service.GetJob(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
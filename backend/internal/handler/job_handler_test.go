@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockJobService is a mock implementation of app.JobService
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) GetJob(ctx context.Context, id uuid.UUID) (domain.Job, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Job), args.Error(1)
+}
+
+func TestGetJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockJobService)
+	handler := NewJobHandler(service)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/jobs/:id", handler.GetJob)
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+		service.On("GetJob", id).Return(domain.Job{
+			ID:          id,
+			Type:        "export",
+			Payload:     []byte(`{"export_id":"42"}`),
+			Status:      domain.JobQueued,
+			Attempts:    1,
+			MaxAttempts: 5,
+			LastError:   "dial tcp 10.0.0.5:5432: connection refused",
+		}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/jobs/"+id.String(), nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"status":"queued","attempts":1,"max_attempts":5`)
+		assert.NotContains(t, resp.Body.String(), "completed_at")
+		assert.NotContains(t, resp.Body.String(), "export_id", "the payload is internal")
+		assert.NotContains(t, resp.Body.String(), "10.0.0.5", "the errors are internal")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		id := uuid.New()
+		service.On("GetJob", id).Return(domain.Job{}, domain.NewNotFoundError("job not found"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/jobs/"+id.String(), nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/jobs/42", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserIDKey is the key under which an authentication middleware stores the ID of
+// the authenticated user in the gin context, so that its requests share a rate limit
+const UserIDKey = "user_id"
+
+// TooManyRequestsError is reported when a client exceeds its rate limit
+type TooManyRequestsError struct {
+	// RetryAfter is the time until the client may send another request
+	RetryAfter time.Duration
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry in %d seconds", retryAfterSeconds(e.RetryAfter))
+}
+
+// retryAfterSeconds rounds a delay up to the whole seconds of a Retry-After header
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
+
+// RateLimit is the sustained rate and the burst of requests allowed to each client
+type RateLimit struct {
+	// Rate is the number of requests per second; 0 disables the limit
+	Rate float64
+	// Burst is the number of requests allowed at once, after a quiet period
+	Burst int
+}
+
+// bucket holds the tokens of a client; Each request takes one token and tokens
+// are refilled at the rate of the limit, up to its burst
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// idleSweepInterval is how often the buckets of clients that went idle are dropped
+const idleSweepInterval = time.Minute
+
+// maxBuckets bounds the clients tracked at once, so that requests from many addresses
+// cannot grow the buckets without bound
+const maxBuckets = 100_000
+
+// RateLimiter limits the requests of each client, identified by its authenticated
+// user or its IP address, with a token bucket; A limiter is meant to be shared by
+// a group of routes, e.g., writes, which then share each client's budget
+type RateLimiter struct {
+	limit      RateLimit
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	maxBuckets int
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter enforcing the given limit
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limit:      limit,
+		buckets:    make(map[string]*bucket),
+		maxBuckets: maxBuckets,
+		now:        time.Now,
+	}
+}
+
+// Middleware rejects the requests of clients that exceeded the limit with
+// 429 Too Many Requests and a Retry-After header
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.limit.Rate <= 0 {
+			c.Next()
+			return
+		}
+
+		if retryAfter, ok := l.allow(rateLimitKey(c)); !ok {
+			c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(retryAfter)))
+			c.Error(&TooManyRequestsError{RetryAfter: retryAfter})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey identifies the client of a request by its user, if authenticated, or its IP;
+// The IP is only taken from X-Forwarded-For when the request comes from a trusted proxy,
+// see gin.Engine.SetTrustedProxies
+func rateLimitKey(c *gin.Context) string {
+	if userID := c.GetString(UserIDKey); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// allow takes a token from the bucket of the client, or reports how long until one is available
+func (l *RateLimiter) allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		l.makeRoom()
+		b = &bucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.limit.Rate * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
+
+func (l *RateLimiter) refill(b *bucket, now time.Time) float64 {
+	return min(float64(l.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*l.limit.Rate)
+}
+
+// sweep drops the buckets that refilled completely, as they are the same as new ones
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// makeRoom evicts an arbitrary bucket when there are maxBuckets of them, those of idle
+// clients being dropped by the periodic sweep; Evicting an active client only resets
+// its budget, which beats tracking clients without bound
+func (l *RateLimiter) makeRoom() {
+	if len(l.buckets) < l.maxBuckets {
+		return
+	}
+	for key := range l.buckets {
+		delete(l.buckets, key)
+		return
+	}
+}
+
+// MaxBodySize is a middleware that rejects request bodies larger than limit bytes
+// with 413 Content Too Large; A limit of zero or less disables it
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			c.Error(&http.MaxBytesError{Limit: limit})
+			c.Abort()
+			return
+		}
+		// Bodies of unknown length fail when reading past the limit
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(RateLimit{Rate: 0.5, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	limited := router.Group("", limiter.Middleware())
+	limited.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	limited.GET("/activities", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(path, ip string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, request("/users", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, request("/activities", "10.0.0.1").Code, "the routes of a group share the burst")
+
+	resp := request("/users", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("Retry-After"))
+	assert.Equal(t, ProblemContentType, resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "rate limit exceeded")
+
+	assert.Equal(t, http.StatusOK, request("/users", "10.0.0.2").Code, "clients have separate buckets")
+
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, http.StatusOK, request("/users", "10.0.0.1").Code, "a token is refilled every 2 seconds")
+	assert.Equal(t, http.StatusTooManyRequests, request("/users", "10.0.0.1").Code)
+
+	now = now.Add(time.Hour)
+	request("/users", "10.0.0.3")
+	assert.Len(t, limiter.buckets, 1, "idle buckets are dropped")
+}
+
+func TestRateLimiterMaxBuckets(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Rate: 1, Burst: 1})
+	limiter.maxBuckets = 3
+
+	for i := range 10 {
+		_, ok := limiter.allow(fmt.Sprintf("ip:10.0.0.%d", i))
+		assert.True(t, ok)
+	}
+	assert.Len(t, limiter.buckets, 3)
+	_, ok := limiter.allow("ip:10.0.0.9")
+	assert.False(t, ok, "the bucket of the latest client is kept")
+}
+
+func TestRateLimitKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, engine := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	c.Request.RemoteAddr = "10.0.0.1:1234"
+
+	assert.Equal(t, "ip:10.0.0.1", rateLimitKey(c))
+
+	// X-Forwarded-For is ignored unless the request comes from a trusted proxy
+	c.Request.Header.Set("X-Forwarded-For", "203.0.113.7")
+	assert.NoError(t, engine.SetTrustedProxies(nil))
+	assert.Equal(t, "ip:10.0.0.1", rateLimitKey(c))
+	assert.NoError(t, engine.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	assert.Equal(t, "ip:203.0.113.7", rateLimitKey(c))
+
+	c.Set(UserIDKey, "6b1f")
+	assert.Equal(t, "user:6b1f", rateLimitKey(c))
+}
+
+func TestMaxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(MaxBodySize(16))
+	router.POST("/users", func(c *gin.Context) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(invalidBody(err, &req))
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	t.Run("within the limit", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Ana"}`))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("declared length over the limit", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Ana Maria"}`))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+		assert.Contains(t, resp.Body.String(), "must not exceed 16 bytes")
+	})
+
+	t.Run("unknown length over the limit", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Ana Maria"}`))
+		req.ContentLength = -1
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	})
+}
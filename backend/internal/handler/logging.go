@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+)
+
+// RequestIDHeader is the header carrying the ID that correlates a request with its logs
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the length of the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestID is a middleware that propagates the X-Request-ID of the request, or
+// generates one, echoes it in the response and annotates the logger carried by the
+// request context with it; It must come first, so that every log has the ID
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, id)
+
+		ctx := c.Request.Context()
+		logger := logging.FromContext(ctx).With("request_id", id)
+		c.Request = c.Request.WithContext(logging.WithLogger(ctx, logger))
+		c.Next()
+	}
+}
+
+// validRequestID reports whether a client-supplied ID is safe to log and echo:
+// non-empty, bounded and made of letters, digits and "-", "_", ".", ":"
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AccessLog is a middleware that logs each request once it is served
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		logging.FromContext(c.Request.Context()).InfoContext(c.Request.Context(), "request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", c.Writer.Status(),
+			"size", c.Writer.Size(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// Recovery is a middleware that turns a panic into an internal error, reported and
+// logged with its stack trace by ErrorHandler, which must come before it
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		c.Error(fmt.Errorf("panic: %v\n%s", recovered, debug.Stack()))
+		c.Abort()
+	})
+}
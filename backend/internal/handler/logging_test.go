@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoggedRouter returns a router with the logging middlewares, writing its logs to buf
+func newLoggedRouter(buf *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logging.NewLogger(buf, slog.LevelDebug)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+	})
+	router.Use(RequestID())
+	router.Use(AccessLog())
+	router.Use(ErrorHandler())
+	router.Use(Recovery())
+	return router
+}
+
+// logRecords decodes the JSON records written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	router := newLoggedRouter(&buf)
+	router.GET("/ping", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("handling")
+		c.Status(http.StatusNoContent)
+	})
+
+	t.Run("propagated", func(t *testing.T) {
+		buf.Reset()
+		req, _ := http.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(RequestIDHeader, "client-id.42")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, "client-id.42", resp.Header().Get(RequestIDHeader))
+		records := logRecords(t, &buf)
+		assert.Len(t, records, 2)
+		for _, record := range records {
+			assert.Equal(t, "client-id.42", record["request_id"])
+		}
+		assert.Equal(t, "request", records[1]["msg"])
+		assert.Equal(t, "/ping", records[1]["route"])
+		assert.Equal(t, float64(http.StatusNoContent), records[1]["status"])
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		for _, header := range []string{"", "has spaces", strings.Repeat("a", maxRequestIDLength+1)} {
+			req, _ := http.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set(RequestIDHeader, header)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			_, err := uuid.Parse(resp.Header().Get(RequestIDHeader))
+			assert.NoError(t, err, "expected a generated ID for %q", header)
+		}
+	})
+}
+
+func TestErrorLogging(t *testing.T) {
+	var buf bytes.Buffer
+	router := newLoggedRouter(&buf)
+	router.GET("/fail", func(c *gin.Context) {
+		c.Error(errors.New("connection reset"))
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		buf.Reset()
+		req, _ := http.NewRequest(http.MethodGet, "/fail", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		records := logRecords(t, &buf)
+		assert.Equal(t, "request failed", records[0]["msg"])
+		assert.Equal(t, "ERROR", records[0]["level"])
+		assert.Equal(t, "connection reset", records[0]["error"])
+		assert.Equal(t, resp.Header().Get(RequestIDHeader), records[0]["request_id"])
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		buf.Reset()
+		req, _ := http.NewRequest(http.MethodGet, "/panic", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Equal(t, ProblemContentType, resp.Header().Get("Content-Type"))
+		records := logRecords(t, &buf)
+		assert.Contains(t, records[0]["error"], "panic: boom")
+		assert.NotContains(t, resp.Body.String(), "boom")
+	})
+}
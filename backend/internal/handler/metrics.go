@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsContentType is the media type of the Prometheus text exposition format
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency histogram
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// unmatchedRoute labels the requests that match no route, so that arbitrary
+// paths do not create new series
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels the requests with a non-standard method, which clients may set freely
+const otherMethod = "other"
+
+// standardMethods are the methods recorded under their own label
+var standardMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+type routeKey struct {
+	method, route string
+}
+
+type requestKey struct {
+	routeKey
+	status int
+}
+
+// histogram counts observations per bucket of latencyBuckets, plus an overflow bucket
+type histogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// Metrics collects per-route request metrics and exposes them, together with the
+// statistics of the database connection pool, in the Prometheus text format
+type Metrics struct {
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	latencies map[routeKey]*histogram
+	dbStats   func() sql.DBStats
+}
+
+// NewMetrics creates a new Metrics reading the connection pool statistics from dbStats
+func NewMetrics(dbStats func() sql.DBStats) *Metrics {
+	return &Metrics{
+		requests:  make(map[requestKey]uint64),
+		latencies: make(map[routeKey]*histogram),
+		dbStats:   dbStats,
+	}
+}
+
+// Middleware records the method, route, status code and latency of each request;
+// It must be added before ErrorHandler, so that the responses it writes are counted
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		if !slices.Contains(standardMethods, method) {
+			method = otherMethod
+		}
+		m.observe(routeKey{method: method, route: route}, c.Writer.Status(), time.Since(start))
+	}
+}
+
+func (m *Metrics) observe(key routeKey, status int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{routeKey: key, status: status}]++
+
+	h, ok := m.latencies[key]
+	if !ok {
+		h = &histogram{buckets: make([]uint64, len(latencyBuckets)+1)}
+		m.latencies[key] = h
+	}
+	seconds := latency.Seconds()
+	i, _ := slices.BinarySearch(latencyBuckets, seconds)
+	h.buckets[i]++
+	h.sum += seconds
+	h.count++
+}
+
+// ServeMetrics godoc
+// @Summary Prometheus metrics
+// @Description Exposes request counts, latency histograms and database connection pool statistics in the Prometheus text format
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string "Metrics in the Prometheus text format"
+// @Router /metrics [get]
+func (m *Metrics) ServeMetrics(c *gin.Context) {
+	var b strings.Builder
+	m.write(&b)
+	c.Data(http.StatusOK, MetricsContentType, []byte(b.String()))
+}
+
+func (m *Metrics) write(w io.Writer) {
+	m.mu.Lock()
+	requests := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		requests = append(requests, key)
+	}
+	slices.SortFunc(requests, func(a, b requestKey) int {
+		return cmp.Or(compareRoutes(a.routeKey, b.routeKey), cmp.Compare(a.status, b.status))
+	})
+
+	writeHeader(w, "http_requests_total", "counter", "Total number of HTTP requests by method, route and status code.")
+	for _, key := range requests {
+		fmt.Fprintf(w, "http_requests_total{method=%s,route=%s,status=\"%d\"} %d\n",
+			quoteLabel(key.method), quoteLabel(key.route), key.status, m.requests[key])
+	}
+
+	routes := make([]routeKey, 0, len(m.latencies))
+	for key := range m.latencies {
+		routes = append(routes, key)
+	}
+	slices.SortFunc(routes, compareRoutes)
+
+	writeHeader(w, "http_request_duration_seconds", "histogram", "Latency of HTTP requests by method and route.")
+	for _, key := range routes {
+		h := m.latencies[key]
+		labels := fmt.Sprintf("method=%s,route=%s", quoteLabel(key.method), quoteLabel(key.route))
+		var cumulative uint64
+		for i, count := range h.buckets {
+			cumulative += count
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = formatFloat(latencyBuckets[i])
+			}
+			fmt.Fprintf(w, "http_request_duration_seconds_bucket{%s,le=%q} %d\n", labels, le, cumulative)
+		}
+		fmt.Fprintf(w, "http_request_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+		fmt.Fprintf(w, "http_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+	m.mu.Unlock()
+
+	stats := m.dbStats()
+	gauges := []struct {
+		name, help string
+		value      int
+	}{
+		{"db_connections_max_open", "Maximum number of open connections to the database.", stats.MaxOpenConnections},
+		{"db_connections_open", "Number of established connections, both in use and idle.", stats.OpenConnections},
+		{"db_connections_in_use", "Number of connections currently in use.", stats.InUse},
+		{"db_connections_idle", "Number of idle connections.", stats.Idle},
+	}
+	for _, g := range gauges {
+		writeHeader(w, g.name, "gauge", g.help)
+		fmt.Fprintf(w, "%s %d\n", g.name, g.value)
+	}
+
+	counters := []struct {
+		name, help string
+		value      string
+	}{
+		{"db_wait_count_total", "Total number of connections waited for.", strconv.FormatInt(stats.WaitCount, 10)},
+		{"db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", formatFloat(stats.WaitDuration.Seconds())},
+		{"db_connections_closed_max_idle_total", "Total number of connections closed due to SetMaxIdleConns.", strconv.FormatInt(stats.MaxIdleClosed, 10)},
+		{"db_connections_closed_max_idle_time_total", "Total number of connections closed due to SetConnMaxIdleTime.", strconv.FormatInt(stats.MaxIdleTimeClosed, 10)},
+		{"db_connections_closed_max_lifetime_total", "Total number of connections closed due to SetConnMaxLifetime.", strconv.FormatInt(stats.MaxLifetimeClosed, 10)},
+	}
+	for _, c := range counters {
+		writeHeader(w, c.name, "counter", c.help)
+		fmt.Fprintf(w, "%s %s\n", c.name, c.value)
+	}
+}
+
+func compareRoutes(a, b routeKey) int {
+	return cmp.Or(cmp.Compare(a.route, b.route), cmp.Compare(a.method, b.method))
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// labelEscaper escapes label values as required by the text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := NewMetrics(func() sql.DBStats {
+		return sql.DBStats{MaxOpenConnections: 25, OpenConnections: 3, InUse: 1, Idle: 2, WaitDuration: 1500 * time.Millisecond}
+	})
+
+	router := gin.New()
+	router.Use(metrics.Middleware())
+	router.Use(ErrorHandler())
+	router.GET("/users/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			c.Error(domain.NewNotFoundError("user not found"))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/metrics", metrics.ServeMetrics)
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/missing", "/unknown"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	for _, method := range []string{"PURGE", "X-RANDOM-1", "X-RANDOM-2"} {
+		req, _ := http.NewRequest(method, "/users/1", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, MetricsContentType, resp.Header().Get("Content-Type"))
+
+	body := resp.Body.String()
+	assert.Contains(t, body, "# TYPE http_requests_total counter\n")
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/users/:id",status="200"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/users/:id",status="404"} 1`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_requests_total{method="other",route="unmatched",status="404"} 3`)
+	assert.NotContains(t, body, "PURGE")
+	assert.Contains(t, body, "# TYPE http_request_duration_seconds histogram\n")
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",route="/users/:id",le="+Inf"} 3`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/users/:id"} 3`)
+	assert.Contains(t, body, "db_connections_max_open 25\n")
+	assert.Contains(t, body, "db_connections_in_use 1\n")
+	assert.Contains(t, body, "db_wait_duration_seconds_total 1.5\n")
+}
+
+func TestMetricsHistogram(t *testing.T) {
+	metrics := NewMetrics(func() sql.DBStats { return sql.DBStats{} })
+	key := routeKey{method: http.MethodGet, route: "/users"}
+
+	metrics.observe(key, http.StatusOK, 5*time.Millisecond)
+	metrics.observe(key, http.StatusOK, 200*time.Millisecond)
+	metrics.observe(key, http.StatusOK, 20*time.Second)
+
+	h := metrics.latencies[key]
+	assert.Equal(t, uint64(1), h.buckets[0], "an observation equal to a bound falls in its bucket")
+	assert.Equal(t, uint64(1), h.buckets[5])
+	assert.Equal(t, uint64(1), h.buckets[len(latencyBuckets)], "observations above every bound overflow")
+	assert.InDelta(t, 20.205, h.sum, 1e-9)
+	assert.Equal(t, uint64(3), h.count)
+}
+
+func TestQuoteLabel(t *testing.T) {
+	if got := quoteLabel("a\"b\\c\nd"); got != `"a\"b\\c\nd"` {
+		t.Errorf("quoteLabel() = %s", got)
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// Limits for the period accepted by endpoints taking "from" and "to" query parameters
+const (
+	defaultPeriodDays = 28
+	maxPeriodDays     = 366
+)
+
+// parsePeriod reads the "from" and "to" query parameters (YYYY-MM-DD);
+// "to" defaults to today and "from" to defaultPeriodDays days before "to"
+func parsePeriod(c *gin.Context) (from, to time.Time, err error) {
+	to = time.Now().UTC()
+	if toParam := c.Query("to"); toParam != "" {
+		if to, err = time.Parse(domain.DateLayout, toParam); err != nil {
+			return from, to, invalidParam("to", domain.CodeInvalidFormat, "must be a date in YYYY-MM-DD format")
+		}
+	}
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	from = to.AddDate(0, 0, -(defaultPeriodDays - 1))
+	if fromParam := c.Query("from"); fromParam != "" {
+		if from, err = time.Parse(domain.DateLayout, fromParam); err != nil {
+			return from, to, invalidParam("from", domain.CodeInvalidFormat, "must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	if to.Before(from) {
+		return from, to, invalidParam("from", domain.CodeOutOfRange, "must not be after 'to'")
+	}
+	if to.Sub(from) >= maxPeriodDays*24*time.Hour {
+		return from, to, invalidParam("to", domain.CodeOutOfRange, fmt.Sprintf("must be less than %d days after 'from'", maxPeriodDays))
+	}
+
+	return from, to, nil
+}
@@ -1,14 +1,9 @@
 package handler
 
-import "github.com/liviaruegger/MAC0350/backend/internal/entity"
-
-// ErrorResponse represents an error message returned by the API.
-// swagger:model
-type ErrorResponse struct {
-	// Error is a description of what went wrong.
-	// Example: Service error
-	Error string `json:"error"`
-}
+import (
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+)
 
 // MessageResponse is used for generic messages.
 // swagger:model
@@ -23,3 +18,18 @@ type MessageResponse struct {
 type GetActivitiesByUserResponse struct {
 	Activities []entity.Activity `json:"activities"`
 }
+
+// StatusResponse reports the status of the API
+// swagger:model
+type StatusResponse struct {
+	// Status is "ok" while the API is alive
+	Status string `json:"status" example:"ok"`
+}
+
+// ExportResponse reports the status of a data export, with the link to its archive once ready
+// swagger:model
+type ExportResponse struct {
+	domain.DataExport
+	// DownloadURL is the path the archive can be downloaded from, once ready
+	DownloadURL string `json:"download_url,omitempty" example:"/api/v1/exports/6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1/download"`
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// RetentionHandler handles HTTP requests restoring deleted records
+type RetentionHandler struct {
+	service    app.RetentionService
+	users      app.UserService
+	activities app.ActivityService
+}
+
+// NewRetentionHandler creates a new RetentionHandler; the user and activity
+// services serve the restored records
+func NewRetentionHandler(service app.RetentionService, users app.UserService, activities app.ActivityService) *RetentionHandler {
+	return &RetentionHandler{service: service, users: users, activities: activities}
+}
+
+// RestoreUser godoc
+// @Summary Restore a deleted user
+// @Description Restores a user deleted within the retention period, along with the activities deleted with them
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} domain.User "User successfully restored"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 404 {object} ProblemDetails "No user deleted within the retention period"
+// @Failure 409 {object} ProblemDetails "Email taken by another user since the deletion"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/restore [post]
+func (h *RetentionHandler) RestoreUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	if err := h.service.RestoreUser(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := h.users.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	renderWithETag(c, http.StatusOK, user)
+}
+
+// RestoreActivity godoc
+// @Summary Restore a deleted activity
+// @Description Restores an activity deleted within the retention period, along with its intervals.
+// @Description The activities of a deleted user are restored with the user.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity ID (UUID)"
+// @Success 200 {object} entity.Activity "Activity successfully restored"
+// @Failure 400 {object} ProblemDetails "Invalid activity ID"
+// @Failure 404 {object} ProblemDetails "No activity deleted within the retention period"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/activities/{id}/restore [post]
+func (h *RetentionHandler) RestoreActivity(c *gin.Context) {
+	activityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	if err := h.service.RestoreActivity(c.Request.Context(), activityID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	activity, err := h.activities.GetActivityByID(c.Request.Context(), activityID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	renderWithETag(c, http.StatusOK, activity)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRetentionService is a mock implementation of app.RetentionService
+type MockRetentionService struct {
+	mock.Mock
+}
+
+func (m *MockRetentionService) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRetentionService) RestoreActivity(ctx context.Context, activityID uuid.UUID) error {
+	args := m.Called(activityID)
+	return args.Error(0)
+}
+
+func (m *MockRetentionService) PurgeDeleted(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestRestoreUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	retention, users := new(MockRetentionService), new(MockUserService)
+	handler := NewRetentionHandler(retention, users, new(MockActivityService))
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/users/:id/restore", handler.RestoreUser)
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+		retention.On("RestoreUser", id).Return(nil)
+		users.On("GetUserByID", id).Return(domain.User{ID: id, Name: "John Doe"}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+id.String()+"/restore", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "John Doe")
+		assert.NotEmpty(t, resp.Header().Get(ETagHeader))
+	})
+
+	t.Run("past retention", func(t *testing.T) {
+		id := uuid.New()
+		retention.On("RestoreUser", id).Return(domain.NewNotFoundError("deleted user not found"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+id.String()+"/restore", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		id := uuid.New()
+		retention.On("RestoreUser", id).Return(domain.NewConflictError("user with this email already exists"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+id.String()+"/restore", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/users/invalid/restore", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestRestoreActivity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	retention, activities := new(MockRetentionService), new(MockActivityService)
+	handler := NewRetentionHandler(retention, new(MockUserService), activities)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/activities/:id/restore", handler.RestoreActivity)
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+		retention.On("RestoreActivity", id).Return(nil)
+		activities.On("GetActivityByID", id).Return(entity.Activity{ID: id, LocationName: "CEPE"}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/activities/"+id.String()+"/restore", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "CEPE")
+	})
+
+	t.Run("not restorable", func(t *testing.T) {
+		id := uuid.New()
+		retention.On("RestoreActivity", id).Return(domain.NewNotFoundError("deleted activity not found"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/activities/"+id.String()+"/restore", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		activities.AssertNotCalled(t, "GetActivityByID", id)
+	})
+}
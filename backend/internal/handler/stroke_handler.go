@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// StrokeHandler handles HTTP requests related to stroke efficiency
+type StrokeHandler struct {
+	service app.StrokeService
+}
+
+// NewStrokeHandler creates a new StrokeHandler
+func NewStrokeHandler(service app.StrokeService) *StrokeHandler {
+	return &StrokeHandler{service: service}
+}
+
+// GetStrokeEfficiency godoc
+// @Summary Get the stroke efficiency trend of a user
+// @Description Returns the stroke count, SWOLF and distance per stroke of each session with stroke data over a period; the period defaults to the last 28 days
+// @Tags strokes
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param from query string false "First day of the period (YYYY-MM-DD)"
+// @Param to query string false "Last day of the period (YYYY-MM-DD)"
+// @Success 200 {object} entity.StrokeEfficiencyTrend
+// @Failure 400 {object} ProblemDetails "Invalid user ID or period"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/stroke-efficiency [get]
+func (h *StrokeHandler) GetStrokeEfficiency(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	trend, err := h.service.GetStrokeEfficiency(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStrokeService is a mock implementation of app.StrokeService
+type MockStrokeService struct {
+	mock.Mock
+}
+
+func (m *MockStrokeService) GetStrokeEfficiency(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.StrokeEfficiencyTrend, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).(entity.StrokeEfficiencyTrend), args.Error(1)
+}
+
+func TestGetStrokeEfficiencyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockStrokeService)
+	handler := NewStrokeHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/stroke-efficiency", handler.GetStrokeEfficiency)
+
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 10, 28, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetStrokeEfficiency", userID, from, to).Return(entity.StrokeEfficiencyTrend{
+			UserID: userID,
+			Sessions: []entity.SessionStrokeEfficiency{
+				{ActivityID: uuid.New(), Date: "2023-10-01", StrokeCount: 68, SWOLF: 42, DistancePerStroke: 1.47},
+			},
+		}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/stroke-efficiency?from=2023-10-01&to=2023-10-28", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"swolf":42`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid UUID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/not-a-uuid/stroke-efficiency", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetStrokeEfficiency", userID, from, to).Return(entity.StrokeEfficiencyTrend{}, errors.New("db error"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/stroke-efficiency?from=2023-10-01&to=2023-10-28", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+}
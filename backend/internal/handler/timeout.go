@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout is a middleware that sets a deadline on the request context, so that
+// database queries still running when it expires are canceled; A timeout of
+// zero or less disables it
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Streaming is the middleware of the routes streaming their response for as long
+// as the client stays connected, registered without Timeout: it lifts the write
+// timeout of the server, so the stream ends only on disconnection or shutdown
+func Streaming() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("slow request", func(t *testing.T) {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(Timeout(10 * time.Millisecond))
+		router.GET("/slow", func(c *gin.Context) {
+			// Simulates a query canceled by the driver once the deadline expires
+			<-c.Request.Context().Done()
+			c.Error(errors.New("pq: canceling statement due to user request"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "the request took too long to complete")
+	})
+
+	t.Run("sets a deadline", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Timeout(time.Minute))
+		router.GET("/", func(c *gin.Context) {
+			_, ok := c.Request.Context().Deadline()
+			assert.True(t, ok)
+			c.Status(http.StatusNoContent)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Timeout(0))
+		router.GET("/", func(c *gin.Context) {
+			_, ok := c.Request.Context().Deadline()
+			assert.False(t, ok)
+			c.Status(http.StatusNoContent)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
+
+func TestStreaming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/stream", Streaming(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteString("first\n")
+		c.Writer.Flush()
+		time.Sleep(50 * time.Millisecond)
+		c.Writer.WriteString("second\n")
+	})
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 10 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(body), "the stream outlives the write timeout")
+}
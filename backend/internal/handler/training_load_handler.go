@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// TrainingLoadHandler handles HTTP requests related to training load
+type TrainingLoadHandler struct {
+	service app.TrainingLoadService
+}
+
+// NewTrainingLoadHandler creates a new TrainingLoadHandler
+func NewTrainingLoadHandler(service app.TrainingLoadService) *TrainingLoadHandler {
+	return &TrainingLoadHandler{service: service}
+}
+
+// GetTrainingLoad godoc
+// @Summary Get the training load of a user
+// @Description Returns the daily session load and the acute (7-day) and chronic (42-day) loads with their ratio; the period defaults to the last 28 days
+// @Tags training-load
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param from query string false "First day of the period (YYYY-MM-DD)"
+// @Param to query string false "Last day of the period (YYYY-MM-DD)"
+// @Success 200 {object} entity.TrainingLoad
+// @Failure 400 {object} ProblemDetails "Invalid user ID or period"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/training-load [get]
+func (h *TrainingLoadHandler) GetTrainingLoad(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	load, err := h.service.GetTrainingLoad(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, load)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTrainingLoadService is a mock implementation of app.TrainingLoadService
+type MockTrainingLoadService struct {
+	mock.Mock
+}
+
+func (m *MockTrainingLoadService) GetTrainingLoad(ctx context.Context, userID uuid.UUID, from, to time.Time) (entity.TrainingLoad, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).(entity.TrainingLoad), args.Error(1)
+}
+
+func TestGetTrainingLoadHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTrainingLoadService)
+	handler := NewTrainingLoadHandler(mockService)
+
+	router := gin.Default()
+	router.Use(ErrorHandler())
+	router.GET("/users/:id/training-load", handler.GetTrainingLoad)
+
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 10, 28, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetTrainingLoad", userID, from, to).Return(entity.TrainingLoad{
+			UserID: userID,
+			From:   "2023-10-01",
+			To:     "2023-10-28",
+			Days: []entity.DailyTrainingLoad{
+				{Date: "2023-10-01", Load: 420, AcuteLoad: 60, ChronicLoad: 10, AcuteChronicRatio: 6},
+			},
+		}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/training-load?from=2023-10-01&to=2023-10-28", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "acute_chronic_ratio")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("default period", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetTrainingLoad", userID, from, to).Return(entity.TrainingLoad{UserID: userID}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/training-load?to=2023-10-28", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid UUID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/not-a-uuid/training-load", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.New().String()+"/training-load?from=01/10/2023", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("from after to", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.New().String()+"/training-load?from=2023-10-28&to=2023-10-01", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		userID := uuid.New()
+		mockService.On("GetTrainingLoad", userID, from, to).Return(entity.TrainingLoad{}, errors.New("db error"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+userID.String()+"/training-load?from=2023-10-01&to=2023-10-28", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+}
@@ -27,29 +27,34 @@ func NewUserHandler(s app.UserService) *UserHandler {
 // @Produce json
 // @Param user body handler.CreateUserRequest true "User data."
 // @Success 201 {object} domain.User "User successfully created"
-// @Failure 400 {object} ErrorResponse "Invalid input"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /users [post]
+// @Failure 400 {object} ProblemDetails "Invalid input"
+// @Failure 409 {object} ProblemDetails "Email already in use"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid JSON"})
+		c.Error(invalidBody(err, &req))
 		return
 	}
 
 	user := domain.User{
-		ID:     uuid.New(),
-		Name:   req.Name,
-		Email:  req.Email,
-		City:   req.City,
-		Phone:  req.Phone,
-		Age:    req.Age,
-		Height: req.Height,
-		Weight: req.Weight,
-	}
-
-	if err := h.service.CreateUser(user); err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: "Service error"})
+		ID:                        uuid.New(),
+		Name:                      req.Name,
+		Email:                     req.Email,
+		City:                      req.City,
+		Phone:                     req.Phone,
+		Age:                       req.Age,
+		Height:                    req.Height,
+		Weight:                    req.Weight,
+		MaxHeartRate:              req.MaxHeartRate,
+		RestingHeartRate:          req.RestingHeartRate,
+		LactateThresholdHeartRate: req.LactateThresholdHeartRate,
+		DisplayUnit:               req.DisplayUnit.OrDefault(),
+	}
+
+	if err := h.service.CreateUser(c.Request.Context(), user); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -63,12 +68,12 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} domain.User "List of users"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /users [get]
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users [get]
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.service.GetAllUsers()
+	users, err := h.service.GetAllUsers(c.Request.Context())
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: "Service error"})
+		c.Error(err)
 		return
 	}
 
@@ -82,25 +87,27 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID (UUID)"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} domain.User "User found"
-// @Failure 400 {object} ErrorResponse "Invalid user ID"
-// @Failure 404 {object} ErrorResponse "User not found"
-// @Router /users/{id} [get]
+// @Success 304 "User unchanged since the given ETag"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Router /api/v1/users/{id} [get]
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
 		return
 	}
 
-	user, err := h.service.GetUserByID(id)
+	user, err := h.service.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		c.Error(err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, user)
+	renderWithETag(c, http.StatusOK, user)
 }
 
 // GetUserByEmail godoc
@@ -111,21 +118,21 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Produce json
 // @Param email path string true "User email"
 // @Success 200 {object} domain.User "User found"
-// @Failure 400 {object} ErrorResponse "Invalid email"
-// @Failure 404 {object} ErrorResponse "User not found"
-// @Router /users/email/{email} [get]
+// @Failure 400 {object} ProblemDetails "Invalid email"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Router /api/v1/users/email/{email} [get]
 func (h *UserHandler) GetUserByEmail(c *gin.Context) {
 	email := c.Param("email")
 
 	// Simple email validation
 	if !isValidEmail(email) {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid email format"})
+		c.Error(invalidParam("email", domain.CodeInvalidFormat, "must be a valid email"))
 		return
 	}
 
-	user, err := h.service.GetUserByEmail(email)
+	user, err := h.service.GetUserByEmail(c.Request.Context(), email)
 	if err != nil {
-		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		c.Error(err)
 		return
 	}
 
@@ -142,62 +149,92 @@ func isValidEmail(email string) bool {
 
 // UpdateUser godoc
 // @Summary Update an existing user
-// @Description Updates the user with the provided ID, name, email, city, and phone
+// @Description Updates the user with the provided ID. The optional fields left out keep their stored value,
+// @Description e.g., the heart-rate settings and display unit when only the name, email, city and phone are sent.
+// @Description With If-Match, the update only applies if the user still has that ETag.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID (UUID)"
-// @Param user body domain.User true "Updated user data"
+// @Param If-Match header string false "ETag the update is based on"
+// @Param user body handler.UpdateUserRequest true "Updated user data"
 // @Success 200 {object} domain.User "User successfully updated"
-// @Failure 400 {object} ErrorResponse "Invalid input"
-// @Failure 404 {object} ErrorResponse "User not found"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /users/{id} [put]
+// @Failure 400 {object} ProblemDetails "Invalid input"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Failure 412 {object} ProblemDetails "User was modified since the given ETag"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err, &req))
+		return
+	}
+
+	current, err := h.service.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	var updatedUser domain.User
-	if err := c.BindJSON(&updatedUser); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid JSON"})
+	updatedUser := req.applyTo(current)
+	// The version is only ever taken from If-Match; 0 updates unconditionally
+	updatedUser.Version = 0
+
+	if c.GetHeader(IfMatchHeader) != "" {
+		if err := checkIfMatch(c, current); err != nil {
+			c.Error(err)
+			return
+		}
+		// The repository rechecks the version, catching writes racing with this one
+		updatedUser.Version = current.Version
+	}
+
+	if err := h.service.UpdateUser(c.Request.Context(), updatedUser); err != nil {
+		c.Error(err)
 		return
 	}
-	updatedUser.ID = id
 
-	if err := h.service.UpdateUser(updatedUser); err != nil {
-		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+	user, err := h.service.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, updatedUser)
+	renderWithETag(c, http.StatusOK, user)
 }
 
 // DeleteUser godoc
 // @Summary Delete a user
-// @Description Deletes the user with the specified ID
+// @Description Deletes the user with the specified ID, along with their activities.
+// @Description They can be restored until the retention period is over, after which their activities,
+// @Description CSS tests, exports and webhooks are purged, and their profile and audit log are anonymized.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID (UUID)"
 // @Success 204 "User successfully deleted"
-// @Failure 400 {object} ErrorResponse "Invalid user ID"
-// @Failure 404 {object} ErrorResponse "User not found"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /users/{id} [delete]
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
 		return
 	}
 
-	if err := h.service.DeleteUser(id); err != nil {
-		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+	if err := h.service.DeleteUser(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
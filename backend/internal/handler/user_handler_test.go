@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -19,32 +20,32 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) CreateUser(user domain.User) error {
+func (m *MockUserService) CreateUser(ctx context.Context, user domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserService) GetAllUsers() ([]domain.User, error) {
+func (m *MockUserService) GetAllUsers(ctx context.Context) ([]domain.User, error) {
 	args := m.Called()
 	return args.Get(0).([]domain.User), args.Error(1)
 }
 
-func (m *MockUserService) GetUserByID(id uuid.UUID) (domain.User, error) {
+func (m *MockUserService) GetUserByID(ctx context.Context, id uuid.UUID) (domain.User, error) {
 	args := m.Called(id)
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
-func (m *MockUserService) GetUserByEmail(email string) (domain.User, error) {
+func (m *MockUserService) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
 	args := m.Called(email)
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
-func (m *MockUserService) UpdateUser(user domain.User) error {
+func (m *MockUserService) UpdateUser(ctx context.Context, user domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserService) DeleteUser(id uuid.UUID) error {
+func (m *MockUserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
@@ -55,6 +56,7 @@ func TestCreateUser(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(ErrorHandler())
 	router.POST("/users", handler.CreateUser)
 
 	t.Run("success", func(t *testing.T) {
@@ -98,6 +100,25 @@ func TestCreateUser(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
 	})
 
+	t.Run("invalid display unit", func(t *testing.T) {
+		newUser := domain.User{
+			Name:        "John Doe",
+			Email:       "john@example.com",
+			City:        "São Paulo",
+			Phone:       "+55 11 91234-5678",
+			DisplayUnit: domain.DistanceUnit("furlongs"),
+		}
+
+		body, _ := json.Marshal(newUser)
+		req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
 	t.Run("service error", func(t *testing.T) {
 		mockService.ExpectedCalls = nil
 		mockService.Calls = nil
@@ -131,6 +152,7 @@ func TestGetAllUsers(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(ErrorHandler())
 	router.GET("/users", handler.GetAllUsers)
 
 	t.Run("success", func(t *testing.T) {
@@ -193,6 +215,7 @@ func TestGetUserByID(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(ErrorHandler())
 	router.GET("/users/:id", handler.GetUserByID)
 
 	t.Run("success", func(t *testing.T) {
@@ -233,7 +256,7 @@ func TestGetUserByID(t *testing.T) {
 
 	t.Run("not found", func(t *testing.T) {
 		id := uuid.New()
-		mockService.On("GetUserByID", id).Return(domain.User{}, errors.New("not found"))
+		mockService.On("GetUserByID", id).Return(domain.User{}, domain.ErrNotFound)
 
 		req, _ := http.NewRequest(http.MethodGet, "/users/"+id.String(), nil)
 		resp := httptest.NewRecorder()
@@ -243,3 +266,144 @@ func TestGetUserByID(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.Code)
 	})
 }
+
+func TestUpdateUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	current := domain.User{ID: userID, Name: "John Doe", Email: "john@example.com", City: "São Paulo", Phone: "123", Version: 3}
+	etag, _, err := etagFor(current)
+	assert.NoError(t, err)
+	body := `{"name":"John Smith","email":"john@example.com","city":"São Paulo","phone":"123","version":99}`
+
+	newRouter := func(s *MockUserService) *gin.Engine {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.PUT("/users/:id", NewUserHandler(s).UpdateUser)
+		return router
+	}
+
+	t.Run("unconditional", func(t *testing.T) {
+		mockService := new(MockUserService)
+		mockService.On("UpdateUser", mock.MatchedBy(func(u domain.User) bool {
+			return u.ID == userID && u.Name == "John Smith" && u.Version == 0
+		})).Return(nil)
+		updated := current
+		updated.Name, updated.Version = "John Smith", 4
+		mockService.On("GetUserByID", userID).Return(updated, nil)
+
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"version":4`)
+		assert.NotEmpty(t, resp.Header().Get(ETagHeader))
+		assert.NotEqual(t, etag, resp.Header().Get(ETagHeader))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("fields left out keep their value", func(t *testing.T) {
+		stored := current
+		stored.MaxHeartRate, stored.LactateThresholdHeartRate, stored.DisplayUnit = 190, 165, domain.DistanceUnitYards
+		mockService := new(MockUserService)
+		mockService.On("GetUserByID", userID).Return(stored, nil)
+		mockService.On("UpdateUser", mock.MatchedBy(func(u domain.User) bool {
+			return u.Name == "John Smith" && u.MaxHeartRate == 190 && u.LactateThresholdHeartRate == 165 && u.DisplayUnit == domain.DistanceUnitYards
+		})).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("optional fields sent are applied", func(t *testing.T) {
+		stored := current
+		stored.MaxHeartRate, stored.RestingHeartRate = 190, 50
+		mockService := new(MockUserService)
+		mockService.On("GetUserByID", userID).Return(stored, nil)
+		mockService.On("UpdateUser", mock.MatchedBy(func(u domain.User) bool {
+			return u.MaxHeartRate == 185 && u.RestingHeartRate == 50 && u.DisplayUnit == domain.DistanceUnitYards
+		})).Return(nil)
+
+		body := `{"name":"John Doe","email":"john@example.com","city":"São Paulo","phone":"123","max_heart_rate":185,"display_unit":"yards"}`
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		mockService := new(MockUserService)
+
+		body := `{"name":"John Doe","email":"not-an-email","city":"São Paulo","max_heart_rate":400,"display_unit":"feet"}`
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockService.AssertNotCalled(t, "GetUserByID", mock.Anything)
+		mockService.AssertNotCalled(t, "UpdateUser", mock.Anything)
+	})
+
+	t.Run("invalid heart rate", func(t *testing.T) {
+		mockService := new(MockUserService)
+
+		body := `{"name":"John Doe","email":"john@example.com","city":"São Paulo","phone":"123","max_heart_rate":400}`
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"field":"max_heart_rate"`)
+		assert.Contains(t, resp.Body.String(), `"code":"out_of_range"`)
+		mockService.AssertNotCalled(t, "UpdateUser", mock.Anything)
+	})
+
+	t.Run("matching If-Match", func(t *testing.T) {
+		mockService := new(MockUserService)
+		mockService.On("GetUserByID", userID).Return(current, nil)
+		mockService.On("UpdateUser", mock.MatchedBy(func(u domain.User) bool {
+			return u.Version == current.Version
+		})).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		req.Header.Set(IfMatchHeader, etag)
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("stale If-Match", func(t *testing.T) {
+		mockService := new(MockUserService)
+		mockService.On("GetUserByID", userID).Return(current, nil)
+
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		req.Header.Set(IfMatchHeader, `"stale"`)
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+		mockService.AssertNotCalled(t, "UpdateUser", mock.Anything)
+	})
+
+	t.Run("concurrent update", func(t *testing.T) {
+		mockService := new(MockUserService)
+		mockService.On("GetUserByID", userID).Return(current, nil)
+		mockService.On("UpdateUser", mock.Anything).Return(domain.NewPreconditionFailedError("user was modified"))
+
+		req, _ := http.NewRequest(http.MethodPut, "/users/"+userID.String(), bytes.NewBufferString(body))
+		req.Header.Set(IfMatchHeader, etag)
+		resp := httptest.NewRecorder()
+		newRouter(mockService).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	})
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// enum is implemented by the domain types restricted to a set of predefined values
+type enum interface {
+	Valid() bool
+	Values() []string
+}
+
+// RegisterValidators registers the custom validation tags used by the request types
+// with Gin's validator, and makes validation errors report fields by their JSON names;
+// It must be called once, before the router starts serving requests
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator engine")
+	}
+
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	// "enum" accepts any value of a domain enum type that is one of its predefined values
+	return v.RegisterValidation("enum", func(fl validator.FieldLevel) bool {
+		value, ok := fl.Field().Interface().(enum)
+		return ok && value.Valid()
+	})
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// invalidBody returns a validation error with the details of each field that
+// made a request body fail to bind into req
+func invalidBody(err error, req any) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]domain.FieldError, len(validationErrs))
+		for i, fieldErr := range validationErrs {
+			fields[i] = fieldError(fieldErr)
+		}
+		return domain.NewValidationError(fields...)
+	}
+
+	// Bodies over the size limit are reported as such, not as invalid input
+	if errors.As(err, new(*http.MaxBytesError)) {
+		return err
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var enumErr *domain.EnumError
+	var durationErr *domain.DurationError
+	switch {
+	case errors.Is(err, io.EOF):
+		return domain.NewValidationError(domain.FieldError{Field: "body", Code: domain.CodeRequired, Message: "is required"})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return domain.NewValidationError(domain.FieldError{Field: "body", Code: domain.CodeInvalidFormat, Message: "must be valid JSON"})
+	case errors.As(err, &typeErr):
+		return domain.NewValidationError(domain.FieldError{
+			Field:   typeErr.Field,
+			Code:    domain.CodeInvalidType,
+			Message: "must be of type " + jsonTypeName(typeErr.Type),
+		})
+	case errors.As(err, &enumErr):
+		return domain.NewValidationError(domain.FieldError{
+			Field:   fieldOfType(req, enumErr.Enum),
+			Code:    domain.CodeUnknownValue,
+			Message: "must be one of: " + strings.Join(enumErr.Values, ", "),
+		})
+	case errors.As(err, &durationErr):
+		return domain.NewValidationError(domain.FieldError{
+			Field:   fieldOfType(req, "Duration"),
+			Code:    domain.CodeInvalidFormat,
+			Message: `must be a duration like "1:45.32", "45.1" or "1m45s"`,
+		})
+	default:
+		return domain.NewValidationError(domain.FieldError{Field: "body", Code: domain.CodeInvalid, Message: err.Error()})
+	}
+}
+
+// fieldOfType returns the JSON name of the field of req whose type has the given name;
+// The JSON decoder does not report which field a custom type rejected, so it is found by type
+func fieldOfType(req any, typeName string) string {
+	t := reflect.TypeOf(req)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "body"
+	}
+
+	field := "body"
+	for i := range t.NumField() {
+		if t.Field(i).Type.Name() != typeName {
+			continue
+		}
+		if field != "body" {
+			// Several fields of the same type, the rejected one is ambiguous
+			return "body"
+		}
+		field = jsonFieldName(t.Field(i))
+	}
+
+	return field
+}
+
+// fieldError translates the failure of a single validation tag
+func fieldError(err validator.FieldError) domain.FieldError {
+	// The namespace starts with the request type, e.g., "CreateIntervalRequest.length_stroke_counts[2]"
+	_, field, _ := strings.Cut(err.Namespace(), ".")
+	param := err.Param()
+
+	switch err.Tag() {
+	case "required":
+		return domain.FieldError{Field: field, Code: domain.CodeRequired, Message: "is required"}
+	case "required_unless":
+		// The parameter is the struct field name and its value, e.g., "Type rest"
+		other, value, _ := strings.Cut(param, " ")
+		return domain.FieldError{Field: field, Code: domain.CodeRequired, Message: fmt.Sprintf("is required unless %s is %s", strings.ToLower(other), value)}
+	case "email":
+		return domain.FieldError{Field: field, Code: domain.CodeInvalidFormat, Message: "must be a valid email"}
+	case "enum":
+		message := "must be one of the predefined values"
+		if value, ok := err.Value().(enum); ok {
+			message = "must be one of: " + strings.Join(value.Values(), ", ")
+		}
+		return domain.FieldError{Field: field, Code: domain.CodeUnknownValue, Message: message}
+	case "oneof":
+		return domain.FieldError{Field: field, Code: domain.CodeUnknownValue, Message: "must be one of: " + strings.ReplaceAll(param, " ", ", ")}
+	case "min", "gte":
+		return domain.FieldError{Field: field, Code: domain.CodeOutOfRange, Message: "must be at least " + param}
+	case "max", "lte":
+		return domain.FieldError{Field: field, Code: domain.CodeOutOfRange, Message: "must be at most " + param}
+	case "gt":
+		return domain.FieldError{Field: field, Code: domain.CodeOutOfRange, Message: "must be greater than " + param}
+	case "lt":
+		return domain.FieldError{Field: field, Code: domain.CodeOutOfRange, Message: "must be less than " + param}
+	default:
+		return domain.FieldError{Field: field, Code: domain.CodeInvalid, Message: "is invalid"}
+	}
+}
+
+// jsonTypeName returns the JSON type a Go type is decoded from
+func jsonTypeName(t reflect.Type) string {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	if err := RegisterValidators(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// bindProblem posts body to a route that binds it into T and returns the resulting problem
+func bindProblem[T any](t *testing.T, body string) (int, ProblemDetails) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/", func(c *gin.Context) {
+		var req T
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(invalidBody(err, &req))
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var problem ProblemDetails
+	if resp.Code != http.StatusNoContent {
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &problem))
+	}
+	return resp.Code, problem
+}
+
+func TestInvalidBody(t *testing.T) {
+	validInterval := func(overrides map[string]any) string {
+		fields := map[string]any{
+			"activity_id": uuid.New(),
+			"duration":    "1m30s",
+			"distance":    100,
+			"type":        "swim",
+			"stroke":      "freestyle",
+		}
+		for k, v := range overrides {
+			fields[k] = v
+		}
+		body, _ := json.Marshal(fields)
+		return string(body)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected []domain.FieldError
+	}{
+		{
+			name: "unknown enum value",
+			body: validInterval(map[string]any{"type": "sprint"}),
+			expected: []domain.FieldError{
+				{Field: "type", Code: domain.CodeUnknownValue, Message: "must be one of: swim, rest, drill, kick, pull, warmup, main_set, cooldown"},
+			},
+		},
+		{
+			name: "zero distance outside rest",
+			body: validInterval(map[string]any{"distance": 0}),
+			expected: []domain.FieldError{
+				{Field: "distance", Code: domain.CodeRequired, Message: "is required unless type is rest"},
+			},
+		},
+		{
+			name: "negative length stroke count",
+			body: validInterval(map[string]any{"length_stroke_counts": []int{14, -1}}),
+			expected: []domain.FieldError{
+				{Field: "length_stroke_counts[1]", Code: domain.CodeOutOfRange, Message: "must be at least 0"},
+			},
+		},
+		{
+			name: "wrong JSON type",
+			body: validInterval(map[string]any{"distance": "far"}),
+			expected: []domain.FieldError{
+				{Field: "distance", Code: domain.CodeInvalidType, Message: "must be of type number"},
+			},
+		},
+		{
+			name: "number instead of UUID",
+			body: validInterval(map[string]any{"activity_id": 42}),
+			expected: []domain.FieldError{
+				{Field: "activity_id", Code: domain.CodeInvalidType, Message: "must be of type string"},
+			},
+		},
+		{
+			name: "invalid duration",
+			body: validInterval(map[string]any{"duration": "fast"}),
+			expected: []domain.FieldError{
+				{Field: "duration", Code: domain.CodeInvalidFormat, Message: `must be a duration like "1:45.32", "45.1" or "1m45s"`},
+			},
+		},
+		{
+			name: "malformed JSON",
+			body: "{",
+			expected: []domain.FieldError{
+				{Field: "body", Code: domain.CodeInvalidFormat, Message: "must be valid JSON"},
+			},
+		},
+		{
+			name: "empty body",
+			body: "",
+			expected: []domain.FieldError{
+				{Field: "body", Code: domain.CodeRequired, Message: "is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, problem := bindProblem[CreateIntervalRequest](t, tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, tt.expected, problem.Errors)
+		})
+	}
+
+	t.Run("rest interval without distance", func(t *testing.T) {
+		status, _ := bindProblem[CreateIntervalRequest](t, validInterval(map[string]any{"distance": 0, "type": "rest", "duration": "30.5"}))
+
+		assert.Equal(t, http.StatusNoContent, status)
+	})
+
+	t.Run("activity ranges", func(t *testing.T) {
+		status, problem := bindProblem[CreateActivityRequest](t, `{
+			"user_id": "`+uuid.NewString()+`", "date": "2023-10-01", "duration": "30m",
+			"distance": -100, "laps": 4, "pool_size": 25, "location_type": "pool",
+			"heart_rate_max": 400
+		}`)
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, []domain.FieldError{
+			{Field: "distance", Code: domain.CodeOutOfRange, Message: "must be greater than 0"},
+			{Field: "heart_rate_max", Code: domain.CodeOutOfRange, Message: "must be at most 250"},
+		}, problem.Errors)
+	})
+
+	t.Run("activity enum", func(t *testing.T) {
+		status, problem := bindProblem[CreateActivityRequest](t, `{"feeling": "great"}`)
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, []domain.FieldError{
+			{Field: "feeling", Code: domain.CodeUnknownValue, Message: "must be one of: excellent, good, regular, tired, bad"},
+		}, problem.Errors)
+	})
+
+	t.Run("enum of a domain type", func(t *testing.T) {
+		status, problem := bindProblem[domain.User](t, `{"display_unit": "furlongs"}`)
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, []domain.FieldError{
+			{Field: "display_unit", Code: domain.CodeUnknownValue, Message: "must be one of: meters, yards"},
+		}, problem.Errors)
+	})
+
+	t.Run("user ranges", func(t *testing.T) {
+		status, problem := bindProblem[CreateUserRequest](t, `{
+			"name": "Ana", "email": "not-an-email", "city": "SP", "phone": "123",
+			"age": 150
+		}`)
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, []domain.FieldError{
+			{Field: "email", Code: domain.CodeInvalidFormat, Message: "must be a valid email"},
+			{Field: "age", Code: domain.CodeOutOfRange, Message: "must be at most 120"},
+		}, problem.Errors)
+	})
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/app"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// WebhookHandler handles HTTP requests related to webhooks
+type WebhookHandler struct {
+	service app.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(service app.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Registers an endpoint the user's events are posted to, as JSON with the event ID, type, time and data.
+// @Description Each delivery is signed: the X-Webhook-Signature header is "sha256=" followed by the hex-encoded HMAC-SHA256,
+// @Description keyed by the webhook secret, of the X-Webhook-Timestamp header, a dot and the raw body.
+// @Description The secret is only returned here. Deliveries not acknowledged with a 2xx status are retried with exponential backoff,
+// @Description with the same X-Webhook-ID. Only the user and admins may register one.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param webhook body handler.CreateWebhookRequest true "Endpoint and events"
+// @Success 201 {object} domain.Webhook "Webhook successfully registered, with its secret"
+// @Failure 400 {object} ProblemDetails "Invalid input"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "User not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err, &req))
+		return
+	}
+	slices.Sort(req.Events)
+
+	webhook, err := h.service.CreateWebhook(c.Request.Context(), domain.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Events: slices.Compact(req.Events),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhooks godoc
+// @Summary Get the webhooks of a user
+// @Description Returns the webhooks of the user, oldest first, without their secrets
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {array} domain.Webhook "Webhooks"
+// @Failure 400 {object} ProblemDetails "Invalid user ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/users/{id}/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	webhooks, err := h.service.GetWebhooksByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Deletes a webhook along with its delivery log; the deliveries still queued are dropped
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 204 "Webhook successfully deleted"
+// @Failure 400 {object} ProblemDetails "Invalid webhook ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "Webhook not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDeliveries godoc
+// @Summary Get the delivery log of a webhook
+// @Description Returns the latest 100 attempts at delivering events to the webhook, most recent first,
+// @Description each with the status of the response or the error that prevented one
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {array} domain.WebhookDelivery "Delivery log"
+// @Failure 400 {object} ProblemDetails "Invalid webhook ID"
+// @Failure 401 {object} ProblemDetails "Not authenticated"
+// @Failure 403 {object} ProblemDetails "Neither the user nor an admin"
+// @Failure 404 {object} ProblemDetails "Webhook not found"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(invalidParam("id", domain.CodeInvalidFormat, "must be a valid UUID"))
+		return
+	}
+
+	deliveries, err := h.service.GetDeliveries(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
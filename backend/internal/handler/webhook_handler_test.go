@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookService is a mock implementation of app.WebhookService
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	args := m.Called(webhook)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhooksByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) GetDeliveries(ctx context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error) {
+	args := m.Called(webhookID)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockWebhookService)
+	handler := NewWebhookHandler(service)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/users/:id/webhooks", handler.CreateWebhook)
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		webhook := domain.Webhook{
+			UserID: userID,
+			URL:    "https://hooks.example.com/swims",
+			Events: []domain.WebhookEvent{domain.WebhookActivityCreated, domain.WebhookRecordAchieved},
+		}
+		created := webhook
+		created.ID, created.Secret = uuid.New(), "whsec_42"
+		service.On("CreateWebhook", webhook).Return(created, nil)
+
+		body := `{"url":"https://hooks.example.com/swims","events":["record.achieved","activity.created","record.achieved"]}`
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userID.String()+"/webhooks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"secret":"whsec_42"`)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		tests := map[string]string{
+			"unknown event": `{"url":"https://hooks.example.com/swims","events":["activity.viewed"]}`,
+			"no events":     `{"url":"https://hooks.example.com/swims","events":[]}`,
+			"invalid URL":   `{"url":"hooks","events":["activity.created"]}`,
+		}
+		for name, body := range tests {
+			t.Run(name, func(t *testing.T) {
+				req, _ := http.NewRequest(http.MethodPost, "/users/"+uuid.NewString()+"/webhooks", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			})
+		}
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockWebhookService)
+	handler := NewWebhookHandler(service)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.DELETE("/webhooks/:id", handler.DeleteWebhook)
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+		service.On("DeleteWebhook", id).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/webhooks/"+id.String(), nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		id := uuid.New()
+		service.On("DeleteWebhook", id).Return(domain.NewForbiddenError("only the user and admins may access the user's webhooks"))
+
+		req, _ := http.NewRequest(http.MethodDelete, "/webhooks/"+id.String(), nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
+
+func TestGetDeliveries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockWebhookService)
+	handler := NewWebhookHandler(service)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/webhooks/:id/deliveries", handler.GetDeliveries)
+
+	id := uuid.New()
+	service.On("GetDeliveries", id).Return([]domain.WebhookDelivery{{
+		ID:         uuid.New(),
+		WebhookID:  id,
+		Event:      domain.WebhookActivityUpdated,
+		Attempt:    3,
+		StatusCode: 500,
+		Error:      "responded 500 Internal Server Error",
+	}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/webhooks/"+id.String()+"/deliveries", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"event":"activity.updated","attempt":3,"status_code":500`)
+}
@@ -0,0 +1,325 @@
+// Package jobs runs work too slow for a request in the background, from a queue
+// of jobs stored in PostgreSQL. Any number of API instances can run workers on
+// the same queue: each job is leased to one worker at a time, retried with
+// exponential backoff when it fails, and run again if its worker dies
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+)
+
+// Retry delays: the first retry waits initialBackoff, and each following one
+// waits twice as long as the previous, up to maxBackoff
+const (
+	initialBackoff = 5 * time.Second
+	maxBackoff     = 10 * time.Minute
+)
+
+// leaseMargin is how much longer than the job timeout a job is leased to its
+// worker, leaving time to record the outcome before another worker may claim it
+const leaseMargin = time.Minute
+
+// Config defines how jobs are run
+type Config struct {
+	// Concurrency is the number of jobs run at the same time by the runner
+	Concurrency int
+	// PollInterval is how often idle workers look for due jobs, e.g., retries,
+	// or jobs enqueued by other instances
+	PollInterval time.Duration
+	// Timeout bounds each attempt of a job
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts after which a failing job is given up
+	MaxAttempts int
+}
+
+// Handler runs a job of some type; an error fails the attempt, and the job is
+// retried unless the error is permanent or it was the last attempt
+type Handler func(ctx context.Context, job domain.Job) error
+
+// permanentError marks the failure of a job that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps the error of a handler so the job fails without being retried
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped by Permanent
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// NewJob builds a job of the given type working for a user, or for no user if
+// userID is not valid, with the payload encoded as JSON
+func NewJob(jobType string, userID uuid.NullUUID, payload any) (domain.Job, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return domain.Job{}, fmt.Errorf("encoding %s job payload: %w", jobType, err)
+	}
+	return domain.Job{ID: uuid.New(), Type: jobType, UserID: userID, Payload: encoded}, nil
+}
+
+// Runner enqueues jobs and runs them with a pool of workers
+type Runner struct {
+	repo     repository.JobRepository
+	cfg      Config
+	handlers map[string]Handler
+	// intervals holds the interval of the recurring job types, registered with Every
+	intervals map[string]time.Duration
+	// unscheduled holds when the next run of the recurring job types whose
+	// scheduling failed is due, for the idle workers to schedule it again
+	mu          sync.Mutex
+	unscheduled map[string]time.Time
+	// wake tells an idle worker that a job was enqueued, without waiting for the next poll
+	wake chan struct{}
+}
+
+// NewRunner creates a new Runner; handlers must be registered with Handle before it is run
+func NewRunner(repo repository.JobRepository, cfg Config) *Runner {
+	return &Runner{
+		repo:        repo,
+		cfg:         cfg,
+		handlers:    make(map[string]Handler),
+		intervals:   make(map[string]time.Duration),
+		unscheduled: make(map[string]time.Time),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Handle registers the handler of a job type
+func (r *Runner) Handle(jobType string, handler Handler) {
+	r.handlers[jobType] = handler
+}
+
+// Every registers the handler of a recurring job type, run once the runner starts
+// and then every interval after each run completes. The instances sharing the
+// queue run a single job of the type at a time; it has no user and no payload
+func (r *Runner) Every(jobType string, interval time.Duration, handler Handler) {
+	r.Handle(jobType, handler)
+	r.intervals[jobType] = interval
+}
+
+// Enqueue queues a job built by NewJob, to be run as soon as a worker is free,
+// and returns it as stored
+func (r *Runner) Enqueue(ctx context.Context, job domain.Job) (domain.Job, error) {
+	if _, ok := r.handlers[job.Type]; !ok {
+		return domain.Job{}, fmt.Errorf("no handler for %s jobs", job.Type)
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = r.cfg.MaxAttempts
+	}
+	if err := r.repo.EnqueueJob(ctx, job); err != nil {
+		return domain.Job{}, err
+	}
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return r.repo.GetJobByID(ctx, job.ID)
+}
+
+// Run runs the due jobs until ctx is done, then waits for the jobs in flight to
+// complete; these are not canceled with ctx but bounded by the job timeout
+func (r *Runner) Run(ctx context.Context) {
+	types := make([]string, 0, len(r.handlers))
+	for jobType := range r.handlers {
+		types = append(types, jobType)
+	}
+
+	var wg sync.WaitGroup
+	for jobType := range r.intervals {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.start(ctx, jobType)
+		}()
+	}
+	for range r.cfg.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx, types)
+		}()
+	}
+	wg.Wait()
+}
+
+// start schedules the first run of a recurring job type, retrying every poll
+// interval until it is scheduled or ctx is done
+func (r *Runner) start(ctx context.Context, jobType string) {
+	for r.schedule(ctx, jobType, 0) != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// schedule queues the next run of a recurring job type after delay, unless one
+// is already queued or running
+func (r *Runner) schedule(ctx context.Context, jobType string, delay time.Duration) error {
+	job, err := NewJob(jobType, uuid.NullUUID{}, struct{}{})
+	if err == nil {
+		job.MaxAttempts = r.cfg.MaxAttempts
+		err = r.repo.ScheduleJob(ctx, job, delay)
+	}
+	if err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "scheduling recurring job failed", "job_type", jobType, "error", err.Error())
+	}
+	return err
+}
+
+// work claims and runs due jobs one at a time, until ctx is done, scheduling
+// again the recurring job types whose scheduling failed before each claim
+func (r *Runner) work(ctx context.Context, types []string) {
+	poll := time.NewTimer(r.cfg.PollInterval)
+	defer poll.Stop()
+	for {
+		r.reschedule(ctx)
+		job, err := r.repo.ClaimJob(ctx, types, r.cfg.Timeout+leaseMargin)
+		if err == nil {
+			r.run(ctx, job)
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			slog.ErrorContext(ctx, "claiming job failed", "error", err.Error())
+		}
+
+		poll.Reset(r.cfg.PollInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-poll.C:
+		}
+	}
+}
+
+// run makes an attempt at a claimed job and records its outcome
+func (r *Runner) run(ctx context.Context, job domain.Job) {
+	logger := slog.Default().With("job_id", job.ID.String(), "job_type", job.Type, "attempt", job.Attempts)
+	ctx = logging.WithLogger(context.WithoutCancel(ctx), logger)
+
+	// A job claimed again after its lease expired on the last attempt is not run
+	// once more, as it may be what kills its workers
+	if job.Attempts > job.MaxAttempts {
+		logger.ErrorContext(ctx, "job failed", "error", "lease expired")
+		if r.record(ctx, r.repo.FailJob(ctx, job.ID, job.Attempts, "lease expired")) {
+			r.next(ctx, job)
+		}
+		return
+	}
+
+	err := r.attempt(ctx, job)
+	switch {
+	case err == nil:
+		logger.InfoContext(ctx, "job succeeded")
+		if r.record(ctx, r.repo.CompleteJob(ctx, job.ID, job.Attempts)) {
+			r.next(ctx, job)
+		}
+	case IsPermanent(err) || job.LastAttempt():
+		logger.ErrorContext(ctx, "job failed", "error", err.Error())
+		if r.record(ctx, r.repo.FailJob(ctx, job.ID, job.Attempts, err.Error())) {
+			r.next(ctx, job)
+		}
+	default:
+		delay := backoff(job.Attempts)
+		logger.WarnContext(ctx, "job attempt failed, retrying", "error", err.Error(), "retry_in", delay.String())
+		r.record(ctx, r.repo.RetryJob(ctx, job.ID, job.Attempts, delay, err.Error()))
+	}
+}
+
+// next schedules the next run of a completed job of a recurring type, left to
+// reschedule if it fails; A job retried is not complete, its retry is the next run
+func (r *Runner) next(ctx context.Context, job domain.Job) {
+	interval, ok := r.intervals[job.Type]
+	if !ok {
+		return
+	}
+	if r.schedule(ctx, job.Type, interval) != nil {
+		r.mu.Lock()
+		r.unscheduled[job.Type] = time.Now().Add(interval)
+		r.mu.Unlock()
+	}
+}
+
+// reschedule schedules again the next run of the recurring job types whose
+// scheduling failed, at the time it was due or at once if that has passed
+func (r *Runner) reschedule(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for jobType, due := range r.unscheduled {
+		if r.schedule(ctx, jobType, max(time.Until(due), 0)) == nil {
+			delete(r.unscheduled, jobType)
+		}
+	}
+}
+
+// attempt runs the handler of a job within the job timeout, recovering from panics
+func (r *Runner) attempt(ctx context.Context, job domain.Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return r.handlers[job.Type](ctx, job)
+}
+
+// record reports whether the outcome of a job was recorded, logging why not: a
+// job whose lease was lost is another worker's, else it is run again once its
+// lease expires
+func (r *Runner) record(ctx context.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, repository.ErrLeaseLost):
+		logging.FromContext(ctx).WarnContext(ctx, "job lease lost, outcome discarded")
+	default:
+		logging.FromContext(ctx).ErrorContext(ctx, "recording job outcome failed", "error", err.Error())
+	}
+	return false
+}
+
+// backoff returns how long to wait before retrying a job after its nth failed attempt
+func backoff(attempt int) time.Duration {
+	delay := initialBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,394 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryJobRepository is an in-memory JobRepository; retried jobs are due at once
+type memoryJobRepository struct {
+	mu     sync.Mutex
+	jobs   map[uuid.UUID]*domain.Job
+	delays []time.Duration
+}
+
+func newMemoryJobRepository() *memoryJobRepository {
+	return &memoryJobRepository{jobs: make(map[uuid.UUID]*domain.Job)}
+}
+
+func (r *memoryJobRepository) EnqueueJob(ctx context.Context, job domain.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job.Status = domain.JobQueued
+	r.jobs[job.ID] = &job
+	return nil
+}
+
+func (r *memoryJobRepository) ScheduleJob(ctx context.Context, job domain.Job, delay time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, scheduled := range r.jobs {
+		if scheduled.Type == job.Type && (scheduled.Status == domain.JobQueued || scheduled.Status == domain.JobRunning) {
+			return nil
+		}
+	}
+	job.Status = domain.JobQueued
+	job.RunAt = time.Now().Add(delay)
+	r.jobs[job.ID] = &job
+	return nil
+}
+
+func (r *memoryJobRepository) ClaimJob(ctx context.Context, types []string, lease time.Duration) (domain.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Job{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.Status == domain.JobQueued && !job.RunAt.After(time.Now()) {
+			job.Status = domain.JobRunning
+			job.Attempts++
+			return *job, nil
+		}
+	}
+	return domain.Job{}, domain.NewNotFoundError("due job not found")
+}
+
+func (r *memoryJobRepository) finish(id uuid.UUID, attempt int, status domain.JobStatus, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != domain.JobRunning || job.Attempts != attempt {
+		return repository.ErrLeaseLost
+	}
+	job.Status = status
+	job.LastError = lastError
+	return nil
+}
+
+func (r *memoryJobRepository) CompleteJob(ctx context.Context, id uuid.UUID, attempt int) error {
+	return r.finish(id, attempt, domain.JobSucceeded, "")
+}
+
+func (r *memoryJobRepository) RetryJob(ctx context.Context, id uuid.UUID, attempt int, delay time.Duration, lastError string) error {
+	r.mu.Lock()
+	r.delays = append(r.delays, delay)
+	r.mu.Unlock()
+	return r.finish(id, attempt, domain.JobQueued, lastError)
+}
+
+func (r *memoryJobRepository) FailJob(ctx context.Context, id uuid.UUID, attempt int, lastError string) error {
+	return r.finish(id, attempt, domain.JobFailed, lastError)
+}
+
+func (r *memoryJobRepository) GetJobByID(ctx context.Context, id uuid.UUID) (domain.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return domain.Job{}, domain.NewNotFoundError("job not found")
+	}
+	return *job, nil
+}
+
+var testConfig = Config{Concurrency: 2, PollInterval: time.Millisecond, Timeout: time.Second, MaxAttempts: 3}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, backoff(1))
+	assert.Equal(t, 10*time.Second, backoff(2))
+	assert.Equal(t, 40*time.Second, backoff(4))
+	assert.Equal(t, maxBackoff, backoff(10))
+	assert.Equal(t, maxBackoff, backoff(1000))
+}
+
+func TestNewJob(t *testing.T) {
+	userID := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	job, err := NewJob("export", userID, map[string]string{"export_id": "42"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, job.ID)
+	assert.Equal(t, "export", job.Type)
+	assert.Equal(t, userID, job.UserID)
+	assert.JSONEq(t, `{"export_id":"42"}`, string(job.Payload))
+
+	_, err = NewJob("export", userID, func() {})
+	assert.Error(t, err)
+}
+
+func TestEnqueue(t *testing.T) {
+	repo := newMemoryJobRepository()
+	runner := NewRunner(repo, testConfig)
+	runner.Handle("export", func(ctx context.Context, job domain.Job) error { return nil })
+
+	job, err := NewJob("export", uuid.NullUUID{}, nil)
+	assert.NoError(t, err)
+	enqueued, err := runner.Enqueue(context.Background(), job)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobQueued, enqueued.Status)
+	assert.Equal(t, 3, enqueued.MaxAttempts)
+
+	job, _ = NewJob("import", uuid.NullUUID{}, nil)
+	_, err = runner.Enqueue(context.Background(), job)
+	assert.EqualError(t, err, "no handler for import jobs")
+}
+
+func TestRun(t *testing.T) {
+	t.Run("runs the jobs until canceled", func(t *testing.T) {
+		repo := newMemoryJobRepository()
+		runner := NewRunner(repo, testConfig)
+
+		var mu sync.Mutex
+		ran := map[uuid.UUID]int{}
+		runner.Handle("export", func(ctx context.Context, job domain.Job) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ran[job.ID]++
+			return nil
+		})
+
+		var ids []uuid.UUID
+		for range 5 {
+			job, _ := NewJob("export", uuid.NullUUID{}, nil)
+			_, err := runner.Enqueue(context.Background(), job)
+			assert.NoError(t, err)
+			ids = append(ids, job.ID)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			runner.Run(ctx)
+		}()
+
+		assert.Eventually(t, func() bool {
+			for _, id := range ids {
+				if job, _ := repo.GetJobByID(context.Background(), id); job.Status != domain.JobSucceeded {
+					return false
+				}
+			}
+			return true
+		}, time.Second, time.Millisecond)
+		cancel()
+		<-done
+
+		for _, id := range ids {
+			assert.Equal(t, 1, ran[id], "each job runs once")
+		}
+	})
+
+	t.Run("waits for the jobs in flight", func(t *testing.T) {
+		repo := newMemoryJobRepository()
+		runner := NewRunner(repo, testConfig)
+
+		started := make(chan struct{})
+		runner.Handle("export", func(ctx context.Context, job domain.Job) error {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+			return ctx.Err()
+		})
+		job, _ := NewJob("export", uuid.NullUUID{}, nil)
+		_, err := runner.Enqueue(context.Background(), job)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+		runner.Run(ctx)
+
+		completed, _ := repo.GetJobByID(context.Background(), job.ID)
+		assert.Equal(t, domain.JobSucceeded, completed.Status, "the job is not canceled by the shutdown")
+	})
+}
+
+func TestEvery(t *testing.T) {
+	repo := newMemoryJobRepository()
+	var mu sync.Mutex
+	ran := 0
+	newRunner := func() *Runner {
+		runner := NewRunner(repo, testConfig)
+		runner.Every("purge", time.Hour, func(ctx context.Context, job domain.Job) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ran++
+			return nil
+		})
+		return runner
+	}
+
+	// Two instances share the queue
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			newRunner().Run(ctx)
+		}()
+	}
+
+	next := func() []domain.Job {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		var queued []domain.Job
+		for _, job := range repo.jobs {
+			if job.Status == domain.JobQueued {
+				queued = append(queued, *job)
+			}
+		}
+		return queued
+	}
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return ran == 1 && len(next()) == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	assert.Equal(t, 1, ran, "a single instance runs the job")
+	queued := next()
+	assert.Len(t, queued, 1, "the next run is scheduled once")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), queued[0].RunAt, time.Minute)
+	assert.Equal(t, 3, queued[0].MaxAttempts)
+}
+
+func TestRunner_Outcomes(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		err      error
+		panics   bool
+		want     domain.JobStatus
+		retries  int
+	}{
+		{name: "succeeded", want: domain.JobSucceeded},
+		{name: "retried", err: errors.New("connection refused"), want: domain.JobQueued, retries: 1},
+		{name: "permanent error", err: Permanent(errors.New("user not found")), want: domain.JobFailed},
+		{name: "last attempt", attempts: 2, err: errors.New("connection refused"), want: domain.JobFailed},
+		{name: "panic", panics: true, want: domain.JobQueued, retries: 1},
+		{name: "lease expired on the last attempt", attempts: 3, want: domain.JobFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryJobRepository()
+			runner := NewRunner(repo, testConfig)
+			ran := false
+			runner.Handle("export", func(ctx context.Context, job domain.Job) error {
+				ran = true
+				if tt.panics {
+					panic("nil map")
+				}
+				return tt.err
+			})
+
+			job, _ := NewJob("export", uuid.NullUUID{}, nil)
+			job.MaxAttempts = 3
+			job.Attempts = tt.attempts
+			assert.NoError(t, repo.EnqueueJob(context.Background(), job))
+			claimed, err := repo.ClaimJob(context.Background(), []string{"export"}, time.Minute)
+			assert.NoError(t, err)
+
+			runner.run(context.Background(), claimed)
+
+			got, _ := repo.GetJobByID(context.Background(), job.ID)
+			assert.Equal(t, tt.want, got.Status)
+			assert.Len(t, repo.delays, tt.retries)
+			assert.Equal(t, tt.attempts < 3, ran)
+			if tt.err != nil {
+				assert.Equal(t, tt.err.Error(), got.LastError)
+			}
+		})
+	}
+}
+
+func TestRunner_LeaseLost(t *testing.T) {
+	repo := newMemoryJobRepository()
+	runner := NewRunner(repo, testConfig)
+	runner.Every("purge", time.Hour, func(ctx context.Context, job domain.Job) error { return nil })
+
+	job, _ := NewJob("purge", uuid.NullUUID{}, nil)
+	assert.NoError(t, repo.EnqueueJob(context.Background(), job))
+	stale, err := repo.ClaimJob(context.Background(), []string{"purge"}, time.Minute)
+	assert.NoError(t, err)
+
+	// The lease expires and another worker claims the job again
+	repo.jobs[job.ID].Status = domain.JobQueued
+	_, err = repo.ClaimJob(context.Background(), []string{"purge"}, time.Minute)
+	assert.NoError(t, err)
+
+	runner.run(context.Background(), stale)
+
+	got, _ := repo.GetJobByID(context.Background(), job.ID)
+	assert.Equal(t, domain.JobRunning, got.Status, "the outcome of the stale attempt is discarded")
+	assert.Equal(t, 2, got.Attempts)
+	assert.Len(t, repo.jobs, 1, "the next run is left to the worker holding the lease")
+}
+
+// flakyJobRepository fails the next scheduling of a job once failSchedule is set
+type flakyJobRepository struct {
+	*memoryJobRepository
+	failSchedule bool
+}
+
+func (r *flakyJobRepository) ScheduleJob(ctx context.Context, job domain.Job, delay time.Duration) error {
+	r.mu.Lock()
+	failed := r.failSchedule
+	r.failSchedule = false
+	r.mu.Unlock()
+	if failed {
+		return errors.New("connection refused")
+	}
+	return r.memoryJobRepository.ScheduleJob(ctx, job, delay)
+}
+
+func TestEvery_RetriesScheduling(t *testing.T) {
+	repo := &flakyJobRepository{memoryJobRepository: newMemoryJobRepository()}
+	runner := NewRunner(repo, testConfig)
+	runner.Every("purge", time.Hour, func(ctx context.Context, job domain.Job) error {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		repo.failSchedule = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.Run(ctx)
+	}()
+
+	queued := func() []domain.Job {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		var queued []domain.Job
+		for _, job := range repo.jobs {
+			if job.Status == domain.JobQueued {
+				queued = append(queued, *job)
+			}
+		}
+		return queued
+	}
+	assert.Eventually(t, func() bool {
+		repo.mu.Lock()
+		ran := len(repo.jobs) == 2
+		repo.mu.Unlock()
+		return ran && len(queued()) == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	next := queued()
+	assert.Len(t, next, 1, "the next run is scheduled once scheduling succeeds")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), next[0].RunAt, time.Minute)
+}
@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != slog.Default() {
+		t.Error("expected the default logger for a context without a logger")
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, slog.LevelInfo).With("request_id", "abc")
+	ctx := WithLogger(context.Background(), logger)
+
+	FromContext(ctx).Debug("hidden")
+	FromContext(ctx).Info("request", "status", 200)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "request" || record["request_id"] != "abc" || record["status"] != float64(200) {
+		t.Errorf("unexpected record %v", record)
+	}
+}
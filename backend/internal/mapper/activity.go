@@ -5,29 +5,46 @@ import (
 	"github.com/liviaruegger/MAC0350/backend/internal/entity"
 )
 
-// MapActivityToEntity maps a domain.Activity to an entity.Activity with intervals
-func MapActivityToEntity(activity domain.Activity, intervals []domain.Interval) entity.Activity {
+// MapActivityToEntity maps a domain.Activity to an entity.Activity with intervals,
+// annotated with the given pace zones (which may be nil); distances and paces are
+// displayed in the given unit, usually the user's preference
+func MapActivityToEntity(activity domain.Activity, intervals []domain.Interval, zones []domain.PaceZone, unit domain.DistanceUnit) entity.Activity {
+	unit = unit.OrDefault()
 	mappedIntervals := make([]entity.Interval, len(intervals))
 	for i, interval := range intervals {
-		mappedIntervals[i] = MapIntervalToEntity(interval)
+		mappedIntervals[i] = MapIntervalToEntity(interval, activity.PoolLength(), zones, unit)
 	}
 
-	return entity.Activity{
+	mapped := entity.Activity{
 		ID:             activity.ID,
 		UserID:         activity.UserID,
 		Date:           activity.Date,
 		Start:          activity.Start,
 		Duration:       activity.Duration,
-		Distance:       activity.Distance,
+		Distance:       domain.ConvertMeters(activity.Distance, unit),
 		Laps:           activity.Laps,
 		PoolSize:       activity.PoolSize,
-		LocationType:   entity.LocationType(activity.LocationType),
+		PoolUnit:       activity.PoolUnit.OrDefault(),
+		Course:         string(activity.Course()),
+		DistanceUnit:   unit,
+		LocationType:   activity.LocationType,
 		LocationName:   activity.LocationName,
-		Feeling:        entity.FeelingType(activity.Feeling),
+		Feeling:        activity.Feeling,
 		HeartRateAvg:   activity.HeartRateAvg,
 		HeartRateMax:   activity.HeartRateMax,
-		AvgPacePer100m: activity.AvgPaceFormatted(),
+		RPE:            activity.RPE,
+		AvgPacePer100m: domain.FormatPace(activity.AvgPacePer100(unit)),
 		Notes:          activity.Notes,
 		Intervals:      mappedIntervals,
+		Version:        activity.Version,
+		CreatedAt:      activity.CreatedAt,
+		UpdatedAt:      activity.UpdatedAt,
 	}
+	if metrics, ok := activity.StrokeMetrics(intervals); ok {
+		mapped.StrokeCount = metrics.StrokeCount
+		mapped.SWOLF = metrics.SWOLF
+		mapped.DistancePerStroke = domain.ConvertMeters(metrics.DistancePerStroke, unit)
+	}
+
+	return mapped
 }
@@ -14,7 +14,7 @@ func TestMapActivityToEntity(t *testing.T) {
 		ID:           uuid.New(),
 		UserID:       uuid.New(),
 		Start:        time.Now(),
-		Duration:     domain.DurationString("1h30m"),
+		Duration:     domain.MustParseDuration("1h30m"),
 		Distance:     4000,
 		Laps:         160,
 		PoolSize:     25,
@@ -25,15 +25,24 @@ func TestMapActivityToEntity(t *testing.T) {
 		{
 			ID:         uuid.New(),
 			ActivityID: activity.ID,
-			Duration:   domain.DurationString("30m"),
+			Duration:   domain.MustParseDuration("30m"),
 			Distance:   1000,
 			Type:       domain.IntervalSwim,
 			Stroke:     domain.StrokeFreestyle,
 			Notes:      "Test interval",
 		},
+		{
+			ID:          uuid.New(),
+			ActivityID:  activity.ID,
+			Duration:    domain.MustParseDuration("1m40s"),
+			Distance:    100,
+			Type:        domain.IntervalMainSet,
+			Stroke:      domain.StrokeFreestyle,
+			StrokeCount: 68,
+		},
 	}
 
-	entity := MapActivityToEntity(activity, intervals)
+	entity := MapActivityToEntity(activity, intervals, nil, "")
 
 	assert.Equal(t, activity.ID, entity.ID)
 	assert.Equal(t, activity.UserID, entity.UserID)
@@ -42,7 +51,31 @@ func TestMapActivityToEntity(t *testing.T) {
 	assert.Equal(t, activity.Distance, entity.Distance)
 	assert.Equal(t, activity.Laps, entity.Laps)
 	assert.Equal(t, activity.PoolSize, entity.PoolSize)
-	assert.Equal(t, string(activity.LocationType), string(entity.LocationType))
+	assert.Equal(t, activity.LocationType, entity.LocationType)
 	assert.Equal(t, activity.Notes, entity.Notes)
 	assert.Len(t, entity.Intervals, len(intervals))
+	assert.Equal(t, 42.0, entity.Intervals[1].SWOLF)
+	assert.Equal(t, 68, entity.StrokeCount)
+	assert.Equal(t, 42.0, entity.SWOLF)
+	assert.Equal(t, domain.DistanceUnitMeters, entity.DistanceUnit)
+	assert.Equal(t, domain.DistanceUnitMeters, entity.PoolUnit)
+	assert.Equal(t, "SCM", entity.Course)
+}
+
+func TestMapActivityToEntity_Yards(t *testing.T) {
+	activity := domain.Activity{
+		ID:           uuid.New(),
+		Duration:     domain.MustParseDuration("15m0s"),
+		Distance:     914.4,
+		PoolSize:     25,
+		PoolUnit:     domain.DistanceUnitYards,
+		LocationType: domain.LocationPool,
+	}
+
+	entity := MapActivityToEntity(activity, nil, nil, domain.DistanceUnitYards)
+
+	assert.InDelta(t, 1000, entity.Distance, 1e-9)
+	assert.Equal(t, "01:30", entity.AvgPacePer100m)
+	assert.Equal(t, domain.DistanceUnitYards, entity.DistanceUnit)
+	assert.Equal(t, "SCY", entity.Course)
 }
@@ -0,0 +1,47 @@
+package mapper
+
+import (
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+)
+
+// MapCSSTestToEntity maps a domain.CSSTest to an entity.CSSTest with its pace zones
+func MapCSSTestToEntity(test domain.CSSTest) entity.CSSTest {
+	mapped := entity.CSSTest{
+		ID:             test.ID,
+		UserID:         test.UserID,
+		Date:           test.Date,
+		Time400:        test.Time400,
+		Time200:        test.Time200,
+		Unit:           test.Unit.OrDefault(),
+		CSSPacePer100m: domain.FormatPace(test.PacePer100m()),
+		Zones:          MapPaceZonesToEntity(test.Zones()),
+	}
+	if test.Interval400ID.Valid {
+		mapped.Interval400ID = &test.Interval400ID.UUID
+	}
+	if test.Interval200ID.Valid {
+		mapped.Interval200ID = &test.Interval200ID.UUID
+	}
+
+	return mapped
+}
+
+// MapPaceZonesToEntity maps domain.PaceZone values to entity.PaceZone values
+func MapPaceZonesToEntity(zones []domain.PaceZone) []entity.PaceZone {
+	mapped := make([]entity.PaceZone, len(zones))
+	for i, zone := range zones {
+		mapped[i] = entity.PaceZone{
+			Number: zone.Number,
+			Name:   zone.Name,
+		}
+		if zone.MinPace > 0 {
+			mapped[i].FastestPace = domain.FormatPace(zone.MinPace)
+		}
+		if zone.MaxPace > 0 {
+			mapped[i].SlowestPace = domain.FormatPace(zone.MaxPace)
+		}
+	}
+
+	return mapped
+}
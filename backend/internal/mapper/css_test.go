@@ -0,0 +1,35 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapCSSTestToEntity(t *testing.T) {
+	intervalID := uuid.New()
+	test := domain.CSSTest{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		Date:          "2023-10-01",
+		Time400:       domain.MustParseDuration("6m0s"),
+		Time200:       domain.MustParseDuration("2m50s"),
+		Interval400ID: uuid.NullUUID{UUID: intervalID, Valid: true},
+	}
+
+	entity := MapCSSTestToEntity(test)
+
+	assert.Equal(t, test.ID, entity.ID)
+	assert.Equal(t, test.UserID, entity.UserID)
+	assert.Equal(t, "6m0s", entity.Time400.String())
+	assert.Equal(t, "01:35", entity.CSSPacePer100m)
+	assert.Equal(t, &intervalID, entity.Interval400ID)
+	assert.Nil(t, entity.Interval200ID)
+	assert.Len(t, entity.Zones, 5)
+	assert.Equal(t, "01:45", entity.Zones[0].FastestPace)
+	assert.Empty(t, entity.Zones[0].SlowestPace)
+	assert.Empty(t, entity.Zones[4].FastestPace)
+	assert.Equal(t, "01:33", entity.Zones[4].SlowestPace)
+}
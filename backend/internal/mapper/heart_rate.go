@@ -0,0 +1,40 @@
+package mapper
+
+import (
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+)
+
+// MapHeartRateZonesToEntity maps the heart-rate zones of a domain.User to an entity.HeartRateZones
+func MapHeartRateZonesToEntity(user domain.User) entity.HeartRateZones {
+	zones := user.HeartRateZones()
+	mapped := make([]entity.HeartRateZone, len(zones))
+	for i, zone := range zones {
+		mapped[i] = entity.HeartRateZone{
+			Number:       zone.Number,
+			Name:         zone.Name,
+			MinHeartRate: zone.MinHR,
+			MaxHeartRate: zone.MaxHR,
+		}
+	}
+
+	return entity.HeartRateZones{
+		UserID: user.ID,
+		Basis:  string(user.HeartRateZoneBasis()),
+		Zones:  mapped,
+	}
+}
+
+// MapZoneTimesToEntity maps domain.ZoneTime values to entity.TimeInZone values
+func MapZoneTimesToEntity(times []domain.ZoneTime) []entity.TimeInZone {
+	mapped := make([]entity.TimeInZone, len(times))
+	for i, zoneTime := range times {
+		mapped[i] = entity.TimeInZone{
+			Zone:     zoneTime.Zone.Number,
+			Name:     zoneTime.Zone.Name,
+			Duration: domain.Duration(zoneTime.Duration),
+		}
+	}
+
+	return mapped
+}
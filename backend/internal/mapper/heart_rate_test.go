@@ -0,0 +1,47 @@
+package mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapHeartRateZonesToEntity(t *testing.T) {
+	user := domain.User{ID: uuid.New(), MaxHeartRate: 200}
+
+	entity := MapHeartRateZonesToEntity(user)
+
+	assert.Equal(t, user.ID, entity.UserID)
+	assert.Equal(t, "max_hr", entity.Basis)
+	assert.Len(t, entity.Zones, 5)
+	assert.Equal(t, "recovery", entity.Zones[0].Name)
+	assert.Equal(t, 0, entity.Zones[0].MinHeartRate)
+	assert.Equal(t, 120, entity.Zones[0].MaxHeartRate)
+	assert.Equal(t, 180, entity.Zones[4].MinHeartRate)
+	assert.Equal(t, 0, entity.Zones[4].MaxHeartRate)
+}
+
+func TestMapHeartRateZonesToEntity_NoData(t *testing.T) {
+	entity := MapHeartRateZonesToEntity(domain.User{})
+
+	assert.Empty(t, entity.Basis)
+	assert.Empty(t, entity.Zones)
+}
+
+func TestMapZoneTimesToEntity(t *testing.T) {
+	times := []domain.ZoneTime{
+		{Zone: domain.HeartRateZone{Number: 1, Name: "recovery"}, Duration: 15 * time.Minute},
+		{Zone: domain.HeartRateZone{Number: 2, Name: "aerobic"}},
+	}
+
+	entity := MapZoneTimesToEntity(times)
+
+	assert.Len(t, entity, 2)
+	assert.Equal(t, 1, entity[0].Zone)
+	assert.Equal(t, "recovery", entity[0].Name)
+	assert.Equal(t, domain.MustParseDuration("15m0s"), entity[0].Duration)
+	assert.Equal(t, domain.MustParseDuration("0s"), entity[1].Duration)
+}
@@ -5,15 +5,32 @@ import (
 	"github.com/liviaruegger/MAC0350/backend/internal/entity"
 )
 
-// MapIntervalToEntity maps a domain.Interval to an entity.Interval
-func MapIntervalToEntity(interval domain.Interval) entity.Interval {
-	return entity.Interval{
-		ID:         interval.ID,
-		ActivityID: interval.ActivityID,
-		Duration:   interval.Duration,
-		Distance:   interval.Distance,
-		Type:       entity.IntervalType(interval.Type),
-		Stroke:     entity.StrokeType(interval.Stroke),
-		Notes:      interval.Notes,
+// MapIntervalToEntity maps a domain.Interval to an entity.Interval, annotating it
+// with the pace zone its pace falls in, if any, and with its SWOLF in a pool of
+// the given length in meters (0 outside the pool); distances and paces are
+// displayed in the given unit
+func MapIntervalToEntity(interval domain.Interval, poolSize float64, zones []domain.PaceZone, unit domain.DistanceUnit) entity.Interval {
+	mapped := entity.Interval{
+		ID:                 interval.ID,
+		ActivityID:         interval.ActivityID,
+		Duration:           interval.Duration,
+		Distance:           domain.ConvertMeters(interval.Distance, unit),
+		Type:               interval.Type,
+		Stroke:             interval.Stroke,
+		Notes:              interval.Notes,
+		HeartRateAvg:       interval.HeartRateAvg,
+		PacePer100m:        domain.FormatPace(interval.PacePer100(unit)),
+		StrokeCount:        interval.TotalStrokes(),
+		LengthStrokeCounts: interval.LengthStrokeCounts,
+		SWOLF:              interval.SWOLF(poolSize),
+		DistancePerStroke:  domain.ConvertMeters(interval.DistancePerStroke(), unit),
+		CreatedAt:          interval.CreatedAt,
+		UpdatedAt:          interval.UpdatedAt,
 	}
+	if zone, ok := domain.ZoneForPace(zones, interval.PacePer100m()); ok {
+		mapped.PaceZone = zone.Number
+		mapped.PaceZoneName = zone.Name
+	}
+
+	return mapped
 }
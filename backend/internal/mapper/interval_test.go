@@ -12,14 +12,14 @@ func TestMapIntervalToEntity(t *testing.T) {
 	interval := domain.Interval{
 		ID:         uuid.New(),
 		ActivityID: uuid.New(),
-		Duration:   domain.DurationString("30m"),
+		Duration:   domain.MustParseDuration("30m"),
 		Distance:   1000,
 		Type:       domain.IntervalSwim,
 		Stroke:     domain.StrokeFreestyle,
 		Notes:      "Test interval",
 	}
 
-	entity := MapIntervalToEntity(interval)
+	entity := MapIntervalToEntity(interval, 25, nil, domain.DistanceUnitMeters)
 
 	assert.Equal(t, interval.ID, entity.ID)
 	assert.Equal(t, interval.ActivityID, entity.ActivityID)
@@ -28,4 +28,56 @@ func TestMapIntervalToEntity(t *testing.T) {
 	assert.Equal(t, string(interval.Type), string(entity.Type))
 	assert.Equal(t, string(interval.Stroke), string(entity.Stroke))
 	assert.Equal(t, interval.Notes, entity.Notes)
+	assert.Equal(t, "03:00", entity.PacePer100m)
+	assert.Zero(t, entity.PaceZone)
+	assert.Zero(t, entity.SWOLF)
+}
+
+func TestMapIntervalToEntity_WithZones(t *testing.T) {
+	interval := domain.Interval{
+		ID:       uuid.New(),
+		Duration: domain.MustParseDuration("1m35s"),
+		Distance: 100,
+		Type:     domain.IntervalMainSet,
+	}
+	test := domain.CSSTest{Time400: domain.MustParseDuration("6m0s"), Time200: domain.MustParseDuration("2m50s")}
+
+	entity := MapIntervalToEntity(interval, 25, test.Zones(), domain.DistanceUnitMeters)
+
+	assert.Equal(t, 4, entity.PaceZone)
+	assert.Equal(t, "threshold", entity.PaceZoneName)
+}
+
+func TestMapIntervalToEntity_WithStrokes(t *testing.T) {
+	interval := domain.Interval{
+		ID:                 uuid.New(),
+		Duration:           domain.MustParseDuration("1m40s"),
+		Distance:           100,
+		Type:               domain.IntervalSwim,
+		LengthStrokeCounts: []int{16, 17, 17, 18},
+	}
+
+	entity := MapIntervalToEntity(interval, 25, nil, domain.DistanceUnitMeters)
+
+	assert.Equal(t, 68, entity.StrokeCount)
+	assert.Equal(t, []int{16, 17, 17, 18}, entity.LengthStrokeCounts)
+	assert.Equal(t, 42.0, entity.SWOLF)
+	assert.Equal(t, 100.0/68, entity.DistancePerStroke)
+}
+
+func TestMapIntervalToEntity_Yards(t *testing.T) {
+	interval := domain.Interval{
+		ID:          uuid.New(),
+		Duration:    domain.MustParseDuration("1m30s"),
+		Distance:    91.44,
+		Type:        domain.IntervalSwim,
+		StrokeCount: 50,
+	}
+
+	entity := MapIntervalToEntity(interval, 25*domain.MetersPerYard, nil, domain.DistanceUnitYards)
+
+	assert.InDelta(t, 100, entity.Distance, 1e-9)
+	assert.Equal(t, "01:30", entity.PacePer100m)
+	assert.InDelta(t, 2, entity.DistancePerStroke, 1e-9)
+	assert.InDelta(t, 35, entity.SWOLF, 1e-9)
 }
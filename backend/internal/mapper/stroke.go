@@ -0,0 +1,17 @@
+package mapper
+
+import (
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+)
+
+// MapSessionStrokeEfficiencyToEntity maps the stroke metrics of a domain.Activity to an entity.SessionStrokeEfficiency
+func MapSessionStrokeEfficiencyToEntity(activity domain.Activity, metrics domain.StrokeMetrics) entity.SessionStrokeEfficiency {
+	return entity.SessionStrokeEfficiency{
+		ActivityID:        activity.ID,
+		Date:              activity.Day().Format(domain.DateLayout),
+		StrokeCount:       metrics.StrokeCount,
+		SWOLF:             metrics.SWOLF,
+		DistancePerStroke: metrics.DistancePerStroke,
+	}
+}
@@ -0,0 +1,22 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapSessionStrokeEfficiencyToEntity(t *testing.T) {
+	activity := domain.Activity{ID: uuid.New(), Date: "2023-10-01"}
+	metrics := domain.StrokeMetrics{StrokeCount: 140, SWOLF: 43.75, DistancePerStroke: 1.5}
+
+	entity := MapSessionStrokeEfficiencyToEntity(activity, metrics)
+
+	assert.Equal(t, activity.ID, entity.ActivityID)
+	assert.Equal(t, "2023-10-01", entity.Date)
+	assert.Equal(t, 140, entity.StrokeCount)
+	assert.Equal(t, 43.75, entity.SWOLF)
+	assert.Equal(t, 1.5, entity.DistancePerStroke)
+}
@@ -0,0 +1,30 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/entity"
+)
+
+// MapTrainingLoadToEntity maps the daily loads of a user over a period to an entity.TrainingLoad
+func MapTrainingLoadToEntity(userID uuid.UUID, from, to time.Time, loads []domain.DailyLoad) entity.TrainingLoad {
+	days := make([]entity.DailyTrainingLoad, len(loads))
+	for i, load := range loads {
+		days[i] = entity.DailyTrainingLoad{
+			Date:              load.Date.Format(domain.DateLayout),
+			Load:              load.Load,
+			AcuteLoad:         load.Acute,
+			ChronicLoad:       load.Chronic,
+			AcuteChronicRatio: load.Ratio(),
+		}
+	}
+
+	return entity.TrainingLoad{
+		UserID: userID,
+		From:   from.Format(domain.DateLayout),
+		To:     to.Format(domain.DateLayout),
+		Days:   days,
+	}
+}
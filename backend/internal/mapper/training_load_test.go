@@ -0,0 +1,30 @@
+package mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapTrainingLoadToEntity(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	loads := []domain.DailyLoad{
+		{Date: from, Load: 420, Acute: 60, Chronic: 10},
+		{Date: to, Load: 0, Acute: 60, Chronic: 10},
+	}
+
+	entity := MapTrainingLoadToEntity(userID, from, to, loads)
+
+	assert.Equal(t, userID, entity.UserID)
+	assert.Equal(t, "2023-10-01", entity.From)
+	assert.Equal(t, "2023-10-02", entity.To)
+	assert.Len(t, entity.Days, 2)
+	assert.Equal(t, "2023-10-01", entity.Days[0].Date)
+	assert.Equal(t, 420.0, entity.Days[0].Load)
+	assert.Equal(t, 6.0, entity.Days[0].AcuteChronicRatio)
+}
@@ -1,21 +1,24 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 )
 
 // ActivityRepository defines the interface for the activity repository
 type ActivityRepository interface {
-	CreateActivity(activity domain.Activity) error
-	GetAllActivities() ([]domain.Activity, error)
-	GetActivitiesByUser(userID uuid.UUID) ([]domain.Activity, error)
-	GetActivityByID(activityID uuid.UUID) (domain.Activity, error)
-	UpdateActivity(activity domain.Activity) error
-	DeleteActivity(activityID uuid.UUID) error
+	CreateActivity(ctx context.Context, activity domain.Activity) error
+	GetAllActivities(ctx context.Context) ([]domain.Activity, error)
+	GetActivitiesByUser(ctx context.Context, userID uuid.UUID) ([]domain.Activity, error)
+	GetRecentActivitiesByUser(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Activity, error)
+	GetActivityByID(ctx context.Context, activityID uuid.UUID) (domain.Activity, error)
+	GetActivitiesByIDs(ctx context.Context, activityIDs []uuid.UUID) ([]domain.Activity, error)
+	UpdateActivity(ctx context.Context, activity domain.Activity) error
+	DeleteActivity(ctx context.Context, activityID uuid.UUID) error
 }
 
 type PostgresActivityRepository struct {
@@ -26,36 +29,45 @@ func NewActivityRepository(db *sql.DB) *PostgresActivityRepository {
 	return &PostgresActivityRepository{db: db}
 }
 
-func (r *PostgresActivityRepository) CreateActivity(activity domain.Activity) error {
-	_, err := r.db.Exec(
+func (r *PostgresActivityRepository) CreateActivity(ctx context.Context, activity domain.Activity) error {
+	if err := checkReference(ctx, r.db, "users", "user_id", activity.UserID); err != nil {
+		return err
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
 		`INSERT INTO activities (
-			id, user_id, date, start, duration, distance, laps, pool_size,
-			location_type, location_name, feeling, heart_rate_avg, heart_rate_max, notes
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+			id, user_id, date, start, duration_ms, distance, laps, pool_size,
+			location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes,
+			pool_unit, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW())`,
 		activity.ID,
 		activity.UserID,
 		activity.Date,
 		activity.Start,
-		int64(activity.Duration.Seconds()),
+		activity.Duration,
 		activity.Distance,
 		activity.Laps,
 		activity.PoolSize,
-		string(activity.LocationType),
+		activity.LocationType,
 		activity.LocationName,
-		string(activity.Feeling),
+		activity.Feeling,
 		activity.HeartRateAvg,
 		activity.HeartRateMax,
+		activity.RPE,
 		activity.Notes,
+		activity.PoolUnit.OrDefault(),
 	)
 
-	return err
+	return translateError(ctx, err, "activity")
 }
 
-func (r *PostgresActivityRepository) GetAllActivities() ([]domain.Activity, error) {
-	rows, err := r.db.Query(
-		`SELECT id, user_id, date, start, duration, distance, laps, pool_size,
-		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, notes
-		 FROM activities`,
+func (r *PostgresActivityRepository) GetAllActivities(ctx context.Context) ([]domain.Activity, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size,
+		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes,
+		        pool_unit, version, created_at, updated_at
+		 FROM activities
+		 WHERE deleted_at IS NULL`,
 	)
 	if err != nil {
 		return nil, err
@@ -65,45 +77,45 @@ func (r *PostgresActivityRepository) GetAllActivities() ([]domain.Activity, erro
 	var activities []domain.Activity
 	for rows.Next() {
 		var a domain.Activity
-		var durationSeconds int64
-		var locationType, feeling string
 
 		err := rows.Scan(
 			&a.ID,
 			&a.UserID,
 			&a.Date,
 			&a.Start,
-			&durationSeconds,
+			&a.Duration,
 			&a.Distance,
 			&a.Laps,
 			&a.PoolSize,
-			&locationType,
+			&a.LocationType,
 			&a.LocationName,
-			&feeling,
+			&a.Feeling,
 			&a.HeartRateAvg,
 			&a.HeartRateMax,
+			&a.RPE,
 			&a.Notes,
+			&a.PoolUnit,
+			&a.Version,
+			&a.CreatedAt,
+			&a.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		a.Duration = domain.DurationString((time.Duration(durationSeconds) * time.Second).String())
-		a.LocationType = domain.LocationType(locationType)
-		a.Feeling = domain.FeelingType(feeling)
-
 		activities = append(activities, a)
 	}
 
 	return activities, nil
 }
 
-func (r *PostgresActivityRepository) GetActivitiesByUser(userID uuid.UUID) ([]domain.Activity, error) {
-	rows, err := r.db.Query(
-		`SELECT id, user_id, date, start, duration, distance, laps, pool_size,
-		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, notes
+func (r *PostgresActivityRepository) GetActivitiesByUser(ctx context.Context, userID uuid.UUID) ([]domain.Activity, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size,
+		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes,
+		        pool_unit, version, created_at, updated_at
 		 FROM activities
-		 WHERE user_id = $1`,
+		 WHERE user_id = $1 AND deleted_at IS NULL`,
 		userID,
 	)
 	if err != nil {
@@ -111,87 +123,163 @@ func (r *PostgresActivityRepository) GetActivitiesByUser(userID uuid.UUID) ([]do
 	}
 	defer rows.Close()
 
+	return scanActivities(rows)
+}
+
+// GetRecentActivitiesByUser returns the most recent activities of a user, up to limit, most
+// recent first; They are ordered by the date they were swum on, as backfilled ones are created late
+func (r *PostgresActivityRepository) GetRecentActivitiesByUser(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Activity, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size,
+		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes,
+		        pool_unit, version, created_at, updated_at
+		 FROM activities
+		 WHERE user_id = $1 AND deleted_at IS NULL
+		 ORDER BY date DESC, start DESC
+		 LIMIT $2`,
+		userID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanActivities(rows)
+}
+
+// scanActivities scans the activities selected by the queries listing them
+func scanActivities(rows *sql.Rows) ([]domain.Activity, error) {
 	var activities []domain.Activity
 	for rows.Next() {
 		var a domain.Activity
-		var durationSeconds int64
-		var locationType, feeling string
 
 		err := rows.Scan(
 			&a.ID,
 			&a.UserID,
 			&a.Date,
 			&a.Start,
-			&durationSeconds,
+			&a.Duration,
 			&a.Distance,
 			&a.Laps,
 			&a.PoolSize,
-			&locationType,
+			&a.LocationType,
 			&a.LocationName,
-			&feeling,
+			&a.Feeling,
 			&a.HeartRateAvg,
 			&a.HeartRateMax,
+			&a.RPE,
 			&a.Notes,
+			&a.PoolUnit,
+			&a.Version,
+			&a.CreatedAt,
+			&a.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		a.Duration = domain.DurationString((time.Duration(durationSeconds) * time.Second).String())
-		a.LocationType = domain.LocationType(locationType)
-		a.Feeling = domain.FeelingType(feeling)
-
 		activities = append(activities, a)
 	}
 
-	return activities, nil
+	return activities, rows.Err()
 }
 
-func (r *PostgresActivityRepository) GetActivityByID(activityID uuid.UUID) (domain.Activity, error) {
+func (r *PostgresActivityRepository) GetActivityByID(ctx context.Context, activityID uuid.UUID) (domain.Activity, error) {
 	var a domain.Activity
-	var durationSeconds int64
-	var locationType, feeling string
 
-	err := r.db.QueryRow(
-		`SELECT id, user_id, date, start, duration, distance, laps, pool_size,
-		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, notes
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size,
+		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes,
+		        pool_unit, version, created_at, updated_at
 		 FROM activities
-		 WHERE id = $1`,
+		 WHERE id = $1 AND deleted_at IS NULL`,
 		activityID,
 	).Scan(
 		&a.ID,
 		&a.UserID,
 		&a.Date,
 		&a.Start,
-		&durationSeconds,
+		&a.Duration,
 		&a.Distance,
 		&a.Laps,
 		&a.PoolSize,
-		&locationType,
+		&a.LocationType,
 		&a.LocationName,
-		&feeling,
+		&a.Feeling,
 		&a.HeartRateAvg,
 		&a.HeartRateMax,
+		&a.RPE,
 		&a.Notes,
+		&a.PoolUnit,
+		&a.Version,
+		&a.CreatedAt,
+		&a.UpdatedAt,
 	)
 	if err != nil {
-		return a, err
+		return a, translateError(ctx, err, "activity")
 	}
 
-	a.Duration = domain.DurationString((time.Duration(durationSeconds) * time.Second).String())
-	a.LocationType = domain.LocationType(locationType)
-	a.Feeling = domain.FeelingType(feeling)
-
 	return a, nil
 }
 
-func (r *PostgresActivityRepository) UpdateActivity(activity domain.Activity) error {
-	_, err := r.db.Exec(
+// GetActivitiesByIDs returns the activities with the given IDs in a single query;
+// Unknown and deleted activities are left out
+func (r *PostgresActivityRepository) GetActivitiesByIDs(ctx context.Context, activityIDs []uuid.UUID) ([]domain.Activity, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size,
+		        location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes,
+		        pool_unit, version, created_at, updated_at
+		 FROM activities
+		 WHERE id = ANY($1) AND deleted_at IS NULL`,
+		pq.Array(activityIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []domain.Activity
+	for rows.Next() {
+		var a domain.Activity
+
+		err := rows.Scan(
+			&a.ID,
+			&a.UserID,
+			&a.Date,
+			&a.Start,
+			&a.Duration,
+			&a.Distance,
+			&a.Laps,
+			&a.PoolSize,
+			&a.LocationType,
+			&a.LocationName,
+			&a.Feeling,
+			&a.HeartRateAvg,
+			&a.HeartRateMax,
+			&a.RPE,
+			&a.Notes,
+			&a.PoolUnit,
+			&a.Version,
+			&a.CreatedAt,
+			&a.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		activities = append(activities, a)
+	}
+
+	return activities, nil
+}
+
+func (r *PostgresActivityRepository) UpdateActivity(ctx context.Context, activity domain.Activity) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx,
 		`UPDATE activities SET
 			user_id = $2,
 			date = $3,
 			start = $4,
-			duration = $5,
+			duration_ms = $5,
 			distance = $6,
 			laps = $7,
 			pool_size = $8,
@@ -200,32 +288,58 @@ func (r *PostgresActivityRepository) UpdateActivity(activity domain.Activity) er
 			feeling = $11,
 			heart_rate_avg = $12,
 			heart_rate_max = $13,
-			notes = $14
-		WHERE id = $1`,
+			rpe = $14,
+			notes = $15,
+			pool_unit = $16,
+			version = version + 1,
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND ($17 = 0 OR version = $17)`,
 		activity.ID,
 		activity.UserID,
 		activity.Date,
 		activity.Start,
-		int64(activity.Duration.Seconds()),
+		activity.Duration,
 		activity.Distance,
 		activity.Laps,
 		activity.PoolSize,
-		string(activity.LocationType),
+		activity.LocationType,
 		activity.LocationName,
-		string(activity.Feeling),
+		activity.Feeling,
 		activity.HeartRateAvg,
 		activity.HeartRateMax,
+		activity.RPE,
 		activity.Notes,
+		activity.PoolUnit.OrDefault(),
+		activity.Version,
 	)
+	if err != nil {
+		return translateError(ctx, err, "activity")
+	}
 
-	return err
+	return expectUpdated(ctx, r.db, result, "activities", activity.ID, activity.Version, "activity")
 }
 
-func (r *PostgresActivityRepository) DeleteActivity(activityID uuid.UUID) error {
-	_, err := r.db.Exec(
-		`DELETE FROM activities WHERE id = $1`,
-		activityID,
-	)
+// DeleteActivity soft deletes the activity along with its intervals
+func (r *PostgresActivityRepository) DeleteActivity(ctx context.Context, activityID uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx dbtx) error {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE activities SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`,
+			activityID,
+		)
+		if err != nil {
+			return err
+		}
+		if err := expectRowsAffected(result, "activity"); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE intervals SET deleted_at = NOW() WHERE activity_id = $1 AND deleted_at IS NULL`,
+			activityID,
+		); err != nil {
+			return err
+		}
 
-	return err
+		return nil
+	})
 }
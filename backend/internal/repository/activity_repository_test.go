@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,15 +18,17 @@ func fakeActivity() domain.Activity {
 		UserID:       uuid.New(),
 		Date:         "2023-10-01",
 		Start:        time.Now(),
-		Duration:     domain.DurationString((30 * time.Minute).String()),
+		Duration:     domain.Duration(30 * time.Minute),
 		Distance:     1000,
 		Laps:         20,
 		PoolSize:     50,
+		PoolUnit:     domain.DistanceUnitMeters,
 		LocationType: domain.LocationPool,
 		LocationName: "CEPE",
 		Feeling:      domain.FeelingTired,
 		HeartRateAvg: 120,
 		HeartRateMax: 140,
+		RPE:          6,
 		Notes:        "Test notes",
 	}
 }
@@ -37,13 +41,16 @@ func TestCreateActivity(t *testing.T) {
 	repo := NewActivityRepository(db)
 	activity := fakeActivity()
 
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM "users" WHERE id = \$1 AND deleted_at IS NULL\)`).
+		WithArgs(activity.UserID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 	mock.ExpectExec(`INSERT INTO activities`).
 		WithArgs(
 			activity.ID,
 			activity.UserID,
 			activity.Date,
 			activity.Start,
-			int64(activity.Duration.Seconds()),
+			activity.Duration.Milliseconds(),
 			activity.Distance,
 			activity.Laps,
 			activity.PoolSize,
@@ -52,11 +59,13 @@ func TestCreateActivity(t *testing.T) {
 			string(activity.Feeling),
 			activity.HeartRateAvg,
 			activity.HeartRateMax,
+			activity.RPE,
 			activity.Notes,
+			string(activity.PoolUnit),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.CreateActivity(activity)
+	err = repo.CreateActivity(context.Background(), activity)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -70,21 +79,26 @@ func TestGetAllActivities(t *testing.T) {
 	now := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "date", "start", "duration", "distance", "laps", "pool_size",
-		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "notes",
+		"id", "user_id", "date", "start", "duration_ms", "distance", "laps", "pool_size",
+		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "rpe", "notes",
+		"pool_unit", "version", "created_at", "updated_at",
 	}).AddRow(
-		uuid.New(), uuid.New(), "2023-10-01", now, int64(1800), 1000, 20, 50,
-		"pool", "CEPE", "tired", 120, 140, "notes",
+		uuid.New(), uuid.New(), "2023-10-01", now, int64(1800000), 1000, 20, 50,
+		"pool", "CEPE", "tired", 120, 140, 6, "notes",
+		"yards", 3, now, now,
 	)
 
-	mock.ExpectQuery(`SELECT id, user_id, date, start, duration, distance, laps, pool_size, location_type, location_name, feeling, heart_rate_avg, heart_rate_max, notes FROM activities`).
+	mock.ExpectQuery(`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size, location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes, pool_unit, version, created_at, updated_at FROM activities WHERE deleted_at IS NULL`).
 		WillReturnRows(rows)
 
-	activities, err := repo.GetAllActivities()
+	activities, err := repo.GetAllActivities(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, activities, 1)
 	assert.Equal(t, "CEPE", activities[0].LocationName)
 	assert.Equal(t, domain.FeelingTired, activities[0].Feeling)
+	assert.Equal(t, domain.DistanceUnitYards, activities[0].PoolUnit)
+	assert.Equal(t, 3, activities[0].Version)
+	assert.Equal(t, now, activities[0].CreatedAt)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -97,24 +111,64 @@ func TestGetActivitiesByUser(t *testing.T) {
 	activity := fakeActivity()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "date", "start", "duration", "distance", "laps", "pool_size",
-		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "notes",
+		"id", "user_id", "date", "start", "duration_ms", "distance", "laps", "pool_size",
+		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "rpe", "notes",
+		"pool_unit", "version", "created_at", "updated_at",
 	}).AddRow(
-		activity.ID, activity.UserID, activity.Date, activity.Start, int64(activity.Duration.Seconds()), activity.Distance, activity.Laps, activity.PoolSize,
-		string(activity.LocationType), activity.LocationName, string(activity.Feeling), activity.HeartRateAvg, activity.HeartRateMax, activity.Notes,
+		activity.ID, activity.UserID, activity.Date, activity.Start, activity.Duration.Milliseconds(), activity.Distance, activity.Laps, activity.PoolSize,
+		string(activity.LocationType), activity.LocationName, string(activity.Feeling), activity.HeartRateAvg, activity.HeartRateMax, activity.RPE, activity.Notes,
+		string(activity.PoolUnit), activity.Version, activity.Start, activity.Start,
 	)
 
-	mock.ExpectQuery(`SELECT id, user_id, date, start, duration, distance, laps, pool_size, location_type, location_name, feeling, heart_rate_avg, heart_rate_max, notes FROM activities WHERE user_id = \$1`).
+	mock.ExpectQuery(`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size, location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes, pool_unit, version, created_at, updated_at FROM activities WHERE user_id = \$1 AND deleted_at IS NULL`).
 		WithArgs(activity.UserID).
 		WillReturnRows(rows)
 
-	result, err := repo.GetActivitiesByUser(activity.UserID)
+	result, err := repo.GetActivitiesByUser(context.Background(), activity.UserID)
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.Equal(t, activity.ID, result[0].ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetRecentActivitiesByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActivityRepository(db)
+	latest := fakeActivity()
+	// Logged after latest, the backfilled activity was swum before it
+	backfilled := fakeActivity()
+	backfilled.UserID = latest.UserID
+	backfilled.Date = "2023-09-01"
+	backfilled.Start = latest.Start.AddDate(0, -1, 0)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "date", "start", "duration_ms", "distance", "laps", "pool_size",
+		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "rpe", "notes",
+		"pool_unit", "version", "created_at", "updated_at",
+	})
+	for _, activity := range []domain.Activity{latest, backfilled} {
+		rows.AddRow(
+			activity.ID, activity.UserID, activity.Date, activity.Start, activity.Duration.Milliseconds(), activity.Distance, activity.Laps, activity.PoolSize,
+			string(activity.LocationType), activity.LocationName, string(activity.Feeling), activity.HeartRateAvg, activity.HeartRateMax, activity.RPE, activity.Notes,
+			string(activity.PoolUnit), activity.Version, activity.Start, activity.Start,
+		)
+	}
+
+	mock.ExpectQuery(`SELECT id, user_id, date, start, .* FROM activities WHERE user_id = \$1 AND deleted_at IS NULL ORDER BY date DESC, start DESC LIMIT \$2`).
+		WithArgs(latest.UserID, 5).
+		WillReturnRows(rows)
+
+	result, err := repo.GetRecentActivitiesByUser(context.Background(), latest.UserID, 5)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, latest.ID, result[0].ID)
+	assert.Equal(t, backfilled.ID, result[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetActivityByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -124,24 +178,56 @@ func TestGetActivityByID(t *testing.T) {
 	activity := fakeActivity()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "date", "start", "duration", "distance", "laps", "pool_size",
-		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "notes",
+		"id", "user_id", "date", "start", "duration_ms", "distance", "laps", "pool_size",
+		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "rpe", "notes",
+		"pool_unit", "version", "created_at", "updated_at",
 	}).AddRow(
-		activity.ID, activity.UserID, activity.Date, activity.Start, int64(activity.Duration.Seconds()), activity.Distance, activity.Laps, activity.PoolSize,
-		string(activity.LocationType), activity.LocationName, string(activity.Feeling), activity.HeartRateAvg, activity.HeartRateMax, activity.Notes,
+		activity.ID, activity.UserID, activity.Date, activity.Start, activity.Duration.Milliseconds(), activity.Distance, activity.Laps, activity.PoolSize,
+		string(activity.LocationType), activity.LocationName, string(activity.Feeling), activity.HeartRateAvg, activity.HeartRateMax, activity.RPE, activity.Notes,
+		string(activity.PoolUnit), activity.Version, activity.Start, activity.Start,
 	)
 
-	mock.ExpectQuery(`SELECT id, user_id, date, start, duration, distance, laps, pool_size, location_type, location_name, feeling, heart_rate_avg, heart_rate_max, notes FROM activities WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, user_id, date, start, duration_ms, distance, laps, pool_size, location_type, location_name, feeling, heart_rate_avg, heart_rate_max, rpe, notes, pool_unit, version, created_at, updated_at FROM activities WHERE id = \$1 AND deleted_at IS NULL`).
 		WithArgs(activity.ID).
 		WillReturnRows(rows)
 
-	result, err := repo.GetActivityByID(activity.ID)
+	result, err := repo.GetActivityByID(context.Background(), activity.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, activity.UserID, result.UserID)
 	assert.Equal(t, activity.Feeling, result.Feeling)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetActivitiesByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActivityRepository(db)
+	activity := fakeActivity()
+	deletedID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "date", "start", "duration_ms", "distance", "laps", "pool_size",
+		"location_type", "location_name", "feeling", "heart_rate_avg", "heart_rate_max", "rpe", "notes",
+		"pool_unit", "version", "created_at", "updated_at",
+	}).AddRow(
+		activity.ID, activity.UserID, activity.Date, activity.Start, activity.Duration.Milliseconds(), activity.Distance, activity.Laps, activity.PoolSize,
+		string(activity.LocationType), activity.LocationName, string(activity.Feeling), activity.HeartRateAvg, activity.HeartRateMax, activity.RPE, activity.Notes,
+		string(activity.PoolUnit), activity.Version, activity.Start, activity.Start,
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM activities WHERE id = ANY\(\$1\) AND deleted_at IS NULL`).
+		WithArgs(pq.Array([]uuid.UUID{activity.ID, deletedID})).
+		WillReturnRows(rows)
+
+	result, err := repo.GetActivitiesByIDs(context.Background(), []uuid.UUID{activity.ID, deletedID})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, activity.ID, result[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdateActivity(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -156,7 +242,7 @@ func TestUpdateActivity(t *testing.T) {
 			activity.UserID,
 			activity.Date,
 			activity.Start,
-			int64(activity.Duration.Seconds()),
+			activity.Duration.Milliseconds(),
 			activity.Distance,
 			activity.Laps,
 			activity.PoolSize,
@@ -165,12 +251,34 @@ func TestUpdateActivity(t *testing.T) {
 			string(activity.Feeling),
 			activity.HeartRateAvg,
 			activity.HeartRateMax,
+			activity.RPE,
 			activity.Notes,
+			string(activity.PoolUnit),
+			activity.Version,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.UpdateActivity(activity)
+	err = repo.UpdateActivity(context.Background(), activity)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateActivity_DeletedUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActivityRepository(db)
+	activity := fakeActivity()
+
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(activity.UserID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	err = repo.CreateActivity(context.Background(), activity)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "user_id", validationErr.Fields[0].Field)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -182,11 +290,30 @@ func TestDeleteActivity(t *testing.T) {
 	repo := NewActivityRepository(db)
 	id := uuid.New()
 
-	mock.ExpectExec(`DELETE FROM activities WHERE id = \$1`).
-		WithArgs(id).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	t.Run("soft deletes the intervals too", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE activities SET deleted_at = NOW\(\) WHERE id = \$1 AND deleted_at IS NULL`).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE intervals SET deleted_at = NOW\(\) WHERE activity_id = \$1 AND deleted_at IS NULL`).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectCommit()
+
+		err := repo.DeleteActivity(context.Background(), id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE activities SET deleted_at`).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.DeleteActivity(context.Background(), id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
 
-	err = repo.DeleteActivity(id)
-	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
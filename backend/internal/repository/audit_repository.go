@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// AuditRepository defines the interface for the audit log; Events are recorded
+// within the transaction of the change they describe
+type AuditRepository interface {
+	Transactor
+	RecordEvent(ctx context.Context, event domain.AuditEvent) error
+	GetEventsByUser(ctx context.Context, userID uuid.UUID) ([]domain.AuditEvent, error)
+}
+
+// PostgresAuditRepository is a concrete implementation of AuditRepository using PostgreSQL
+type PostgresAuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new PostgresAuditRepository
+func NewAuditRepository(db *sql.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+// WithinTx runs fn in a transaction, see PostgresTransactor
+func (r *PostgresAuditRepository) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return NewTransactor(r.db).WithinTx(ctx, fn)
+}
+
+// RecordEvent appends an event to the audit log, timestamped by the database
+func (r *PostgresAuditRepository) RecordEvent(ctx context.Context, event domain.AuditEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO audit_events (
+			id, occurred_at, actor_id, user_id, entity_type, entity_id, action, changes
+		) VALUES ($1, NOW(), $2, $3, $4, $5, $6, $7)
+	`,
+		event.ID,
+		event.ActorID,
+		event.UserID,
+		event.EntityType,
+		event.EntityID,
+		event.Action,
+		changes,
+	)
+	return translateError(ctx, err, "audit event")
+}
+
+// GetEventsByUser returns the events about the records owned by a user, most recent first;
+// The events of a deleted user are kept, so their changes can still be traced
+func (r *PostgresAuditRepository) GetEventsByUser(ctx context.Context, userID uuid.UUID) ([]domain.AuditEvent, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, occurred_at, actor_id, user_id, entity_type, entity_id, action, changes
+		FROM audit_events WHERE user_id = $1
+		ORDER BY occurred_at DESC, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.AuditEvent
+	for rows.Next() {
+		var event domain.AuditEvent
+		var changes []byte
+		if err := rows.Scan(
+			&event.ID,
+			&event.OccurredAt,
+			&event.ActorID,
+			&event.UserID,
+			&event.EntityType,
+			&event.EntityID,
+			&event.Action,
+			&changes,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &event.Changes); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
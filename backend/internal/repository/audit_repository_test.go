@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	event := domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		UserID:     uuid.New(),
+		EntityType: domain.AuditEntityUser,
+		EntityID:   uuid.New(),
+		Action:     domain.AuditActionUpdate,
+		Changes:    map[string]domain.FieldChange{"name": {Before: "John Doe", After: "John Updated"}},
+	}
+
+	mock.ExpectExec(`INSERT INTO audit_events (.+) VALUES \(\$1, NOW\(\), \$2, \$3, \$4, \$5, \$6, \$7\)`).
+		WithArgs(event.ID, event.ActorID.UUID.String(), event.UserID, "user", event.EntityID, "update",
+			[]byte(`{"name":{"before":"John Doe","after":"John Updated"}}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.RecordEvent(context.Background(), event)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventsByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+	userID := uuid.New()
+
+	expected := domain.AuditEvent{
+		ID:         uuid.New(),
+		OccurredAt: time.Date(2025, 6, 7, 8, 9, 10, 0, time.UTC),
+		UserID:     userID,
+		EntityType: domain.AuditEntityActivity,
+		EntityID:   uuid.New(),
+		Action:     domain.AuditActionDelete,
+		Changes:    map[string]domain.FieldChange{"notes": {Before: "felt strong"}},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "occurred_at", "actor_id", "user_id", "entity_type", "entity_id", "action", "changes"}).
+		AddRow(expected.ID, expected.OccurredAt, nil, userID, "activity", expected.EntityID, "delete", []byte(`{"notes":{"before":"felt strong"}}`))
+
+	mock.ExpectQuery(`SELECT id, occurred_at, actor_id, user_id, entity_type, entity_id, action, changes FROM audit_events WHERE user_id = \$1 ORDER BY occurred_at DESC`).
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	events, err := repo.GetEventsByUser(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.AuditEvent{expected}, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// CSSRepository defines the interface for the CSS test repository
+type CSSRepository interface {
+	CreateCSSTest(ctx context.Context, test domain.CSSTest) error
+	GetCSSTestsByUser(ctx context.Context, userID uuid.UUID) ([]domain.CSSTest, error)
+}
+
+// PostgresCSSRepository is a concrete implementation of CSSRepository using PostgreSQL
+type PostgresCSSRepository struct {
+	db *sql.DB
+}
+
+// NewCSSRepository creates a new PostgresCSSRepository
+func NewCSSRepository(db *sql.DB) *PostgresCSSRepository {
+	return &PostgresCSSRepository{db: db}
+}
+
+func (r *PostgresCSSRepository) CreateCSSTest(ctx context.Context, test domain.CSSTest) error {
+	if err := checkReference(ctx, r.db, "users", "user_id", test.UserID); err != nil {
+		return err
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO css_tests (
+			id, user_id, date, time_400_ms, time_200_ms, interval_400_id, interval_200_id, unit, css_pace
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		test.ID,
+		test.UserID,
+		test.Date,
+		test.Time400,
+		test.Time200,
+		test.Interval400ID,
+		test.Interval200ID,
+		test.Unit.OrDefault(),
+		test.PacePer100m(),
+	)
+	return translateError(ctx, err, "CSS test")
+}
+
+// GetCSSTestsByUser returns the CSS test history of a user, most recent first;
+// The history of a deleted user is kept until they are purged, but not served
+func (r *PostgresCSSRepository) GetCSSTestsByUser(ctx context.Context, userID uuid.UUID) ([]domain.CSSTest, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, user_id, date, time_400_ms, time_200_ms, interval_400_id, interval_200_id, unit
+		FROM css_tests WHERE user_id = $1 AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)
+		ORDER BY date DESC, created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tests []domain.CSSTest
+	for rows.Next() {
+		var test domain.CSSTest
+		if err := rows.Scan(
+			&test.ID,
+			&test.UserID,
+			&test.Date,
+			&test.Time400,
+			&test.Time200,
+			&test.Interval400ID,
+			&test.Interval200ID,
+			&test.Unit,
+		); err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCSSTest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCSSRepository(db)
+	test := domain.CSSTest{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		Date:          "2023-10-01",
+		Time400:       domain.MustParseDuration("6m0s"),
+		Time200:       domain.MustParseDuration("2m50s"),
+		Interval400ID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		Interval200ID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+	}
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM "users" WHERE id = \$1 AND deleted_at IS NULL\)`).
+		WithArgs(test.UserID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`INSERT INTO css_tests`).
+		WithArgs(
+			test.ID,
+			test.UserID,
+			test.Date,
+			int64(360000),
+			int64(170000),
+			test.Interval400ID,
+			test.Interval200ID,
+			domain.DistanceUnitMeters,
+			95.0,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.CreateCSSTest(context.Background(), test)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCSSTestsByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCSSRepository(db)
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		intervalID := uuid.New()
+		rows := sqlmock.NewRows([]string{"id", "user_id", "date", "time_400_ms", "time_200_ms", "interval_400_id", "interval_200_id", "unit"}).
+			AddRow(uuid.New(), userID, "2023-10-08", int64(355000), int64(170000), intervalID, nil, "yards").
+			AddRow(uuid.New(), userID, "2023-10-01", int64(360000), int64(170000), nil, nil, "meters")
+
+		mock.ExpectQuery(`SELECT id, user_id, date, time_400_ms, time_200_ms, interval_400_id, interval_200_id, unit FROM css_tests WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnRows(rows)
+
+		result, err := repo.GetCSSTestsByUser(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, domain.MustParseDuration("5m55s"), result[0].Time400)
+		assert.Equal(t, uuid.NullUUID{UUID: intervalID, Valid: true}, result[0].Interval400ID)
+		assert.False(t, result[0].Interval200ID.Valid)
+		assert.Equal(t, domain.DistanceUnitYards, result[0].Unit)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, user_id, date, time_400_ms, time_200_ms, interval_400_id, interval_200_id, unit FROM css_tests WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnError(assert.AnError)
+
+		result, err := repo.GetCSSTestsByUser(context.Background(), userID)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/liviaruegger/MAC0350/backend/internal/logging"
+)
+
+// PostgreSQL error codes translated into domain errors
+const (
+	pgNotNullViolation          = "23502"
+	pgForeignKeyViolation       = "23503"
+	pgUniqueViolation           = "23505"
+	pgCheckViolation            = "23514"
+	pgInvalidTextRepresentation = "22P02"
+)
+
+// translateError converts database errors into the domain error model;
+// resource names the kind of record involved, e.g., "user", and is used in messages.
+// Errors that are not caused by the input (e.g., a lost connection) are returned as is.
+// As the domain errors hide the database details, these are logged with the request logger
+func translateError(ctx context.Context, err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewNotFoundError(resource + " not found")
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "database error",
+		"resource", resource,
+		"code", string(pqErr.Code),
+		"constraint", pqErr.Constraint,
+		"detail", pqErr.Detail,
+		"error", pqErr.Message,
+	)
+
+	switch pqErr.Code {
+	case pgUniqueViolation:
+		return domain.NewConflictError(fmt.Sprintf("%s with this %s already exists", resource, errorField(pqErr)))
+	case pgForeignKeyViolation:
+		return missingReference(errorField(pqErr))
+	case pgNotNullViolation:
+		return domain.NewValidationError(domain.FieldError{Field: errorField(pqErr), Code: domain.CodeRequired, Message: "is required"})
+	case pgCheckViolation, pgInvalidTextRepresentation:
+		return domain.NewValidationError(domain.FieldError{Field: errorField(pqErr), Code: domain.CodeInvalid, Message: "is invalid"})
+	default:
+		return err
+	}
+}
+
+// errorField guesses the column an integrity error refers to, from the column
+// reported by the server, the key in the detail message or the constraint name
+func errorField(pqErr *pq.Error) string {
+	if pqErr.Column != "" {
+		return pqErr.Column
+	}
+
+	// e.g., `Key (email)=(john@example.com) already exists.`
+	if start := strings.Index(pqErr.Detail, "Key ("); start >= 0 {
+		rest := pqErr.Detail[start+len("Key ("):]
+		if end := strings.Index(rest, ")"); end >= 0 {
+			return rest[:end]
+		}
+	}
+
+	// e.g., "activities_rpe_check" or "users_email_key"
+	if pqErr.Constraint != "" {
+		field := strings.TrimPrefix(pqErr.Constraint, pqErr.Table+"_")
+		for _, suffix := range []string{"_check", "_key", "_fkey"} {
+			field = strings.TrimSuffix(field, suffix)
+		}
+		return field
+	}
+
+	return "value"
+}
+
+// expectRowsAffected returns a not found error when a statement did not touch any row
+func expectRowsAffected(result sql.Result, resource string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.NewNotFoundError(resource + " not found")
+	}
+
+	return nil
+}
+
+// expectUpdated is like expectRowsAffected for an update conditioned on the version
+// of the record, telling a record that does not exist from one that was modified
+// since version was read, for which it returns a precondition failed error
+func expectUpdated(ctx context.Context, db *sql.DB, result sql.Result, table string, id uuid.UUID, version int, resource string) error {
+	err := expectRowsAffected(result, resource)
+	if version == 0 || !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	var exists bool
+	if err := conn(ctx, db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM `+pq.QuoteIdentifier(table)+` WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return translateError(ctx, err, resource)
+	}
+	if !exists {
+		return err
+	}
+	return domain.NewPreconditionFailedError(resource + " was modified since it was retrieved")
+}
+
+// checkReference returns the error of a foreign key violation on field when the
+// record it references by id does not exist or was soft deleted, which the
+// constraint itself does not catch
+func checkReference(ctx context.Context, db *sql.DB, table, field string, id uuid.UUID) error {
+	var exists bool
+	if err := conn(ctx, db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM `+pq.QuoteIdentifier(table)+` WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return missingReference(field)
+	}
+	return nil
+}
+
+// missingReference returns a validation error for a field referencing a record that does not exist
+func missingReference(field string) error {
+	return domain.NewValidationError(domain.FieldError{Field: field, Code: domain.CodeNotFound, Message: "references a record that does not exist"})
+}
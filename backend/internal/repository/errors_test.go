@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		assert.NoError(t, translateError(context.Background(), nil, "user"))
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		err := translateError(context.Background(), sql.ErrNoRows, "activity")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Equal(t, "activity not found", err.Error())
+	})
+
+	t.Run("foreign key violation", func(t *testing.T) {
+		err := translateError(context.Background(), &pq.Error{
+			Code:   pgForeignKeyViolation,
+			Detail: `Key (user_id)=(6b1f) is not present in table "users".`,
+		}, "activity")
+
+		var validationErr *domain.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "user_id", validationErr.Fields[0].Field)
+	})
+
+	t.Run("check violation", func(t *testing.T) {
+		err := translateError(context.Background(), &pq.Error{
+			Code:       pgCheckViolation,
+			Table:      "activities",
+			Constraint: "activities_rpe_check",
+		}, "activity")
+
+		var validationErr *domain.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "rpe", validationErr.Fields[0].Field)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		err := translateError(context.Background(), sql.ErrConnDone, "user")
+		assert.Equal(t, sql.ErrConnDone, err)
+	})
+}
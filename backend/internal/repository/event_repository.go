@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// EventChannel is the Postgres notification channel the events of the users are relayed on
+const EventChannel = "user_events"
+
+// EventRepository defines the interface for relaying events between API instances
+type EventRepository interface {
+	NotifyEvent(ctx context.Context, payload string) error
+}
+
+// PostgresEventRepository is a concrete implementation of EventRepository using PostgreSQL NOTIFY
+type PostgresEventRepository struct {
+	db *sql.DB
+}
+
+// NewEventRepository creates a new PostgresEventRepository
+func NewEventRepository(db *sql.DB) *PostgresEventRepository {
+	return &PostgresEventRepository{db: db}
+}
+
+// NotifyEvent sends a payload to the instances listening on EventChannel, this one included;
+// Postgres rejects payloads of 8000 bytes or more
+func (r *PostgresEventRepository) NotifyEvent(ctx context.Context, payload string) error {
+	_, err := r.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, EventChannel, payload)
+	return translateError(ctx, err, "event")
+}
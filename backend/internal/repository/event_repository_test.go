@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEventRepository(db)
+
+	mock.ExpectExec(`SELECT pg_notify\(\$1, \$2\)`).
+		WithArgs("user_events", `{"type":"activity.created"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.NotifyEvent(context.Background(), `{"type":"activity.created"}`)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// ExportRepository defines the interface for the data exports and their archives
+type ExportRepository interface {
+	CreateExport(ctx context.Context, export domain.DataExport, ttl time.Duration) error
+	CompleteExport(ctx context.Context, id uuid.UUID, archive []byte) error
+	FailExport(ctx context.Context, id uuid.UUID) error
+	GetExportByID(ctx context.Context, id uuid.UUID) (domain.DataExport, error)
+	GetExportArchive(ctx context.Context, id uuid.UUID) ([]byte, error)
+}
+
+// PostgresExportRepository is a concrete implementation of ExportRepository using PostgreSQL
+type PostgresExportRepository struct {
+	db *sql.DB
+}
+
+// NewExportRepository creates a new PostgresExportRepository
+func NewExportRepository(db *sql.DB) *PostgresExportRepository {
+	return &PostgresExportRepository{db: db}
+}
+
+// CreateExport records a pending export, expiring ttl after its creation
+func (r *PostgresExportRepository) CreateExport(ctx context.Context, export domain.DataExport, ttl time.Duration) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO data_exports (id, user_id, status, job_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW() + make_interval(secs => $5))
+	`, export.ID, export.UserID, domain.ExportPending, export.JobID, ttl.Seconds())
+	return translateError(ctx, err, "export")
+}
+
+// CompleteExport stores the archive of a pending export and marks it ready
+func (r *PostgresExportRepository) CompleteExport(ctx context.Context, id uuid.UUID, archive []byte) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE data_exports SET status = $2, archive = $3, size = $4, completed_at = NOW()
+		WHERE id = $1 AND status = $5
+	`, id, domain.ExportReady, archive, len(archive), domain.ExportPending)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result, "pending export")
+}
+
+// FailExport marks a pending export as failed
+func (r *PostgresExportRepository) FailExport(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE data_exports SET status = $2, completed_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, domain.ExportFailed, domain.ExportPending)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result, "pending export")
+}
+
+// GetExportByID returns an export that has not expired, without its archive
+func (r *PostgresExportRepository) GetExportByID(ctx context.Context, id uuid.UUID) (domain.DataExport, error) {
+	var export domain.DataExport
+	var completedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, status, size, created_at, completed_at, expires_at, job_id
+		FROM data_exports WHERE id = $1 AND expires_at > NOW()
+	`, id).Scan(
+		&export.ID,
+		&export.UserID,
+		&export.Status,
+		&export.Size,
+		&export.CreatedAt,
+		&completedAt,
+		&export.ExpiresAt,
+		&export.JobID,
+	)
+	if err != nil {
+		return domain.DataExport{}, translateError(ctx, err, "export")
+	}
+	export.CompletedAt = completedAt.Time
+
+	return export, nil
+}
+
+// GetExportArchive returns the archive of a ready export that has not expired
+func (r *PostgresExportRepository) GetExportArchive(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var archive []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT archive FROM data_exports WHERE id = $1 AND status = $2 AND expires_at > NOW()
+	`, id, domain.ExportReady).Scan(&archive)
+	if err != nil {
+		return nil, translateError(ctx, err, "export archive")
+	}
+	return archive, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateExport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExportRepository(db)
+	export := domain.DataExport{ID: uuid.New(), UserID: uuid.New(), JobID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}
+
+	mock.ExpectExec(`INSERT INTO data_exports (.+) VALUES \(\$1, \$2, \$3, \$4, NOW\(\), NOW\(\) \+ make_interval\(secs => \$5\)\)`).
+		WithArgs(export.ID, export.UserID, "pending", export.JobID.UUID.String(), float64(86400)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.CreateExport(context.Background(), export, 24*time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCompleteExport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExportRepository(db)
+	id := uuid.New()
+	archive := []byte("PK")
+
+	t.Run("pending", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE data_exports SET status = \$2, archive = \$3, size = \$4, completed_at = NOW\(\) WHERE id = \$1 AND status = \$5`).
+			WithArgs(id, "ready", archive, 2, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.CompleteExport(context.Background(), id, archive))
+	})
+
+	t.Run("no longer pending", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE data_exports SET status`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.CompleteExport(context.Background(), id, archive), domain.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExportByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExportRepository(db)
+	expected := domain.DataExport{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Status:    domain.ExportPending,
+		CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		ExpiresAt: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+		JobID:     uuid.NullUUID{UUID: uuid.New(), Valid: true},
+	}
+
+	t.Run("pending", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "status", "size", "created_at", "completed_at", "expires_at", "job_id"}).
+			AddRow(expected.ID, expected.UserID, "pending", 0, expected.CreatedAt, nil, expected.ExpiresAt, expected.JobID.UUID)
+		mock.ExpectQuery(`SELECT id, user_id, status, size, created_at, completed_at, expires_at, job_id FROM data_exports WHERE id = \$1 AND expires_at > NOW\(\)`).
+			WithArgs(expected.ID).
+			WillReturnRows(rows)
+
+		export, err := repo.GetExportByID(context.Background(), expected.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, export)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT (.+) FROM data_exports`).
+			WithArgs(expected.ID).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetExportByID(context.Background(), expected.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExportArchive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExportRepository(db)
+	id := uuid.New()
+
+	mock.ExpectQuery(`SELECT archive FROM data_exports WHERE id = \$1 AND status = \$2 AND expires_at > NOW\(\)`).
+		WithArgs(id, "ready").
+		WillReturnRows(sqlmock.NewRows([]string{"archive"}).AddRow([]byte("PK")))
+
+	archive, err := repo.GetExportArchive(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("PK"), archive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
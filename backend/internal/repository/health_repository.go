@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// HealthRepository defines the interface for checking the state of the database
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	GetSchemaVersion(ctx context.Context) (int, error)
+	Stats() sql.DBStats
+}
+
+// PostgresHealthRepository is a concrete implementation of HealthRepository using PostgreSQL
+type PostgresHealthRepository struct {
+	db *sql.DB
+}
+
+// NewHealthRepository creates a new PostgresHealthRepository
+func NewHealthRepository(db *sql.DB) *PostgresHealthRepository {
+	return &PostgresHealthRepository{db: db}
+}
+
+// Ping checks that the database is reachable
+func (r *PostgresHealthRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// GetSchemaVersion returns the version of the schema recorded by the last migration
+func (r *PostgresHealthRepository) GetSchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := r.db.QueryRowContext(ctx, `SELECT version FROM schema_version`).Scan(&version)
+	return version, translateError(ctx, err, "schema version")
+}
+
+// Stats returns the statistics of the connection pool
+func (r *PostgresHealthRepository) Stats() sql.DBStats {
+	return r.db.Stats()
+}
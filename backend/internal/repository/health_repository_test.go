@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSchemaVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHealthRepository(db)
+
+	mock.ExpectQuery(`SELECT version FROM schema_version`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(13))
+
+	version, err := repo.GetSchemaVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 13, version)
+
+	mock.ExpectQuery(`SELECT version FROM schema_version`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+	_, err = repo.GetSchemaVersion(context.Background())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
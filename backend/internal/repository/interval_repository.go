@@ -1,17 +1,20 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 )
 
 // IntervalRepository defines the interface for the interval repository
 type IntervalRepository interface {
-	CreateInterval(interval domain.Interval) error
-	GetIntervalsByActivity(activityID uuid.UUID) ([]domain.Interval, error)
+	CreateInterval(ctx context.Context, interval domain.Interval) error
+	GetIntervalsByActivity(ctx context.Context, activityID uuid.UUID) ([]domain.Interval, error)
+	GetIntervalsByActivities(ctx context.Context, activityIDs []uuid.UUID) (map[uuid.UUID][]domain.Interval, error)
+	GetIntervalByID(ctx context.Context, intervalID uuid.UUID) (domain.Interval, error)
 }
 
 // PostgresIntervalRepository is a concrete implementation of IntervalRepository using PostgreSQL
@@ -24,51 +27,154 @@ func NewIntervalRepository(db *sql.DB) *PostgresIntervalRepository {
 	return &PostgresIntervalRepository{db: db}
 }
 
-func (r *PostgresIntervalRepository) CreateInterval(interval domain.Interval) error {
-	_, err := r.db.Exec(`
+func (r *PostgresIntervalRepository) CreateInterval(ctx context.Context, interval domain.Interval) error {
+	if err := checkReference(ctx, r.db, "activities", "activity_id", interval.ActivityID); err != nil {
+		return err
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
 		INSERT INTO intervals (
-			id, activity_id, duration, distance, type, stroke, notes
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg,
+			stroke_count, length_stroke_counts, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
 	`,
 		interval.ID,
 		interval.ActivityID,
-		int64(interval.Duration.Seconds()),
+		interval.Duration,
 		interval.Distance,
-		string(interval.Type),
-		string(interval.Stroke),
+		interval.Type,
+		interval.Stroke,
 		interval.Notes,
+		interval.HeartRateAvg,
+		interval.StrokeCount,
+		toInt64Array(interval.LengthStrokeCounts),
 	)
-	return err
+	return translateError(ctx, err, "interval")
 }
 
-func (r *PostgresIntervalRepository) GetIntervalsByActivity(activityID uuid.UUID) ([]domain.Interval, error) {
-	rows, err := r.db.Query(`
-		SELECT id, activity_id, duration, distance, type, stroke, notes
-		FROM intervals WHERE activity_id = $1
+func (r *PostgresIntervalRepository) GetIntervalsByActivity(ctx context.Context, activityID uuid.UUID) ([]domain.Interval, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg,
+			stroke_count, length_stroke_counts, created_at, updated_at
+		FROM intervals WHERE activity_id = $1 AND deleted_at IS NULL
 	`, activityID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanIntervals(rows)
+}
+
+// GetIntervalsByActivities returns the intervals of several activities in a single query,
+// keyed by activity ID; Activities without intervals are absent from the map
+func (r *PostgresIntervalRepository) GetIntervalsByActivities(ctx context.Context, activityIDs []uuid.UUID) (map[uuid.UUID][]domain.Interval, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg,
+			stroke_count, length_stroke_counts, created_at, updated_at
+		FROM intervals WHERE activity_id = ANY($1) AND deleted_at IS NULL
+	`, pq.Array(activityIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	intervals, err := scanIntervals(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byActivity := make(map[uuid.UUID][]domain.Interval)
+	for _, interval := range intervals {
+		byActivity[interval.ActivityID] = append(byActivity[interval.ActivityID], interval)
+	}
+	return byActivity, nil
+}
+
+// scanIntervals scans the rows of a query selecting the columns of intervals
+func scanIntervals(rows *sql.Rows) ([]domain.Interval, error) {
 	var intervals []domain.Interval
 	for rows.Next() {
 		var interval domain.Interval
-		var durationSeconds int64
+		var lengthStrokeCounts pq.Int64Array
 		if err := rows.Scan(
 			&interval.ID,
 			&interval.ActivityID,
-			&durationSeconds,
+			&interval.Duration,
 			&interval.Distance,
 			&interval.Type,
 			&interval.Stroke,
 			&interval.Notes,
+			&interval.HeartRateAvg,
+			&interval.StrokeCount,
+			&lengthStrokeCounts,
+			&interval.CreatedAt,
+			&interval.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
-		interval.Duration = domain.DurationString((time.Duration(durationSeconds) * time.Second).String())
+		interval.LengthStrokeCounts = fromInt64Array(lengthStrokeCounts)
 		intervals = append(intervals, interval)
 	}
 
 	return intervals, nil
 }
+
+func (r *PostgresIntervalRepository) GetIntervalByID(ctx context.Context, intervalID uuid.UUID) (domain.Interval, error) {
+	var interval domain.Interval
+	var lengthStrokeCounts pq.Int64Array
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg,
+			stroke_count, length_stroke_counts, created_at, updated_at
+		FROM intervals WHERE id = $1 AND deleted_at IS NULL
+	`, intervalID).Scan(
+		&interval.ID,
+		&interval.ActivityID,
+		&interval.Duration,
+		&interval.Distance,
+		&interval.Type,
+		&interval.Stroke,
+		&interval.Notes,
+		&interval.HeartRateAvg,
+		&interval.StrokeCount,
+		&lengthStrokeCounts,
+		&interval.CreatedAt,
+		&interval.UpdatedAt,
+	)
+	if err != nil {
+		return interval, translateError(ctx, err, "interval")
+	}
+
+	interval.LengthStrokeCounts = fromInt64Array(lengthStrokeCounts)
+
+	return interval, nil
+}
+
+// toInt64Array converts per-length counts to a Postgres INTEGER[] (NULL if empty)
+func toInt64Array(values []int) pq.Int64Array {
+	if len(values) == 0 {
+		return nil
+	}
+
+	array := make(pq.Int64Array, len(values))
+	for i, value := range values {
+		array[i] = int64(value)
+	}
+
+	return array
+}
+
+// fromInt64Array converts a Postgres INTEGER[] to per-length counts
+func fromInt64Array(array pq.Int64Array) []int {
+	if len(array) == 0 {
+		return nil
+	}
+
+	values := make([]int, len(array))
+	for i, value := range array {
+		values[i] = int(value)
+	}
+
+	return values
+}
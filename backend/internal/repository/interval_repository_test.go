@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
@@ -20,27 +22,34 @@ func TestCreateInterval(t *testing.T) {
 	activityID := uuid.New()
 
 	interval := domain.Interval{
-		ActivityID: activityID,
-		Duration:   domain.DurationString((time.Minute * 30).String()),
-		Distance:   1000,
-		Type:       "swim",
-		Stroke:     "freestyle",
-		Notes:      "Test interval",
+		ActivityID:         activityID,
+		Duration:           domain.Duration(time.Minute * 30),
+		Distance:           1000,
+		Type:               "swim",
+		Stroke:             "freestyle",
+		Notes:              "Test interval",
+		LengthStrokeCounts: []int{16, 17},
 	}
 
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM "activities" WHERE id = \$1 AND deleted_at IS NULL\)`).
+		WithArgs(activityID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 	mock.ExpectExec(`INSERT INTO intervals`).
 		WithArgs(
 			sqlmock.AnyArg(), // id (generated UUID)
 			interval.ActivityID,
-			int64(interval.Duration.Seconds()),
+			interval.Duration.Milliseconds(),
 			interval.Distance,
 			string(interval.Type),
 			string(interval.Stroke),
 			interval.Notes,
+			interval.HeartRateAvg,
+			interval.StrokeCount,
+			toInt64Array(interval.LengthStrokeCounts),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.CreateInterval(interval)
+	err = repo.CreateInterval(context.Background(), interval)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -59,68 +68,155 @@ func TestGetIntervalsByActivity(t *testing.T) {
 			{
 				ID:         uuid.New(),
 				ActivityID: activityID,
-				Duration:   domain.DurationString((time.Minute * 30).String()),
+				Duration:   domain.Duration(time.Minute * 30),
 				Distance:   1000,
 				Type:       "swim",
 				Stroke:     "freestyle",
 				Notes:      "Test interval 1",
 			},
 			{
-				ID:         uuid.New(),
-				ActivityID: activityID,
-				Duration:   domain.DurationString((time.Minute * 20).String()),
-				Distance:   800,
-				Type:       "swim",
-				Stroke:     "backstroke",
-				Notes:      "Test interval 2",
+				ID:           uuid.New(),
+				ActivityID:   activityID,
+				Duration:     domain.Duration(time.Minute * 20),
+				Distance:     800,
+				Type:         "swim",
+				Stroke:       "backstroke",
+				Notes:        "Test interval 2",
+				HeartRateAvg: 142,
+				StrokeCount:  66,
+			},
+			{
+				ID:                 uuid.New(),
+				ActivityID:         activityID,
+				Duration:           domain.Duration(time.Second * 100),
+				Distance:           100,
+				Type:               "main_set",
+				Stroke:             "freestyle",
+				Notes:              "Test interval 3",
+				LengthStrokeCounts: []int{16, 17, 17, 18},
 			},
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "activity_id", "duration", "distance", "type", "stroke", "notes"})
+		rows := sqlmock.NewRows([]string{"id", "activity_id", "duration_ms", "distance", "type", "stroke", "notes", "heart_rate_avg", "stroke_count", "length_stroke_counts", "created_at", "updated_at"})
 		for _, interval := range intervals {
 			rows.AddRow(
 				interval.ID,
 				interval.ActivityID,
-				int64(interval.Duration.Seconds()),
+				interval.Duration.Milliseconds(),
 				interval.Distance,
 				string(interval.Type),
 				string(interval.Stroke),
 				interval.Notes,
+				interval.HeartRateAvg,
+				interval.StrokeCount,
+				toInt64Array(interval.LengthStrokeCounts),
+				interval.CreatedAt,
+				interval.UpdatedAt,
 			)
 		}
 
-		mock.ExpectQuery(`SELECT id, activity_id, duration, distance, type, stroke, notes FROM intervals WHERE activity_id = \$1`).
+		mock.ExpectQuery(`SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg, stroke_count, length_stroke_counts, created_at, updated_at FROM intervals WHERE activity_id = \$1 AND deleted_at IS NULL`).
 			WithArgs(activityID).
 			WillReturnRows(rows)
 
-		result, err := repo.GetIntervalsByActivity(activityID)
+		result, err := repo.GetIntervalsByActivity(context.Background(), activityID)
 		assert.NoError(t, err)
 		assert.Equal(t, intervals, result)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("query error", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, activity_id, duration, distance, type, stroke, notes FROM intervals WHERE activity_id = \$1`).
+		mock.ExpectQuery(`SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg, stroke_count, length_stroke_counts, created_at, updated_at FROM intervals WHERE activity_id = \$1 AND deleted_at IS NULL`).
 			WithArgs(activityID).
 			WillReturnError(assert.AnError)
 
-		result, err := repo.GetIntervalsByActivity(activityID)
+		result, err := repo.GetIntervalsByActivity(context.Background(), activityID)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("scan error", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "activity_id", "duration", "distance", "type", "stroke", "notes"}).
-			AddRow("invalid-uuid", activityID, 1800, 1000, "swim", "freestyle", "Test interval 1")
+		rows := sqlmock.NewRows([]string{"id", "activity_id", "duration_ms", "distance", "type", "stroke", "notes", "heart_rate_avg", "stroke_count", "length_stroke_counts", "created_at", "updated_at"}).
+			AddRow("invalid-uuid", activityID, 1800, 1000, "swim", "freestyle", "Test interval 1", 0, 0, nil, time.Now(), time.Now())
 
-		mock.ExpectQuery(`SELECT id, activity_id, duration, distance, type, stroke, notes FROM intervals WHERE activity_id = \$1`).
+		mock.ExpectQuery(`SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg, stroke_count, length_stroke_counts, created_at, updated_at FROM intervals WHERE activity_id = \$1 AND deleted_at IS NULL`).
 			WithArgs(activityID).
 			WillReturnRows(rows)
 
-		result, err := repo.GetIntervalsByActivity(activityID)
+		result, err := repo.GetIntervalsByActivity(context.Background(), activityID)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestGetIntervalsByActivities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewIntervalRepository(db)
+	first, second, empty := uuid.New(), uuid.New(), uuid.New()
+	created := time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "activity_id", "duration_ms", "distance", "type", "stroke", "notes", "heart_rate_avg", "stroke_count", "length_stroke_counts", "created_at", "updated_at"})
+	for _, activityID := range []uuid.UUID{first, second, first} {
+		rows.AddRow(uuid.New(), activityID, 90000, 100, "main_set", "freestyle", "", 0, 0, nil, created, created)
+	}
+
+	mock.ExpectQuery(`SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg, stroke_count, length_stroke_counts, created_at, updated_at FROM intervals WHERE activity_id = ANY\(\$1\) AND deleted_at IS NULL`).
+		WithArgs(pq.Array([]uuid.UUID{first, second, empty})).
+		WillReturnRows(rows)
+
+	result, err := repo.GetIntervalsByActivities(context.Background(), []uuid.UUID{first, second, empty})
+	assert.NoError(t, err)
+	assert.Len(t, result[first], 2)
+	assert.Len(t, result[second], 1)
+	assert.NotContains(t, result, empty)
+	assert.Equal(t, domain.Duration(90*time.Second), result[second][0].Duration)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetIntervalByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewIntervalRepository(db)
+
+	interval := domain.Interval{
+		ID:           uuid.New(),
+		ActivityID:   uuid.New(),
+		Duration:     domain.Duration(time.Minute * 6),
+		Distance:     400,
+		Type:         "main_set",
+		Stroke:       "freestyle",
+		Notes:        "CSS trial",
+		HeartRateAvg: 165,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "activity_id", "duration_ms", "distance", "type", "stroke", "notes", "heart_rate_avg", "stroke_count", "length_stroke_counts", "created_at", "updated_at"}).
+			AddRow(interval.ID, interval.ActivityID, int64(360000), interval.Distance, "main_set", "freestyle", interval.Notes, interval.HeartRateAvg, 0, nil, interval.CreatedAt, interval.UpdatedAt)
+
+		mock.ExpectQuery(`SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg, stroke_count, length_stroke_counts, created_at, updated_at FROM intervals WHERE id = \$1 AND deleted_at IS NULL`).
+			WithArgs(interval.ID).
+			WillReturnRows(rows)
+
+		result, err := repo.GetIntervalByID(context.Background(), interval.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, interval, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, activity_id, duration_ms, distance, type, stroke, notes, heart_rate_avg, stroke_count, length_stroke_counts, created_at, updated_at FROM intervals WHERE id = \$1 AND deleted_at IS NULL`).
+			WithArgs(interval.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "duration_ms", "distance", "type", "stroke", "notes", "heart_rate_avg", "stroke_count", "length_stroke_counts", "created_at", "updated_at"}))
+
+		_, err := repo.GetIntervalByID(context.Background(), interval.ID)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// JobRepository defines the interface for the queue of background jobs
+type JobRepository interface {
+	EnqueueJob(ctx context.Context, job domain.Job) error
+	ScheduleJob(ctx context.Context, job domain.Job, delay time.Duration) error
+	ClaimJob(ctx context.Context, types []string, lease time.Duration) (domain.Job, error)
+	CompleteJob(ctx context.Context, id uuid.UUID, attempt int) error
+	RetryJob(ctx context.Context, id uuid.UUID, attempt int, delay time.Duration, lastError string) error
+	FailJob(ctx context.Context, id uuid.UUID, attempt int, lastError string) error
+	GetJobByID(ctx context.Context, id uuid.UUID) (domain.Job, error)
+}
+
+// ErrLeaseLost is returned when recording the outcome of an attempt at a job whose
+// lease expired: the job was claimed again, or its outcome recorded, by another worker
+var ErrLeaseLost = errors.New("job lease lost")
+
+// PostgresJobRepository is a concrete implementation of JobRepository using PostgreSQL
+type PostgresJobRepository struct {
+	db *sql.DB
+}
+
+// NewJobRepository creates a new PostgresJobRepository
+func NewJobRepository(db *sql.DB) *PostgresJobRepository {
+	return &PostgresJobRepository{db: db}
+}
+
+// jobColumns are the columns scanned by scanJob
+const jobColumns = `id, type, payload, user_id, status, attempts, max_attempts, run_at, last_error, created_at, updated_at, completed_at`
+
+// EnqueueJob queues a job to be run as soon as a worker is free
+func (r *PostgresJobRepository) EnqueueJob(ctx context.Context, job domain.Job) error {
+	payload := []byte(job.Payload)
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO jobs (id, type, payload, user_id, status, max_attempts, run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`, job.ID, job.Type, payload, job.UserID, domain.JobQueued, job.MaxAttempts)
+	return translateError(ctx, err, "job")
+}
+
+// ScheduleJob queues a job of a recurring type to be run after delay, unless a
+// job of the type is already queued or running
+func (r *PostgresJobRepository) ScheduleJob(ctx context.Context, job domain.Job, delay time.Duration) error {
+	return inTx(ctx, r.db, func(tx dbtx) error {
+		// Instances scheduling the type at the same time wait for one another, so a single one queues it
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", job.Type); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO jobs (id, type, payload, user_id, status, max_attempts, run_at)
+			SELECT $1, $2, '{}', NULL, $3, $4, NOW() + make_interval(secs => $5)
+			WHERE NOT EXISTS (SELECT 1 FROM jobs WHERE type = $2 AND status IN ($3, $6))
+		`, job.ID, job.Type, domain.JobQueued, job.MaxAttempts, delay.Seconds(), domain.JobRunning)
+		return translateError(ctx, err, "job")
+	})
+}
+
+// ClaimJob leases the next due job of one of the given types to the caller for
+// the lease duration, counting a new attempt. A running job whose lease expired,
+// e.g., because its worker crashed, is due again. Concurrent workers skip the
+// jobs being claimed by one another instead of waiting for them.
+// It returns a not found error when no job is due
+func (r *PostgresJobRepository) ClaimJob(ctx context.Context, types []string, lease time.Duration) (domain.Job, error) {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE jobs SET
+			status = $3, attempts = attempts + 1,
+			locked_until = NOW() + make_interval(secs => $2), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE type = ANY($1) AND (
+				status = $4 AND run_at <= NOW() OR
+				status = $3 AND locked_until <= NOW()
+			)
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns,
+		pq.Array(types), lease.Seconds(), domain.JobRunning, domain.JobQueued,
+	)
+	job, err := scanJob(row)
+	if err != nil {
+		return domain.Job{}, translateError(ctx, err, "due job")
+	}
+	return job, nil
+}
+
+// CompleteJob marks a running job as succeeded, if attempt is still its current one
+func (r *PostgresJobRepository) CompleteJob(ctx context.Context, id uuid.UUID, attempt int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $2, locked_until = NULL, updated_at = NOW(), completed_at = NOW()
+		WHERE id = $1 AND status = $3 AND attempts = $4
+	`, id, domain.JobSucceeded, domain.JobRunning, attempt)
+	if err != nil {
+		return err
+	}
+	return expectLeased(result)
+}
+
+// RetryJob queues a running job again, to be run after delay, if attempt is still its current one
+func (r *PostgresJobRepository) RetryJob(ctx context.Context, id uuid.UUID, attempt int, delay time.Duration, lastError string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET
+			status = $2, run_at = NOW() + make_interval(secs => $3), last_error = $4,
+			locked_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = $5 AND attempts = $6
+	`, id, domain.JobQueued, delay.Seconds(), lastError, domain.JobRunning, attempt)
+	if err != nil {
+		return err
+	}
+	return expectLeased(result)
+}
+
+// FailJob marks a running job as failed for good, if attempt is still its current one
+func (r *PostgresJobRepository) FailJob(ctx context.Context, id uuid.UUID, attempt int, lastError string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $2, last_error = $3, locked_until = NULL, updated_at = NOW(), completed_at = NOW()
+		WHERE id = $1 AND status = $4 AND attempts = $5
+	`, id, domain.JobFailed, lastError, domain.JobRunning, attempt)
+	if err != nil {
+		return err
+	}
+	return expectLeased(result)
+}
+
+// expectLeased returns ErrLeaseLost when the outcome of an attempt did not update its job
+func expectLeased(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// GetJobByID returns a job
+func (r *PostgresJobRepository) GetJobByID(ctx context.Context, id uuid.UUID) (domain.Job, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id)
+	job, err := scanJob(row)
+	if err != nil {
+		return domain.Job{}, translateError(ctx, err, "job")
+	}
+	return job, nil
+}
+
+// scanJob reads a job selected with jobColumns
+func scanJob(row *sql.Row) (domain.Job, error) {
+	var job domain.Job
+	var payload []byte
+	var completedAt sql.NullTime
+	if err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&payload,
+		&job.UserID,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LastError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&completedAt,
+	); err != nil {
+		return domain.Job{}, err
+	}
+	job.Payload = payload
+	job.CompletedAt = completedAt.Time
+	return job, nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var jobRowColumns = []string{"id", "type", "payload", "user_id", "status", "attempts", "max_attempts", "run_at", "last_error", "created_at", "updated_at", "completed_at"}
+
+func TestEnqueueJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	job := domain.Job{
+		ID:          uuid.New(),
+		Type:        "export",
+		Payload:     json.RawMessage(`{"export_id":"42"}`),
+		UserID:      uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		MaxAttempts: 5,
+	}
+
+	mock.ExpectExec(`INSERT INTO jobs \(id, type, payload, user_id, status, max_attempts, run_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, NOW\(\)\)`).
+		WithArgs(job.ID, "export", []byte(`{"export_id":"42"}`), job.UserID.UUID.String(), "queued", 5).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.EnqueueJob(context.Background(), job)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduleJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	job := domain.Job{ID: uuid.New(), Type: "purge_deleted", MaxAttempts: 5}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).WithArgs("purge_deleted").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO jobs (.+) SELECT (.+) WHERE NOT EXISTS \(SELECT 1 FROM jobs WHERE type = \$2 AND status IN \(\$3, \$6\)\)`).
+		WithArgs(job.ID, "purge_deleted", "queued", 5, float64(3600), "running").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = repo.ScheduleJob(context.Background(), job, time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	expected := domain.Job{
+		ID:          uuid.New(),
+		Type:        "export",
+		Payload:     json.RawMessage(`{}`),
+		Status:      domain.JobRunning,
+		Attempts:    1,
+		MaxAttempts: 5,
+		RunAt:       time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2026, 10, 1, 12, 0, 1, 0, time.UTC),
+	}
+
+	t.Run("due job", func(t *testing.T) {
+		rows := sqlmock.NewRows(jobRowColumns).
+			AddRow(expected.ID, "export", []byte(`{}`), nil, "running", 1, 5, expected.RunAt, "", expected.CreatedAt, expected.UpdatedAt, nil)
+		mock.ExpectQuery(`UPDATE jobs SET status = \$3, attempts = attempts \+ 1, locked_until = NOW\(\) \+ make_interval\(secs => \$2\), updated_at = NOW\(\) `+
+			`WHERE id = \( SELECT id FROM jobs WHERE type = ANY\(\$1\) AND \( status = \$4 AND run_at <= NOW\(\) OR status = \$3 AND locked_until <= NOW\(\) \) `+
+			`ORDER BY run_at LIMIT 1 FOR UPDATE SKIP LOCKED \) RETURNING id, type, payload`).
+			WithArgs("{\"export\"}", float64(600), "running", "queued").
+			WillReturnRows(rows)
+
+		job, err := repo.ClaimJob(context.Background(), []string{"export"}, 10*time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, job)
+	})
+
+	t.Run("no due job", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE jobs SET status`).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.ClaimJob(context.Background(), []string{"export"}, 10*time.Minute)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetryJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	id := uuid.New()
+
+	t.Run("running", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE jobs SET status = \$2, run_at = NOW\(\) \+ make_interval\(secs => \$3\), last_error = \$4, locked_until = NULL, updated_at = NOW\(\) WHERE id = \$1 AND status = \$5 AND attempts = \$6`).
+			WithArgs(id, "queued", float64(30), "connection refused", "running", 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.RetryJob(context.Background(), id, 2, 30*time.Second, "connection refused"))
+	})
+
+	t.Run("lease lost", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE jobs SET status`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.RetryJob(context.Background(), id, 2, 30*time.Second, "connection refused"), ErrLeaseLost)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFailJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE jobs SET status = \$2, last_error = \$3, locked_until = NULL, updated_at = NOW\(\), completed_at = NOW\(\) WHERE id = \$1 AND status = \$4 AND attempts = \$5`).
+		WithArgs(id, "failed", "user not found", "running", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.FailJob(context.Background(), id, 3, "user not found"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJobByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	userID := uuid.New()
+	expected := domain.Job{
+		ID:          uuid.New(),
+		Type:        "export",
+		Payload:     json.RawMessage(`{}`),
+		UserID:      uuid.NullUUID{UUID: userID, Valid: true},
+		Status:      domain.JobSucceeded,
+		Attempts:    2,
+		MaxAttempts: 5,
+		RunAt:       time.Date(2026, 10, 1, 12, 0, 5, 0, time.UTC),
+		LastError:   "connection refused",
+		CreatedAt:   time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2026, 10, 1, 12, 0, 9, 0, time.UTC),
+		CompletedAt: time.Date(2026, 10, 1, 12, 0, 9, 0, time.UTC),
+	}
+
+	rows := sqlmock.NewRows(jobRowColumns).
+		AddRow(expected.ID, "export", []byte(`{}`), userID, "succeeded", 2, 5, expected.RunAt, "connection refused",
+			expected.CreatedAt, expected.UpdatedAt, expected.CompletedAt)
+	mock.ExpectQuery(`SELECT id, type, payload, user_id, status, attempts, max_attempts, run_at, last_error, created_at, updated_at, completed_at FROM jobs WHERE id = \$1`).
+		WithArgs(expected.ID).
+		WillReturnRows(rows)
+
+	job, err := repo.GetJobByID(context.Background(), expected.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, job)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionRepository defines the interface for restoring soft deleted records
+// and purging the ones deleted longer than the retention period ago
+type RetentionRepository interface {
+	RestoreUser(ctx context.Context, id uuid.UUID, retention time.Duration) error
+	RestoreActivity(ctx context.Context, activityID uuid.UUID, retention time.Duration) error
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// PostgresRetentionRepository is a concrete implementation of RetentionRepository using PostgreSQL
+type PostgresRetentionRepository struct {
+	db *sql.DB
+}
+
+// NewRetentionRepository creates a new PostgresRetentionRepository
+func NewRetentionRepository(db *sql.DB) *PostgresRetentionRepository {
+	return &PostgresRetentionRepository{db: db}
+}
+
+// RestoreUser undeletes a user deleted within the retention period, along with
+// the activities and intervals deleted with them
+func (r *PostgresRetentionRepository) RestoreUser(ctx context.Context, id uuid.UUID, retention time.Duration) error {
+	return inTx(ctx, r.db, func(tx dbtx) error {
+		var deletedAt time.Time
+		err := tx.QueryRowContext(ctx,
+			`SELECT deleted_at FROM users
+			 WHERE id = $1 AND deleted_at > NOW() - make_interval(secs => $2) AND anonymized_at IS NULL
+			 FOR UPDATE`,
+			id, retention.Seconds(),
+		).Scan(&deletedAt)
+		if err != nil {
+			return translateError(ctx, err, "deleted user")
+		}
+
+		// Fails if another user took the email in the meantime
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET deleted_at = NULL WHERE id = $1", id); err != nil {
+			return translateError(ctx, err, "user")
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE intervals SET deleted_at = NULL
+			 WHERE deleted_at = $2 AND activity_id IN (SELECT id FROM activities WHERE user_id = $1)`,
+			id, deletedAt,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE activities SET deleted_at = NULL WHERE user_id = $1 AND deleted_at = $2", id, deletedAt); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// RestoreActivity undeletes an activity deleted within the retention period,
+// along with the intervals deleted with it; The activity of a deleted user can
+// only be restored with the user
+func (r *PostgresRetentionRepository) RestoreActivity(ctx context.Context, activityID uuid.UUID, retention time.Duration) error {
+	return inTx(ctx, r.db, func(tx dbtx) error {
+		var deletedAt time.Time
+		err := tx.QueryRowContext(ctx,
+			`SELECT a.deleted_at FROM activities a JOIN users u ON u.id = a.user_id
+			 WHERE a.id = $1 AND a.deleted_at > NOW() - make_interval(secs => $2) AND u.deleted_at IS NULL
+			 FOR UPDATE OF a`,
+			activityID, retention.Seconds(),
+		).Scan(&deletedAt)
+		if err != nil {
+			return translateError(ctx, err, "deleted activity")
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE activities SET deleted_at = NULL WHERE id = $1", activityID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE intervals SET deleted_at = NULL WHERE activity_id = $1 AND deleted_at = $2", activityID, deletedAt); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// expiredUsers selects the users deleted longer than the retention period ago,
+// given as $1 in seconds, that are not anonymized yet
+const expiredUsers = `SELECT id FROM users WHERE deleted_at <= NOW() - make_interval(secs => $1) AND anonymized_at IS NULL`
+
+// PurgeDeleted permanently deletes the activities and intervals deleted longer than
+// the retention period ago, as well as the expired data exports and the jobs and
+// webhook deliveries finished as long ago, and anonymizes the users deleted as long
+// ago: their CSS tests, exports, jobs and webhooks are deleted, the values in their
+// audit log are erased and their profile is blanked, leaving a row that keeps the
+// audit log attributed. It returns how many records were purged or anonymized
+func (r *PostgresRetentionRepository) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`DELETE FROM intervals WHERE deleted_at <= NOW() - make_interval(secs => $1)`,
+		`DELETE FROM activities WHERE deleted_at <= NOW() - make_interval(secs => $1)`,
+		`DELETE FROM css_tests WHERE user_id IN (` + expiredUsers + `)`,
+		`DELETE FROM data_exports WHERE expires_at <= NOW() OR user_id IN (` + expiredUsers + `)`,
+		`DELETE FROM jobs WHERE completed_at <= NOW() - make_interval(secs => $1) OR user_id IN (` + expiredUsers + `)`,
+		`DELETE FROM webhook_deliveries WHERE delivered_at <= NOW() - make_interval(secs => $1)`,
+		`DELETE FROM webhooks WHERE user_id IN (` + expiredUsers + `)`,
+		`UPDATE audit_events SET changes = '{}' WHERE user_id IN (` + expiredUsers + `)`,
+		`UPDATE users SET
+			name = '', email = '', city = '', phone = '', age = 0, height = 0, weight = 0,
+			max_heart_rate = 0, resting_heart_rate = 0, lactate_threshold_heart_rate = 0,
+			anonymized_at = NOW()
+		 WHERE id IN (` + expiredUsers + `)`,
+	}
+
+	var purged int64
+	for _, statement := range statements {
+		result, err := tx.ExecContext(ctx, statement, retention.Seconds())
+		if err != nil {
+			return 0, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		purged += rows
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
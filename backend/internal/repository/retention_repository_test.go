@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const retention = 30 * 24 * time.Hour
+
+func TestRestoreUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetentionRepository(db)
+	id := uuid.New()
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("restores the activities deleted with the user", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT deleted_at FROM users WHERE id = \$1 AND deleted_at > NOW\(\) - make_interval\(secs => \$2\) AND anonymized_at IS NULL FOR UPDATE`).
+			WithArgs(id, retention.Seconds()).
+			WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+		mock.ExpectExec(`UPDATE users SET deleted_at = NULL WHERE id = \$1`).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE intervals SET deleted_at = NULL WHERE deleted_at = \$2`).
+			WithArgs(id, deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 8))
+		mock.ExpectExec(`UPDATE activities SET deleted_at = NULL WHERE user_id = \$1 AND deleted_at = \$2`).
+			WithArgs(id, deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.RestoreUser(context.Background(), id, retention))
+	})
+
+	t.Run("not deleted or past retention", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT deleted_at FROM users`).
+			WithArgs(id, retention.Seconds()).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repo.RestoreUser(context.Background(), id, retention)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Equal(t, "deleted user not found", err.Error())
+	})
+
+	t.Run("email taken since the deletion", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT deleted_at FROM users`).
+			WithArgs(id, retention.Seconds()).
+			WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+		mock.ExpectExec(`UPDATE users SET deleted_at = NULL`).
+			WithArgs(id).
+			WillReturnError(&pq.Error{Code: pgUniqueViolation, Detail: "Key (email)=(john@example.com) already exists."})
+		mock.ExpectRollback()
+
+		err := repo.RestoreUser(context.Background(), id, retention)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreActivity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetentionRepository(db)
+	id := uuid.New()
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("restores the intervals deleted with the activity", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT a.deleted_at FROM activities a JOIN users u ON u.id = a.user_id WHERE a.id = \$1 AND (.+) AND u.deleted_at IS NULL FOR UPDATE OF a`).
+			WithArgs(id, retention.Seconds()).
+			WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+		mock.ExpectExec(`UPDATE activities SET deleted_at = NULL WHERE id = \$1`).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE intervals SET deleted_at = NULL WHERE activity_id = \$1 AND deleted_at = \$2`).
+			WithArgs(id, deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.RestoreActivity(context.Background(), id, retention))
+	})
+
+	t.Run("not restorable", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT a.deleted_at FROM activities`).
+			WithArgs(id, retention.Seconds()).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repo.RestoreActivity(context.Background(), id, retention)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetentionRepository(db)
+
+	t.Run("purges records and anonymizes users", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM intervals WHERE deleted_at <= NOW\(\) - make_interval\(secs => \$1\)`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec(`DELETE FROM activities WHERE deleted_at <=`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM css_tests WHERE user_id IN \(SELECT id FROM users WHERE deleted_at <= (.+) AND anonymized_at IS NULL\)`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM data_exports WHERE expires_at <= NOW\(\) OR user_id IN`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM jobs WHERE completed_at <= NOW\(\) - make_interval\(secs => \$1\) OR user_id IN`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectExec(`DELETE FROM webhook_deliveries WHERE delivered_at <= NOW\(\) - make_interval\(secs => \$1\)`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 6))
+		mock.ExpectExec(`DELETE FROM webhooks WHERE user_id IN`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE audit_events SET changes = '\{\}' WHERE user_id IN`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`UPDATE users SET name = '', email = '', (.+) anonymized_at = NOW\(\) WHERE id IN`).
+			WithArgs(retention.Seconds()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		purged, err := repo.PurgeDeleted(context.Background(), retention)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(23), purged)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM intervals`).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`DELETE FROM activities`).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		purged, err := repo.PurgeDeleted(context.Background(), retention)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.Zero(t, purged)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// dbtx runs statements, on the connection pool or in a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// Transactor runs functions in a database transaction, carried by the context they
+// are given; The repositories run their statements in the transaction of their context
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// PostgresTransactor is a concrete implementation of Transactor using a PostgreSQL database connection
+type PostgresTransactor struct {
+	db *sql.DB
+}
+
+// NewTransactor creates a new PostgresTransactor
+func NewTransactor(db *sql.DB) *PostgresTransactor {
+	return &PostgresTransactor{db: db}
+}
+
+// WithinTx runs fn in a transaction, committed if fn succeeds and rolled back otherwise;
+// If ctx already carries a transaction, fn joins it
+func (t *PostgresTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// conn returns the transaction carried by ctx, if any, or else db
+func conn(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTx runs fn in the transaction carried by ctx or, if there is none, in a new one
+func inTx(ctx context.Context, db *sql.DB, fn func(tx dbtx) error) error {
+	return NewTransactor(db).WithinTx(ctx, func(ctx context.Context) error {
+		return fn(conn(ctx, db))
+	})
+}
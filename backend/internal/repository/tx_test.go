@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithinTx(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com", City: "Santos", Phone: "123"}
+	event := domain.AuditEvent{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   user.ID,
+		Action:     domain.AuditActionCreate,
+	}
+
+	t.Run("commits the change and its event together", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		users, audit := NewUserRepository(db), NewAuditRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO users`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO audit_events`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = audit.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := users.CreateUser(ctx, user); err != nil {
+				return err
+			}
+			return audit.RecordEvent(ctx, event)
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls the change back if its event fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		users, audit := NewUserRepository(db), NewAuditRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO users`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO audit_events`).WillReturnError(errors.New("connection refused"))
+		mock.ExpectRollback()
+
+		err = audit.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := users.CreateUser(ctx, user); err != nil {
+				return err
+			}
+			return audit.RecordEvent(ctx, event)
+		})
+		assert.EqualError(t, err, "connection refused")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("joins the transaction of the context", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		users, audit := NewUserRepository(db), NewAuditRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE users SET deleted_at`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE intervals SET deleted_at`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE activities SET deleted_at`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO audit_events`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = audit.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := users.DeleteUser(ctx, user.ID); err != nil {
+				return err
+			}
+			return audit.RecordEvent(ctx, event)
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/google/uuid"
@@ -9,12 +10,12 @@ import (
 
 // UserRepository defines the interface for the user repository
 type UserRepository interface {
-	CreateUser(user domain.User) error
-	GetAllUsers() ([]domain.User, error)
-	GetUserByID(id uuid.UUID) (domain.User, error)
-	GetUserByEmail(email string) (domain.User, error)
-	UpdateUser(user domain.User) error
-	DeleteUser(id uuid.UUID) error
+	CreateUser(ctx context.Context, user domain.User) error
+	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (domain.User, error)
+	UpdateUser(ctx context.Context, user domain.User) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
 }
 
 // PostgresUserRepository is a concrete implementation of UserRepository using a PostgreSQL database connection
@@ -27,17 +28,21 @@ func NewUserRepository(db *sql.DB) *PostgresUserRepository {
 	return &PostgresUserRepository{db: db}
 }
 
-func (r *PostgresUserRepository) CreateUser(user domain.User) error {
-	_, err := r.db.Exec(
-		`INSERT INTO users (id, name, email, city, phone, age, height, weight)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user domain.User) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO users (
+			id, name, email, city, phone, age, height, weight,
+			max_heart_rate, resting_heart_rate, lactate_threshold_heart_rate, display_unit,
+			created_at, updated_at
+		 ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())`,
 		user.ID, user.Name, user.Email, user.City, user.Phone, user.Age, user.Height, user.Weight,
+		user.MaxHeartRate, user.RestingHeartRate, user.LactateThresholdHeartRate, user.DisplayUnit.OrDefault(),
 	)
-	return err
+	return translateError(ctx, err, "user")
 }
 
-func (r *PostgresUserRepository) GetAllUsers() ([]domain.User, error) {
-	rows, err := r.db.Query("SELECT id, name, email, city, phone, age, height, weight FROM users")
+func (r *PostgresUserRepository) GetAllUsers(ctx context.Context) ([]domain.User, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT id, name, email, city, phone, age, height, weight, max_heart_rate, resting_heart_rate, lactate_threshold_heart_rate, display_unit, version, created_at, updated_at FROM users WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +51,7 @@ func (r *PostgresUserRepository) GetAllUsers() ([]domain.User, error) {
 	var users []domain.User
 	for rows.Next() {
 		var user domain.User
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.City, &user.Phone, &user.Age, &user.Height, &user.Weight); err != nil {
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.City, &user.Phone, &user.Age, &user.Height, &user.Weight, &user.MaxHeartRate, &user.RestingHeartRate, &user.LactateThresholdHeartRate, &user.DisplayUnit, &user.Version, &user.CreatedAt, &user.UpdatedAt); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -59,34 +64,62 @@ func (r *PostgresUserRepository) GetAllUsers() ([]domain.User, error) {
 	return users, nil
 }
 
-func (r *PostgresUserRepository) GetUserByID(id uuid.UUID) (domain.User, error) {
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (domain.User, error) {
 	var user domain.User
-	row := r.db.QueryRow("SELECT id, name, email, city, phone, age, height, weight FROM users WHERE id = $1", id)
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.City, &user.Phone, &user.Age, &user.Height, &user.Weight)
-	return user, err
+	row := conn(ctx, r.db).QueryRowContext(ctx, "SELECT id, name, email, city, phone, age, height, weight, max_heart_rate, resting_heart_rate, lactate_threshold_heart_rate, display_unit, version, created_at, updated_at FROM users WHERE id = $1 AND deleted_at IS NULL", id)
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.City, &user.Phone, &user.Age, &user.Height, &user.Weight, &user.MaxHeartRate, &user.RestingHeartRate, &user.LactateThresholdHeartRate, &user.DisplayUnit, &user.Version, &user.CreatedAt, &user.UpdatedAt)
+	return user, translateError(ctx, err, "user")
 }
 
-func (r *PostgresUserRepository) GetUserByEmail(email string) (domain.User, error) {
+func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
 	var user domain.User
-	row := r.db.QueryRow("SELECT id, name, email, city, phone, age, height, weight FROM users WHERE email = $1", email)
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.City, &user.Phone, &user.Age, &user.Height, &user.Weight)
-	if err == sql.ErrNoRows {
-		return user, nil // No user found with the given email
-	}
-	return user, err
+	row := conn(ctx, r.db).QueryRowContext(ctx, "SELECT id, name, email, city, phone, age, height, weight, max_heart_rate, resting_heart_rate, lactate_threshold_heart_rate, display_unit, version, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL", email)
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.City, &user.Phone, &user.Age, &user.Height, &user.Weight, &user.MaxHeartRate, &user.RestingHeartRate, &user.LactateThresholdHeartRate, &user.DisplayUnit, &user.Version, &user.CreatedAt, &user.UpdatedAt)
+	return user, translateError(ctx, err, "user")
 }
 
-func (r *PostgresUserRepository) UpdateUser(user domain.User) error {
-	_, err := r.db.Exec(
+func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user domain.User) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx,
 		`UPDATE users 
-		 SET name = $1, email = $2, city = $3, phone = $4, age = $5, height = $6, weight = $7 
-		 WHERE id = $8`,
-		user.Name, user.Email, user.City, user.Phone, user.Age, user.Height, user.Weight, user.ID,
+		 SET name = $1, email = $2, city = $3, phone = $4, age = $5, height = $6, weight = $7,
+		     max_heart_rate = $8, resting_heart_rate = $9, lactate_threshold_heart_rate = $10, display_unit = $11,
+		     version = version + 1, updated_at = NOW()
+		 WHERE id = $12 AND deleted_at IS NULL AND ($13 = 0 OR version = $13)`,
+		user.Name, user.Email, user.City, user.Phone, user.Age, user.Height, user.Weight,
+		user.MaxHeartRate, user.RestingHeartRate, user.LactateThresholdHeartRate, user.DisplayUnit.OrDefault(), user.ID,
+		user.Version,
 	)
-	return err
+	if err != nil {
+		return translateError(ctx, err, "user")
+	}
+	return expectUpdated(ctx, r.db, result, "users", user.ID, user.Version, "user")
 }
 
-func (r *PostgresUserRepository) DeleteUser(id uuid.UUID) error {
-	_, err := r.db.Exec("DELETE FROM users WHERE id = $1", id)
-	return err
+// DeleteUser soft deletes the user along with their activities and intervals,
+// all marked with the same deletion time so RestoreUser can tell them apart
+// from the activities and intervals that were deleted on their own before
+func (r *PostgresUserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx dbtx) error {
+		// NOW() is the start time of the transaction, the same in every statement
+		result, err := tx.ExecContext(ctx, "UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id)
+		if err != nil {
+			return err
+		}
+		if err := expectRowsAffected(result, "user"); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE intervals SET deleted_at = NOW()
+			 WHERE deleted_at IS NULL AND activity_id IN (SELECT id FROM activities WHERE user_id = $1 AND deleted_at IS NULL)`,
+			id,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE activities SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL", id); err != nil {
+			return err
+		}
+
+		return nil
+	})
 }
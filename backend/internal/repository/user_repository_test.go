@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/liviaruegger/MAC0350/backend/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
@@ -17,21 +21,24 @@ func TestCreateUser(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	user := domain.User{
-		ID:     uuid.New(),
-		Name:   "John Doe",
-		Email:  "john.doe@example.com",
-		City:   "São Paulo",
-		Phone:  "+5511999999999",
-		Age:    30,
-		Height: 170,
-		Weight: 65.5,
+		ID:               uuid.New(),
+		Name:             "John Doe",
+		Email:            "john.doe@example.com",
+		City:             "São Paulo",
+		Phone:            "+5511999999999",
+		Age:              30,
+		Height:           170,
+		Weight:           65.5,
+		MaxHeartRate:     188,
+		RestingHeartRate: 52,
 	}
 
 	mock.ExpectExec("INSERT INTO users").
-		WithArgs(user.ID, user.Name, user.Email, user.City, user.Phone, user.Age, user.Height, user.Weight).
+		WithArgs(user.ID, user.Name, user.Email, user.City, user.Phone, user.Age, user.Height, user.Weight,
+			user.MaxHeartRate, user.RestingHeartRate, user.LactateThresholdHeartRate, "meters").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.CreateUser(user)
+	err = repo.CreateUser(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -44,29 +51,55 @@ func TestGetAllUsers(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	expectedUser := domain.User{
-		ID:     uuid.New(),
-		Name:   "John Doe",
-		Email:  "john@example.com",
-		City:   "São Paulo",
-		Phone:  "+5511999999999",
-		Age:    30,
-		Height: 170,
-		Weight: 65.5,
+		ID:               uuid.New(),
+		Name:             "John Doe",
+		Email:            "john@example.com",
+		City:             "São Paulo",
+		Phone:            "+5511999999999",
+		Age:              30,
+		Height:           170,
+		Weight:           65.5,
+		MaxHeartRate:     188,
+		RestingHeartRate: 52,
+		DisplayUnit:      domain.DistanceUnitMeters,
+		Version:          2,
+		CreatedAt:        time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt:        time.Date(2025, 6, 7, 8, 9, 10, 0, time.UTC),
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "city", "phone", "age", "height", "weight"}).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "city", "phone", "age", "height", "weight", "max_heart_rate", "resting_heart_rate", "lactate_threshold_heart_rate", "display_unit", "version", "created_at", "updated_at"}).
 		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Email, expectedUser.City, expectedUser.Phone,
-			expectedUser.Age, expectedUser.Height, expectedUser.Weight)
+			expectedUser.Age, expectedUser.Height, expectedUser.Weight, expectedUser.MaxHeartRate, expectedUser.RestingHeartRate, expectedUser.LactateThresholdHeartRate, expectedUser.DisplayUnit, expectedUser.Version, expectedUser.CreatedAt, expectedUser.UpdatedAt)
 
-	mock.ExpectQuery("SELECT id, name, email, city, phone, age, height, weight FROM users").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT id, name, email, city, phone, age, height, weight, max_heart_rate, resting_heart_rate, lactate_threshold_heart_rate, display_unit, version, created_at, updated_at FROM users WHERE deleted_at IS NULL").WillReturnRows(rows)
 
-	users, err := repo.GetAllUsers()
+	users, err := repo.GetAllUsers(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, users, 1)
 	assert.Equal(t, expectedUser, users[0])
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetAllUsers_ContextCanceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM users").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = repo.GetAllUsers(ctx)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second, "query should be canceled at the deadline")
+}
+
 func TestGetUserByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -75,25 +108,27 @@ func TestGetUserByID(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	expectedUser := domain.User{
-		ID:     uuid.New(),
-		Name:   "Jane Smith",
-		Email:  "jane@example.com",
-		City:   "Rio de Janeiro",
-		Phone:  "+5521999999999",
-		Age:    25,
-		Height: 165,
-		Weight: 55.0,
+		ID:           uuid.New(),
+		Name:         "Jane Smith",
+		Email:        "jane@example.com",
+		City:         "Rio de Janeiro",
+		Phone:        "+5521999999999",
+		Age:          25,
+		Height:       165,
+		Weight:       55.0,
+		MaxHeartRate: 195,
+		DisplayUnit:  domain.DistanceUnitYards,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "city", "phone", "age", "height", "weight"}).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "city", "phone", "age", "height", "weight", "max_heart_rate", "resting_heart_rate", "lactate_threshold_heart_rate", "display_unit", "version", "created_at", "updated_at"}).
 		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Email, expectedUser.City, expectedUser.Phone,
-			expectedUser.Age, expectedUser.Height, expectedUser.Weight)
+			expectedUser.Age, expectedUser.Height, expectedUser.Weight, expectedUser.MaxHeartRate, expectedUser.RestingHeartRate, expectedUser.LactateThresholdHeartRate, expectedUser.DisplayUnit, expectedUser.Version, expectedUser.CreatedAt, expectedUser.UpdatedAt)
 
-	mock.ExpectQuery("SELECT id, name, email, city, phone, age, height, weight FROM users WHERE id =").
+	mock.ExpectQuery("SELECT id, name, email, city, phone, age, height, weight, max_heart_rate, resting_heart_rate, lactate_threshold_heart_rate, display_unit, version, created_at, updated_at FROM users WHERE id = \\$1 AND deleted_at IS NULL").
 		WithArgs(expectedUser.ID).
 		WillReturnRows(rows)
 
-	user, err := repo.GetUserByID(expectedUser.ID)
+	user, err := repo.GetUserByID(context.Background(), expectedUser.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -107,21 +142,23 @@ func TestUpdateUser(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	user := domain.User{
-		ID:     uuid.New(),
-		Name:   "John Updated",
-		Email:  "john.updated@example.com",
-		City:   "Campinas",
-		Phone:  "+5511987654321",
-		Age:    35,
-		Height: 175,
-		Weight: 70.2,
+		ID:          uuid.New(),
+		Name:        "John Updated",
+		Email:       "john.updated@example.com",
+		City:        "Campinas",
+		Phone:       "+5511987654321",
+		Age:         35,
+		Height:      175,
+		Weight:      70.2,
+		DisplayUnit: domain.DistanceUnitYards,
 	}
 
 	mock.ExpectExec("UPDATE users").
-		WithArgs(user.Name, user.Email, user.City, user.Phone, user.Age, user.Height, user.Weight, user.ID).
+		WithArgs(user.Name, user.Email, user.City, user.Phone, user.Age, user.Height, user.Weight,
+			user.MaxHeartRate, user.RestingHeartRate, user.LactateThresholdHeartRate, "yards", user.ID, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.UpdateUser(user)
+	err = repo.UpdateUser(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -134,11 +171,119 @@ func TestDeleteUser(t *testing.T) {
 	repo := NewUserRepository(db)
 	id := uuid.New()
 
-	mock.ExpectExec("DELETE FROM users WHERE id =").
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET deleted_at = NOW\(\) WHERE id = \$1 AND deleted_at IS NULL`).
 		WithArgs(id).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE intervals SET deleted_at = NOW\(\) WHERE deleted_at IS NULL AND activity_id IN \(SELECT id FROM activities WHERE user_id = \$1`).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec(`UPDATE activities SET deleted_at = NOW\(\) WHERE user_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err = repo.DeleteUser(context.Background(), id)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateUser_DuplicateEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec("INSERT INTO users").
+		WillReturnError(&pq.Error{Code: pgUniqueViolation, Detail: "Key (email)=(john@example.com) already exists."})
+
+	err = repo.CreateUser(context.Background(), domain.User{ID: uuid.New(), Email: "john@example.com"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Equal(t, "user with this email already exists", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserByEmail_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE email =").
+		WithArgs("nobody@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetUserByEmail(context.Background(), "nobody@example.com")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateUser_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec("UPDATE users").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateUser(context.Background(), domain.User{ID: uuid.New()})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteUser_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	id := uuid.New()
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users SET deleted_at").
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.DeleteUser(context.Background(), id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users SET deleted_at").
+			WithArgs(id).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err := repo.DeleteUser(context.Background(), id)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NotErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	err = repo.DeleteUser(id)
+func TestUpdateUser_VersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	id := uuid.New()
+
+	mock.ExpectExec("UPDATE users").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM "users" WHERE id = \$1 AND deleted_at IS NULL\)`).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err = repo.UpdateUser(context.Background(), domain.User{ID: id, Version: 3})
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+)
+
+// WebhookRepository defines the interface for the webhooks and their delivery log
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) error
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (domain.Webhook, error)
+	GetWebhooksByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	RecordDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	GetDeliveriesByWebhook(ctx context.Context, webhookID uuid.UUID, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// PostgresWebhookRepository is a concrete implementation of WebhookRepository using PostgreSQL
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new PostgresWebhookRepository
+func NewWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+// CreateWebhook registers a webhook, secret included
+func (r *PostgresWebhookRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) error {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhooks (id, user_id, url, events, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`, webhook.ID, webhook.UserID, webhook.URL, pq.Array(events), webhook.Secret)
+	return translateError(ctx, err, "webhook")
+}
+
+// GetWebhookByID returns a webhook, secret included
+func (r *PostgresWebhookRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (domain.Webhook, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, url, events, secret, created_at FROM webhooks WHERE id = $1
+	`, id)
+	webhook, err := scanWebhook(row)
+	if err != nil {
+		return domain.Webhook{}, translateError(ctx, err, "webhook")
+	}
+	return webhook, nil
+}
+
+// GetWebhooksByUser returns the webhooks of a user, secrets included, oldest first
+func (r *PostgresWebhookRepository) GetWebhooksByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, url, events, secret, created_at FROM webhooks WHERE user_id = $1
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []domain.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes a webhook along with its delivery log
+func (r *PostgresWebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result, "webhook")
+}
+
+// RecordDelivery appends an attempt to the delivery log of a webhook, timestamped by the database
+func (r *PostgresWebhookRepository) RecordDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (
+			id, webhook_id, event_id, event, attempt, status_code, error, duration_ms, delivered_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+	`,
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.EventID,
+		delivery.Event,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.DurationMS,
+	)
+	return translateError(ctx, err, "webhook delivery")
+}
+
+// GetDeliveriesByWebhook returns the latest attempts at delivering events to a webhook, most recent first
+func (r *PostgresWebhookRepository) GetDeliveriesByWebhook(ctx context.Context, webhookID uuid.UUID, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, webhook_id, event_id, event, attempt, status_code, error, duration_ms, delivered_at
+		FROM webhook_deliveries WHERE webhook_id = $1
+		ORDER BY delivered_at DESC, id
+		LIMIT $2
+	`, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.Event,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Error,
+			&delivery.DurationMS,
+			&delivery.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// scanWebhook reads a webhook from a row of a query selecting its columns
+func scanWebhook(row interface{ Scan(dest ...any) error }) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	var events []string
+	if err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		pq.Array(&events),
+		&webhook.Secret,
+		&webhook.CreatedAt,
+	); err != nil {
+		return domain.Webhook{}, err
+	}
+	webhook.Events = make([]domain.WebhookEvent, len(events))
+	for i, event := range events {
+		webhook.Events[i] = domain.WebhookEvent(event)
+	}
+	return webhook, nil
+}
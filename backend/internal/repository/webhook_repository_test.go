@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/liviaruegger/MAC0350/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	webhook := domain.Webhook{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+		URL:    "https://hooks.example.com/swims",
+		Events: []domain.WebhookEvent{domain.WebhookActivityCreated, domain.WebhookRecordAchieved},
+		Secret: "whsec_42",
+	}
+
+	mock.ExpectExec(`INSERT INTO webhooks \(id, user_id, url, events, secret, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, NOW\(\)\)`).
+		WithArgs(webhook.ID, webhook.UserID, webhook.URL, `{"activity.created","record.achieved"}`, "whsec_42").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.CreateWebhook(context.Background(), webhook)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetWebhooksByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	expected := domain.Webhook{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		URL:       "https://hooks.example.com/swims",
+		Events:    []domain.WebhookEvent{domain.WebhookActivityDeleted},
+		Secret:    "whsec_42",
+		CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "url", "events", "secret", "created_at"}).
+		AddRow(expected.ID, expected.UserID, expected.URL, []byte(`{activity.deleted}`), expected.Secret, expected.CreatedAt)
+	mock.ExpectQuery(`SELECT id, user_id, url, events, secret, created_at FROM webhooks WHERE user_id = \$1 ORDER BY created_at, id`).
+		WithArgs(expected.UserID).
+		WillReturnRows(rows)
+
+	webhooks, err := repo.GetWebhooksByUser(context.Background(), expected.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Webhook{expected}, webhooks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetWebhookByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	id := uuid.New()
+
+	mock.ExpectQuery(`SELECT (.+) FROM webhooks WHERE id = \$1`).
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetWebhookByID(context.Background(), id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	id := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM webhooks WHERE id = \$1`).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM webhooks`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.DeleteWebhook(context.Background(), id))
+	assert.ErrorIs(t, repo.DeleteWebhook(context.Background(), id), domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	delivery := domain.WebhookDelivery{
+		ID:         uuid.New(),
+		WebhookID:  uuid.New(),
+		EventID:    uuid.New(),
+		Event:      domain.WebhookActivityCreated,
+		Attempt:    2,
+		StatusCode: 503,
+		Error:      "responded 503 Service Unavailable",
+		DurationMS: 120,
+	}
+
+	mock.ExpectExec(`INSERT INTO webhook_deliveries (.+) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, NOW\(\)\)`).
+		WithArgs(delivery.ID, delivery.WebhookID, delivery.EventID, "activity.created", 2, 503, delivery.Error, int64(120)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.RecordDelivery(context.Background(), delivery)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveriesByWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	expected := domain.WebhookDelivery{
+		ID:          uuid.New(),
+		WebhookID:   uuid.New(),
+		EventID:     uuid.New(),
+		Event:       domain.WebhookRecordAchieved,
+		Attempt:     1,
+		StatusCode:  204,
+		DurationMS:  35,
+		DeliveredAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "webhook_id", "event_id", "event", "attempt", "status_code", "error", "duration_ms", "delivered_at"}).
+		AddRow(expected.ID, expected.WebhookID, expected.EventID, "record.achieved", 1, 204, "", 35, expected.DeliveredAt)
+	mock.ExpectQuery(`SELECT id, webhook_id, event_id, event, attempt, status_code, error, duration_ms, delivered_at FROM webhook_deliveries WHERE webhook_id = \$1 ORDER BY delivered_at DESC, id LIMIT \$2`).
+		WithArgs(expected.WebhookID, 100).
+		WillReturnRows(rows)
+
+	deliveries, err := repo.GetDeliveriesByWebhook(context.Background(), expected.WebhookID, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.WebhookDelivery{expected}, deliveries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -15,7 +15,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/activities": {
+        "/api/v1/activities": {
             "get": {
                 "description": "Retrieves all swim activities in the system",
                 "consumes": [
@@ -41,7 +41,7 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
@@ -79,21 +79,21 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/intervals": {
-            "post": {
-                "description": "Creates an interval with the data provided in the request body",
+        "/api/v1/activities/{id}": {
+            "get": {
+                "description": "Retrieves a swim activity with its intervals, pace zones and time spent in each heart-rate zone",
                 "consumes": [
                     "application/json"
                 ],
@@ -101,45 +101,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "intervals"
+                    "activities"
                 ],
-                "summary": "Create a new interval",
+                "summary": "Get an activity",
                 "parameters": [
                     {
-                        "description": "Interval data",
-                        "name": "interval",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateIntervalRequest"
-                        }
+                        "type": "string",
+                        "description": "Activity ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Interval successfully created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Interval"
+                            "$ref": "#/definitions/entity.Activity"
                         }
                     },
+                    "304": {
+                        "description": "Activity unchanged since the given ETag"
+                    },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid activity ID",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Activity not found",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/users": {
-            "get": {
-                "description": "Returns a list of all users with their name, email, city, and phone",
+            },
+            "put": {
+                "description": "Replaces the data of a swim activity; its user and intervals are kept.\nWith If-Match, the update only applies if the activity still has that ETag.",
                 "consumes": [
                     "application/json"
                 ],
@@ -147,29 +152,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "activities"
+                ],
+                "summary": "Update an activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Activity ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the update is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Activity data",
+                        "name": "activity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateActivityRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "Activity successfully updated",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.User"
-                            }
+                            "$ref": "#/definitions/entity.Activity"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Activity not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Activity was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Creates a user with the provided name, email, city, and phone",
+            "delete": {
+                "description": "Deletes a swim activity along with its intervals.\nIt can be restored until the retention period is over, after which it is purged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -177,45 +221,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "activities"
                 ],
-                "summary": "Create a new user",
+                "summary": "Delete an activity",
                 "parameters": [
                     {
-                        "description": "User data.",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Activity ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User successfully created",
+                    "204": {
+                        "description": "Activity successfully deleted"
+                    },
+                    "400": {
+                        "description": "Invalid activity ID",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "404": {
+                        "description": "Activity not found",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users/email/{email}": {
-            "get": {
-                "description": "Returns the user with name, email, city, and phone for the specified email",
+        "/api/v1/activities/{id}/restore": {
+            "post": {
+                "description": "Restores an activity deleted within the retention period, along with its intervals.\nThe activities of a deleted user are restored with the user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -223,43 +268,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "activities"
                 ],
-                "summary": "Get user by email",
+                "summary": "Restore a deleted activity",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User email",
-                        "name": "email",
+                        "description": "Activity ID (UUID)",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User found",
+                        "description": "Activity successfully restored",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/entity.Activity"
                         }
                     },
                     "400": {
-                        "description": "Invalid email",
+                        "description": "Invalid activity ID",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "No activity deleted within the retention period",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
+        "/api/v1/exports/{id}": {
             "get": {
-                "description": "Returns the user with name, email, city, and phone for the specified ID",
+                "description": "Returns the status of an export, with the link to download its archive once ready",
                 "consumes": [
                     "application/json"
                 ],
@@ -269,11 +320,11 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get a data export",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID (UUID)",
+                        "description": "Export ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -281,84 +332,112 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User found",
+                        "description": "Export",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/handler.ExportResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid export ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Export not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates the user with the provided ID, name, email, city, and phone",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/exports/{id}/download": {
+            "get": {
+                "description": "Returns the ZIP archive of a ready export",
                 "produces": [
-                    "application/json"
+                    "application/zip"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Update an existing user",
+                "summary": "Download a data export",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID (UUID)",
+                        "description": "Export ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated user data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.User"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User successfully updated",
+                        "description": "ZIP archive",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid export ID",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Export not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Export still pending or failed",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes the user with the specified ID",
+            }
+        },
+        "/api/v1/graphql": {
+            "post": {
+                "description": "Runs a GraphQL query over users, their activities and the intervals of the activities, e.g., to fetch a user\nand the distance and stroke of the intervals of their last 10 activities in one request. Only queries are\nsupported. The intervals, and the metrics derived from them, of all the activities of a response are loaded\ntogether. The response is always 200 OK with the data and errors of the query, as GraphQL clients expect,\nunless the body is not a GraphQL request. Errors of resolved fields have an extensions.status with the\nstatus code the REST API would have responded with. Queries can nest fields 6 levels deep, select 300\nfields and alias 20, counting the fields of a fragment each time it is spread. The schema is served by\nGET /api/v1/graphql/schema, and through introspection with __schema and __type, e.g., for GraphiQL.",
                 "consumes": [
                     "application/json"
                 ],
@@ -366,46 +445,105 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "graphql"
                 ],
-                "summary": "Delete a user",
+                "summary": "Run a GraphQL query",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Query, operation name and variables",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Request"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "User successfully deleted"
+                    "200": {
+                        "description": "Data and errors of the query",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/graphql/schema": {
+            "get": {
+                "description": "Returns the schema of the GraphQL endpoint in the GraphQL schema definition language",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "Get the GraphQL schema",
+                "responses": {
+                    "200": {
+                        "description": "Schema definition",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/intervals": {
+            "post": {
+                "description": "Creates an interval with the data provided in the request body",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "intervals"
+                ],
+                "summary": "Create a new interval",
+                "parameters": [
+                    {
+                        "description": "Interval data",
+                        "name": "interval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateIntervalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Interval successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Interval"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users/{user_id}/activities": {
+        "/api/v1/jobs/{id}": {
             "get": {
-                "description": "Retrieves all swim activities and their intervals for a given user ID",
+                "description": "Returns the status of a background job, e.g., the one generating a data export, and its attempts so far.\nA failing job is retried with exponential backoff until it runs out of attempts.\nOnly the user the job works for and admins may read it; jobs working for no user are served to admins only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -413,61 +551,1969 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "activities"
+                    "jobs"
                 ],
-                "summary": "Get all activities of a user",
+                "summary": "Get a background job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID (UUID)",
-                        "name": "user_id",
+                        "description": "Job ID (UUID)",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Job",
                         "schema": {
-                            "$ref": "#/definitions/handler.GetActivitiesByUserResponse"
+                            "$ref": "#/definitions/domain.Job"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid job ID",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "User not found or no activities",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Neither the user nor an admin",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/handler.ProblemDetails"
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "description": "Returns a list of all users with their name, email, city, and phone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a user with the provided name, email, city, and phone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User data.",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/email/{email}": {
+            "get": {
+                "description": "Returns the user with name, email, city, and phone for the specified email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid email",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}": {
+            "get": {
+                "description": "Returns the user with name, email, city, and phone for the specified ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "304": {
+                        "description": "User unchanged since the given ETag"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the user with the provided ID. The optional fields left out keep their stored value,\ne.g., the heart-rate settings and display unit when only the name, email, city and phone are sent.\nWith If-Match, the update only applies if the user still has that ETag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update an existing user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the update is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated user data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the user with the specified ID, along with their activities.\nThey can be restored until the retention period is over, after which their activities,\nCSS tests, exports and webhooks are purged, and their profile and audit log are anonymized.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User successfully deleted"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/audit": {
+            "get": {
+                "description": "Returns the changes made to the user's profile, activities, intervals and CSS tests, most recent first,\neach with who made it and the values of the changed fields before and after it.\nOnly the user and admins, identified by the authentication header, may read it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the audit log of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audit log",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.AuditEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/css": {
+            "get": {
+                "description": "Returns all CSS tests of the user with their pace zones, most recent first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "css"
+                ],
+                "summary": "Get the CSS test history of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSS test history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.CSSTest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Computes the Critical Swim Speed from a 400m and a 200m trial interval; if no intervals are given, they are detected in the user's most recent activity containing both distances",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "css"
+                ],
+                "summary": "Record a CSS test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trial intervals",
+                        "name": "test",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateCSSTestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "CSS test successfully recorded",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CSSTest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or trials",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "No trials found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/events": {
+            "get": {
+                "description": "Streams the changes to the data of the user as server-sent events, e.g., to update a dashboard live.\nEach event is named after its type, \"activity.created\", \"activity.updated\" or \"activity.deleted\", with\nthe event ID as its id and a JSON payload shaped like the body of a webhook delivery, whose data is the activity.\nThe data is null when it was too large to relay from another API instance, and clients should reload it.\nThe stream is not bound by the request timeout. It is closed when the client falls too far behind or the\nserver shuts down, after which clients reconnect and reload the data they show, as events may have been missed.\nOnly the user and admins may subscribe.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream the events of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/domain.WebhookPayload"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/export": {
+            "post": {
+                "description": "Starts generating a ZIP archive of the user's profile, activities and intervals (as JSON and CSV),\nCSS tests and audit log. Poll the export, linked by the Location header, until it is ready to download.\nThe archive is generated by a background job, retried when it fails, whose attempts are served by /api/v1/jobs/{job_id}.\nOnly the user and admins may request it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Request an export of the data of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Export requested",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ExportResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of the export"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/heart-rate-zones": {
+            "get": {
+                "description": "Returns the five heart-rate zones of the user, derived from the lactate threshold heart rate, the maximum heart rate or the age, in this order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "heart-rate"
+                ],
+                "summary": "Get the heart-rate zones of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.HeartRateZones"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/pace-zones": {
+            "get": {
+                "description": "Returns the pace zones per 100m derived from the user's most recent CSS test",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "css"
+                ],
+                "summary": "Get the pace zones of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pace zones",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.PaceZone"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "No CSS test recorded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/restore": {
+            "post": {
+                "description": "Restores a user deleted within the retention period, along with the activities deleted with them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Restore a deleted user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User successfully restored",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "No user deleted within the retention period",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Email taken by another user since the deletion",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/stroke-efficiency": {
+            "get": {
+                "description": "Returns the stroke count, SWOLF and distance per stroke of each session with stroke data over a period; the period defaults to the last 28 days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strokes"
+                ],
+                "summary": "Get the stroke efficiency trend of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the period (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StrokeEfficiencyTrend"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or period",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/time-in-zone": {
+            "get": {
+                "description": "Aggregates the time spent in each heart-rate zone over a period, at interval resolution when intervals have heart-rate data; the period defaults to the last 28 days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "heart-rate"
+                ],
+                "summary": "Get the time a user spent in each heart-rate zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the period (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TimeInZoneSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or period",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/training-load": {
+            "get": {
+                "description": "Returns the daily session load and the acute (7-day) and chronic (42-day) loads with their ratio; the period defaults to the last 28 days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "training-load"
+                ],
+                "summary": "Get the training load of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the period (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TrainingLoad"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or period",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/webhooks": {
+            "get": {
+                "description": "Returns the webhooks of the user, oldest first, without their secrets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get the webhooks of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhooks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Webhook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registers an endpoint the user's events are posted to, as JSON with the event ID, type, time and data.\nEach delivery is signed: the X-Webhook-Signature header is \"sha256=\" followed by the hex-encoded HMAC-SHA256,\nkeyed by the webhook secret, of the X-Webhook-Timestamp header, a dot and the raw body.\nThe secret is only returned here. Deliveries not acknowledged with a 2xx status are retried with exponential backoff,\nwith the same X-Webhook-ID. Only the user and admins may register one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Endpoint and events",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook successfully registered, with its secret",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{user_id}/activities": {
+            "get": {
+                "description": "Retrieves all swim activities and their intervals for a given user ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "activities"
+                ],
+                "summary": "Get all activities of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetActivitiesByUserResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Activities unchanged since the given ETag"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found or no activities",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "delete": {
+                "description": "Deletes a webhook along with its delivery log; the deliveries still queued are dropped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook successfully deleted"
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Returns the latest 100 attempts at delivering events to the webhook, most recent first,\neach with the status of the response or the error that prevented one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get the delivery log of a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery log",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Neither the user nor an admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is up; it does not check its dependencies, so a failing database does not get the API restarted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "The API is alive",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Exposes request counts, latency histograms and database connection pool statistics in the Prometheus text format",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Prometheus metrics",
+                "responses": {
+                    "200": {
+                        "description": "Metrics in the Prometheus text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Pings the database and checks that its schema has been migrated to the version this build expects",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "The API is ready to serve requests",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Readiness"
+                        }
+                    },
+                    "503": {
+                        "description": "A check failed",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Readiness"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.Activity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt is when the activity was created, set by the repository",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "date": {
+                    "description": "Date in ISO 8601 format, e.g., \"2023-10-01\"",
+                    "type": "string"
+                },
+                "distance": {
+                    "description": "Total distance in meters",
+                    "type": "number"
+                },
+                "duration": {
+                    "description": "Duration of the activity, with millisecond precision",
+                    "type": "string"
+                },
+                "feeling": {
+                    "description": "Optional feeling after the swim, e.g., \"tired\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.FeelingType"
+                        }
+                    ]
+                },
+                "heart_rate_avg": {
+                    "description": "Average heart rate during the activity",
+                    "type": "integer"
+                },
+                "heart_rate_max": {
+                    "description": "Maximum heart rate during the activity",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the activity (PK)",
+                    "type": "string"
+                },
+                "laps": {
+                    "description": "Number of pool laps",
+                    "type": "integer"
+                },
+                "location_name": {
+                    "description": "Optional name for the location, e.g., \"CEPE\"",
+                    "type": "string"
+                },
+                "location_type": {
+                    "description": "\"pool\" or \"open_water\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.LocationType"
+                        }
+                    ]
+                },
+                "notes": {
+                    "description": "Optional notes",
+                    "type": "string"
+                },
+                "pool_size": {
+                    "description": "Pool length, in PoolUnit (0 if open water)",
+                    "type": "number"
+                },
+                "pool_unit": {
+                    "description": "Unit of the pool length, \"meters\" (default) or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
+                "rpe": {
+                    "description": "Optional rating of perceived exertion, from 1 (very easy) to 10 (maximal)",
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Start time of the activity",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the activity was last updated, set by the repository",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user who performed the activity (FK)",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is incremented on every update; An update with a non-zero version\nonly applies if the stored activity still has that version",
+                    "type": "integer",
+                    "readOnly": true
+                }
+            }
+        },
+        "domain.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore"
+            ],
+            "x-enum-varnames": [
+                "AuditActionCreate",
+                "AuditActionUpdate",
+                "AuditActionDelete",
+                "AuditActionRestore"
+            ]
+        },
+        "domain.AuditEntityType": {
+            "type": "string",
+            "enum": [
+                "user",
+                "activity",
+                "interval",
+                "css_test"
+            ],
+            "x-enum-varnames": [
+                "AuditEntityUser",
+                "AuditEntityActivity",
+                "AuditEntityInterval",
+                "AuditEntityCSSTest"
+            ]
+        },
+        "domain.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is the kind of change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.AuditAction"
+                        }
+                    ]
+                },
+                "actor_id": {
+                    "description": "ActorID is the ID of the user who made the change, null if made anonymously",
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Changes maps each changed field, by its JSON name, to its values before and after the change",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "entity_id": {
+                    "description": "EntityID is the ID of the changed record",
+                    "type": "string"
+                },
+                "entity_type": {
+                    "description": "EntityType is the kind of the changed record",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.AuditEntityType"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the event (PK)",
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "description": "OccurredAt is when the change was made, set by the repository",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user owning the changed record",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DistanceUnit": {
+            "type": "string",
+            "enum": [
+                "meters",
+                "yards"
+            ],
+            "x-enum-varnames": [
+                "DistanceUnitMeters",
+                "DistanceUnitYards"
+            ]
+        },
+        "domain.ExportStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "ready",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ExportPending",
+                "ExportReady",
+                "ExportFailed"
+            ]
+        },
+        "domain.FeelingType": {
+            "type": "string",
+            "enum": [
+                "excellent",
+                "good",
+                "regular",
+                "tired",
+                "bad"
+            ],
+            "x-enum-varnames": [
+                "FeelingExcellent",
+                "FeelingGood",
+                "FeelingRegular",
+                "FeelingTired",
+                "FeelingBad"
+            ]
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "domain.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable reason, one of the Code constants, e.g., \"invalid_format\"",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Name of the field, as sent by the client, e.g., \"email\"",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Human-readable reason, e.g., \"must be a valid email\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Interval": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "description": "Foreign key to the swim activity/session",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt is when the interval was created, set by the repository",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "distance": {
+                    "description": "Distance in meters",
+                    "type": "number"
+                },
+                "duration": {
+                    "description": "Duration of the interval, with millisecond precision",
+                    "type": "string"
+                },
+                "heart_rate_avg": {
+                    "description": "Optional average heart rate during the interval",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "length_stroke_counts": {
+                    "description": "Optional number of strokes of each pool length, when imported from a device",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "notes": {
+                    "description": "Optional notes like \"felt strong\", \"used fins\"",
+                    "type": "string"
+                },
+                "stroke": {
+                    "description": "Type of swimming stroke",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.StrokeType"
+                        }
+                    ]
+                },
+                "stroke_count": {
+                    "description": "Optional total number of strokes taken during the interval",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "One of the predefined types",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.IntervalType"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the interval was last updated, set by the repository",
+                    "type": "string",
+                    "readOnly": true
+                }
+            }
+        },
+        "domain.IntervalType": {
+            "type": "string",
+            "enum": [
+                "swim",
+                "rest",
+                "drill",
+                "kick",
+                "pull",
+                "warmup",
+                "main_set",
+                "cooldown"
+            ],
+            "x-enum-varnames": [
+                "IntervalSwim",
+                "IntervalRest",
+                "IntervalDrill",
+                "IntervalKick",
+                "IntervalPull",
+                "IntervalWarmUp",
+                "IntervalMainSet",
+                "IntervalCoolDown"
+            ]
+        },
+        "domain.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts counts the runs of the job so far",
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "description": "CompletedAt is when the job succeeded or failed",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt is when the job was enqueued, set by the repository",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the job (PK)",
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "description": "MaxAttempts is the number of runs after which a failing job is given up",
+                    "type": "integer"
+                },
+                "run_at": {
+                    "description": "RunAt is when the job is run next, while queued",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the progress of the job",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.JobStatus"
+                        }
+                    ]
+                },
+                "type": {
+                    "description": "Type selects the handler running the job, e.g., \"export\"",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the job last changed status, set by the repository",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user the job works for, null for system jobs",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.JobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "JobQueued",
+                "JobRunning",
+                "JobSucceeded",
+                "JobFailed"
+            ]
+        },
+        "domain.LocationType": {
+            "type": "string",
+            "enum": [
+                "pool",
+                "open_water"
+            ],
+            "x-enum-varnames": [
+                "LocationPool",
+                "LocationOpenWater"
+            ]
+        },
+        "domain.StrokeType": {
+            "type": "string",
+            "enum": [
+                "freestyle",
+                "backstroke",
+                "breaststroke",
+                "butterfly",
+                "medley",
+                "unknown"
+            ],
+            "x-enum-varnames": [
+                "StrokeFreestyle",
+                "StrokeBackstroke",
+                "StrokeBreaststroke",
+                "StrokeButterfly",
+                "StrokeMedley",
+                "StrokeUnknown"
+            ]
+        },
+        "domain.User": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt is when the user was created, set by the repository",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "display_unit": {
+                    "description": "Unit distances and paces are displayed in, \"meters\" (default) or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lactate_threshold_heart_rate": {
+                    "description": "Optional lactate threshold heart rate in bpm, preferred for heart-rate zones",
+                    "type": "integer"
+                },
+                "max_heart_rate": {
+                    "description": "Optional maximum heart rate in bpm",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "resting_heart_rate": {
+                    "description": "Optional resting heart rate in bpm",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the user was last updated, set by the repository",
+                    "type": "string",
+                    "readOnly": true
+                },
+                "version": {
+                    "description": "Version is incremented on every update; An update with a non-zero version\nonly applies if the stored user still has that version",
+                    "type": "integer",
+                    "readOnly": true
+                },
+                "weight": {
+                    "type": "number"
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Activity": {
+        },
+        "domain.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt is when the webhook was registered, set by the repository",
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Events are the events the webhook subscribes to",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the webhook (PK)",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret is the key of the HMAC-SHA256 signature of the deliveries; it is only\nserved when the webhook is created",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the http or https endpoint the events are posted to",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user whose events are sent (FK)",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "description": "Attempt counts the attempts at delivering the event, from 1",
+                    "type": "integer"
+                },
+                "delivered_at": {
+                    "description": "DeliveredAt is when the attempt was made, set by the repository",
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "description": "DurationMS is how long the endpoint took to respond, in milliseconds",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Error tells why the attempt failed, e.g., a timeout or an unsuccessful status",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "Event is the event posted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WebhookEvent"
+                        }
+                    ]
+                },
+                "event_id": {
+                    "description": "EventID is the ID of the event, shared by the attempts at delivering it",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the delivery (PK)",
+                    "type": "string"
+                },
+                "status_code": {
+                    "description": "StatusCode is the HTTP status of the response, 0 if there was none",
+                    "type": "integer"
+                },
+                "webhook_id": {
+                    "description": "WebhookID is the ID of the webhook the event was posted to (FK)",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.WebhookEvent": {
+            "type": "string",
+            "enum": [
+                "activity.created",
+                "activity.updated",
+                "activity.deleted",
+                "record.achieved"
+            ],
+            "x-enum-varnames": [
+                "WebhookActivityCreated",
+                "WebhookActivityUpdated",
+                "WebhookActivityDeleted",
+                "WebhookRecordAchieved"
+            ]
+        },
+        "domain.WebhookPayload": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "event": {
+                    "$ref": "#/definitions/domain.WebhookEvent"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Activity": {
             "type": "object",
             "properties": {
+                "avg_pace_per_100m": {
+                    "description": "Average pace per 100 of DistanceUnit, formatted mm:ss",
+                    "type": "string"
+                },
+                "course": {
+                    "description": "Standard course of the pool, \"SCM\", \"SCY\" or \"LCM\" (omitted if non-standard)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt is when the activity was created",
+                    "type": "string"
+                },
                 "date": {
                     "description": "Date in ISO 8601 format, e.g., \"2023-10-01\"",
                     "type": "string"
                 },
                 "distance": {
-                    "description": "Total distance in meters",
+                    "description": "Total distance, in DistanceUnit",
+                    "type": "number"
+                },
+                "distance_per_stroke": {
+                    "description": "Average distance, in DistanceUnit, covered by each stroke",
                     "type": "number"
                 },
+                "distance_unit": {
+                    "description": "Unit the distances and paces are displayed in, \"meters\" or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
                 "duration": {
-                    "description": "Duration of the activity in string format, e.g., \"1h30m\"",
+                    "description": "Duration of the activity, e.g., \"1h30m0s\" or \"1:30:00\"",
                     "type": "string"
                 },
                 "feeling": {
@@ -490,6 +2536,13 @@ const docTemplate = `{
                     "description": "ID is the unique identifier for the activity (PK)",
                     "type": "string"
                 },
+                "intervals": {
+                    "description": "Intervals are the segments of the swim session",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Interval"
+                    }
+                },
                 "laps": {
                     "description": "Number of pool laps",
                     "type": "integer"
@@ -511,58 +2564,244 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "pool_size": {
-                    "description": "Pool length in meters (0 if open water)",
+                    "description": "Pool length, in PoolUnit (0 if open water)",
                     "type": "number"
                 },
+                "pool_unit": {
+                    "description": "Unit of the pool length, \"meters\" or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
+                "rpe": {
+                    "description": "Rating of perceived exertion, from 1 (very easy) to 10 (maximal)",
+                    "type": "integer"
+                },
                 "start": {
                     "description": "Start time of the activity",
                     "type": "string"
                 },
+                "stroke_count": {
+                    "description": "Total number of strokes over the intervals with stroke data",
+                    "type": "integer"
+                },
+                "swolf": {
+                    "description": "Average strokes plus seconds per pool length",
+                    "type": "number"
+                },
+                "time_in_zone": {
+                    "description": "Time spent in each heart-rate zone, when heart-rate data is available",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TimeInZone"
+                    }
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the activity was last updated",
+                    "type": "string"
+                },
                 "user_id": {
                     "description": "UserID is the ID of the user who performed the activity (FK)",
                     "type": "string"
+                },
+                "version": {
+                    "description": "Version is incremented on every update of the activity",
+                    "type": "integer"
                 }
             }
         },
-        "domain.FeelingType": {
-            "type": "string",
-            "enum": [
-                "excellent",
-                "good",
-                "regular",
-                "tired",
-                "bad"
-            ],
-            "x-enum-varnames": [
-                "FeelingExcellent",
-                "FeelingGood",
-                "FeelingRegular",
-                "FeelingTired",
-                "FeelingBad"
-            ]
+        "entity.CSSTest": {
+            "type": "object",
+            "properties": {
+                "css_pace_per_100m": {
+                    "description": "CSS pace per 100 meters, formatted mm:ss",
+                    "type": "string"
+                },
+                "date": {
+                    "description": "Date in ISO 8601 format, e.g., \"2023-10-01\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the test",
+                    "type": "string"
+                },
+                "interval_200_id": {
+                    "description": "ID of the logged interval used as the 200m trial, if any",
+                    "type": "string"
+                },
+                "interval_400_id": {
+                    "description": "ID of the logged interval used as the 400m trial, if any",
+                    "type": "string"
+                },
+                "time_200": {
+                    "description": "Time of the 200m trial, e.g., \"2m50s\" or \"2:50\"",
+                    "type": "string"
+                },
+                "time_400": {
+                    "description": "Time of the 400m trial, e.g., \"6m0s\" or \"6:00\"",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Unit of the trial distances, \"meters\" or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user who took the test",
+                    "type": "string"
+                },
+                "zones": {
+                    "description": "Pace zones derived from the CSS pace",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PaceZone"
+                    }
+                }
+            }
+        },
+        "entity.DailyTrainingLoad": {
+            "type": "object",
+            "properties": {
+                "acute_chronic_ratio": {
+                    "description": "Acute:chronic workload ratio; values above ~1.5 suggest overreaching",
+                    "type": "number"
+                },
+                "acute_load": {
+                    "description": "Average daily load over the last 7 days (fatigue)",
+                    "type": "number"
+                },
+                "chronic_load": {
+                    "description": "Average daily load over the last 42 days (fitness)",
+                    "type": "number"
+                },
+                "date": {
+                    "description": "Date in ISO 8601 format, e.g., \"2023-10-01\"",
+                    "type": "string"
+                },
+                "load": {
+                    "description": "Sum of the session loads on this day",
+                    "type": "number"
+                }
+            }
+        },
+        "entity.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error is a generic message when the check failed; the reason is only logged",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the check, e.g., \"database\" or \"migrations\"",
+                    "type": "string",
+                    "example": "database"
+                },
+                "status": {
+                    "description": "Status is \"ok\" or \"failed\"",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "entity.HeartRateZone": {
+            "type": "object",
+            "properties": {
+                "max_heart_rate": {
+                    "description": "Highest heart rate of the zone, exclusive (omitted if unbounded)",
+                    "type": "integer"
+                },
+                "min_heart_rate": {
+                    "description": "Lowest heart rate of the zone (omitted if unbounded)",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name of the zone, e.g., \"aerobic\"",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number of the zone, from 1 (easiest) upwards",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.HeartRateZones": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "description": "What the zones are derived from: \"lthr\", \"max_hr\" or \"age\"",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user the zones belong to",
+                    "type": "string"
+                },
+                "zones": {
+                    "description": "Zones from 1 (easiest) to 5",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.HeartRateZone"
+                    }
+                }
+            }
         },
-        "domain.Interval": {
+        "entity.Interval": {
             "type": "object",
             "properties": {
                 "activity_id": {
                     "description": "Foreign key to the swim activity/session",
                     "type": "string"
                 },
+                "created_at": {
+                    "description": "CreatedAt is when the interval was created",
+                    "type": "string"
+                },
                 "distance": {
-                    "description": "Distance in meters",
+                    "description": "Distance in the display unit of the activity",
+                    "type": "number"
+                },
+                "distance_per_stroke": {
+                    "description": "Average distance, in the display unit of the activity, covered by each stroke",
                     "type": "number"
                 },
                 "duration": {
-                    "description": "Duration of the interval in string format, e.g., \"1h30m\"",
+                    "description": "Duration of the interval, e.g., \"1m45.32s\" or \"1:45.32\"",
                     "type": "string"
                 },
+                "heart_rate_avg": {
+                    "description": "Average heart rate during the interval",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
+                "length_stroke_counts": {
+                    "description": "Number of strokes of each pool length, when imported from a device",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "notes": {
                     "description": "Optional notes like \"felt strong\", \"used fins\"",
                     "type": "string"
                 },
+                "pace_per_100m": {
+                    "description": "Pace per 100 of the display unit of the activity, formatted mm:ss",
+                    "type": "string"
+                },
+                "pace_zone": {
+                    "description": "Number of the CSS pace zone the interval pace falls in (0 if unknown)",
+                    "type": "integer"
+                },
+                "pace_zone_name": {
+                    "description": "Name of the CSS pace zone, e.g., \"threshold\"",
+                    "type": "string"
+                },
                 "stroke": {
                     "description": "Type of swimming stroke",
                     "allOf": [
@@ -571,6 +2810,14 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "stroke_count": {
+                    "description": "Total number of strokes taken during the interval",
+                    "type": "integer"
+                },
+                "swolf": {
+                    "description": "Average strokes plus seconds per pool length",
+                    "type": "number"
+                },
                 "type": {
                     "description": "One of the predefined types",
                     "allOf": [
@@ -578,280 +2825,234 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.IntervalType"
                         }
                     ]
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the interval was last updated",
+                    "type": "string"
                 }
             }
         },
-        "domain.IntervalType": {
-            "type": "string",
-            "enum": [
-                "swim",
-                "rest",
-                "drill",
-                "kick",
-                "pull",
-                "warmup",
-                "main_set",
-                "cooldown"
-            ],
-            "x-enum-varnames": [
-                "IntervalSwim",
-                "IntervalRest",
-                "IntervalDrill",
-                "IntervalKick",
-                "IntervalPull",
-                "IntervalWarmUp",
-                "IntervalMainSet",
-                "IntervalCoolDown"
-            ]
-        },
-        "domain.LocationType": {
-            "type": "string",
-            "enum": [
-                "pool",
-                "open_water"
-            ],
-            "x-enum-varnames": [
-                "LocationPool",
-                "LocationOpenWater"
-            ]
-        },
-        "domain.StrokeType": {
-            "type": "string",
-            "enum": [
-                "freestyle",
-                "backstroke",
-                "breaststroke",
-                "butterfly",
-                "medley",
-                "unknown"
-            ],
-            "x-enum-varnames": [
-                "StrokeFreestyle",
-                "StrokeBackstroke",
-                "StrokeBreaststroke",
-                "StrokeButterfly",
-                "StrokeMedley",
-                "StrokeUnknown"
-            ]
-        },
-        "domain.User": {
+        "entity.PaceZone": {
             "type": "object",
             "properties": {
-                "age": {
-                    "type": "integer"
-                },
-                "city": {
+                "fastest_pace": {
+                    "description": "Fastest pace of the zone, formatted mm:ss (omitted if unbounded)",
                     "type": "string"
                 },
-                "email": {
+                "name": {
+                    "description": "Name of the zone, e.g., \"threshold\"",
                     "type": "string"
                 },
-                "height": {
+                "number": {
+                    "description": "Number of the zone, from 1 (easiest) upwards",
                     "type": "integer"
                 },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "phone": {
+                "slowest_pace": {
+                    "description": "Slowest pace of the zone, formatted mm:ss (omitted if unbounded)",
                     "type": "string"
+                }
+            }
+        },
+        "entity.Readiness": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Checks are the individual checks, in the order they ran",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.HealthCheck"
+                    }
                 },
-                "weight": {
-                    "type": "number"
+                "status": {
+                    "description": "Status is \"ready\" when every check passed and \"unavailable\" otherwise",
+                    "type": "string",
+                    "example": "ready"
                 }
             }
         },
-        "entity.Activity": {
+        "entity.SessionStrokeEfficiency": {
             "type": "object",
             "properties": {
-                "avg_pace_per_100m": {
-                    "description": "Average pace in seconds per 100 meters, formatted mm:ss",
+                "activity_id": {
+                    "description": "ActivityID is the ID of the session",
                     "type": "string"
                 },
                 "date": {
                     "description": "Date in ISO 8601 format, e.g., \"2023-10-01\"",
                     "type": "string"
                 },
-                "distance": {
-                    "description": "Total distance in meters",
+                "distance_per_stroke": {
+                    "description": "Average distance, in meters, covered by each stroke",
                     "type": "number"
                 },
-                "duration": {
-                    "description": "Duration of the activity in string format, e.g., \"1h30m\"",
-                    "type": "string"
-                },
-                "feeling": {
-                    "description": "Optional feeling after the swim, e.g., \"tired\"",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/entity.FeelingType"
-                        }
-                    ]
-                },
-                "heart_rate_avg": {
-                    "description": "Average heart rate during the activity",
-                    "type": "integer"
-                },
-                "heart_rate_max": {
-                    "description": "Maximum heart rate during the activity",
+                "stroke_count": {
+                    "description": "Total number of strokes over the intervals with stroke data",
                     "type": "integer"
                 },
-                "id": {
-                    "description": "ID is the unique identifier for the activity (PK)",
+                "swolf": {
+                    "description": "Average strokes plus seconds per pool length (omitted outside the pool)",
+                    "type": "number"
+                }
+            }
+        },
+        "entity.StrokeEfficiencyTrend": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "First day of the period in ISO 8601 format, e.g., \"2023-10-01\"",
                     "type": "string"
                 },
-                "intervals": {
-                    "description": "Intervals are the segments of the swim session",
+                "sessions": {
+                    "description": "Sessions with stroke data, from the oldest to the most recent",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.Interval"
+                        "$ref": "#/definitions/entity.SessionStrokeEfficiency"
                     }
                 },
-                "laps": {
-                    "description": "Number of pool laps",
-                    "type": "integer"
+                "to": {
+                    "description": "Last day of the period in ISO 8601 format, e.g., \"2023-10-28\"",
+                    "type": "string"
                 },
-                "location_name": {
-                    "description": "Optional name for the location, e.g., \"CEPE\"",
+                "user_id": {
+                    "description": "UserID is the ID of the user the trend refers to",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TimeInZone": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Time spent in the zone, e.g., \"12m30s\" or \"12:30\"",
                     "type": "string"
                 },
-                "location_type": {
-                    "description": "\"pool\" or \"open_water\"",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/entity.LocationType"
-                        }
-                    ]
+                "name": {
+                    "description": "Name of the zone, e.g., \"aerobic\"",
+                    "type": "string"
                 },
-                "notes": {
-                    "description": "Optional notes",
+                "zone": {
+                    "description": "Number of the zone",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TimeInZoneSummary": {
+            "type": "object",
+            "properties": {
+                "activities": {
+                    "description": "Number of activities in the period",
+                    "type": "integer"
+                },
+                "from": {
+                    "description": "First day of the period in ISO 8601 format, e.g., \"2023-10-01\"",
                     "type": "string"
                 },
-                "pool_size": {
-                    "description": "Pool length in meters (0 if open water)",
-                    "type": "number"
+                "time_in_zone": {
+                    "description": "Time spent in each zone",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TimeInZone"
+                    }
                 },
-                "start": {
-                    "description": "Start time of the activity",
+                "to": {
+                    "description": "Last day of the period in ISO 8601 format, e.g., \"2023-10-28\"",
                     "type": "string"
                 },
                 "user_id": {
-                    "description": "UserID is the ID of the user who performed the activity (FK)",
+                    "description": "UserID is the ID of the user the summary refers to",
                     "type": "string"
                 }
             }
         },
-        "entity.FeelingType": {
-            "type": "string",
-            "enum": [
-                "excellent",
-                "good",
-                "regular",
-                "tired",
-                "bad"
-            ],
-            "x-enum-varnames": [
-                "FeelingExcellent",
-                "FeelingGood",
-                "FeelingRegular",
-                "FeelingTired",
-                "FeelingBad"
-            ]
-        },
-        "entity.Interval": {
+        "entity.TrainingLoad": {
             "type": "object",
             "properties": {
-                "activity_id": {
-                    "description": "Foreign key to the swim activity/session",
-                    "type": "string"
-                },
-                "distance": {
-                    "description": "Distance in meters",
-                    "type": "number"
+                "days": {
+                    "description": "Days holds one entry per day of the period",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DailyTrainingLoad"
+                    }
                 },
-                "duration": {
-                    "description": "Duration of the interval in string format, e.g., \"1h30m\"",
+                "from": {
+                    "description": "First day of the period in ISO 8601 format, e.g., \"2023-10-01\"",
                     "type": "string"
                 },
-                "id": {
+                "to": {
+                    "description": "Last day of the period in ISO 8601 format, e.g., \"2023-10-28\"",
                     "type": "string"
                 },
-                "notes": {
-                    "description": "Optional notes like \"felt strong\", \"used fins\"",
+                "user_id": {
+                    "description": "UserID is the ID of the user the load refers to",
                     "type": "string"
-                },
-                "stroke": {
-                    "description": "Type of swimming stroke",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/entity.StrokeType"
-                        }
-                    ]
-                },
-                "type": {
-                    "description": "One of the predefined types",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/entity.IntervalType"
-                        }
-                    ]
                 }
             }
         },
-        "entity.IntervalType": {
-            "type": "string",
-            "enum": [
-                "swim",
-                "rest",
-                "drill",
-                "kick",
-                "pull",
-                "warmup",
-                "main_set",
-                "cooldown"
-            ],
-            "x-enum-varnames": [
-                "IntervalSwim",
-                "IntervalRest",
-                "IntervalDrill",
-                "IntervalKick",
-                "IntervalPull",
-                "IntervalWarmUp",
-                "IntervalMainSet",
-                "IntervalCoolDown"
-            ]
+        "graphql.Error": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Location"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
         },
-        "entity.LocationType": {
-            "type": "string",
-            "enum": [
-                "pool",
-                "open_water"
-            ],
-            "x-enum-varnames": [
-                "LocationPool",
-                "LocationOpenWater"
-            ]
+        "graphql.Location": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
         },
-        "entity.StrokeType": {
-            "type": "string",
-            "enum": [
-                "freestyle",
-                "backstroke",
-                "breaststroke",
-                "butterfly",
-                "medley",
-                "unknown"
+        "graphql.Request": {
+            "type": "object",
+            "required": [
+                "query"
             ],
-            "x-enum-varnames": [
-                "StrokeFreestyle",
-                "StrokeBackstroke",
-                "StrokeBreaststroke",
-                "StrokeButterfly",
-                "StrokeMedley",
-                "StrokeUnknown"
-            ]
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ user(id: \"550e8400-e29b-41d4-a716-446655440000\") { name activities(limit: 10) { date distance intervals { distance stroke } } } }"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Error"
+                    }
+                }
+            }
         },
         "handler.CreateActivityRequest": {
             "type": "object",
@@ -870,11 +3071,11 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "distance": {
-                    "description": "Total distance in meters",
+                    "description": "Total distance in PoolUnit, stored in meters",
                     "type": "number"
                 },
                 "duration": {
-                    "description": "Start time of the activity\nStart time.Time ` + "`" + `json:\"start\"` + "`" + ` // TODO - must implement format handling\nDuration of the activity in a string format, e.g., \"1h30m\"",
+                    "description": "Start time of the activity\nStart time.Time ` + "`" + `json:\"start\"` + "`" + ` // TODO - must implement format handling\nDuration of the activity in swim notation, e.g., \"1:30:00\", or Go syntax, e.g., \"1h30m\"",
                     "type": "string"
                 },
                 "feeling": {
@@ -887,11 +3088,15 @@ const docTemplate = `{
                 },
                 "heart_rate_avg": {
                     "description": "Average heart rate during the activity",
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
                 },
                 "heart_rate_max": {
                     "description": "Maximum heart rate during the activity",
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
                 },
                 "laps": {
                     "description": "Number of pool laps",
@@ -914,20 +3119,46 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "pool_size": {
-                    "description": "Pool size in meters (0 if open water)",
+                    "description": "Pool size in PoolUnit (0 if open water)",
                     "type": "number"
                 },
+                "pool_unit": {
+                    "description": "Optional unit of the pool size, \"meters\" (default) or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
+                "rpe": {
+                    "description": "Optional rating of perceived exertion, from 1 (very easy) to 10 (maximal)",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                },
                 "user_id": {
                     "description": "ID of the user who performed the activity",
                     "type": "string"
                 }
             }
         },
+        "handler.CreateCSSTestRequest": {
+            "type": "object",
+            "properties": {
+                "interval_200_id": {
+                    "description": "Interval200ID is the ID of the logged interval used as the 200m trial",
+                    "type": "string"
+                },
+                "interval_400_id": {
+                    "description": "Interval400ID is the ID of the logged interval used as the 400m trial",
+                    "type": "string"
+                }
+            }
+        },
         "handler.CreateIntervalRequest": {
             "type": "object",
             "required": [
                 "activity_id",
-                "distance",
                 "duration",
                 "stroke",
                 "type"
@@ -938,13 +3169,35 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "distance": {
-                    "description": "Distance in meters",
-                    "type": "number"
+                    "description": "Distance in DistanceUnit, stored in meters; may only be 0 for rest intervals",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "distance_unit": {
+                    "description": "Optional unit of the distance, usually the pool unit of the activity, \"meters\" (default) or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
                 },
                 "duration": {
-                    "description": "Duration of the interval in string format, e.g., \"1h30m\"",
+                    "description": "Duration of the interval in swim notation, e.g., \"1:45.32\", or Go syntax, e.g., \"1m45.32s\"",
                     "type": "string"
                 },
+                "heart_rate_avg": {
+                    "description": "HeartRateAvg is the optional average heart rate during the interval",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
+                "length_stroke_counts": {
+                    "description": "LengthStrokeCounts are the optional stroke counts of each pool length",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "notes": {
                     "description": "Notes are optional remarks such as \"felt strong\", \"used fins\"",
                     "type": "string"
@@ -957,6 +3210,11 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "stroke_count": {
+                    "description": "StrokeCount is the optional total number of strokes taken during the interval",
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "type": {
                     "description": "Type is one of the predefined interval types like \"swim\", \"rest\", etc.",
                     "allOf": [
@@ -977,16 +3235,40 @@ const docTemplate = `{
             ],
             "properties": {
                 "age": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 1
                 },
                 "city": {
                     "type": "string"
                 },
+                "display_unit": {
+                    "description": "Optional unit distances and paces are displayed in, \"meters\" (default) or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
                 "email": {
                     "type": "string"
                 },
                 "height": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 50
+                },
+                "lactate_threshold_heart_rate": {
+                    "description": "Optional lactate threshold heart rate in bpm, preferred for heart-rate zones",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
+                "max_heart_rate": {
+                    "description": "Optional maximum heart rate in bpm, used for heart-rate based training load",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
                 },
                 "name": {
                     "type": "string"
@@ -994,16 +3276,81 @@ const docTemplate = `{
                 "phone": {
                     "type": "string"
                 },
+                "resting_heart_rate": {
+                    "description": "Optional resting heart rate in bpm, used for heart-rate based training load",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
                 "weight": {
-                    "type": "number"
+                    "type": "number",
+                    "maximum": 400
                 }
             }
         },
-        "handler.ErrorResponse": {
+        "handler.CreateWebhookRequest": {
             "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
             "properties": {
-                "error": {
-                    "description": "Error is a description of what went wrong.\nExample: Service error",
+                "events": {
+                    "description": "Events to subscribe to: \"activity.created\", \"activity.updated\", \"activity.deleted\" or \"record.achieved\"",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/domain.WebhookEvent"
+                    }
+                },
+                "url": {
+                    "description": "URL is the http or https endpoint the events are posted to",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ExportResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "CompletedAt is when the archive was generated or failed",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt is when the export was requested, set by the repository",
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "DownloadURL is the path the archive can be downloaded from, once ready",
+                    "type": "string",
+                    "example": "/api/v1/exports/6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1/download"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is when the export is deleted, archive included",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the export (PK)",
+                    "type": "string"
+                },
+                "job_id": {
+                    "description": "JobID is the ID of the background job generating the archive, null for exports\nrequested before jobs existed",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Size of the archive in bytes, once ready",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status is the progress of the export",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ExportStatus"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user whose data is exported",
                     "type": "string"
                 }
             }
@@ -1018,6 +3365,194 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "handler.ProblemDetails": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "Detail is an explanation specific to this occurrence of the problem\nExample: user not found",
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors holds the details of each invalid field, for validation problems",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldError"
+                    }
+                },
+                "instance": {
+                    "description": "Instance is the path of the request that caused the problem\nExample: /users/6b1f0c1e-5d0e-4a7b-9d55-0ad3c1b2e0f1",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the HTTP status code\nExample: 404",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Title is a short summary of the problem type\nExample: Not Found",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is a URI reference identifying the problem type\nExample: about:blank",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.StatusResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "Status is \"ok\" while the API is alive",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.UpdateActivityRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "distance",
+                "duration",
+                "laps",
+                "location_type",
+                "pool_size"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Date in ISO 8601 format, e.g., \"2023-10-01\"",
+                    "type": "string"
+                },
+                "distance": {
+                    "description": "Total distance in PoolUnit, stored in meters",
+                    "type": "number"
+                },
+                "duration": {
+                    "description": "Start time of the activity\nStart time.Time ` + "`" + `json:\"start\"` + "`" + ` // TODO - must implement format handling\nDuration of the activity in swim notation, e.g., \"1:30:00\", or Go syntax, e.g., \"1h30m\"",
+                    "type": "string"
+                },
+                "feeling": {
+                    "description": "Optional feeling after the swim, e.g., \"tired\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.FeelingType"
+                        }
+                    ]
+                },
+                "heart_rate_avg": {
+                    "description": "Average heart rate during the activity",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
+                "heart_rate_max": {
+                    "description": "Maximum heart rate during the activity",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
+                "laps": {
+                    "description": "Number of pool laps",
+                    "type": "integer"
+                },
+                "location_name": {
+                    "description": "Optional name for the location, e.g., \"CEPE\"",
+                    "type": "string"
+                },
+                "location_type": {
+                    "description": "\"pool\" or \"open_water\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.LocationType"
+                        }
+                    ]
+                },
+                "notes": {
+                    "description": "Optional notes",
+                    "type": "string"
+                },
+                "pool_size": {
+                    "description": "Pool size in PoolUnit (0 if open water)",
+                    "type": "number"
+                },
+                "pool_unit": {
+                    "description": "Optional unit of the pool size, \"meters\" (default) or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
+                "rpe": {
+                    "description": "Optional rating of perceived exertion, from 1 (very easy) to 10 (maximal)",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                }
+            }
+        },
+        "handler.UpdateUserRequest": {
+            "type": "object",
+            "required": [
+                "city",
+                "email",
+                "name",
+                "phone"
+            ],
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 1
+                },
+                "city": {
+                    "type": "string"
+                },
+                "display_unit": {
+                    "description": "Optional unit distances and paces are displayed in, \"meters\" or \"yards\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DistanceUnit"
+                        }
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 50
+                },
+                "lactate_threshold_heart_rate": {
+                    "description": "Optional lactate threshold heart rate in bpm, preferred for heart-rate zones",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
+                "max_heart_rate": {
+                    "description": "Optional maximum heart rate in bpm, used for heart-rate based training load",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "resting_heart_rate": {
+                    "description": "Optional resting heart rate in bpm, used for heart-rate based training load",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 30
+                },
+                "weight": {
+                    "type": "number",
+                    "maximum": 400
+                }
+            }
         }
     }
 }`
@@ -150,7 +150,7 @@ const AtividadeLog: React.FC = () => {
             };
 
             // 1. Create activity
-            const response = await fetch(`http://localhost:8080/activities`, {
+            const response = await fetch(`http://localhost:8080/api/v1/activities`, {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify(apiActivity)
@@ -175,7 +175,7 @@ const AtividadeLog: React.FC = () => {
                 if (interval.notes) {
                     apiInterval.notes = interval.notes;
                 }
-                const intervalResp = await fetch(`http://localhost:8080/intervals`, {
+                const intervalResp = await fetch(`http://localhost:8080/api/v1/intervals`, {
                     method: 'POST',
                     headers: { 'Content-Type': 'application/json' },
                     body: JSON.stringify(apiInterval)
@@ -18,7 +18,7 @@ const Dashboard: React.FC = () => {
         const fetchWeeklyStats = async () => {
             setLoading(true);
             try {
-                const response = await fetch(`http://localhost:8080/users/${userId}/activities`);
+                const response = await fetch(`http://localhost:8080/api/v1/users/${userId}/activities`);
                 const data = await response.json();
                 const activities = Array.isArray(data.activities) ? data.activities : [];
                 // Get start of week (Monday)
@@ -82,6 +82,18 @@ const Dashboard: React.FC = () => {
             }
         };
         fetchWeeklyStats();
+        // Reload the stats whenever an activity changes, or after the stream reconnects, as events may have been missed;
+        // The stream requires authentication, which the dev server proxy provides (see vite.config.ts)
+        const events = new EventSource(`/api/v1/users/${userId}/events`);
+        ['activity.created', 'activity.updated', 'activity.deleted'].forEach((type) => {
+            events.addEventListener(type, fetchWeeklyStats);
+        });
+        let connected = false;
+        events.onopen = () => {
+            if (connected) fetchWeeklyStats();
+            connected = true;
+        };
+        return () => events.close();
     }, [userId]);
 
     const stats = [
@@ -43,7 +43,7 @@ const Historico: React.FC = () => {
             setLoading(true);
             setError(null);
             try {
-                const response = await fetch(`http://localhost:8080/users/${userId}/activities`);
+                const response = await fetch(`http://localhost:8080/api/v1/users/${userId}/activities`);
                 if (!response.ok) throw new Error('Erro ao buscar atividades');
                 const data = await response.json();
                 // Map API response to Activity[]
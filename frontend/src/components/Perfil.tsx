@@ -20,7 +20,7 @@ const Perfil: React.FC = () => {
 		// IMPORTANT: change id from hardcoded to fetched (auth)
 		const userId = "59f4e428-9d42-4af8-a18d-1e1dabef47e0";
 
-		fetch(`http://localhost:8080/users/${userId}`)
+		fetch(`http://localhost:8080/api/v1/users/${userId}`)
 		.then(response => response.json())
 		.then(data => {
 			setFormData(prevData => ({
@@ -36,7 +36,7 @@ const Perfil: React.FC = () => {
 		});
 
 		// Fetch total activities and distance
-		fetch(`http://localhost:8080/users/${userId}/activities`)
+		fetch(`http://localhost:8080/api/v1/users/${userId}/activities`)
 			.then(response => response.json())
 			.then(data => {
 				if (Array.isArray(data.activities)) {
@@ -80,7 +80,7 @@ const Perfil: React.FC = () => {
 			phone: formData.phone
 		};
 
-		fetch(`http://localhost:8080/users/${userId}`, {
+		fetch(`http://localhost:8080/api/v1/users/${userId}`, {
 			method: 'PUT',
 			headers: {
 				'Content-Type': 'application/json',
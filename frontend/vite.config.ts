@@ -1,18 +1,33 @@
-import { defineConfig } from 'vite';
+import { defineConfig, loadEnv } from 'vite';
 import react from '@vitejs/plugin-react';
 
 // https://vitejs.dev/config/
-export default defineConfig({
-  plugins: [react()],
-  optimizeDeps: {
-    exclude: ['lucide-react'],
-  },
-  server: {
-    proxy: {
-      '/users': {
-        target: 'http://localhost:8080', // Change this if your backend runs elsewhere
-        changeOrigin: true,
+export default defineConfig(({ mode }) => {
+  const env = loadEnv(mode, process.cwd(), '');
+  const target = env.API_TARGET || 'http://localhost:8080'; // Change this if your backend runs elsewhere
+
+  return {
+    plugins: [react()],
+    optimizeDeps: {
+      exclude: ['lucide-react'],
+    },
+    server: {
+      proxy: {
+        '/users': {
+          target,
+          changeOrigin: true,
+        },
+        // Dev auth: requests to /api are authenticated as DEV_USER_ID, the user the components show.
+        // The backend only reads the user header from its TRUSTED_PROXIES, so run it with
+        // AUTH_USER_HEADER=X-User-ID and TRUSTED_PROXIES set to the address of this server, e.g., 127.0.0.1
+        '/api': {
+          target,
+          changeOrigin: true,
+          headers: {
+            'X-User-ID': env.DEV_USER_ID || '59f4e428-9d42-4af8-a18d-1e1dabef47e0',
+          },
+        },
       },
     },
-  },
+  };
 });